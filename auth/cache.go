@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCurrentUserCacheDuration is how long an account is reused for the same token. It is short, so that
+	// changes of roles and status take effect quickly.
+	DefaultCurrentUserCacheDuration = 30 * time.Second
+	maxCachedTokens                 = 10000
+)
+
+type cachedUser struct {
+	account businesslogic.Account
+	expires time.Time
+}
+
+// CachingAuthenticationStrategy wraps an IAuthenticationStrategy so that each request is authenticated at most once,
+// and so that the requests of a page load do not each look up the identity provider and the account repository.
+// The account of a request is taken from its context first, then from a short-lived cache by the Authorization
+// header, and only then from the wrapped strategy. Tokens are kept in the cache as hashes.
+type CachingAuthenticationStrategy struct {
+	strategy IAuthenticationStrategy
+	duration time.Duration
+	lock     *sync.Mutex
+	users    map[[sha256.Size]byte]cachedUser
+	Now      func() time.Time
+}
+
+func NewCachingAuthenticationStrategy(strategy IAuthenticationStrategy, duration time.Duration) CachingAuthenticationStrategy {
+	return CachingAuthenticationStrategy{
+		strategy: strategy,
+		duration: duration,
+		lock:     new(sync.Mutex),
+		users:    make(map[[sha256.Size]byte]cachedUser),
+		Now:      time.Now,
+	}
+}
+
+// GetCurrentUser returns the account of the request. Failed authentications are not cached.
+func (cache CachingAuthenticationStrategy) GetCurrentUser(r *http.Request) (businesslogic.Account, error) {
+	if account, ok := CurrentUser(r.Context()); ok {
+		return account, nil
+	}
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return cache.strategy.GetCurrentUser(r)
+	}
+	key := sha256.Sum256([]byte(header))
+	now := cache.Now()
+
+	cache.lock.Lock()
+	cached, ok := cache.users[key]
+	cache.lock.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.account, nil
+	}
+
+	account, err := cache.strategy.GetCurrentUser(r)
+	if err != nil {
+		return account, err
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if len(cache.users) >= maxCachedTokens {
+		cache.evictExpired(now)
+	}
+	cache.users[key] = cachedUser{account: account, expires: now.Add(cache.duration)}
+	return account, nil
+}
+
+// CreateUser creates the account with the wrapped strategy
+func (cache CachingAuthenticationStrategy) CreateUser(account *businesslogic.Account) error {
+	return cache.strategy.CreateUser(account)
+}
+
+// Forget removes the account of the Authorization header of the request from the cache, so that the token stops
+// working right away after it is revoked
+func (cache CachingAuthenticationStrategy) Forget(r *http.Request) {
+	key := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	delete(cache.users, key)
+}
+
+// ForgetAccount removes every token of the account from the cache, so that tokens of sessions that are revoked, or
+// of an account that is locked, stop working right away
+func (cache CachingAuthenticationStrategy) ForgetAccount(accountID int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	for key, each := range cache.users {
+		if each.account.ID == accountID {
+			delete(cache.users, key)
+		}
+	}
+}
+
+// WithStrategy returns a cache that authenticates with the strategy and shares the accounts of this cache, so that
+// the cache can be created before the strategy that depends on it
+func (cache CachingAuthenticationStrategy) WithStrategy(strategy IAuthenticationStrategy) CachingAuthenticationStrategy {
+	cache.strategy = strategy
+	return cache
+}
+
+// evictExpired removes expired accounts, and everything if the cache is still full. The caller must hold the lock.
+func (cache CachingAuthenticationStrategy) evictExpired(now time.Time) {
+	for key, each := range cache.users {
+		if !now.Before(each.expires) {
+			delete(cache.users, key)
+		}
+	}
+	if len(cache.users) >= maxCachedTokens {
+		for key := range cache.users {
+			delete(cache.users, key)
+		}
+	}
+}
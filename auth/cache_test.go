@@ -0,0 +1,98 @@
+package auth_test
+
+import (
+	"errors"
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// countingStrategy accepts the token "Bearer valid" as account 7, and counts how many times it is asked
+type countingStrategy struct {
+	calls *int
+}
+
+func (strategy countingStrategy) GetCurrentUser(r *http.Request) (businesslogic.Account, error) {
+	*strategy.calls++
+	if r.Header.Get("Authorization") != "Bearer valid" {
+		return businesslogic.Account{}, errors.New("invalid authentication token")
+	}
+	return businesslogic.Account{ID: 7}, nil
+}
+
+func (strategy countingStrategy) CreateUser(account *businesslogic.Account) error {
+	return nil
+}
+
+func request(token string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1.0/account/profile", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestCachingAuthenticationStrategy_GetCurrentUser(t *testing.T) {
+	calls := 0
+	cache := auth.NewCachingAuthenticationStrategy(countingStrategy{calls: &calls}, 30*time.Second)
+	now := time.Now()
+	cache.Now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		account, err := cache.GetCurrentUser(request("valid"))
+		assert.Nil(t, err)
+		assert.Equal(t, 7, account.ID)
+	}
+	assert.Equal(t, 1, calls, "accounts should be cached by token")
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetCurrentUser(request("invalid"))
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, 3, calls, "failed authentications should not be cached")
+
+	now = now.Add(31 * time.Second)
+	cache.GetCurrentUser(request("valid"))
+	assert.Equal(t, 4, calls, "cached accounts should expire")
+
+	cache.Forget(request("valid"))
+	cache.GetCurrentUser(request("valid"))
+	assert.Equal(t, 5, calls, "forgotten tokens should be authenticated again")
+}
+
+func TestCachingAuthenticationStrategy_ForgetAccount(t *testing.T) {
+	calls := 0
+	cache := auth.NewCachingAuthenticationStrategy(nil, 30*time.Second).WithStrategy(countingStrategy{calls: &calls})
+
+	cache.GetCurrentUser(request("valid"))
+	cache.GetCurrentUser(request("valid"))
+	assert.Equal(t, 1, calls)
+
+	cache.ForgetAccount(8)
+	cache.GetCurrentUser(request("valid"))
+	assert.Equal(t, 1, calls, "tokens of other accounts should stay cached")
+
+	cache.ForgetAccount(7)
+	cache.GetCurrentUser(request("valid"))
+	assert.Equal(t, 2, calls, "tokens of the forgotten account should be authenticated again")
+}
+
+func TestCachingAuthenticationStrategy_RequestContext(t *testing.T) {
+	calls := 0
+	cache := auth.NewCachingAuthenticationStrategy(countingStrategy{calls: &calls}, 30*time.Second)
+
+	r := request("valid")
+	_, ok := auth.CurrentUser(r.Context())
+	assert.False(t, ok)
+
+	r = r.WithContext(auth.WithCurrentUser(r.Context(), businesslogic.Account{ID: 8}))
+	account, ok := auth.CurrentUser(r.Context())
+	assert.True(t, ok)
+	assert.Equal(t, 8, account.ID)
+
+	account, err := cache.GetCurrentUser(r)
+	assert.Nil(t, err)
+	assert.Equal(t, 8, account.ID, "the account in the request context should be used")
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+	"github.com/DancesportSoftware/das/businesslogic"
+)
+
+type contextKey int
+
+const currentUserKey contextKey = iota
+
+// WithCurrentUser returns a copy of the context that carries the authenticated account of the request
+func WithCurrentUser(ctx context.Context, account businesslogic.Account) context.Context {
+	return context.WithValue(ctx, currentUserKey, account)
+}
+
+// CurrentUser returns the authenticated account of the request, including its roles. The second value is false if
+// the request has not been authenticated, which is the case for endpoints that do not require a role.
+func CurrentUser(ctx context.Context) (businesslogic.Account, bool) {
+	account, ok := ctx.Value(currentUserKey).(businesslogic.Account)
+	return account, ok
+}
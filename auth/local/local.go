@@ -0,0 +1,285 @@
+// Package local implements IAuthenticationStrategy with DAS as the identity provider. Passwords are stored in
+// DAS.ACCOUNT_SECURITY, and DAS issues its own JSON Web Tokens, so that DAS can run without any external service.
+package local
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Default lifetimes of tokens. Access tokens are short-lived, and are renewed with the refresh token of the session.
+const (
+	DefaultAccessTokenValidity  = time.Hour
+	DefaultRefreshTokenValidity = 30 * 24 * time.Hour
+)
+
+// InvalidTokenError is returned when a token is malformed, expired, or belongs to a session that has ended
+var InvalidTokenError = errors.New("invalid or expired token")
+
+// TokenPair is issued when users log in or refresh their session
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// LocalAuthenticationStrategy implements IAuthenticationStrategy with accounts and passwords stored in DAS. Each login
+// starts an AccountSession. Access tokens are verified against the session of the token, so that they stop working
+// as soon as users log out.
+type LocalAuthenticationStrategy struct {
+	accountRepo          businesslogic.IAccountRepository
+	sessionRepo          businesslogic.IAccountSessionRepository
+	security             businesslogic.AccountSecurityService
+	signer               ISigner
+	AccessTokenValidity  time.Duration
+	RefreshTokenValidity time.Duration
+	Now                  func() time.Time
+}
+
+func NewLocalAuthenticationStrategy(accountRepo businesslogic.IAccountRepository,
+	sessionRepo businesslogic.IAccountSessionRepository,
+	security businesslogic.AccountSecurityService,
+	signer ISigner) LocalAuthenticationStrategy {
+	return LocalAuthenticationStrategy{
+		accountRepo:          accountRepo,
+		sessionRepo:          sessionRepo,
+		security:             security,
+		signer:               signer,
+		AccessTokenValidity:  DefaultAccessTokenValidity,
+		RefreshTokenValidity: DefaultRefreshTokenValidity,
+		Now:                  time.Now,
+	}
+}
+
+// GetCurrentUser verifies the bearer access token of the request, and returns the account of the token if its session
+// is still active
+func (strategy LocalAuthenticationStrategy) GetCurrentUser(r *http.Request) (businesslogic.Account, error) {
+	claims, err := strategy.verifyRequest(r)
+	if err != nil {
+		return businesslogic.Account{}, err
+	}
+	account, err := strategy.findAccount(claims.Subject)
+	if err != nil {
+		return businesslogic.Account{}, err
+	}
+	if _, err := strategy.activeSession(account.ID, claims.SessionID); err != nil {
+		return businesslogic.Account{}, err
+	}
+	return account, nil
+}
+
+// CreateUser creates the account in DAS. Accounts of this strategy are identified by a random UID, which is
+// generated if the account does not have one.
+func (strategy LocalAuthenticationStrategy) CreateUser(account *businesslogic.Account) error {
+	if account.UID == "" {
+		uid, err := randomID()
+		if err != nil {
+			return err
+		}
+		account.UID = uid
+	}
+	if account.AccountStatusID == 0 {
+		account.AccountStatusID = businesslogic.AccountStatusActivated
+	}
+	existing, err := strategy.accountRepo.SearchAccount(businesslogic.SearchAccountCriteria{Email: account.Email})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return errors.New("an account with this email already exists")
+	}
+	return strategy.accountRepo.CreateAccount(account)
+}
+
+// SignUp creates the account with the password. The password is checked before the account is created.
+func (strategy LocalAuthenticationStrategy) SignUp(account *businesslogic.Account, password string) error {
+	if err := strategy.security.Policy.Check(password, *account); err != nil {
+		return err
+	}
+	if err := strategy.CreateUser(account); err != nil {
+		return err
+	}
+	return strategy.security.SetPassword(*account, password, account.ID)
+}
+
+// Login checks the email and the password, and starts a new session for the account
+func (strategy LocalAuthenticationStrategy) Login(email, password string) (TokenPair, error) {
+	account, err := strategy.security.Authenticate(email, password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return strategy.startSession(account)
+}
+
+// Refresh exchanges the refresh token of a session for a new pair of tokens. Each refresh token can only be used once:
+// the session of the token is revoked and a new session is started, so that a stolen refresh token stops working as
+// soon as either party uses it.
+func (strategy LocalAuthenticationStrategy) Refresh(refreshToken string) (TokenPair, error) {
+	claims, err := DecodeToken(strategy.signer, refreshToken, strategy.Now())
+	if err != nil || claims.Use != TokenUseRefresh {
+		return TokenPair{}, InvalidTokenError
+	}
+	account, err := strategy.findAccount(claims.Subject)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	session, err := strategy.activeSession(account.ID, claims.SessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := strategy.revoke(session, account.ID); err != nil {
+		return TokenPair{}, err
+	}
+	return strategy.startSession(account)
+}
+
+// Logout revokes the session of the access token of the request
+func (strategy LocalAuthenticationStrategy) Logout(r *http.Request) error {
+	claims, err := strategy.verifyRequest(r)
+	if err != nil {
+		return err
+	}
+	account, err := strategy.findAccount(claims.Subject)
+	if err != nil {
+		return err
+	}
+	session, err := strategy.activeSession(account.ID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	return strategy.revoke(session, account.ID)
+}
+
+// ChangePassword replaces the password of the account of the access token of the request. The session of the token
+// stays signed in, and all other sessions of the account are ended.
+func (strategy LocalAuthenticationStrategy) ChangePassword(r *http.Request, currentPassword, newPassword string) error {
+	claims, err := strategy.verifyRequest(r)
+	if err != nil {
+		return err
+	}
+	account, err := strategy.findAccount(claims.Subject)
+	if err != nil {
+		return err
+	}
+	if _, err := strategy.activeSession(account.ID, claims.SessionID); err != nil {
+		return err
+	}
+	return strategy.security.ChangePassword(account, claims.SessionID, currentPassword, newPassword)
+}
+
+func (strategy LocalAuthenticationStrategy) verifyRequest(r *http.Request) (Claims, error) {
+	authHeader := r.Header.Get("Authorization")
+	if len(authHeader) < 1 {
+		return Claims{}, errors.New("empty authentication token")
+	}
+	bearerToken := strings.Split(authHeader, " ")
+	if len(bearerToken) != 2 || bearerToken[0] != "Bearer" {
+		return Claims{}, errors.New("invalid authentication token")
+	}
+	claims, err := DecodeToken(strategy.signer, bearerToken[1], strategy.Now())
+	if err != nil {
+		log.Printf("[local-auth] rejected token of request %v: %v", r.URL, err)
+		return Claims{}, InvalidTokenError
+	}
+	if claims.Use != TokenUseAccess {
+		return Claims{}, InvalidTokenError
+	}
+	return claims, nil
+}
+
+// findAccount returns the account of the UID if it can still sign in
+func (strategy LocalAuthenticationStrategy) findAccount(uid string) (businesslogic.Account, error) {
+	accounts, err := strategy.accountRepo.SearchAccount(businesslogic.SearchAccountCriteria{UUID: uid})
+	if err != nil {
+		return businesslogic.Account{}, err
+	}
+	if len(accounts) != 1 {
+		return businesslogic.Account{}, InvalidTokenError
+	}
+	account := accounts[0]
+	if account.AccountStatusID == businesslogic.AccountStatusSuspended || account.AccountStatusID == businesslogic.AccountStatusLocked {
+		return businesslogic.Account{}, errors.New("account cannot sign in")
+	}
+	return account, nil
+}
+
+func (strategy LocalAuthenticationStrategy) activeSession(accountID int, tokenID string) (businesslogic.AccountSession, error) {
+	sessions, err := strategy.sessionRepo.SearchAccountSession(businesslogic.SearchAccountSessionCriteria{
+		AccountID: accountID,
+		TokenID:   tokenID,
+	})
+	if err != nil {
+		return businesslogic.AccountSession{}, err
+	}
+	if len(sessions) != 1 || !sessions[0].IsActive(strategy.Now()) {
+		return businesslogic.AccountSession{}, InvalidTokenError
+	}
+	return sessions[0], nil
+}
+
+func (strategy LocalAuthenticationStrategy) revoke(session businesslogic.AccountSession, updatedBy int) error {
+	now := strategy.Now()
+	session.DateTimeRevoked = &now
+	session.UpdateUserID = updatedBy
+	session.DateTimeUpdated = now
+	return strategy.sessionRepo.UpdateAccountSession(session)
+}
+
+func (strategy LocalAuthenticationStrategy) startSession(account businesslogic.Account) (TokenPair, error) {
+	sessionID, err := randomID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	now := strategy.Now()
+	session := businesslogic.AccountSession{
+		AccountID:       account.ID,
+		TokenID:         sessionID,
+		DateTimeExpires: now.Add(strategy.RefreshTokenValidity),
+		CreateUserID:    account.ID,
+		DateTimeCreated: now,
+		UpdateUserID:    account.ID,
+		DateTimeUpdated: now,
+	}
+	if err := strategy.sessionRepo.CreateAccountSession(&session); err != nil {
+		return TokenPair{}, err
+	}
+
+	pair := TokenPair{ExpiresAt: now.Add(strategy.AccessTokenValidity)}
+	if pair.AccessToken, err = strategy.issue(account, sessionID, TokenUseAccess, pair.ExpiresAt); err != nil {
+		return TokenPair{}, err
+	}
+	if pair.RefreshToken, err = strategy.issue(account, sessionID, TokenUseRefresh, session.DateTimeExpires); err != nil {
+		return TokenPair{}, err
+	}
+	return pair, nil
+}
+
+func (strategy LocalAuthenticationStrategy) issue(account businesslogic.Account, sessionID, use string, expires time.Time) (string, error) {
+	tokenID, err := randomID()
+	if err != nil {
+		return "", err
+	}
+	return EncodeToken(strategy.signer, Claims{
+		Issuer:    Issuer,
+		Subject:   account.UID,
+		SessionID: sessionID,
+		TokenID:   tokenID,
+		Use:       use,
+		IssuedAt:  strategy.Now().Unix(),
+		ExpiresAt: expires.Unix(),
+	})
+}
+
+func randomID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
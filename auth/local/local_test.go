@@ -0,0 +1,198 @@
+package local_test
+
+import (
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testSigner = local.NewHMACSigner([]byte("0123456789abcdef0123456789abcdef"))
+
+// newAthleteAccount is account 7, which signs in with the password "secret"
+func newAthleteAccount() businesslogic.Account {
+	return businesslogic.Account{ID: 7, UID: "uid-7", Email: "athlete@example.com", AccountStatusID: businesslogic.AccountStatusActivated}
+}
+
+func newAthleteSecurity(hasher local.PBKDF2PasswordHasher) []businesslogic.AccountSecurity {
+	hash, _ := hasher.HashPassword("secret")
+	return []businesslogic.AccountSecurity{{ID: 1, AccountID: 7, PasswordHash: hash}}
+}
+
+// storeSessions keeps the sessions of the strategy in memory, and expects them to be created, searched and updated the
+// given number of times
+func storeSessions(sessionRepo *mock_businesslogic.MockIAccountSessionRepository, created, searched, updated int) {
+	sessions := make(map[string]*businesslogic.AccountSession)
+	sessionRepo.EXPECT().CreateAccountSession(gomock.Any()).DoAndReturn(func(session *businesslogic.AccountSession) error {
+		session.ID = len(sessions) + 1
+		stored := *session
+		sessions[session.TokenID] = &stored
+		return nil
+	}).Times(created)
+	sessionRepo.EXPECT().SearchAccountSession(gomock.Any()).DoAndReturn(func(criteria businesslogic.SearchAccountSessionCriteria) ([]businesslogic.AccountSession, error) {
+		results := make([]businesslogic.AccountSession, 0)
+		for _, each := range sessions {
+			if each.AccountID == criteria.AccountID && (criteria.TokenID == "" || each.TokenID == criteria.TokenID) {
+				results = append(results, *each)
+			}
+		}
+		return results, nil
+	}).Times(searched)
+	sessionRepo.EXPECT().UpdateAccountSession(gomock.Any()).DoAndReturn(func(session businesslogic.AccountSession) error {
+		sessions[session.TokenID] = &session
+		return nil
+	}).Times(updated)
+}
+
+func bearerRequest(token string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1.0/account/profile", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestLocalAuthenticationStrategy_Login(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := local.PBKDF2PasswordHasher{Iterations: 1000}
+	security := businesslogic.NewAccountSecurityService(accountRepo, securityRepo,
+		mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl), sessionRepo, hasher,
+		mock_businesslogic.NewMockIMailer(mockCtrl))
+	strategy := local.NewLocalAuthenticationStrategy(accountRepo, sessionRepo, security, testSigner)
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	strategy.Now = func() time.Time { return now }
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "athlete@example.com"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(2)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "nobody@example.com"}).Return([]businesslogic.Account{}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{UUID: "uid-7"}).Return([]businesslogic.Account{newAthleteAccount()}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return(newAthleteSecurity(hasher), nil).Times(2)
+	storeSessions(sessionRepo, 1, 1, 0)
+
+	securityRepo.EXPECT().AddFailedLogin(7).Return(1, nil)
+	_, err := strategy.Login("athlete@example.com", "wrong")
+	assert.Equal(t, businesslogic.InvalidCredentialError, err)
+	_, err = strategy.Login("nobody@example.com", "secret")
+	assert.Equal(t, businesslogic.InvalidCredentialError, err, "unknown emails should not be told apart from wrong passwords")
+
+	pair, err := strategy.Login("athlete@example.com", "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(local.DefaultAccessTokenValidity), pair.ExpiresAt)
+
+	account, err := strategy.GetCurrentUser(bearerRequest(pair.AccessToken))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, account.ID)
+
+	_, err = strategy.GetCurrentUser(bearerRequest(pair.RefreshToken))
+	assert.NotNil(t, err, "refresh tokens should not authenticate requests")
+
+	now = now.Add(2 * time.Hour)
+	_, err = strategy.GetCurrentUser(bearerRequest(pair.AccessToken))
+	assert.NotNil(t, err, "expired access tokens should be rejected")
+}
+
+func TestLocalAuthenticationStrategy_Refresh(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := local.PBKDF2PasswordHasher{Iterations: 1000}
+	security := businesslogic.NewAccountSecurityService(accountRepo, securityRepo,
+		mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl), sessionRepo, hasher,
+		mock_businesslogic.NewMockIMailer(mockCtrl))
+	strategy := local.NewLocalAuthenticationStrategy(accountRepo, sessionRepo, security, testSigner)
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	strategy.Now = func() time.Time { return now }
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "athlete@example.com"}).Return([]businesslogic.Account{newAthleteAccount()}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{UUID: "uid-7"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(3)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return(newAthleteSecurity(hasher), nil)
+	storeSessions(sessionRepo, 2, 3, 1)
+
+	pair, _ := strategy.Login("athlete@example.com", "secret")
+	now = now.Add(2 * time.Hour)
+	refreshed, err := strategy.Refresh(pair.RefreshToken)
+	assert.Nil(t, err)
+	_, err = strategy.GetCurrentUser(bearerRequest(refreshed.AccessToken))
+	assert.Nil(t, err)
+
+	_, err = strategy.Refresh(pair.RefreshToken)
+	assert.Equal(t, local.InvalidTokenError, err, "refresh tokens should only be used once")
+	_, err = strategy.Refresh(refreshed.AccessToken)
+	assert.Equal(t, local.InvalidTokenError, err, "access tokens should not be exchanged for new tokens")
+
+	now = now.Add(local.DefaultRefreshTokenValidity)
+	_, err = strategy.Refresh(refreshed.RefreshToken)
+	assert.Equal(t, local.InvalidTokenError, err, "sessions should expire")
+}
+
+func TestLocalAuthenticationStrategy_Logout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := local.PBKDF2PasswordHasher{Iterations: 1000}
+	security := businesslogic.NewAccountSecurityService(accountRepo, securityRepo,
+		mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl), sessionRepo, hasher,
+		mock_businesslogic.NewMockIMailer(mockCtrl))
+	strategy := local.NewLocalAuthenticationStrategy(accountRepo, sessionRepo, security, testSigner)
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	strategy.Now = func() time.Time { return now }
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "athlete@example.com"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(2)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{UUID: "uid-7"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(4)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return(newAthleteSecurity(hasher), nil).Times(2)
+	storeSessions(sessionRepo, 2, 4, 1)
+
+	first, _ := strategy.Login("athlete@example.com", "secret")
+	second, _ := strategy.Login("athlete@example.com", "secret")
+	assert.Nil(t, strategy.Logout(bearerRequest(first.AccessToken)))
+
+	_, err := strategy.GetCurrentUser(bearerRequest(first.AccessToken))
+	assert.Equal(t, local.InvalidTokenError, err, "access tokens should stop working after logout")
+	_, err = strategy.Refresh(first.RefreshToken)
+	assert.Equal(t, local.InvalidTokenError, err, "refresh tokens should stop working after logout")
+	_, err = strategy.GetCurrentUser(bearerRequest(second.AccessToken))
+	assert.Nil(t, err, "other sessions should not be logged out")
+}
+
+func TestLocalAuthenticationStrategy_ChangePassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := local.PBKDF2PasswordHasher{Iterations: 1000}
+	security := businesslogic.NewAccountSecurityService(accountRepo, securityRepo,
+		mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl), sessionRepo, hasher,
+		mock_businesslogic.NewMockIMailer(mockCtrl))
+	strategy := local.NewLocalAuthenticationStrategy(accountRepo, sessionRepo, security, testSigner)
+	// sessions are ended by the password service at the current time
+	strategy.Now = time.Now
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "athlete@example.com"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(2)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{UUID: "uid-7"}).Return([]businesslogic.Account{newAthleteAccount()}, nil).Times(3)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return(newAthleteSecurity(hasher), nil).Times(4)
+	securityRepo.EXPECT().UpdateAccountSecurity(gomock.Any()).Return(nil)
+	storeSessions(sessionRepo, 2, 4, 1)
+
+	first, _ := strategy.Login("athlete@example.com", "secret")
+	second, _ := strategy.Login("athlete@example.com", "secret")
+	assert.Nil(t, strategy.ChangePassword(bearerRequest(first.AccessToken), "secret", "Quickstep-1924"))
+
+	_, err := strategy.GetCurrentUser(bearerRequest(first.AccessToken))
+	assert.Nil(t, err, "the session that changes the password should stay signed in")
+	_, err = strategy.GetCurrentUser(bearerRequest(second.AccessToken))
+	assert.Equal(t, local.InvalidTokenError, err, "other sessions should be ended when the password is changed")
+}
@@ -0,0 +1,73 @@
+package local
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	pbkdf2Prefix = "pbkdf2-sha256"
+
+	// DefaultPBKDF2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+	DefaultPBKDF2Iterations = 600000
+	pbkdf2SaltLength        = 16
+	pbkdf2KeyLength         = 32
+)
+
+// PBKDF2PasswordHasher implements businesslogic.IPasswordHasher with PBKDF2-HMAC-SHA256. Hashes are stored as
+// "pbkdf2-sha256$<iterations>$<salt>$<key>", so that passwords hashed with fewer iterations can still be verified after
+// Iterations is raised.
+type PBKDF2PasswordHasher struct {
+	Iterations int
+}
+
+func NewPBKDF2PasswordHasher() PBKDF2PasswordHasher {
+	return PBKDF2PasswordHasher{Iterations: DefaultPBKDF2Iterations}
+}
+
+// HashPassword derives a key from the password with a random salt
+func (hasher PBKDF2PasswordHasher) HashPassword(password string) (string, error) {
+	salt := make([]byte, pbkdf2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, hasher.Iterations, pbkdf2KeyLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v$%d$%v$%v", pbkdf2Prefix, hasher.Iterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword derives a key from the password with the salt and the iterations of the hash, and compares it with
+// the key of the hash in constant time
+func (hasher PBKDF2PasswordHasher) VerifyPassword(password, hash string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != pbkdf2Prefix {
+		return false, errors.New("unsupported password hash")
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false, errors.New("invalid iterations in password hash")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, errors.New("invalid salt in password hash")
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, errors.New("invalid key in password hash")
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
@@ -0,0 +1,33 @@
+package local_test
+
+import (
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2PasswordHasher(t *testing.T) {
+	hasher := local.PBKDF2PasswordHasher{Iterations: 1000}
+	hash, err := hasher.HashPassword("correct horse battery staple")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "pbkdf2-sha256$1000$"))
+	assert.NotContains(t, hash, "correct horse")
+
+	again, _ := hasher.HashPassword("correct horse battery staple")
+	assert.NotEqual(t, hash, again, "each hash should have its own salt")
+
+	valid, err := hasher.VerifyPassword("correct horse battery staple", hash)
+	assert.Nil(t, err)
+	assert.True(t, valid)
+	valid, err = hasher.VerifyPassword("Correct horse battery staple", hash)
+	assert.Nil(t, err)
+	assert.False(t, valid)
+
+	stronger := local.PBKDF2PasswordHasher{Iterations: 2000}
+	valid, _ = stronger.VerifyPassword("correct horse battery staple", hash)
+	assert.True(t, valid, "passwords should be verified with the iterations of their hash")
+
+	_, err = hasher.VerifyPassword("correct horse battery staple", "$2a$10$abcdefghijklmnopqrstuv")
+	assert.NotNil(t, err, "unknown hashes should not be accepted")
+}
@@ -0,0 +1,181 @@
+package local
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Uses of tokens. Access tokens authenticate requests, and refresh tokens can only be exchanged for new tokens.
+const (
+	TokenUseAccess  = "access"
+	TokenUseRefresh = "refresh"
+)
+
+// Issuer is the "iss" claim of tokens issued by DAS
+const Issuer = "das"
+
+// Claims are the claims of the JSON Web Tokens issued by DAS. Subject is the UID of the account, and SessionID is the
+// TokenID of the AccountSession that the token belongs to.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	SessionID string `json:"sid"`
+	TokenID   string `json:"jti"`
+	Use       string `json:"use"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// ISigner specifies the functions that a JSON Web Signature algorithm should implement
+type ISigner interface {
+	Algorithm() string
+	Sign(content []byte) ([]byte, error)
+	Verify(content, signature []byte) error
+}
+
+// HMACSigner signs tokens with HS256
+type HMACSigner struct {
+	key []byte
+}
+
+func NewHMACSigner(key []byte) HMACSigner {
+	return HMACSigner{key: key}
+}
+
+func (signer HMACSigner) Algorithm() string {
+	return "HS256"
+}
+
+func (signer HMACSigner) Sign(content []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, signer.key)
+	mac.Write(content)
+	return mac.Sum(nil), nil
+}
+
+func (signer HMACSigner) Verify(content, signature []byte) error {
+	expected, _ := signer.Sign(content)
+	if !hmac.Equal(expected, signature) {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+// RSASigner signs tokens with RS256. Tokens can be verified by other services with the public key only.
+type RSASigner struct {
+	key *rsa.PrivateKey
+}
+
+func NewRSASigner(key *rsa.PrivateKey) RSASigner {
+	return RSASigner{key: key}
+}
+
+// ParseRSAPrivateKey parses a PEM encoded RSA private key in either PKCS #1 or PKCS #8 form
+func ParseRSAPrivateKey(encoded string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("RSA private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+func (signer RSASigner) Algorithm() string {
+	return "RS256"
+}
+
+func (signer RSASigner) Sign(content []byte) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	return rsa.SignPKCS1v15(nil, signer.key, crypto.SHA256, digest[:])
+}
+
+func (signer RSASigner) Verify(content, signature []byte) error {
+	digest := sha256.Sum256(content)
+	if err := rsa.VerifyPKCS1v15(&signer.key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// EncodeToken signs the claims as a compact JSON Web Token
+func EncodeToken(signer ISigner, claims Claims) (string, error) {
+	header, err := json.Marshal(tokenHeader{Algorithm: signer.Algorithm(), Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := signer.Sign([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DecodeToken verifies the signature and the expiry of the token and returns its claims. Tokens that are signed with
+// any algorithm other than the one of the signer are rejected.
+func DecodeToken(signer ISigner, token string, now time.Time) (Claims, error) {
+	claims := Claims{}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("malformed token")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return claims, errors.New("malformed token header")
+	}
+	header := tokenHeader{}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return claims, errors.New("malformed token header")
+	}
+	if header.Algorithm != signer.Algorithm() {
+		return claims, errors.New("unexpected token algorithm")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, errors.New("malformed token signature")
+	}
+	if err := signer.Verify([]byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return claims, err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, errors.New("malformed token payload")
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, errors.New("malformed token payload")
+	}
+	if claims.Issuer != Issuer {
+		return claims, errors.New("unexpected token issuer")
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, errors.New("token is expired")
+	}
+	return claims, nil
+}
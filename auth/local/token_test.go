@@ -0,0 +1,76 @@
+package local_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testClaims = local.Claims{
+	Issuer:    local.Issuer,
+	Subject:   "uid-1",
+	SessionID: "session-1",
+	TokenID:   "token-1",
+	Use:       local.TokenUseAccess,
+	IssuedAt:  time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC).Unix(),
+	ExpiresAt: time.Date(2018, 11, 1, 13, 0, 0, 0, time.UTC).Unix(),
+}
+
+var testNow = time.Date(2018, 11, 1, 12, 30, 0, 0, time.UTC)
+
+func TestEncodeToken_HS256(t *testing.T) {
+	signer := local.NewHMACSigner([]byte("0123456789abcdef0123456789abcdef"))
+	token, err := local.EncodeToken(signer, testClaims)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(strings.Split(token, ".")))
+
+	claims, err := local.DecodeToken(signer, token, testNow)
+	assert.Nil(t, err)
+	assert.Equal(t, testClaims, claims)
+
+	_, err = local.DecodeToken(signer, token, testNow.Add(time.Hour))
+	assert.NotNil(t, err, "expired tokens should be rejected")
+
+	other := local.NewHMACSigner([]byte("fedcba9876543210fedcba9876543210"))
+	_, err = local.DecodeToken(other, token, testNow)
+	assert.NotNil(t, err, "tokens signed with other keys should be rejected")
+
+	parts := strings.Split(token, ".")
+	forged := testClaims
+	forged.Subject = "uid-2"
+	forgedToken, _ := local.EncodeToken(other, forged)
+	_, err = local.DecodeToken(signer, strings.Split(forgedToken, ".")[0]+"."+strings.Split(forgedToken, ".")[1]+"."+parts[2], testNow)
+	assert.NotNil(t, err, "tampered claims should be rejected")
+}
+
+func TestEncodeToken_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if !assert.Nil(t, err) {
+		return
+	}
+	encoded := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	parsed, err := local.ParseRSAPrivateKey(encoded)
+	assert.Nil(t, err)
+	signer := local.NewRSASigner(parsed)
+
+	token, err := local.EncodeToken(signer, testClaims)
+	assert.Nil(t, err)
+	claims, err := local.DecodeToken(signer, token, testNow)
+	assert.Nil(t, err)
+	assert.Equal(t, testClaims, claims)
+
+	// a token signed with HS256 and the public key must not pass as RS256
+	public := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+	confused, _ := local.EncodeToken(local.NewHMACSigner(public), testClaims)
+	_, err = local.DecodeToken(signer, confused, testNow)
+	assert.NotNil(t, err, "tokens signed with other algorithms should be rejected")
+
+	_, err = local.ParseRSAPrivateKey("not a key")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeyCacheDuration is how long keys are cached when the issuer does not say how long they can be cached
+	DefaultKeyCacheDuration = time.Hour
+	// minKeyRefreshInterval limits how often keys are fetched when a token is signed by an unknown key, so that tokens
+	// with made up key IDs cannot flood the issuer
+	minKeyRefreshInterval = time.Minute
+	// clockSkew is the leeway given to the clocks of DAS and the issuer
+	clockSkew = time.Minute
+)
+
+var maxAgePattern = regexp.MustCompile(`max-age=(\d+)`)
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// keySet caches the signing keys of the issuer. Keys are fetched again when the cache expires, or when a token is
+// signed with a key that is not in the cache because the issuer has rotated its keys. It is safe for concurrent use.
+type keySet struct {
+	issuer    string
+	client    *http.Client
+	lock      sync.Mutex
+	jwksURI   string
+	keys      map[string]*rsa.PublicKey
+	expires   time.Time
+	lastFetch time.Time
+}
+
+func newKeySet(issuer string, client *http.Client) *keySet {
+	return &keySet{issuer: issuer, client: client}
+}
+
+// key returns the public key of the key ID
+func (set *keySet) key(keyID string, now time.Time) (*rsa.PublicKey, error) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	if set.keys == nil || now.After(set.expires) {
+		if err := set.fetch(now); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := set.keys[keyID]; ok {
+		return key, nil
+	}
+	if now.Sub(set.lastFetch) >= minKeyRefreshInterval {
+		if err := set.fetch(now); err != nil {
+			return nil, err
+		}
+		if key, ok := set.keys[keyID]; ok {
+			return key, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("unknown signing key %v", keyID))
+}
+
+func (set *keySet) fetch(now time.Time) error {
+	set.lastFetch = now
+	if set.jwksURI == "" {
+		document := discoveryDocument{}
+		if _, err := set.getJSON(strings.TrimSuffix(set.issuer, "/")+"/.well-known/openid-configuration", &document); err != nil {
+			return err
+		}
+		if document.Issuer != set.issuer {
+			return errors.New(fmt.Sprintf("issuer %v does not match the configured issuer %v", document.Issuer, set.issuer))
+		}
+		if document.JWKSURI == "" {
+			return errors.New("issuer does not publish its keys")
+		}
+		set.jwksURI = document.JWKSURI
+	}
+
+	jwks := jsonWebKeySet{}
+	header, err := set.getJSON(set.jwksURI, &jwks)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, each := range jwks.Keys {
+		if each.KeyType != "RSA" || (each.Use != "" && each.Use != "sig") {
+			continue
+		}
+		key, err := each.rsaPublicKey()
+		if err != nil {
+			log.Printf("[warning] skipping key %v of issuer %v: %v", each.KeyID, set.issuer, err)
+			continue
+		}
+		keys[each.KeyID] = key
+	}
+	set.keys = keys
+	set.expires = set.lastFetch.Add(cacheDuration(header))
+	return nil
+}
+
+func (set *keySet) getJSON(url string, v interface{}) (http.Header, error) {
+	response, err := set.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("%v responded with %v", url, response.Status))
+	}
+	return response.Header, json.NewDecoder(response.Body).Decode(v)
+}
+
+// cacheDuration returns the max-age of the response, or DefaultKeyCacheDuration if it does not have one
+func cacheDuration(header http.Header) time.Duration {
+	if match := maxAgePattern.FindStringSubmatch(header.Get("Cache-Control")); match != nil {
+		if seconds, err := strconv.Atoi(match[1]); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return DefaultKeyCacheDuration
+}
+
+func (key jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(key.Modulus)
+	if err != nil {
+		return nil, errors.New("invalid modulus")
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(key.Exponent)
+	if err != nil || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("invalid exponent")
+	}
+	e := 0
+	for _, b := range exponent {
+		e = e<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: e}, nil
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// verifyRS256 checks the RS256 signature of the token with the key of the issuer and returns the payload
+func (set *keySet) verifyRS256(token string, now time.Time) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("malformed token header")
+	}
+	header := tokenHeader{}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errors.New("malformed token header")
+	}
+	if header.Algorithm != "RS256" {
+		return nil, errors.New(fmt.Sprintf("unsupported token algorithm %v", header.Algorithm))
+	}
+	key, err := set.key(header.KeyID, now)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("invalid token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token payload")
+	}
+	return payload, nil
+}
@@ -0,0 +1,203 @@
+// Package oidc implements IAuthenticationStrategy with any OpenID Connect provider, such as the single sign-on of a
+// university. ID tokens that the provider issues to the client of DAS are verified with the keys that the provider
+// publishes, so that DAS never sees the passwords of users.
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of an ID token that DAS uses
+type Claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	NotBefore     int64    `json:"nbf"`
+	Email         string   `json:"email"`
+	EmailVerified *bool    `json:"email_verified"`
+	Name          string   `json:"name"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+	PhoneNumber   string   `json:"phone_number"`
+}
+
+// audience is the "aud" claim, which is either a string or an array of strings
+type audience []string
+
+func (aud *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*aud = multiple
+	return nil
+}
+
+func (aud audience) contains(clientID string) bool {
+	for _, each := range aud {
+		if each == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// OIDCAuthenticationStrategy implements IAuthenticationStrategy with the ID tokens of an OpenID Connect provider. The
+// provider is discovered from the issuer when the first token is verified, so that DAS can start while the provider
+// is unavailable.
+type OIDCAuthenticationStrategy struct {
+	accountRepository businesslogic.IAccountRepository
+	issuer            string
+	clientID          string
+	keys              *keySet
+	Now               func() time.Time
+}
+
+// NewOIDCAuthenticationStrategy creates a strategy that accepts ID tokens that the issuer has issued to the client
+func NewOIDCAuthenticationStrategy(issuer, clientID string, accountRepo businesslogic.IAccountRepository) OIDCAuthenticationStrategy {
+	return NewOIDCAuthenticationStrategyWithClient(issuer, clientID, accountRepo, &http.Client{Timeout: 10 * time.Second})
+}
+
+// NewOIDCAuthenticationStrategyWithClient creates a strategy that fetches the keys of the issuer with the HTTP client
+func NewOIDCAuthenticationStrategyWithClient(issuer, clientID string, accountRepo businesslogic.IAccountRepository, client *http.Client) OIDCAuthenticationStrategy {
+	return OIDCAuthenticationStrategy{
+		accountRepository: accountRepo,
+		issuer:            issuer,
+		clientID:          clientID,
+		keys:              newKeySet(issuer, client),
+		Now:               time.Now,
+	}
+}
+
+// VerifyIDToken checks the signature, the issuer, the audience, and the lifetime of the ID token, and returns its
+// claims
+func (strategy OIDCAuthenticationStrategy) VerifyIDToken(token string) (Claims, error) {
+	claims := Claims{}
+	now := strategy.Now()
+	payload, err := strategy.keys.verifyRS256(token, now)
+	if err != nil {
+		return claims, err
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, errors.New("malformed token payload")
+	}
+	if claims.Issuer != strategy.issuer {
+		return claims, errors.New("unexpected token issuer")
+	}
+	if !claims.Audience.contains(strategy.clientID) {
+		return claims, errors.New("token is not issued to DAS")
+	}
+	if now.Add(-clockSkew).Unix() >= claims.ExpiresAt {
+		return claims, errors.New("token is expired")
+	}
+	if claims.NotBefore > 0 && now.Add(clockSkew).Unix() < claims.NotBefore {
+		return claims, errors.New("token is not valid yet")
+	}
+	if claims.Subject == "" {
+		return claims, errors.New("token does not have a subject")
+	}
+	return claims, nil
+}
+
+// convertClaimsToDasUser returns the DAS account of the email of the claims. If the user does not have an account
+// yet, an account that is not saved is made from the claims, so that the user can register. An existing account is
+// only returned to the subject that it is linked to, so that other users of the provider cannot sign in to it with the
+// same email.
+func (strategy OIDCAuthenticationStrategy) convertClaimsToDasUser(claims Claims) (businesslogic.Account, error) {
+	searchAccounts, searchErr := strategy.accountRepository.SearchAccount(businesslogic.SearchAccountCriteria{
+		Email: claims.Email,
+	})
+	if searchErr != nil {
+		return businesslogic.Account{}, searchErr
+	}
+	if len(searchAccounts) != 1 || searchAccounts[0].Email != claims.Email {
+		firstName, lastName := claims.GivenName, claims.FamilyName
+		if firstName == "" || lastName == "" {
+			if names := strings.Fields(claims.Name); len(names) > 1 {
+				firstName = names[0]
+				lastName = names[len(names)-1]
+			} else {
+				firstName = "Unknown"
+				lastName = "Unknown"
+			}
+		}
+		return businesslogic.Account{
+			AccountStatusID: businesslogic.AccountStatusActivated,
+			UserGenderID:    businesslogic.GENDER_UNKNOWN,
+			FirstName:       firstName,
+			LastName:        lastName,
+			Email:           claims.Email,
+			Phone:           claims.PhoneNumber,
+			UID:             claims.Subject,
+			DateTimeCreated: time.Unix(claims.IssuedAt, 0),
+		}, nil
+	}
+	if searchAccounts[0].UID != claims.Subject {
+		log.Printf("[oidc-auth] account %v of email %v is not linked to subject %v", searchAccounts[0].ID, claims.Email, claims.Subject)
+		return businesslogic.Account{}, errors.New(fmt.Sprintf("account of email %v is linked to another identity", claims.Email))
+	}
+	return searchAccounts[0], nil
+}
+
+// GetCurrentUser verifies the bearer ID token of the request and returns the account of the user. Users whose email
+// is not verified by the provider, including tokens without the email_verified claim, are rejected, because accounts
+// are matched by email.
+func (strategy OIDCAuthenticationStrategy) GetCurrentUser(r *http.Request) (businesslogic.Account, error) {
+	authHeader := r.Header.Get("Authorization")
+	if len(authHeader) < 1 {
+		log.Printf("request %v misses authorization header", r.URL)
+		return businesslogic.Account{}, errors.New("empty authentication token")
+	}
+	bearerToken := strings.Split(authHeader, " ")
+	if len(bearerToken) != 2 {
+		log.Printf("request %v has invalid authorization header: %v", r.URL, authHeader)
+		return businesslogic.Account{}, errors.New("invalid authentication token")
+	}
+
+	claims, err := strategy.VerifyIDToken(bearerToken[1])
+	if err != nil {
+		log.Printf("[oidc-auth] error verifying token: %v", err)
+		return businesslogic.Account{}, err
+	}
+	if claims.Email == "" {
+		return businesslogic.Account{}, errors.New("token does not have an email")
+	}
+	if claims.EmailVerified == nil || !*claims.EmailVerified {
+		return businesslogic.Account{}, errors.New(fmt.Sprintf("email %v is not verified", claims.Email))
+	}
+	user, err := strategy.convertClaimsToDasUser(claims)
+	if err != nil {
+		return businesslogic.Account{}, err
+	}
+	switch user.AccountStatusID {
+	case businesslogic.AccountStatusSuspended, businesslogic.AccountStatusLocked:
+		return businesslogic.Account{}, errors.New("account cannot sign in")
+	}
+	return user, nil
+}
+
+// CreateUser creates the account in DAS. The account is expected to come from GetCurrentUser, so the UID is the
+// subject of the provider.
+func (strategy OIDCAuthenticationStrategy) CreateUser(account *businesslogic.Account) error {
+	if account.UID == "" {
+		return errors.New("account does not have a UID from the identity provider")
+	}
+	if account.ID > 0 {
+		return errors.New("account already exists in DAS")
+	}
+	return strategy.accountRepository.CreateAccount(account)
+}
@@ -0,0 +1,156 @@
+package oidc_test
+
+import (
+	"github.com/DancesportSoftware/das/auth/oidc"
+	"github.com/DancesportSoftware/das/auth/oidc/oidctest"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const clientID = "das"
+
+func idTokenClaims(issuer *oidctest.Issuer, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":            issuer.URL(),
+		"sub":            "248289761001",
+		"aud":            clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"email":          "jsmith@osu.edu",
+		"email_verified": true,
+		"given_name":     "John",
+		"family_name":    "Smith",
+	}
+}
+
+func bearerRequest(token string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1.0/account/profile", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestOIDCAuthenticationStrategy_VerifyIDToken(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	strategy := oidc.NewOIDCAuthenticationStrategy(issuer.URL(), clientID, nil)
+	now := time.Now()
+
+	claims, err := strategy.VerifyIDToken(issuer.Sign(idTokenClaims(issuer, now)))
+	assert.Nil(t, err)
+	assert.Equal(t, "248289761001", claims.Subject)
+	assert.Equal(t, "jsmith@osu.edu", claims.Email)
+
+	multiple := idTokenClaims(issuer, now)
+	multiple["aud"] = []string{"another-client", clientID}
+	_, err = strategy.VerifyIDToken(issuer.Sign(multiple))
+	assert.Nil(t, err, "tokens with multiple audiences should be accepted")
+
+	otherClient := idTokenClaims(issuer, now)
+	otherClient["aud"] = "another-client"
+	_, err = strategy.VerifyIDToken(issuer.Sign(otherClient))
+	assert.NotNil(t, err, "tokens issued to other clients should be rejected")
+
+	otherIssuer := idTokenClaims(issuer, now)
+	otherIssuer["iss"] = "https://accounts.example.com"
+	_, err = strategy.VerifyIDToken(issuer.Sign(otherIssuer))
+	assert.NotNil(t, err, "tokens of other issuers should be rejected")
+
+	expired := idTokenClaims(issuer, now.Add(-2*time.Hour))
+	_, err = strategy.VerifyIDToken(issuer.Sign(expired))
+	assert.NotNil(t, err, "expired tokens should be rejected")
+
+	token := issuer.Sign(idTokenClaims(issuer, now))
+	parts := strings.Split(token, ".")
+	forged := strings.Split(issuer.Sign(map[string]interface{}{"iss": issuer.URL(), "sub": "1", "aud": clientID, "exp": now.Add(time.Hour).Unix()}), ".")
+	_, err = strategy.VerifyIDToken(parts[0] + "." + forged[1] + "." + parts[2])
+	assert.NotNil(t, err, "tampered tokens should be rejected")
+	_, err = strategy.VerifyIDToken("eyJhbGciOiJub25lIn0." + parts[1] + ".")
+	assert.NotNil(t, err, "unsigned tokens should be rejected")
+}
+
+func TestOIDCAuthenticationStrategy_KeyRotation(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	issuer.MaxAge = 600
+	strategy := oidc.NewOIDCAuthenticationStrategy(issuer.URL(), clientID, nil)
+	now := time.Now()
+	strategy.Now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err := strategy.VerifyIDToken(issuer.Sign(idTokenClaims(issuer, now)))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1, issuer.KeyRequests(), "keys should be cached")
+
+	old := "key-1"
+	now = now.Add(2 * time.Minute)
+	issuer.RotateKey()
+	_, err := strategy.VerifyIDToken(issuer.Sign(idTokenClaims(issuer, now)))
+	assert.Nil(t, err, "tokens signed with a new key should be accepted")
+	assert.Equal(t, 2, issuer.KeyRequests(), "keys should be fetched when a token is signed with an unknown key")
+
+	issuer.RemoveKey(old)
+	issuer.RotateKey()
+	_, err = strategy.VerifyIDToken(issuer.Sign(idTokenClaims(issuer, now)))
+	assert.NotNil(t, err, "keys should not be fetched again right away")
+	assert.Equal(t, 2, issuer.KeyRequests())
+
+	now = now.Add(11 * time.Minute)
+	_, err = strategy.VerifyIDToken(issuer.Sign(idTokenClaims(issuer, now)))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, issuer.KeyRequests(), "keys should be fetched again when the cache expires")
+}
+
+func TestOIDCAuthenticationStrategy_GetCurrentUser(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	strategy := oidc.NewOIDCAuthenticationStrategy(issuer.URL(), clientID, accountRepo)
+	now := time.Now()
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@osu.edu"}).Return([]businesslogic.Account{}, nil)
+	user, err := strategy.GetCurrentUser(bearerRequest(issuer.Sign(idTokenClaims(issuer, now))))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, user.ID, "users without accounts should be able to register")
+	assert.Equal(t, "248289761001", user.UID)
+	assert.Equal(t, "John", user.FirstName)
+	assert.Equal(t, "Smith", user.LastName)
+
+	accountRepo.EXPECT().CreateAccount(&user).Return(nil)
+	assert.Nil(t, strategy.CreateUser(&user))
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@osu.edu"}).Return([]businesslogic.Account{
+		{ID: 7, UID: "248289761001", Email: "jsmith@osu.edu", AccountStatusID: businesslogic.AccountStatusActivated},
+	}, nil)
+	user, err = strategy.GetCurrentUser(bearerRequest(issuer.Sign(idTokenClaims(issuer, now))))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, user.ID)
+
+	unverified := idTokenClaims(issuer, now)
+	unverified["email_verified"] = false
+	_, err = strategy.GetCurrentUser(bearerRequest(issuer.Sign(unverified)))
+	assert.NotNil(t, err, "users should not sign in with unverified emails")
+
+	delete(unverified, "email_verified")
+	_, err = strategy.GetCurrentUser(bearerRequest(issuer.Sign(unverified)))
+	assert.NotNil(t, err, "users should not sign in if the provider does not verify their emails")
+
+	other := idTokenClaims(issuer, now)
+	other["sub"] = "990011223344"
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@osu.edu"}).Return([]businesslogic.Account{
+		{ID: 7, UID: "248289761001", Email: "jsmith@osu.edu", AccountStatusID: businesslogic.AccountStatusActivated},
+	}, nil)
+	_, err = strategy.GetCurrentUser(bearerRequest(issuer.Sign(other)))
+	assert.NotNil(t, err, "users should not sign in to accounts that are linked to other subjects with the same email")
+
+	_, err = strategy.GetCurrentUser(bearerRequest("not-a-token"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,127 @@
+// Package oidctest provides a stub OpenID Connect issuer for tests. The issuer publishes its discovery document and
+// its keys over HTTP, and signs ID tokens with whatever claims a test needs.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Issuer is a stub issuer that serves on a local address. It is safe for concurrent use.
+type Issuer struct {
+	Server *httptest.Server
+	// MaxAge is sent as the max-age of the key set if it is positive
+	MaxAge int
+
+	lock         sync.Mutex
+	keys         map[string]*rsa.PrivateKey
+	currentKeyID string
+	keySequence  int
+	keyRequests  int
+}
+
+// NewIssuer starts an issuer with a signing key. Close must be called when the test finishes.
+func NewIssuer() *Issuer {
+	issuer := &Issuer{keys: make(map[string]*rsa.PrivateKey)}
+	issuer.RotateKey()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discoveryHandler)
+	mux.HandleFunc("/keys", issuer.keysHandler)
+	issuer.Server = httptest.NewServer(mux)
+	return issuer
+}
+
+// URL is the issuer identifier, which is also the "iss" claim of its tokens
+func (issuer *Issuer) URL() string {
+	return issuer.Server.URL
+}
+
+func (issuer *Issuer) Close() {
+	issuer.Server.Close()
+}
+
+// RotateKey adds a new signing key, which signs tokens from now on. Earlier keys are still published until RemoveKey
+// is called, just like real issuers do during rotation.
+func (issuer *Issuer) RotateKey() string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	issuer.lock.Lock()
+	defer issuer.lock.Unlock()
+	issuer.keySequence++
+	issuer.currentKeyID = fmt.Sprintf("key-%d", issuer.keySequence)
+	issuer.keys[issuer.currentKeyID] = key
+	return issuer.currentKeyID
+}
+
+// RemoveKey stops publishing the key
+func (issuer *Issuer) RemoveKey(keyID string) {
+	issuer.lock.Lock()
+	defer issuer.lock.Unlock()
+	delete(issuer.keys, keyID)
+}
+
+// KeyRequests returns how many times the key set has been requested
+func (issuer *Issuer) KeyRequests() int {
+	issuer.lock.Lock()
+	defer issuer.lock.Unlock()
+	return issuer.keyRequests
+}
+
+// Sign signs the claims with the current key as an RS256 ID token
+func (issuer *Issuer) Sign(claims map[string]interface{}) string {
+	issuer.lock.Lock()
+	keyID := issuer.currentKeyID
+	key := issuer.keys[keyID]
+	issuer.lock.Unlock()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	payload, _ := json.Marshal(claims)
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(content))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (issuer *Issuer) discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":   issuer.URL(),
+		"jwks_uri": issuer.URL() + "/keys",
+	})
+}
+
+func (issuer *Issuer) keysHandler(w http.ResponseWriter, r *http.Request) {
+	issuer.lock.Lock()
+	defer issuer.lock.Unlock()
+	issuer.keyRequests++
+	keys := make([]map[string]string, 0)
+	for keyID, key := range issuer.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if issuer.MaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", issuer.MaxAge))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+}
@@ -0,0 +1,579 @@
+package businesslogic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// InvalidCredentialError is returned when the email or the password does not match any account. It does not tell
+// which one is wrong, so that it cannot be used to find out who has an account.
+var InvalidCredentialError = errors.New("invalid email or password")
+
+// AccountLockedError is returned when an account is locked after too many failed logins
+var AccountLockedError = errors.New("account is locked after too many failed logins, check your email to unlock it")
+
+// InvalidSecurityTokenError is returned when a password reset or unlock token is unknown, used, or expired
+var InvalidSecurityTokenError = errors.New("the link is invalid or has expired")
+
+const (
+	// DefaultMaxFailedLogins is the number of failed logins in a row after which an account is locked
+	DefaultMaxFailedLogins = 5
+	// PasswordResetTokenValidity is how long a password reset link can be used
+	PasswordResetTokenValidity = time.Hour
+	// UnlockTokenValidity is how long an unlock link can be used
+	UnlockTokenValidity = 24 * time.Hour
+)
+
+// Purposes of AccountSecurityToken
+const (
+	SecurityTokenPurposePasswordReset = "password-reset"
+	SecurityTokenPurposeUnlock        = "unlock"
+)
+
+// AccountSecurity contains the credential of an account that signs in with DAS rather than an external identity
+// provider. PasswordHash is produced by an IPasswordHasher and never contains the password.
+type AccountSecurity struct {
+	ID                      int
+	AccountID               int
+	PasswordHash            string
+	DateTimePasswordChanged time.Time
+	FailedLoginAttempts     int
+	CreateUserID            int
+	DateTimeCreated         time.Time
+	UpdateUserID            int
+	DateTimeUpdated         time.Time
+}
+
+// SearchAccountSecurityCriteria specifies the parameters that can be used to search AccountSecurity
+type SearchAccountSecurityCriteria struct {
+	AccountID int
+}
+
+// IAccountSecurityRepository specifies the functions that an AccountSecurity Repository should implement.
+// AddFailedLogin counts a failed login of the account in a single statement, so that concurrent failed logins are all
+// counted, and returns the failed logins in a row after it.
+type IAccountSecurityRepository interface {
+	CreateAccountSecurity(security *AccountSecurity) error
+	SearchAccountSecurity(criteria SearchAccountSecurityCriteria) ([]AccountSecurity, error)
+	UpdateAccountSecurity(security AccountSecurity) error
+	AddFailedLogin(accountID int) (int, error)
+}
+
+// AccountSession is a session of an account that signs in with DAS. Tokens of the session carry its TokenID, and
+// stop working when the session expires or is revoked.
+type AccountSession struct {
+	ID              int
+	AccountID       int
+	TokenID         string
+	DateTimeExpires time.Time
+	DateTimeRevoked *time.Time
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// IsActive checks if the session has neither expired nor been revoked at the time
+func (session AccountSession) IsActive(at time.Time) bool {
+	return session.DateTimeRevoked == nil && at.Before(session.DateTimeExpires)
+}
+
+// SearchAccountSessionCriteria specifies the parameters that can be used to search AccountSession
+type SearchAccountSessionCriteria struct {
+	AccountID int
+	TokenID   string
+}
+
+// IAccountSessionRepository specifies the functions that an AccountSession Repository should implement
+type IAccountSessionRepository interface {
+	CreateAccountSession(session *AccountSession) error
+	SearchAccountSession(criteria SearchAccountSessionCriteria) ([]AccountSession, error)
+	UpdateAccountSession(session AccountSession) error
+}
+
+// AccountSecurityToken is a single-use token that is sent to the email of an account to reset its password or to
+// unlock it. Only the hash of the token is stored, so that the tokens cannot be read from the database.
+type AccountSecurityToken struct {
+	ID              int
+	AccountID       int
+	Purpose         string
+	TokenHash       string
+	DateTimeExpires time.Time
+	DateTimeUsed    *time.Time
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// IsUsable checks if the token has neither expired nor been used at the time
+func (token AccountSecurityToken) IsUsable(at time.Time) bool {
+	return token.DateTimeUsed == nil && at.Before(token.DateTimeExpires)
+}
+
+// SearchAccountSecurityTokenCriteria specifies the parameters that can be used to search AccountSecurityToken
+type SearchAccountSecurityTokenCriteria struct {
+	AccountID int
+	Purpose   string
+	TokenHash string
+}
+
+// IAccountSecurityTokenRepository specifies the functions that an AccountSecurityToken Repository should implement.
+// UseAccountSecurityToken marks the token as used only if it has not been used, and returns InvalidSecurityTokenError
+// otherwise, so that a token cannot be used by two requests at the same time.
+type IAccountSecurityTokenRepository interface {
+	CreateAccountSecurityToken(token *AccountSecurityToken) error
+	SearchAccountSecurityToken(criteria SearchAccountSecurityTokenCriteria) ([]AccountSecurityToken, error)
+	UseAccountSecurityToken(token AccountSecurityToken) error
+}
+
+// IPasswordHasher specifies the functions that a key derivation function for passwords should implement. The hash
+// must contain everything that is needed to verify the password, including the salt and the parameters.
+type IPasswordHasher interface {
+	HashPassword(password string) (string, error)
+	VerifyPassword(password, hash string) (bool, error)
+}
+
+// PasswordPolicy specifies the requirements of passwords. Character classes are lower case letters, upper case
+// letters, digits, and everything else.
+type PasswordPolicy struct {
+	MinLength           int
+	MaxLength           int
+	MinCharacterClasses int
+}
+
+// DefaultPasswordPolicy requires passwords of at least 10 characters from at least 3 character classes
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:           10,
+	MaxLength:           128,
+	MinCharacterClasses: 3,
+}
+
+// Check returns an error that describes the first requirement that the password does not meet. Passwords must not
+// contain the name or the email of the account either.
+func (policy PasswordPolicy) Check(password string, account Account) error {
+	length := len([]rune(password))
+	if length < policy.MinLength {
+		return errors.New(fmt.Sprintf("password must have at least %v characters", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		return errors.New(fmt.Sprintf("password must have at most %v characters", policy.MaxLength))
+	}
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, has := range []bool{lower, upper, digit, other} {
+		if has {
+			classes++
+		}
+	}
+	if classes < policy.MinCharacterClasses {
+		return errors.New(fmt.Sprintf("password must contain at least %v of: lower case letters, upper case letters, digits, and symbols", policy.MinCharacterClasses))
+	}
+	lowered := strings.ToLower(password)
+	for _, each := range []string{account.FirstName, account.LastName, strings.Split(account.Email, "@")[0]} {
+		if len(each) >= 3 && strings.Contains(lowered, strings.ToLower(each)) {
+			return errors.New("password must not contain your name or email")
+		}
+	}
+	return nil
+}
+
+// AccountSecurityService manages the passwords of accounts that sign in with DAS. Accounts are locked after
+// MaxFailedLogins failed logins in a row, and can be unlocked with the link that is sent to their email or by
+// resetting the password.
+type AccountSecurityService struct {
+	accountRepo     IAccountRepository
+	securityRepo    IAccountSecurityRepository
+	tokenRepo       IAccountSecurityTokenRepository
+	sessionRepo     IAccountSessionRepository
+	hasher          IPasswordHasher
+	mailer          IMailer
+	Policy          PasswordPolicy
+	MaxFailedLogins int
+	// WebAppURL is the address of the web application that links in emails point to
+	WebAppURL string
+	// ForgetAccount, if set, is called when the sessions of an account are revoked or the account is locked, so
+	// that accounts that are cached for their tokens are authenticated again
+	ForgetAccount func(accountID int)
+}
+
+func NewAccountSecurityService(accountRepo IAccountRepository,
+	securityRepo IAccountSecurityRepository,
+	tokenRepo IAccountSecurityTokenRepository,
+	sessionRepo IAccountSessionRepository,
+	hasher IPasswordHasher,
+	mailer IMailer) AccountSecurityService {
+	return AccountSecurityService{
+		accountRepo:     accountRepo,
+		securityRepo:    securityRepo,
+		tokenRepo:       tokenRepo,
+		sessionRepo:     sessionRepo,
+		hasher:          hasher,
+		mailer:          mailer,
+		Policy:          DefaultPasswordPolicy,
+		MaxFailedLogins: DefaultMaxFailedLogins,
+	}
+}
+
+// SetPassword replaces the password of the account, or creates the credential of the account if it does not have one.
+// The password must meet the policy of the service.
+func (service AccountSecurityService) SetPassword(account Account, password string, updatedBy int) error {
+	if err := service.Policy.Check(password, account); err != nil {
+		return err
+	}
+	hash, err := service.hasher.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	results, err := service.securityRepo.SearchAccountSecurity(SearchAccountSecurityCriteria{AccountID: account.ID})
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		security := results[0]
+		security.PasswordHash = hash
+		security.DateTimePasswordChanged = time.Now()
+		security.FailedLoginAttempts = 0
+		security.UpdateUserID = updatedBy
+		security.DateTimeUpdated = time.Now()
+		return service.securityRepo.UpdateAccountSecurity(security)
+	}
+	security := AccountSecurity{
+		AccountID:               account.ID,
+		PasswordHash:            hash,
+		DateTimePasswordChanged: time.Now(),
+		CreateUserID:            updatedBy,
+		DateTimeCreated:         time.Now(),
+		UpdateUserID:            updatedBy,
+		DateTimeUpdated:         time.Now(),
+	}
+	return service.securityRepo.CreateAccountSecurity(&security)
+}
+
+// Authenticate returns the account of the email if the password is correct. Suspended and locked accounts cannot sign
+// in, and the account is locked when its failed logins in a row reach MaxFailedLogins.
+func (service AccountSecurityService) Authenticate(email, password string) (Account, error) {
+	account, security, err := service.findCredential(email)
+	if err != nil {
+		return Account{}, err
+	}
+	if security.ID == 0 {
+		// hash the password anyway, so that unknown emails take as long as wrong passwords
+		service.hasher.HashPassword(password)
+		return Account{}, InvalidCredentialError
+	}
+	switch account.AccountStatusID {
+	case AccountStatusSuspended:
+		return Account{}, errors.New("account is suspended")
+	case AccountStatusLocked:
+		return Account{}, AccountLockedError
+	}
+
+	valid, err := service.hasher.VerifyPassword(password, security.PasswordHash)
+	if err != nil {
+		return Account{}, err
+	}
+	if !valid {
+		return Account{}, service.recordFailedLogin(account)
+	}
+	if security.FailedLoginAttempts > 0 {
+		security.FailedLoginAttempts = 0
+		security.DateTimeUpdated = time.Now()
+		if err := service.securityRepo.UpdateAccountSecurity(security); err != nil {
+			return Account{}, err
+		}
+	}
+	return account, nil
+}
+
+// recordFailedLogin counts the failed login. When the failed logins reach the limit, the account is locked and the
+// unlock link is sent to its email. The limit is checked against the count that the repository returns, so that
+// failed logins at the same time cannot get past it.
+func (service AccountSecurityService) recordFailedLogin(account Account) error {
+	failedLogins, err := service.securityRepo.AddFailedLogin(account.ID)
+	if err != nil {
+		return err
+	}
+	if failedLogins < service.MaxFailedLogins {
+		return InvalidCredentialError
+	}
+
+	account.AccountStatusID = AccountStatusLocked
+	if err := service.accountRepo.UpdateAccount(account); err != nil {
+		return err
+	}
+	service.forgetAccount(account)
+	log.Printf("[warning] account %v is locked after %v failed logins", account.ID, failedLogins)
+	token, err := service.issueToken(account, SecurityTokenPurposeUnlock, UnlockTokenValidity)
+	if err != nil {
+		return err
+	}
+	if err := service.mailer.SendMail(MailMessage{
+		To:      account.Email,
+		Subject: "Your DAS account is locked",
+		Body: fmt.Sprintf("Hi %v,\r\n\r\nYour account is locked after %v failed logins. If it was you, open the link below "+
+			"within %v hours to unlock your account, or reset your password.\r\n\r\n%v\r\n",
+			account.FirstName, failedLogins, UnlockTokenValidity.Hours(), service.link("/account/unlock", token)),
+	}); err != nil {
+		log.Printf("[error] sending unlock link to account %v: %v", account.ID, err)
+	}
+	return AccountLockedError
+}
+
+// ChangePassword replaces the password of the current user after checking the current password, and ends all other
+// sessions of the current user. The session with currentSessionID stays signed in.
+func (service AccountSecurityService) ChangePassword(currentUser Account, currentSessionID, currentPassword, newPassword string) error {
+	results, err := service.securityRepo.SearchAccountSecurity(SearchAccountSecurityCriteria{AccountID: currentUser.ID})
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return errors.New("account does not have a password")
+	}
+	valid, err := service.hasher.VerifyPassword(currentPassword, results[0].PasswordHash)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("current password is incorrect")
+	}
+	if currentPassword == newPassword {
+		return errors.New("new password must be different from the current password")
+	}
+	if err := service.SetPassword(currentUser, newPassword, currentUser.ID); err != nil {
+		return err
+	}
+	return service.revokeSessions(currentUser, currentSessionID)
+}
+
+// RequestPasswordReset sends a password reset link to the email if it belongs to an account with a password. The
+// result is the same for any email, so that it cannot be used to find out who has an account.
+func (service AccountSecurityService) RequestPasswordReset(email string) error {
+	account, security, err := service.findCredential(email)
+	if err != nil {
+		return err
+	}
+	if security.ID == 0 || account.AccountStatusID == AccountStatusSuspended {
+		log.Printf("[info] password reset is requested for an email without a password")
+		return nil
+	}
+	token, err := service.issueToken(account, SecurityTokenPurposePasswordReset, PasswordResetTokenValidity)
+	if err != nil {
+		return err
+	}
+	return service.mailer.SendMail(MailMessage{
+		To:      account.Email,
+		Subject: "Reset your DAS password",
+		Body: fmt.Sprintf("Hi %v,\r\n\r\nOpen the link below within %v minutes to reset your password. The link can only "+
+			"be used once. If you did not ask to reset your password, you can ignore this email.\r\n\r\n%v\r\n",
+			account.FirstName, PasswordResetTokenValidity.Minutes(), service.link("/account/password/reset", token)),
+	})
+}
+
+// ResetPassword replaces the password of the account of the reset token. Resetting the password also unlocks the
+// account and ends all of its sessions. The token is used before the password is replaced, so that the token cannot
+// reset the password twice.
+func (service AccountSecurityService) ResetPassword(token, newPassword string) error {
+	securityToken, account, err := service.findToken(token, SecurityTokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	if err := service.Policy.Check(newPassword, account); err != nil {
+		return err
+	}
+	if err := service.useToken(securityToken); err != nil {
+		return err
+	}
+	if err := service.SetPassword(account, newPassword, account.ID); err != nil {
+		return err
+	}
+	if err := service.activate(account); err != nil {
+		return err
+	}
+	return service.revokeSessions(account, "")
+}
+
+// UnlockAccount unlocks the account of the unlock token
+func (service AccountSecurityService) UnlockAccount(token string) error {
+	securityToken, account, err := service.findToken(token, SecurityTokenPurposeUnlock)
+	if err != nil {
+		return err
+	}
+	if err := service.useToken(securityToken); err != nil {
+		return err
+	}
+	results, err := service.securityRepo.SearchAccountSecurity(SearchAccountSecurityCriteria{AccountID: account.ID})
+	if err != nil {
+		return err
+	}
+	for _, each := range results {
+		each.FailedLoginAttempts = 0
+		each.UpdateUserID = account.ID
+		each.DateTimeUpdated = time.Now()
+		if err := service.securityRepo.UpdateAccountSecurity(each); err != nil {
+			return err
+		}
+	}
+	return service.activate(account)
+}
+
+// findCredential returns the account of the email and its credential. The credential is empty if the email does not
+// belong to an account with a password.
+func (service AccountSecurityService) findCredential(email string) (Account, AccountSecurity, error) {
+	accounts, err := service.accountRepo.SearchAccount(SearchAccountCriteria{Email: email})
+	if err != nil {
+		return Account{}, AccountSecurity{}, err
+	}
+	if len(accounts) != 1 {
+		return Account{}, AccountSecurity{}, nil
+	}
+	results, err := service.securityRepo.SearchAccountSecurity(SearchAccountSecurityCriteria{AccountID: accounts[0].ID})
+	if err != nil {
+		return Account{}, AccountSecurity{}, err
+	}
+	if len(results) != 1 {
+		return accounts[0], AccountSecurity{}, nil
+	}
+	return accounts[0], results[0], nil
+}
+
+// issueToken creates a token for the account and returns it. Earlier tokens of the same purpose stop working, so
+// that only the link of the latest email can be used.
+func (service AccountSecurityService) issueToken(account Account, purpose string, validity time.Duration) (string, error) {
+	issued, err := service.tokenRepo.SearchAccountSecurityToken(SearchAccountSecurityTokenCriteria{
+		AccountID: account.ID,
+		Purpose:   purpose,
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, each := range issued {
+		if each.IsUsable(time.Now()) {
+			if err := service.useToken(each); err != nil && err != InvalidSecurityTokenError {
+				return "", err
+			}
+		}
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+	securityToken := AccountSecurityToken{
+		AccountID:       account.ID,
+		Purpose:         purpose,
+		TokenHash:       hashSecurityToken(token),
+		DateTimeExpires: time.Now().Add(validity),
+		CreateUserID:    account.ID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    account.ID,
+		DateTimeUpdated: time.Now(),
+	}
+	if err := service.tokenRepo.CreateAccountSecurityToken(&securityToken); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findToken returns the usable token of the purpose and its account
+func (service AccountSecurityService) findToken(token, purpose string) (AccountSecurityToken, Account, error) {
+	if token == "" {
+		return AccountSecurityToken{}, Account{}, InvalidSecurityTokenError
+	}
+	results, err := service.tokenRepo.SearchAccountSecurityToken(SearchAccountSecurityTokenCriteria{
+		Purpose:   purpose,
+		TokenHash: hashSecurityToken(token),
+	})
+	if err != nil {
+		return AccountSecurityToken{}, Account{}, err
+	}
+	if len(results) != 1 || !results[0].IsUsable(time.Now()) {
+		return AccountSecurityToken{}, Account{}, InvalidSecurityTokenError
+	}
+	accounts, err := service.accountRepo.SearchAccount(SearchAccountCriteria{ID: results[0].AccountID})
+	if err != nil {
+		return AccountSecurityToken{}, Account{}, err
+	}
+	if len(accounts) != 1 {
+		return AccountSecurityToken{}, Account{}, InvalidSecurityTokenError
+	}
+	return results[0], accounts[0], nil
+}
+
+// useToken marks the token as used. It returns InvalidSecurityTokenError if the token has been used by another request.
+func (service AccountSecurityService) useToken(token AccountSecurityToken) error {
+	now := time.Now()
+	token.DateTimeUsed = &now
+	token.DateTimeUpdated = now
+	return service.tokenRepo.UseAccountSecurityToken(token)
+}
+
+// activate unlocks the account if it is locked
+func (service AccountSecurityService) activate(account Account) error {
+	if account.AccountStatusID != AccountStatusLocked {
+		return nil
+	}
+	account.AccountStatusID = AccountStatusActivated
+	return service.accountRepo.UpdateAccount(account)
+}
+
+// revokeSessions ends the active sessions of the account, except the session with keepSessionID
+func (service AccountSecurityService) revokeSessions(account Account, keepSessionID string) error {
+	sessions, err := service.sessionRepo.SearchAccountSession(SearchAccountSessionCriteria{AccountID: account.ID})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, each := range sessions {
+		if !each.IsActive(now) || (keepSessionID != "" && each.TokenID == keepSessionID) {
+			continue
+		}
+		each.DateTimeRevoked = &now
+		each.UpdateUserID = account.ID
+		each.DateTimeUpdated = now
+		if err := service.sessionRepo.UpdateAccountSession(each); err != nil {
+			return err
+		}
+	}
+	service.forgetAccount(account)
+	return nil
+}
+
+func (service AccountSecurityService) forgetAccount(account Account) {
+	if service.ForgetAccount != nil {
+		service.ForgetAccount(account.ID)
+	}
+}
+
+func (service AccountSecurityService) link(path, token string) string {
+	if service.WebAppURL == "" {
+		return fmt.Sprintf("Token: %v", token)
+	}
+	return fmt.Sprintf("%v%v?token=%v", strings.TrimSuffix(service.WebAppURL, "/"), path, token)
+}
+
+func hashSecurityToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
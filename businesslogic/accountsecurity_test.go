@@ -0,0 +1,255 @@
+package businesslogic_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	account := businesslogic.Account{FirstName: "John", LastName: "Smith", Email: "jsmith@example.com"}
+	policy := businesslogic.DefaultPasswordPolicy
+	assert.NotNil(t, policy.Check("Ab1!", account), "short passwords should be rejected")
+	assert.NotNil(t, policy.Check("abcdefghijkl", account), "passwords of a single character class should be rejected")
+	assert.NotNil(t, policy.Check("Smith-2018-ballroom", account), "passwords should not contain names")
+	assert.NotNil(t, policy.Check("JSmith@2018!", account), "passwords should not contain emails")
+	assert.Nil(t, policy.Check("Quickstep-1924", account))
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAccountSecurityService_Authenticate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	tokenRepo := mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl)
+	hasher := mock_businesslogic.NewMockIPasswordHasher(mockCtrl)
+	mailer := mock_businesslogic.NewMockIMailer(mockCtrl)
+	service := businesslogic.NewAccountSecurityService(accountRepo, securityRepo, tokenRepo,
+		mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl), hasher, mailer)
+	service.MaxFailedLogins = 3
+	forgotten := make([]int, 0)
+	service.ForgetAccount = func(accountID int) { forgotten = append(forgotten, accountID) }
+
+	account := businesslogic.Account{ID: 7, FirstName: "John", Email: "jsmith@example.com", AccountStatusID: businesslogic.AccountStatusActivated}
+	security := businesslogic.AccountSecurity{ID: 1, AccountID: 7, PasswordHash: "hash:Quickstep-1924"}
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@example.com"}).Return([]businesslogic.Account{account}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	hasher.EXPECT().VerifyPassword("wrong", "hash:Quickstep-1924").Return(false, nil)
+	securityRepo.EXPECT().AddFailedLogin(7).Return(1, nil)
+	_, err := service.Authenticate("jsmith@example.com", "wrong")
+	assert.Equal(t, businesslogic.InvalidCredentialError, err)
+
+	security.FailedLoginAttempts = 1
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@example.com"}).Return([]businesslogic.Account{account}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	hasher.EXPECT().VerifyPassword("Quickstep-1924", "hash:Quickstep-1924").Return(true, nil)
+	securityRepo.EXPECT().UpdateAccountSecurity(gomock.Any()).Do(func(security businesslogic.AccountSecurity) {
+		assert.Equal(t, 0, security.FailedLoginAttempts, "successful logins should reset failed logins")
+	}).Return(nil)
+	authenticated, err := service.Authenticate("jsmith@example.com", "Quickstep-1924")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, authenticated.ID)
+
+	// other failed logins are counted at the same time, so the count that is read is behind the count that is returned
+	security.FailedLoginAttempts = 0
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@example.com"}).Return([]businesslogic.Account{account}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	hasher.EXPECT().VerifyPassword("wrong", "hash:Quickstep-1924").Return(false, nil)
+	securityRepo.EXPECT().AddFailedLogin(7).Return(3, nil)
+	accountRepo.EXPECT().UpdateAccount(gomock.Any()).Do(func(account businesslogic.Account) {
+		assert.Equal(t, businesslogic.AccountStatusLocked, account.AccountStatusID)
+	}).Return(nil)
+	tokenRepo.EXPECT().SearchAccountSecurityToken(businesslogic.SearchAccountSecurityTokenCriteria{AccountID: 7, Purpose: businesslogic.SecurityTokenPurposeUnlock}).Return([]businesslogic.AccountSecurityToken{}, nil)
+	tokenRepo.EXPECT().CreateAccountSecurityToken(gomock.Any()).Return(nil)
+	mailer.EXPECT().SendMail(gomock.Any()).Do(func(message businesslogic.MailMessage) {
+		assert.Equal(t, "jsmith@example.com", message.To, "the unlock link should be sent when the account is locked")
+	}).Return(nil)
+	assert.Empty(t, forgotten)
+	_, err = service.Authenticate("jsmith@example.com", "wrong")
+	assert.Equal(t, businesslogic.AccountLockedError, err)
+	assert.Equal(t, []int{7}, forgotten, "cached tokens of locked accounts should be forgotten")
+
+	account.AccountStatusID = businesslogic.AccountStatusLocked
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@example.com"}).Return([]businesslogic.Account{account}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	_, err = service.Authenticate("jsmith@example.com", "Quickstep-1924")
+	assert.Equal(t, businesslogic.AccountLockedError, err, "locked accounts should not sign in with the correct password")
+}
+
+func TestAccountSecurityService_UnlockAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	tokenRepo := mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl)
+	service := businesslogic.NewAccountSecurityService(accountRepo, securityRepo, tokenRepo, mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl),
+		mock_businesslogic.NewMockIPasswordHasher(mockCtrl), mock_businesslogic.NewMockIMailer(mockCtrl))
+
+	token := businesslogic.AccountSecurityToken{ID: 5, AccountID: 7, Purpose: businesslogic.SecurityTokenPurposeUnlock, TokenHash: hashToken("unlock"), DateTimeExpires: time.Now().Add(time.Hour)}
+	criteria := businesslogic.SearchAccountSecurityTokenCriteria{Purpose: businesslogic.SecurityTokenPurposeUnlock, TokenHash: hashToken("unlock")}
+	locked := businesslogic.Account{ID: 7, AccountStatusID: businesslogic.AccountStatusLocked}
+
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 7}).Return([]businesslogic.Account{locked}, nil)
+	tokenRepo.EXPECT().UseAccountSecurityToken(gomock.Any()).Do(func(used businesslogic.AccountSecurityToken) {
+		assert.Equal(t, 5, used.ID)
+		assert.NotNil(t, used.DateTimeUsed)
+	}).Return(nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{{ID: 1, AccountID: 7, FailedLoginAttempts: 3}}, nil)
+	securityRepo.EXPECT().UpdateAccountSecurity(gomock.Any()).Do(func(security businesslogic.AccountSecurity) {
+		assert.Equal(t, 0, security.FailedLoginAttempts)
+	}).Return(nil)
+	accountRepo.EXPECT().UpdateAccount(gomock.Any()).Do(func(account businesslogic.Account) {
+		assert.Equal(t, businesslogic.AccountStatusActivated, account.AccountStatusID)
+	}).Return(nil)
+	assert.Nil(t, service.UnlockAccount("unlock"))
+
+	// another request uses the link at the same time
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 7}).Return([]businesslogic.Account{locked}, nil)
+	tokenRepo.EXPECT().UseAccountSecurityToken(gomock.Any()).Return(businesslogic.InvalidSecurityTokenError)
+	assert.Equal(t, businesslogic.InvalidSecurityTokenError, service.UnlockAccount("unlock"), "unlock links should only be used once")
+}
+
+func TestAccountSecurityService_ChangePassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := mock_businesslogic.NewMockIPasswordHasher(mockCtrl)
+	service := businesslogic.NewAccountSecurityService(mock_businesslogic.NewMockIAccountRepository(mockCtrl), securityRepo,
+		mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl), sessionRepo, hasher, mock_businesslogic.NewMockIMailer(mockCtrl))
+	forgotten := make([]int, 0)
+	service.ForgetAccount = func(accountID int) { forgotten = append(forgotten, accountID) }
+
+	account := businesslogic.Account{ID: 7, FirstName: "John", LastName: "Smith", Email: "jsmith@example.com"}
+	security := businesslogic.AccountSecurity{ID: 1, AccountID: 7, PasswordHash: "hash:Quickstep-1924"}
+
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	hasher.EXPECT().VerifyPassword("wrong", "hash:Quickstep-1924").Return(false, nil)
+	assert.NotNil(t, service.ChangePassword(account, "current", "wrong", "Foxtrot-1914!"), "current password should be checked")
+
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil)
+	hasher.EXPECT().VerifyPassword("Quickstep-1924", "hash:Quickstep-1924").Return(true, nil)
+	assert.NotNil(t, service.ChangePassword(account, "current", "Quickstep-1924", "foxtrot"), "new password should meet the policy")
+
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{security}, nil).Times(2)
+	hasher.EXPECT().VerifyPassword("Quickstep-1924", "hash:Quickstep-1924").Return(true, nil)
+	hasher.EXPECT().HashPassword("Foxtrot-1914!").Return("hash:Foxtrot-1914!", nil)
+	securityRepo.EXPECT().UpdateAccountSecurity(gomock.Any()).Do(func(security businesslogic.AccountSecurity) {
+		assert.Equal(t, "hash:Foxtrot-1914!", security.PasswordHash)
+	}).Return(nil)
+	sessionRepo.EXPECT().SearchAccountSession(businesslogic.SearchAccountSessionCriteria{AccountID: 7}).Return([]businesslogic.AccountSession{
+		{ID: 1, AccountID: 7, TokenID: "current", DateTimeExpires: time.Now().Add(time.Hour)},
+		{ID: 2, AccountID: 7, TokenID: "other", DateTimeExpires: time.Now().Add(time.Hour)},
+		{ID: 3, AccountID: 7, TokenID: "expired", DateTimeExpires: time.Now().Add(-time.Hour)},
+	}, nil)
+	sessionRepo.EXPECT().UpdateAccountSession(gomock.Any()).Do(func(session businesslogic.AccountSession) {
+		assert.Equal(t, 2, session.ID, "only other active sessions should be ended")
+		assert.NotNil(t, session.DateTimeRevoked)
+	}).Return(nil)
+	assert.Empty(t, forgotten)
+	assert.Nil(t, service.ChangePassword(account, "current", "Quickstep-1924", "Foxtrot-1914!"))
+	assert.Equal(t, []int{7}, forgotten, "cached tokens of the ended sessions should be forgotten")
+}
+
+func TestAccountSecurityService_RequestPasswordReset(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	tokenRepo := mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl)
+	mailer := mock_businesslogic.NewMockIMailer(mockCtrl)
+	service := businesslogic.NewAccountSecurityService(accountRepo, securityRepo, tokenRepo, mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl),
+		mock_businesslogic.NewMockIPasswordHasher(mockCtrl), mailer)
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "nobody@example.com"}).Return([]businesslogic.Account{}, nil)
+	assert.Nil(t, service.RequestPasswordReset("nobody@example.com"), "unknown emails should not be sent anything")
+
+	earlier := businesslogic.AccountSecurityToken{ID: 4, AccountID: 7, Purpose: businesslogic.SecurityTokenPurposePasswordReset, DateTimeExpires: time.Now().Add(time.Hour)}
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{Email: "jsmith@example.com"}).Return([]businesslogic.Account{{ID: 7, Email: "jsmith@example.com"}}, nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{{ID: 1, AccountID: 7}}, nil)
+	tokenRepo.EXPECT().SearchAccountSecurityToken(businesslogic.SearchAccountSecurityTokenCriteria{AccountID: 7, Purpose: businesslogic.SecurityTokenPurposePasswordReset}).Return([]businesslogic.AccountSecurityToken{earlier}, nil)
+	tokenRepo.EXPECT().UseAccountSecurityToken(gomock.Any()).Do(func(token businesslogic.AccountSecurityToken) {
+		assert.Equal(t, 4, token.ID, "earlier links should stop working")
+	}).Return(nil)
+	var created businesslogic.AccountSecurityToken
+	tokenRepo.EXPECT().CreateAccountSecurityToken(gomock.Any()).Do(func(token *businesslogic.AccountSecurityToken) {
+		created = *token
+	}).Return(nil)
+	var body string
+	mailer.EXPECT().SendMail(gomock.Any()).Do(func(message businesslogic.MailMessage) {
+		body = message.Body
+	}).Return(nil)
+	assert.Nil(t, service.RequestPasswordReset("jsmith@example.com"))
+
+	token := strings.TrimSpace(body[strings.Index(body, "Token: ")+len("Token: "):])
+	assert.NotEqual(t, token, created.TokenHash, "tokens should not be stored")
+	assert.Equal(t, hashToken(token), created.TokenHash)
+}
+
+func TestAccountSecurityService_ResetPassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	securityRepo := mock_businesslogic.NewMockIAccountSecurityRepository(mockCtrl)
+	tokenRepo := mock_businesslogic.NewMockIAccountSecurityTokenRepository(mockCtrl)
+	sessionRepo := mock_businesslogic.NewMockIAccountSessionRepository(mockCtrl)
+	hasher := mock_businesslogic.NewMockIPasswordHasher(mockCtrl)
+	service := businesslogic.NewAccountSecurityService(accountRepo, securityRepo, tokenRepo, sessionRepo, hasher, mock_businesslogic.NewMockIMailer(mockCtrl))
+
+	token := businesslogic.AccountSecurityToken{ID: 5, AccountID: 7, Purpose: businesslogic.SecurityTokenPurposePasswordReset, TokenHash: hashToken("reset"), DateTimeExpires: time.Now().Add(time.Hour)}
+	criteria := businesslogic.SearchAccountSecurityTokenCriteria{Purpose: businesslogic.SecurityTokenPurposePasswordReset, TokenHash: hashToken("reset")}
+	locked := businesslogic.Account{ID: 7, FirstName: "John", Email: "jsmith@example.com", AccountStatusID: businesslogic.AccountStatusLocked}
+
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 7}).Return([]businesslogic.Account{locked}, nil)
+	assert.NotNil(t, service.ResetPassword("reset", "foxtrot"), "weak passwords should not use up the link")
+
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 7}).Return([]businesslogic.Account{locked}, nil)
+	tokenRepo.EXPECT().UseAccountSecurityToken(gomock.Any()).Return(nil)
+	hasher.EXPECT().HashPassword("Foxtrot-1914!").Return("hash:Foxtrot-1914!", nil)
+	securityRepo.EXPECT().SearchAccountSecurity(businesslogic.SearchAccountSecurityCriteria{AccountID: 7}).Return([]businesslogic.AccountSecurity{{ID: 1, AccountID: 7, FailedLoginAttempts: 5}}, nil)
+	securityRepo.EXPECT().UpdateAccountSecurity(gomock.Any()).Do(func(security businesslogic.AccountSecurity) {
+		assert.Equal(t, "hash:Foxtrot-1914!", security.PasswordHash)
+		assert.Equal(t, 0, security.FailedLoginAttempts)
+	}).Return(nil)
+	accountRepo.EXPECT().UpdateAccount(gomock.Any()).Do(func(account businesslogic.Account) {
+		assert.Equal(t, businesslogic.AccountStatusActivated, account.AccountStatusID, "resetting the password should unlock the account")
+	}).Return(nil)
+	sessionRepo.EXPECT().SearchAccountSession(businesslogic.SearchAccountSessionCriteria{AccountID: 7}).Return([]businesslogic.AccountSession{
+		{ID: 1, AccountID: 7, TokenID: "active", DateTimeExpires: time.Now().Add(time.Hour)},
+	}, nil)
+	sessionRepo.EXPECT().UpdateAccountSession(gomock.Any()).Do(func(session businesslogic.AccountSession) {
+		assert.NotNil(t, session.DateTimeRevoked, "resetting the password should end active sessions")
+	}).Return(nil)
+	assert.Nil(t, service.ResetPassword("reset", "Foxtrot-1914!"))
+
+	// another request uses the link at the same time, so the password is not replaced again
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 7}).Return([]businesslogic.Account{locked}, nil)
+	tokenRepo.EXPECT().UseAccountSecurityToken(gomock.Any()).Return(businesslogic.InvalidSecurityTokenError)
+	assert.Equal(t, businesslogic.InvalidSecurityTokenError, service.ResetPassword("reset", "Waltz-1910!"), "reset links should only be used once")
+
+	token.DateTimeExpires = time.Now().Add(-time.Minute)
+	tokenRepo.EXPECT().SearchAccountSecurityToken(criteria).Return([]businesslogic.AccountSecurityToken{token}, nil)
+	assert.Equal(t, businesslogic.InvalidSecurityTokenError, service.ResetPassword("reset", "Waltz-1910!"), "reset links should expire")
+}
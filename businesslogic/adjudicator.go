@@ -0,0 +1,183 @@
+package businesslogic
+
+import (
+	"errors"
+)
+
+// NotAssignedAdjudicatorError is returned when the adjudicator does not adjudicate the round
+var NotAssignedAdjudicatorError = errors.New("adjudicator is not assigned to this round")
+
+// MarksAlreadySubmittedError is returned when the adjudicator submits marks of a dance more than once. Submitted marks
+// can only be corrected by the scrutineer.
+var MarksAlreadySubmittedError = errors.New("marks of this dance are already submitted")
+
+// AdjudicatorHeatList is the information that an adjudicator needs to mark a round: the dances of the round, the
+// couples who dance in the round, and the dances that the adjudicator has submitted marks for.
+type AdjudicatorHeatList struct {
+	Round                   Round
+	Scoresheet              Scoresheet
+	AdjudicatorRoundEntryID int
+	Dances                  []EventDance
+	Couples                 []PartnershipRoundEntry
+	SubmittedDances         []int // IDs of EventDance
+}
+
+// AdjudicatorService provides the functions that adjudicators use to mark rounds electronically. Only adjudicators who
+// are active officials of the competition and are assigned to the round can mark the round.
+type AdjudicatorService struct {
+	eventRepo            IEventRepository
+	roundRepo            IRoundRepository
+	officialRepo         ICompetitionOfficialRepository
+	eventDanceRepo       IEventDanceRepository
+	adjudicatorEntryRepo IAdjudicatorRoundEntryRepository
+	partnershipEntryRepo IPartnershipRoundEntryRepository
+	scoresheetRepo       IScoresheetRepository
+	placementRepo        IPlacementRepository
+	marker               scoresheetMarker
+}
+
+func NewAdjudicatorService(
+	eventRepo IEventRepository,
+	roundRepo IRoundRepository,
+	officialRepo ICompetitionOfficialRepository,
+	eventDanceRepo IEventDanceRepository,
+	adjudicatorEntryRepo IAdjudicatorRoundEntryRepository,
+	partnershipEntryRepo IPartnershipRoundEntryRepository,
+	scoresheetRepo IScoresheetRepository,
+	placementRepo IPlacementRepository) AdjudicatorService {
+	return AdjudicatorService{
+		eventRepo:            eventRepo,
+		roundRepo:            roundRepo,
+		officialRepo:         officialRepo,
+		eventDanceRepo:       eventDanceRepo,
+		adjudicatorEntryRepo: adjudicatorEntryRepo,
+		partnershipEntryRepo: partnershipEntryRepo,
+		scoresheetRepo:       scoresheetRepo,
+		placementRepo:        placementRepo,
+		marker: scoresheetMarker{
+			eventDanceRepo:       eventDanceRepo,
+			adjudicatorEntryRepo: adjudicatorEntryRepo,
+			partnershipEntryRepo: partnershipEntryRepo,
+			placementRepo:        placementRepo,
+			validateRecall:       true,
+		},
+	}
+}
+
+// authorize returns the round if the current user is an active adjudicator of the competition and is assigned to the
+// round
+func (service AdjudicatorService) authorize(currentUser Account, entry AdjudicatorRoundEntry) (Round, error) {
+	if entry.AdjudicatorEntryID != currentUser.ID {
+		return Round{}, NotAssignedAdjudicatorError
+	}
+	round, competitionID, err := getRoundCompetition(entry.RoundEntry.RoundID, service.roundRepo, service.eventRepo)
+	if err != nil {
+		return round, err
+	}
+	_, err = GetActiveCompetitionOfficial(competitionID, currentUser, AccountTypeAdjudicator, service.officialRepo)
+	return round, err
+}
+
+// GetCurrentHeatLists returns the heat lists of all the rounds that the current user is assigned to and are open for
+// marking
+func (service AdjudicatorService) GetCurrentHeatLists(currentUser Account) ([]AdjudicatorHeatList, error) {
+	heatLists := make([]AdjudicatorHeatList, 0)
+	entries, err := service.adjudicatorEntryRepo.SearchAdjudicatorRoundEntry(SearchAdjudicatorRoundEntryCriteria{AdjudicatorID: currentUser.ID})
+	if err != nil {
+		return heatLists, err
+	}
+	for _, entry := range entries {
+		scoresheets, err := service.scoresheetRepo.SearchScoresheet(SearchScoresheetCriteria{RoundID: entry.RoundEntry.RoundID})
+		if err != nil {
+			return heatLists, err
+		}
+		if len(scoresheets) != 1 || !scoresheets[0].IsOpen() {
+			continue
+		}
+		round, err := service.authorize(currentUser, entry)
+		if err == NotActiveCompetitionOfficialError {
+			continue
+		}
+		if err != nil {
+			return heatLists, err
+		}
+		heatList, err := service.getHeatList(round, scoresheets[0], entry)
+		if err != nil {
+			return heatLists, err
+		}
+		heatLists = append(heatLists, heatList)
+	}
+	return heatLists, nil
+}
+
+func (service AdjudicatorService) getHeatList(round Round, scoresheet Scoresheet, entry AdjudicatorRoundEntry) (AdjudicatorHeatList, error) {
+	heatList := AdjudicatorHeatList{
+		Round:                   round,
+		Scoresheet:              scoresheet,
+		AdjudicatorRoundEntryID: entry.ID,
+		SubmittedDances:         make([]int, 0),
+	}
+	var err error
+	if heatList.Dances, err = service.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{EventID: round.EventID}); err != nil {
+		return heatList, err
+	}
+	if heatList.Couples, err = service.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: round.ID}); err != nil {
+		return heatList, err
+	}
+	placements, err := service.placementRepo.SearchPlacement(SearchPlacementCriteria{
+		RoundID:                 round.ID,
+		AdjudicatorRoundEntryID: entry.ID,
+	})
+	if err != nil {
+		return heatList, err
+	}
+	submitted := make(map[int]bool)
+	for _, each := range placements {
+		if !submitted[each.EventDanceID] {
+			submitted[each.EventDanceID] = true
+			heatList.SubmittedDances = append(heatList.SubmittedDances, each.EventDanceID)
+		}
+	}
+	return heatList, nil
+}
+
+// SubmitMarks validates and saves the marks of the current user in a dance of an open round. Each dance can only be
+// submitted once, and the adjudicator must recall the requested number of couples in a preliminary round.
+func (service AdjudicatorService) SubmitMarks(currentUser Account, submission JudgeMarksSubmission) error {
+	entries, err := service.adjudicatorEntryRepo.SearchAdjudicatorRoundEntry(SearchAdjudicatorRoundEntryCriteria{
+		RoundID:       submission.RoundID,
+		AdjudicatorID: currentUser.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(entries) != 1 {
+		return NotAssignedAdjudicatorError
+	}
+	submission.AdjudicatorRoundEntryID = entries[0].ID
+
+	round, err := service.authorize(currentUser, entries[0])
+	if err != nil {
+		return err
+	}
+	scoresheet, err := getScoresheet(round.ID, service.scoresheetRepo)
+	if err != nil {
+		return err
+	}
+	if !scoresheet.IsOpen() {
+		return ScoresheetLockedError
+	}
+
+	existing, err := service.placementRepo.SearchPlacement(SearchPlacementCriteria{
+		RoundID:                 round.ID,
+		EventDanceID:            submission.EventDanceID,
+		AdjudicatorRoundEntryID: submission.AdjudicatorRoundEntryID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return MarksAlreadySubmittedError
+	}
+	return service.marker.save(round, scoresheet, submission, currentUser.ID)
+}
@@ -0,0 +1,245 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newAdjudicator(id int) businesslogic.Account {
+	account := businesslogic.Account{ID: id}
+	account.SetRoles([]businesslogic.AccountRole{{AccountID: id, AccountTypeID: businesslogic.AccountTypeAdjudicator}})
+	return account
+}
+
+// activeAdjudicators returns the positions of adjudicators who serve the competition from yesterday until tomorrow
+func activeAdjudicators(accountIDs ...int) []businesslogic.CompetitionOfficial {
+	officials := make([]businesslogic.CompetitionOfficial, 0)
+	for _, each := range accountIDs {
+		officials = append(officials, businesslogic.CompetitionOfficial{
+			Official:       businesslogic.Account{ID: each},
+			OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+			EffectiveFrom:  time.Now().AddDate(0, 0, -1),
+			EffectiveUntil: time.Now().AddDate(0, 0, 1),
+		})
+	}
+	return officials
+}
+
+func TestAdjudicatorService_SubmitMarks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(eventRepo, roundRepo, officialRepo, eventDanceRepo,
+		adjudicatorEntryRepo, partnershipEntryRepo, scoresheetRepo, placementRepo)
+
+	// adjudicator 31 adjudicates round 7 of event 5 at competition 3 through adjudicator entry 21
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{RoundID: 7, AdjudicatorID: 31}).Return([]businesslogic.AdjudicatorRoundEntry{
+		{ID: 21, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 7}},
+	}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+	}).Return(activeAdjudicators(31), nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{RoundID: 7, PreliminaryRoundIndicator: true, RecallSize: 2, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21}).Return([]businesslogic.Placement{}, nil).Times(2)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventDanceID: 3, EventID: 5}).Return([]businesslogic.EventDance{{ID: 3, EventID: 5}}, nil)
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{ID: 21, RoundID: 7}).Return([]businesslogic.AdjudicatorRoundEntry{{ID: 21}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 7}).Return([]businesslogic.PartnershipRoundEntry{{ID: 101}, {ID: 102}, {ID: 103}}, nil)
+	placementRepo.EXPECT().CreatePlacement(gomock.Any()).Do(func(placement *businesslogic.Placement) {
+		assert.Equal(t, 21, placement.AdjudicatorRoundEntryID, "marks should be saved under the entry of the current user")
+	}).Return(nil).Times(3)
+
+	err := service.SubmitMarks(newAdjudicator(31), businesslogic.JudgeMarksSubmission{
+		RoundID:                 7,
+		EventDanceID:            3,
+		AdjudicatorRoundEntryID: 22,
+		Placements: []businesslogic.Placement{
+			{PartnershipRoundEntryID: 101, Callback: true},
+			{PartnershipRoundEntryID: 102, Callback: false},
+			{PartnershipRoundEntryID: 103, Callback: true},
+		},
+	})
+	assert.Nil(t, err)
+}
+
+func TestAdjudicatorService_SubmitMarks_WrongRecallCount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(eventRepo, roundRepo, officialRepo, eventDanceRepo,
+		adjudicatorEntryRepo, partnershipEntryRepo, scoresheetRepo, placementRepo)
+
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{RoundID: 7, AdjudicatorID: 31}).Return([]businesslogic.AdjudicatorRoundEntry{
+		{ID: 21, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 7}},
+	}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+	}).Return(activeAdjudicators(31), nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{RoundID: 7, PreliminaryRoundIndicator: true, RecallSize: 2, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21}).Return([]businesslogic.Placement{}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventDanceID: 3, EventID: 5}).Return([]businesslogic.EventDance{{ID: 3, EventID: 5}}, nil)
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{ID: 21, RoundID: 7}).Return([]businesslogic.AdjudicatorRoundEntry{{ID: 21}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 7}).Return([]businesslogic.PartnershipRoundEntry{{ID: 101}, {ID: 102}, {ID: 103}}, nil)
+
+	err := service.SubmitMarks(newAdjudicator(31), businesslogic.JudgeMarksSubmission{
+		RoundID:      7,
+		EventDanceID: 3,
+		Placements: []businesslogic.Placement{
+			{PartnershipRoundEntryID: 101, Callback: true},
+			{PartnershipRoundEntryID: 102, Callback: true},
+			{PartnershipRoundEntryID: 103, Callback: true},
+		},
+	})
+	assert.NotNil(t, err, "adjudicator should recall exactly the requested number of couples")
+}
+
+func TestAdjudicatorService_SubmitMarks_AlreadySubmitted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(eventRepo, roundRepo, officialRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl), adjudicatorEntryRepo,
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl), scoresheetRepo, placementRepo)
+
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{RoundID: 7, AdjudicatorID: 31}).Return([]businesslogic.AdjudicatorRoundEntry{
+		{ID: 21, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 7}},
+	}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+	}).Return(activeAdjudicators(31), nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21}).Return([]businesslogic.Placement{{ID: 1}}, nil)
+
+	err := service.SubmitMarks(newAdjudicator(31), businesslogic.JudgeMarksSubmission{RoundID: 7, EventDanceID: 3})
+	assert.Equal(t, businesslogic.MarksAlreadySubmittedError, err)
+}
+
+func TestAdjudicatorService_SubmitMarks_NotAssigned(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(
+		mock_businesslogic.NewMockIEventRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundRepository(mockCtrl),
+		mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl),
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		adjudicatorEntryRepo,
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIScoresheetRepository(mockCtrl),
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl))
+
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{RoundID: 7, AdjudicatorID: 32}).Return([]businesslogic.AdjudicatorRoundEntry{}, nil)
+	err := service.SubmitMarks(newAdjudicator(32), businesslogic.JudgeMarksSubmission{RoundID: 7, EventDanceID: 3})
+	assert.Equal(t, businesslogic.NotAssignedAdjudicatorError, err)
+}
+
+func TestAdjudicatorService_SubmitMarks_NotCompetitionOfficial(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(eventRepo, roundRepo, officialRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl), adjudicatorEntryRepo,
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIScoresheetRepository(mockCtrl),
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl))
+
+	// adjudicator 31 is still entered in the round, but no longer serves the competition
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{RoundID: 7, AdjudicatorID: 31}).Return([]businesslogic.AdjudicatorRoundEntry{
+		{ID: 21, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 7}},
+	}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+	}).Return(activeAdjudicators(32), nil)
+
+	err := service.SubmitMarks(newAdjudicator(31), businesslogic.JudgeMarksSubmission{RoundID: 7, EventDanceID: 3})
+	assert.Equal(t, businesslogic.NotActiveCompetitionOfficialError, err)
+}
+
+func TestAdjudicatorService_GetCurrentHeatLists(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	service := businesslogic.NewAdjudicatorService(eventRepo, roundRepo, officialRepo, eventDanceRepo,
+		adjudicatorEntryRepo, partnershipEntryRepo, scoresheetRepo, placementRepo)
+
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{AdjudicatorID: 31}).Return([]businesslogic.AdjudicatorRoundEntry{
+		{ID: 21, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 7}},
+		{ID: 22, AdjudicatorEntryID: 31, RoundEntry: businesslogic.RoundEntry{RoundID: 8}},
+	}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{{RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 8}).Return([]businesslogic.Scoresheet{{RoundID: 8, Status: businesslogic.SCORESHEET_STATUS_LOCKED}}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeAdjudicator,
+	}).Return(activeAdjudicators(31), nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 3}, {ID: 4}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 7}).Return([]businesslogic.PartnershipRoundEntry{{ID: 101}, {ID: 102}}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7, AdjudicatorRoundEntryID: 21}).Return([]businesslogic.Placement{
+		{EventDanceID: 3, PartnershipRoundEntryID: 101},
+		{EventDanceID: 3, PartnershipRoundEntryID: 102},
+	}, nil)
+
+	heatLists, err := service.GetCurrentHeatLists(newAdjudicator(31))
+	assert.Nil(t, err)
+	assert.Len(t, heatLists, 1, "locked rounds should not be marked")
+	assert.Equal(t, []int{3}, heatLists[0].SubmittedDances)
+	assert.Len(t, heatLists[0].Couples, 2)
+}
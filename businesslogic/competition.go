@@ -350,7 +350,36 @@ type CompetitionOfficial struct {
 
 // Active checks if the status of this position is still active.
 func (official CompetitionOfficial) Active() bool {
-	return time.Now().Before(official.EffectiveUntil) && time.Now().After(official.EffectiveFrom)
+	return official.ActiveAt(time.Now())
+}
+
+// ActiveAt checks if the position is active at the specified time
+func (official CompetitionOfficial) ActiveAt(t time.Time) bool {
+	return t.Before(official.EffectiveUntil) && t.After(official.EffectiveFrom)
+}
+
+// NotActiveCompetitionOfficialError is returned when the account does not serve the competition with the required role
+var NotActiveCompetitionOfficialError = errors.New("not an active official of this competition")
+
+// GetActiveCompetitionOfficial returns the position of the account at the competition with the specified role, if the
+// position is active at the time of checking.
+func GetActiveCompetitionOfficial(competitionID int, account Account, roleID int, repo ICompetitionOfficialRepository) (CompetitionOfficial, error) {
+	if !account.HasRole(roleID) {
+		return CompetitionOfficial{}, NotActiveCompetitionOfficialError
+	}
+	officials, err := repo.SearchCompetitionOfficial(SearchCompetitionOfficialCriteria{
+		CompetitionID:  competitionID,
+		OfficialRoleID: roleID,
+	})
+	if err != nil {
+		return CompetitionOfficial{}, err
+	}
+	for _, each := range officials {
+		if each.Official.ID == account.ID && each.Active() {
+			return each, nil
+		}
+	}
+	return CompetitionOfficial{}, NotActiveCompetitionOfficialError
 }
 
 type SearchCompetitionOfficialCriteria struct {
@@ -143,9 +143,14 @@ func (collection *CompetitionLeadTagCollection) SetTags(tags []CompetitionLeadTa
 	collection.tags = tags
 }
 
+// Tags returns the tags in the collection
+func (collection CompetitionLeadTagCollection) Tags() []CompetitionLeadTag {
+	return collection.tags
+}
+
 func (collection CompetitionLeadTagCollection) HasLead(accountId int) bool {
 	for _, each := range collection.tags {
-		if each.ID == accountId {
+		if each.LeadID == accountId {
 			return true
 		}
 	}
@@ -169,14 +174,15 @@ func (collection *CompetitionLeadTagCollection) Swap(i, j int) {
 
 // SearchCompetitionLeadTagCriteria defines the parameters that can be used to search lead's tags at competitions
 type SearchCompetitionLeadTagCriteria struct {
-	ID            int
-	CompetitionID int
-	LeadID        int
-	Tag           int
+	ID            int `schema:"id"`
+	CompetitionID int `schema:"competition"`
+	LeadID        int `schema:"lead"`
+	Tag           int `schema:"tag"`
 	CreateUserID  int
 }
 
-// ICompetitionLeadTagRepository defines the interface that a lead tag repository should implement.
+// ICompetitionLeadTagRepository defines the interface that a lead tag repository should implement. Create and Update
+// must return LeadTagTakenError if another lead of the competition has the tag.
 type ICompetitionLeadTagRepository interface {
 	CreateCompetitionLeadTag(tag *CompetitionLeadTag) error
 	DeleteCompetitionLeadTag(tag CompetitionLeadTag) error
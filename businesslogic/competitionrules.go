@@ -1,7 +1,54 @@
 package businesslogic
 
-import "errors"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
+// Reasons that a registration is not eligible for an event. Reasons are stable and can be used by clients to explain
+// the rejection in their own words.
+const (
+	EligibilityReasonSameSex            = "SAME_SEX_NOT_ALLOWED"
+	EligibilityReasonAgeOutOfRange      = "AGE_OUT_OF_RANGE"
+	EligibilityReasonDateOfBirthUnknown = "DATE_OF_BIRTH_UNKNOWN"
+	EligibilityReasonDanceDown          = "PROFICIENCY_DANCE_DOWN"
+	EligibilityReasonNotNewcomer        = "NOT_NEWCOMER"
+	EligibilityReasonMaxEventsExceeded  = "MAX_EVENTS_EXCEEDED"
+	EligibilityReasonPointedOut         = "POINTED_OUT"
+)
+
+// EligibilityViolation is a reason that a registration cannot enter an event. EventID and AthleteID are 0 if the
+// violation is not about a specific event or athlete.
+type EligibilityViolation struct {
+	Reason    string
+	EventID   int
+	AthleteID int
+	Message   string
+}
+
+// EventEligibilityError is returned by rules when a registration violates them
+type EventEligibilityError struct {
+	Violations []EligibilityViolation
+}
+
+func (err EventEligibilityError) Error() string {
+	messages := make([]string, 0)
+	for _, each := range err.Violations {
+		messages = append(messages, each.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func newEligibilityError(violations []EligibilityViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return EventEligibilityError{Violations: violations}
+}
+
+// IRule checks if a registration is eligible for the events it adds. A rule returns EventEligibilityError when the
+// registration violates the rule, and other errors when the rule cannot be checked.
 type IRule interface {
 	Apply(registration EventRegistrationForm) error
 }
@@ -14,7 +61,451 @@ type GenderRule struct {
 
 func (rule GenderRule) Apply(registration EventRegistrationForm) error {
 	if registration.Couple.SameSex && (!rule.AllowSameSex) {
-		return errors.New("same sex is not allowed")
+		return newEligibilityError([]EligibilityViolation{{
+			Reason:  EligibilityReasonSameSex,
+			Message: "same sex is not allowed",
+		}})
 	}
 	return nil
 }
+
+// EligibilityRuleSet is a group of rules that apply to the events of a federation and division. A FederationID or
+// DivisionID of 0 matches all federations or divisions.
+type EligibilityRuleSet struct {
+	FederationID int
+	DivisionID   int
+	Rules        []IRule
+}
+
+func (ruleSet EligibilityRuleSet) matches(event Event) bool {
+	return (ruleSet.FederationID == 0 || ruleSet.FederationID == event.FederationID) &&
+		(ruleSet.DivisionID == 0 || ruleSet.DivisionID == event.DivisionID)
+}
+
+// EligibilityRuleSettings specifies the rules that apply to the events of a federation and division, so that each
+// federation and division can have its own rules. A FederationID or DivisionID of 0 matches all federations or
+// divisions. Rules are not applied if their settings are zero.
+type EligibilityRuleSettings struct {
+	FederationID            int      `json:"federation"`
+	DivisionID              int      `json:"division"`
+	RejectSameSex           bool     `json:"rejectSameSex"`
+	EnforceAge              bool     `json:"enforceAge"`
+	NewcomerProficiency     string   `json:"newcomerProficiency"`
+	NewcomerMaxCompetitions int      `json:"newcomerMaxCompetitions"`
+	ProficiencyLevels       []string `json:"proficiencyLevels"` // from the lowest to the highest
+	MaxLevelsBelow          int      `json:"maxLevelsBelow"`
+	MaxEvents               int      `json:"maxEvents"`
+	PointOut                bool     `json:"pointOut"`
+}
+
+// DefaultEligibilityRuleSettings apply to events of all federations and divisions: athletes must be within the
+// enforced age range, and same-sex couples are rejected. Newcomer, dance-down, maximum events and point-out rules are
+// not applied by default, and federations and divisions opt in to them with their own settings.
+var DefaultEligibilityRuleSettings = []EligibilityRuleSettings{
+	{
+		RejectSameSex: true,
+		EnforceAge:    true,
+	},
+}
+
+// EligibilityRuleFactory creates the rule sets of EligibilityRuleSettings
+type EligibilityRuleFactory struct {
+	accountRepo          IAccountRepository
+	ageRepo              IAgeRepository
+	proficiencyRepo      IProficiencyRepository
+	eventRepo            IEventRepository
+	eventEntryRepo       IPartnershipEventEntryRepository
+	competitionEntryRepo IAthleteCompetitionEntryRepository
+	pointService         ProficiencyPointService
+}
+
+func NewEligibilityRuleFactory(
+	accountRepo IAccountRepository,
+	ageRepo IAgeRepository,
+	proficiencyRepo IProficiencyRepository,
+	eventRepo IEventRepository,
+	eventEntryRepo IPartnershipEventEntryRepository,
+	competitionEntryRepo IAthleteCompetitionEntryRepository,
+	pointService ProficiencyPointService) EligibilityRuleFactory {
+	return EligibilityRuleFactory{
+		accountRepo:          accountRepo,
+		ageRepo:              ageRepo,
+		proficiencyRepo:      proficiencyRepo,
+		eventRepo:            eventRepo,
+		eventEntryRepo:       eventEntryRepo,
+		competitionEntryRepo: competitionEntryRepo,
+		pointService:         pointService,
+	}
+}
+
+// NewRuleSet creates the rules that the settings enable
+func (factory EligibilityRuleFactory) NewRuleSet(settings EligibilityRuleSettings) EligibilityRuleSet {
+	ruleSet := EligibilityRuleSet{
+		FederationID: settings.FederationID,
+		DivisionID:   settings.DivisionID,
+		Rules:        make([]IRule, 0),
+	}
+	if settings.RejectSameSex {
+		ruleSet.Rules = append(ruleSet.Rules, GenderRule{AllowSameSex: false})
+	}
+	if settings.EnforceAge {
+		ruleSet.Rules = append(ruleSet.Rules, NewAgeRule(factory.accountRepo, factory.ageRepo))
+	}
+	if settings.NewcomerProficiency != "" {
+		ruleSet.Rules = append(ruleSet.Rules, NewNewcomerRule(settings.NewcomerProficiency, settings.NewcomerMaxCompetitions, factory.proficiencyRepo, factory.competitionEntryRepo))
+	}
+	if len(settings.ProficiencyLevels) > 0 {
+		ruleSet.Rules = append(ruleSet.Rules, NewProficiencyDanceDownRule(settings.MaxLevelsBelow, settings.ProficiencyLevels, factory.proficiencyRepo, factory.eventRepo, factory.eventEntryRepo))
+	}
+	if settings.MaxEvents > 0 {
+		ruleSet.Rules = append(ruleSet.Rules, NewMaxEventsRule(settings.MaxEvents, factory.eventRepo, factory.eventEntryRepo))
+	}
+	if settings.PointOut {
+		ruleSet.Rules = append(ruleSet.Rules, NewProficiencyPointOutRule(factory.pointService))
+	}
+	return ruleSet
+}
+
+// NewEngine creates an engine that applies the rule sets of all the settings
+func (factory EligibilityRuleFactory) NewEngine(settings []EligibilityRuleSettings) EventEligibilityEngine {
+	ruleSets := make([]EligibilityRuleSet, 0)
+	for _, each := range settings {
+		ruleSets = append(ruleSets, factory.NewRuleSet(each))
+	}
+	return NewEventEligibilityEngine(ruleSets...)
+}
+
+// EventEligibilityEngine applies the rule sets that match the events added by a registration. Each rule only sees the
+// added events that its rule set matches. Violations of all rules are collected, so that a registration can be
+// corrected at once.
+type EventEligibilityEngine struct {
+	ruleSets []EligibilityRuleSet
+}
+
+func NewEventEligibilityEngine(ruleSets ...EligibilityRuleSet) EventEligibilityEngine {
+	return EventEligibilityEngine{ruleSets: ruleSets}
+}
+
+// Apply checks the registration against all the matching rules, and implements IRule
+func (engine EventEligibilityEngine) Apply(registration EventRegistrationForm) error {
+	violations := make([]EligibilityViolation, 0)
+	for _, ruleSet := range engine.ruleSets {
+		form := registration
+		form.EventsAdded = make([]Event, 0)
+		for _, each := range registration.EventsAdded {
+			if ruleSet.matches(each) {
+				form.EventsAdded = append(form.EventsAdded, each)
+			}
+		}
+		if len(form.EventsAdded) == 0 {
+			continue
+		}
+		for _, rule := range ruleSet.Rules {
+			err := rule.Apply(form)
+			if eligibilityErr, ok := err.(EventEligibilityError); ok {
+				violations = append(violations, eligibilityErr.Violations...)
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	return newEligibilityError(violations)
+}
+
+// AgeRule requires both athletes to be within the age range of the events they enter. Ages are calculated on the
+// first day of the competition, and only ranges that are enforced are checked. Athletes whose date of birth is unknown
+// cannot enter enforced ranges.
+type AgeRule struct {
+	accountRepo IAccountRepository
+	ageRepo     IAgeRepository
+}
+
+func NewAgeRule(accountRepo IAccountRepository, ageRepo IAgeRepository) AgeRule {
+	return AgeRule{accountRepo: accountRepo, ageRepo: ageRepo}
+}
+
+func (rule AgeRule) Apply(registration EventRegistrationForm) error {
+	athletes := make([]Account, 0)
+	for _, each := range []Account{registration.Couple.Lead, registration.Couple.Follow} {
+		if each.DateOfBirth.IsZero() {
+			each = GetAccountByID(each.ID, rule.accountRepo)
+		}
+		athletes = append(athletes, each)
+	}
+	competitionDate := registration.Competition.StartDateTime
+	if competitionDate.IsZero() {
+		competitionDate = time.Now()
+	}
+
+	violations := make([]EligibilityViolation, 0)
+	for _, event := range registration.EventsAdded {
+		ages, err := rule.ageRepo.SearchAge(SearchAgeCriteria{AgeID: event.AgeID})
+		if err != nil {
+			return err
+		}
+		if len(ages) != 1 || !ages[0].Enforced {
+			continue
+		}
+		for _, athlete := range athletes {
+			if athlete.DateOfBirth.IsZero() {
+				violations = append(violations, EligibilityViolation{
+					Reason:    EligibilityReasonDateOfBirthUnknown,
+					EventID:   event.ID,
+					AthleteID: athlete.ID,
+					Message:   fmt.Sprintf("date of birth of athlete %v is unknown", athlete.ID),
+				})
+				continue
+			}
+			age := ageOn(athlete.DateOfBirth, competitionDate)
+			if age < ages[0].AgeMinimum || (ages[0].AgeMaximum > 0 && age > ages[0].AgeMaximum) {
+				violations = append(violations, EligibilityViolation{
+					Reason:    EligibilityReasonAgeOutOfRange,
+					EventID:   event.ID,
+					AthleteID: athlete.ID,
+					Message:   fmt.Sprintf("athlete %v is %v years old and not eligible for %v", athlete.ID, age, ages[0].Name),
+				})
+			}
+		}
+	}
+	return newEligibilityError(violations)
+}
+
+// ageOn returns the age in years of someone who was born on dateOfBirth. Month and day are compared rather than the
+// day of the year, which is shifted by leap years.
+func ageOn(dateOfBirth, date time.Time) int {
+	age := date.Year() - dateOfBirth.Year()
+	if date.Month() < dateOfBirth.Month() || (date.Month() == dateOfBirth.Month() && date.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// DefaultProficiencyLevels is the order of the proficiencies of all federations, from the lowest to the highest.
+// Proficiencies that span several levels, such as Syllabus, are not ranked.
+var DefaultProficiencyLevels = []string{
+	"Newcomer",
+	"Pre Bronze",
+	"Bronze",
+	"Full Bronze",
+	"Intermediate Silver",
+	"Silver",
+	"Full Silver",
+	"Intermediate Gold",
+	"Gold",
+	"Full Gold",
+	"Novice",
+	"Pre-Championship",
+	"Championship",
+	"Open Championship",
+}
+
+// ProficiencyDanceDownRule prevents couples from dancing down: within a style and division, a couple may not enter an
+// event that is more than MaxLevelsBelow proficiencies below the highest proficiency it enters at the competition.
+// Proficiencies are ranked by the order of their names in levels, counting only the levels that the division has.
+// Events of proficiencies that are not in levels are not checked.
+type ProficiencyDanceDownRule struct {
+	maxLevelsBelow  int
+	levels          []string
+	proficiencyRepo IProficiencyRepository
+	eventRepo       IEventRepository
+	eventEntryRepo  IPartnershipEventEntryRepository
+}
+
+func NewProficiencyDanceDownRule(maxLevelsBelow int, levels []string, proficiencyRepo IProficiencyRepository, eventRepo IEventRepository, eventEntryRepo IPartnershipEventEntryRepository) ProficiencyDanceDownRule {
+	return ProficiencyDanceDownRule{
+		maxLevelsBelow:  maxLevelsBelow,
+		levels:          levels,
+		proficiencyRepo: proficiencyRepo,
+		eventRepo:       eventRepo,
+		eventEntryRepo:  eventEntryRepo,
+	}
+}
+
+func (rule ProficiencyDanceDownRule) Apply(registration EventRegistrationForm) error {
+	entered, err := getEnteredEvents(registration, rule.eventRepo, rule.eventEntryRepo)
+	if err != nil {
+		return err
+	}
+	ranks := make(map[int]map[int]int) // key: division, proficiency
+	highest := make(map[[2]int]int)    // key: division, style
+	for _, each := range entered {
+		rank, err := rule.getRank(each, ranks)
+		if err != nil {
+			return err
+		}
+		key := [2]int{each.DivisionID, each.StyleID}
+		if rank > highest[key] {
+			highest[key] = rank
+		}
+	}
+
+	violations := make([]EligibilityViolation, 0)
+	for _, each := range registration.EventsAdded {
+		rank, err := rule.getRank(each, ranks)
+		if err != nil {
+			return err
+		}
+		if rank > 0 && highest[[2]int{each.DivisionID, each.StyleID}]-rank > rule.maxLevelsBelow {
+			violations = append(violations, EligibilityViolation{
+				Reason:  EligibilityReasonDanceDown,
+				EventID: each.ID,
+				Message: fmt.Sprintf("event %v is more than %v proficiency levels below other events of the same style", each.ID, rule.maxLevelsBelow),
+			})
+		}
+	}
+	return newEligibilityError(violations)
+}
+
+// getRank returns the 1-based rank of the proficiency of the event in its division, or 0 if the proficiency is not
+// ranked
+func (rule ProficiencyDanceDownRule) getRank(event Event, ranks map[int]map[int]int) (int, error) {
+	if ranks[event.DivisionID] == nil {
+		proficiencies, err := rule.proficiencyRepo.SearchProficiency(SearchProficiencyCriteria{DivisionID: event.DivisionID})
+		if err != nil {
+			return 0, err
+		}
+		ranks[event.DivisionID] = make(map[int]int)
+		rank := 0
+		for _, level := range rule.levels {
+			for _, each := range proficiencies {
+				if strings.EqualFold(each.Name, level) {
+					rank++
+					ranks[event.DivisionID][each.ID] = rank
+				}
+			}
+		}
+	}
+	return ranks[event.DivisionID][event.ProficiencyID], nil
+}
+
+// NewcomerRule limits the events of a newcomer proficiency to athletes who have entered at most MaxCompetitions other
+// competitions
+type NewcomerRule struct {
+	proficiencyName      string
+	maxCompetitions      int
+	proficiencyRepo      IProficiencyRepository
+	competitionEntryRepo IAthleteCompetitionEntryRepository
+}
+
+func NewNewcomerRule(proficiencyName string, maxCompetitions int, proficiencyRepo IProficiencyRepository, competitionEntryRepo IAthleteCompetitionEntryRepository) NewcomerRule {
+	return NewcomerRule{
+		proficiencyName:      proficiencyName,
+		maxCompetitions:      maxCompetitions,
+		proficiencyRepo:      proficiencyRepo,
+		competitionEntryRepo: competitionEntryRepo,
+	}
+}
+
+func (rule NewcomerRule) Apply(registration EventRegistrationForm) error {
+	newcomerEvents := make([]Event, 0)
+	for _, each := range registration.EventsAdded {
+		name := each.Proficiency.Name
+		if name == "" {
+			proficiencies, err := rule.proficiencyRepo.SearchProficiency(SearchProficiencyCriteria{ProficiencyID: each.ProficiencyID})
+			if err != nil {
+				return err
+			}
+			if len(proficiencies) == 1 {
+				name = proficiencies[0].Name
+			}
+		}
+		if strings.EqualFold(name, rule.proficiencyName) {
+			newcomerEvents = append(newcomerEvents, each)
+		}
+	}
+	if len(newcomerEvents) == 0 {
+		return nil
+	}
+
+	violations := make([]EligibilityViolation, 0)
+	for _, athlete := range []Account{registration.Couple.Lead, registration.Couple.Follow} {
+		entries, err := rule.competitionEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{AthleteID: athlete.ID})
+		if err != nil {
+			return err
+		}
+		competitions := make(map[int]bool)
+		for _, each := range entries {
+			if each.Competition.ID != registration.Competition.ID {
+				competitions[each.Competition.ID] = true
+			}
+		}
+		if len(competitions) <= rule.maxCompetitions {
+			continue
+		}
+		for _, event := range newcomerEvents {
+			violations = append(violations, EligibilityViolation{
+				Reason:    EligibilityReasonNotNewcomer,
+				EventID:   event.ID,
+				AthleteID: athlete.ID,
+				Message:   fmt.Sprintf("athlete %v has entered %v competitions and is not a newcomer", athlete.ID, len(competitions)),
+			})
+		}
+	}
+	return newEligibilityError(violations)
+}
+
+// MaxEventsRule limits the number of events that a couple can enter at a competition
+type MaxEventsRule struct {
+	maxEvents      int
+	eventRepo      IEventRepository
+	eventEntryRepo IPartnershipEventEntryRepository
+}
+
+func NewMaxEventsRule(maxEvents int, eventRepo IEventRepository, eventEntryRepo IPartnershipEventEntryRepository) MaxEventsRule {
+	return MaxEventsRule{
+		maxEvents:      maxEvents,
+		eventRepo:      eventRepo,
+		eventEntryRepo: eventEntryRepo,
+	}
+}
+
+func (rule MaxEventsRule) Apply(registration EventRegistrationForm) error {
+	entered, err := getEnteredEvents(registration, rule.eventRepo, rule.eventEntryRepo)
+	if err != nil {
+		return err
+	}
+	if len(entered) <= rule.maxEvents {
+		return nil
+	}
+	return newEligibilityError([]EligibilityViolation{{
+		Reason:  EligibilityReasonMaxEventsExceeded,
+		Message: fmt.Sprintf("couple can enter at most %v events, but is entering %v events", rule.maxEvents, len(entered)),
+	}})
+}
+
+// getEnteredEvents returns the events of the competition that the couple will be in after the registration: events
+// that the couple has entered and does not drop, and the events that are added
+func getEnteredEvents(registration EventRegistrationForm, eventRepo IEventRepository, eventEntryRepo IPartnershipEventEntryRepository) ([]Event, error) {
+	entries, err := eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{PartnershipID: registration.Couple.ID})
+	if err != nil {
+		return nil, err
+	}
+	dropped := make(map[int]bool)
+	for _, each := range registration.EventsDropped {
+		dropped[each.ID] = true
+	}
+
+	entered := make([]Event, 0)
+	included := make(map[int]bool)
+	for _, each := range entries {
+		if dropped[each.Event.ID] || included[each.Event.ID] {
+			continue
+		}
+		events, err := eventRepo.SearchEvent(SearchEventCriteria{EventID: each.Event.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(events) != 1 || events[0].CompetitionID != registration.Competition.ID {
+			continue
+		}
+		entered = append(entered, events[0])
+		included[each.Event.ID] = true
+	}
+	for _, each := range registration.EventsAdded {
+		if !included[each.ID] {
+			entered = append(entered, each)
+			included[each.ID] = true
+		}
+	}
+	return entered, nil
+}
@@ -0,0 +1,218 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newEligibilityForm(added ...businesslogic.Event) businesslogic.EventRegistrationForm {
+	return businesslogic.EventRegistrationForm{
+		Competition: businesslogic.Competition{ID: 3, StartDateTime: time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)},
+		Couple: businesslogic.Partnership{
+			ID:     21,
+			Lead:   businesslogic.Account{ID: 1, DateOfBirth: time.Date(1995, time.May, 1, 0, 0, 0, 0, time.UTC)},
+			Follow: businesslogic.Account{ID: 2, DateOfBirth: time.Date(2001, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		EventsAdded: added,
+	}
+}
+
+func TestGenderRule_Apply(t *testing.T) {
+	form := newEligibilityForm()
+	form.Couple.SameSex = true
+
+	err := businesslogic.GenderRule{AllowSameSex: false}.Apply(form)
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	assert.Equal(t, businesslogic.EligibilityReasonSameSex, err.(businesslogic.EventEligibilityError).Violations[0].Reason)
+	assert.Nil(t, businesslogic.GenderRule{AllowSameSex: true}.Apply(form))
+}
+
+func TestAgeRule_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	ageRepo := mock_businesslogic.NewMockIAgeRepository(mockCtrl)
+
+	ageRepo.EXPECT().SearchAge(businesslogic.SearchAgeCriteria{AgeID: 7}).Return([]businesslogic.Age{{ID: 7, Name: "Adult", Enforced: true, AgeMinimum: 19}}, nil)
+	ageRepo.EXPECT().SearchAge(businesslogic.SearchAgeCriteria{AgeID: 8}).Return([]businesslogic.Age{{ID: 8, Name: "Youth", Enforced: false, AgeMaximum: 18}}, nil)
+
+	rule := businesslogic.NewAgeRule(accountRepo, ageRepo)
+	err := rule.Apply(newEligibilityForm(businesslogic.Event{ID: 11, AgeID: 7}, businesslogic.Event{ID: 12, AgeID: 8}))
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	violations := err.(businesslogic.EventEligibilityError).Violations
+	assert.Len(t, violations, 1, "follow is 16 on the day of the competition, and youth age is not enforced")
+	assert.Equal(t, businesslogic.EligibilityReasonAgeOutOfRange, violations[0].Reason)
+	assert.Equal(t, 11, violations[0].EventID)
+	assert.Equal(t, 2, violations[0].AthleteID)
+}
+
+func TestAgeRule_Apply_LeapYear(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	ageRepo := mock_businesslogic.NewMockIAgeRepository(mockCtrl)
+
+	ageRepo.EXPECT().SearchAge(businesslogic.SearchAgeCriteria{AgeID: 7}).Return([]businesslogic.Age{{ID: 7, Name: "Adult", Enforced: true, AgeMinimum: 19}}, nil)
+
+	form := newEligibilityForm(businesslogic.Event{ID: 11, AgeID: 7})
+	form.Competition.StartDateTime = time.Date(2019, time.March, 1, 8, 0, 0, 0, time.UTC)
+	form.Couple.Lead.DateOfBirth = time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC)
+	form.Couple.Follow.DateOfBirth = time.Date(1999, time.March, 2, 0, 0, 0, 0, time.UTC)
+	err := businesslogic.NewAgeRule(accountRepo, ageRepo).Apply(form)
+	assert.Nil(t, err, "athletes born on March 1 of a leap year should be 19 on March 1 nineteen years later")
+}
+
+func TestAgeRule_Apply_DateOfBirthUnknown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	ageRepo := mock_businesslogic.NewMockIAgeRepository(mockCtrl)
+
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 2}).Return([]businesslogic.Account{{ID: 2}}, nil).Times(1)
+	ageRepo.EXPECT().SearchAge(businesslogic.SearchAgeCriteria{AgeID: 7}).Return([]businesslogic.Age{{ID: 7, Name: "Adult", Enforced: true, AgeMinimum: 19}}, nil).Times(1)
+
+	form := newEligibilityForm(businesslogic.Event{ID: 11, AgeID: 7})
+	form.Couple.Follow = businesslogic.Account{ID: 2}
+	err := businesslogic.NewAgeRule(accountRepo, ageRepo).Apply(form)
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	violations := err.(businesslogic.EventEligibilityError).Violations
+	assert.Len(t, violations, 1, "follow has no date of birth and should not be treated as born in year 1")
+	assert.Equal(t, businesslogic.EligibilityReasonDateOfBirthUnknown, violations[0].Reason)
+	assert.Equal(t, 11, violations[0].EventID)
+	assert.Equal(t, 2, violations[0].AthleteID)
+}
+
+// expectEntries sets up partnership 21 as entered in event 31 (Gold) and event 32 of another competition
+func expectEntries(eventRepo *mock_businesslogic.MockIEventRepository, eventEntryRepo *mock_businesslogic.MockIPartnershipEventEntryRepository) {
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{PartnershipID: 21}).Return([]businesslogic.PartnershipEventEntry{
+		{Event: businesslogic.Event{ID: 31}},
+		{Event: businesslogic.Event{ID: 32}},
+	}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 31}).Return([]businesslogic.Event{{ID: 31, CompetitionID: 3, DivisionID: 4, StyleID: 1, ProficiencyID: 104}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 32}).Return([]businesslogic.Event{{ID: 32, CompetitionID: 5, DivisionID: 4, StyleID: 2, ProficiencyID: 104}}, nil)
+}
+
+func TestProficiencyDanceDownRule_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+
+	expectEntries(eventRepo, eventEntryRepo)
+	// Bronze is added after Gold, so IDs do not follow the order of the levels
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{DivisionID: 4}).Return([]businesslogic.Proficiency{
+		{ID: 104, Name: "Gold"}, {ID: 101, Name: "Newcomer"}, {ID: 103, Name: "Silver"}, {ID: 105, Name: "Bronze"}, {ID: 106, Name: "Syllabus"},
+	}, nil).Times(1)
+
+	rule := businesslogic.NewProficiencyDanceDownRule(1, businesslogic.DefaultProficiencyLevels, proficiencyRepo, eventRepo, eventEntryRepo)
+	err := rule.Apply(newEligibilityForm(
+		businesslogic.Event{ID: 41, CompetitionID: 3, DivisionID: 4, StyleID: 1, ProficiencyID: 103},
+		businesslogic.Event{ID: 42, CompetitionID: 3, DivisionID: 4, StyleID: 1, ProficiencyID: 105},
+		businesslogic.Event{ID: 43, CompetitionID: 3, DivisionID: 4, StyleID: 2, ProficiencyID: 101},
+		businesslogic.Event{ID: 44, CompetitionID: 3, DivisionID: 4, StyleID: 1, ProficiencyID: 106},
+	))
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	violations := err.(businesslogic.EventEligibilityError).Violations
+	assert.Len(t, violations, 1, "entries of other competitions and other styles, and proficiencies that are not ranked, should not count")
+	assert.Equal(t, businesslogic.EligibilityReasonDanceDown, violations[0].Reason)
+	assert.Equal(t, 42, violations[0].EventID)
+}
+
+func TestNewcomerRule_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	entryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 101}).Return([]businesslogic.Proficiency{{ID: 101, Name: "Newcomer"}}, nil)
+	entryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{AthleteID: 1}).Return([]businesslogic.AthleteCompetitionEntry{
+		{Competition: businesslogic.Competition{ID: 3}},
+	}, nil)
+	entryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{AthleteID: 2}).Return([]businesslogic.AthleteCompetitionEntry{
+		{Competition: businesslogic.Competition{ID: 3}},
+		{Competition: businesslogic.Competition{ID: 1}},
+	}, nil)
+
+	rule := businesslogic.NewNewcomerRule("Newcomer", 0, proficiencyRepo, entryRepo)
+	err := rule.Apply(newEligibilityForm(
+		businesslogic.Event{ID: 41, ProficiencyID: 101},
+		businesslogic.Event{ID: 42, ProficiencyID: 102, Proficiency: businesslogic.Proficiency{ID: 102, Name: "Bronze"}},
+	))
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	violations := err.(businesslogic.EventEligibilityError).Violations
+	assert.Len(t, violations, 1, "the current competition should not count against newcomers")
+	assert.Equal(t, businesslogic.EligibilityReasonNotNewcomer, violations[0].Reason)
+	assert.Equal(t, 41, violations[0].EventID)
+	assert.Equal(t, 2, violations[0].AthleteID)
+}
+
+func TestMaxEventsRule_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+
+	expectEntries(eventRepo, eventEntryRepo)
+	form := newEligibilityForm(businesslogic.Event{ID: 41}, businesslogic.Event{ID: 42})
+	err := businesslogic.NewMaxEventsRule(2, eventRepo, eventEntryRepo).Apply(form)
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	assert.Equal(t, businesslogic.EligibilityReasonMaxEventsExceeded, err.(businesslogic.EventEligibilityError).Violations[0].Reason)
+
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{PartnershipID: 21}).Return([]businesslogic.PartnershipEventEntry{
+		{Event: businesslogic.Event{ID: 31}},
+	}, nil)
+	form.EventsDropped = []businesslogic.Event{{ID: 31}}
+	assert.Nil(t, businesslogic.NewMaxEventsRule(2, eventRepo, eventEntryRepo).Apply(form), "dropped events should not count")
+}
+
+func TestEventEligibilityEngine_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	wdsfRule := mock_businesslogic.NewMockIRule(mockCtrl)
+	collegiateRule := mock_businesslogic.NewMockIRule(mockCtrl)
+	globalRule := mock_businesslogic.NewMockIRule(mockCtrl)
+
+	wdsfEvent := businesslogic.Event{ID: 41, FederationID: 2, DivisionID: 4}
+	collegiateEvent := businesslogic.Event{ID: 42, FederationID: 1, DivisionID: 5}
+	form := newEligibilityForm(wdsfEvent, collegiateEvent)
+
+	wdsfForm := form
+	wdsfForm.EventsAdded = []businesslogic.Event{wdsfEvent}
+	wdsfRule.EXPECT().Apply(wdsfForm).Return(businesslogic.EventEligibilityError{Violations: []businesslogic.EligibilityViolation{
+		{Reason: businesslogic.EligibilityReasonAgeOutOfRange, EventID: 41},
+	}})
+	collegiateRule.EXPECT().Apply(gomock.Any()).Times(0)
+	globalRule.EXPECT().Apply(form).Return(businesslogic.EventEligibilityError{Violations: []businesslogic.EligibilityViolation{
+		{Reason: businesslogic.EligibilityReasonMaxEventsExceeded},
+	}})
+
+	engine := businesslogic.NewEventEligibilityEngine(
+		businesslogic.EligibilityRuleSet{FederationID: 2, Rules: []businesslogic.IRule{wdsfRule}},
+		businesslogic.EligibilityRuleSet{FederationID: 1, DivisionID: 6, Rules: []businesslogic.IRule{collegiateRule}},
+		businesslogic.EligibilityRuleSet{Rules: []businesslogic.IRule{globalRule}},
+	)
+	err := engine.Apply(form)
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	assert.Len(t, err.(businesslogic.EventEligibilityError).Violations, 2, "violations of all rule sets should be reported")
+}
+
+func TestEligibilityRuleFactory_NewRuleSet(t *testing.T) {
+	factory := businesslogic.NewEligibilityRuleFactory(nil, nil, nil, nil, nil, nil, businesslogic.ProficiencyPointService{})
+
+	ruleSet := factory.NewRuleSet(businesslogic.EligibilityRuleSettings{FederationID: 1, DivisionID: 6, MaxEvents: 8, RejectSameSex: true})
+	assert.Equal(t, 1, ruleSet.FederationID)
+	assert.Equal(t, 6, ruleSet.DivisionID)
+	assert.Len(t, ruleSet.Rules, 2, "only the rules that are enabled should be created")
+	assert.IsType(t, businesslogic.GenderRule{}, ruleSet.Rules[0])
+	assert.IsType(t, businesslogic.MaxEventsRule{}, ruleSet.Rules[1])
+
+	defaults := factory.NewRuleSet(businesslogic.DefaultEligibilityRuleSettings[0])
+	assert.Len(t, defaults.Rules, 2, "only age and gender rules should apply to all federations by default")
+	assert.IsType(t, businesslogic.GenderRule{}, defaults.Rules[0])
+	assert.IsType(t, businesslogic.AgeRule{}, defaults.Rules[1])
+}
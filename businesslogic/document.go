@@ -0,0 +1,368 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DocumentCouple is a couple as printed on competition documents. Couples are numbered by the tag of the lead.
+type DocumentCouple struct {
+	PartnershipID int
+	Tag           int
+	LeadName      string
+	FollowName    string
+}
+
+// BackNumber is the competitor number that a lead wears at a competition
+type BackNumber struct {
+	CompetitionName string
+	Tag             int
+	LeadName        string
+}
+
+// HeatSheetHeat is a heat of a dance of a round, or all the couples who entered the event if heats are not drawn yet
+type HeatSheetHeat struct {
+	Round   int // 1-based, 0 if heats of the event are not drawn
+	Dance   string
+	Number  int
+	Couples []DocumentCouple
+}
+
+// HeatSheet lists the heats of an event
+type HeatSheet struct {
+	CompetitionName string
+	EventName       string
+	Heats           []HeatSheetHeat
+}
+
+// SchoolEntry is a couple who represents a school at a competition and the events that the couple entered
+type SchoolEntry struct {
+	Couple DocumentCouple
+	Events []string
+}
+
+// SchoolEntrySummary lists the couples who represent a school at a competition
+type SchoolEntrySummary struct {
+	CompetitionName string
+	SchoolName      string
+	Entries         []SchoolEntry
+}
+
+// IDocumentRenderer specifies the functions that renderers of printable competition documents should implement
+type IDocumentRenderer interface {
+	ContentType() string
+	RenderBackNumbers(numbers []BackNumber) ([]byte, error)
+	RenderHeatSheets(sheets []HeatSheet) ([]byte, error)
+	RenderSchoolEntrySummaries(summaries []SchoolEntrySummary) ([]byte, error)
+}
+
+// BackNumbersPolicy allows the officials who run the floor to print the back numbers of the competition
+var BackNumbersPolicy = RunCompetitionPolicy
+
+// HeatSheetPolicy allows the officials who run the floor to print the heat sheets of the competition
+var HeatSheetPolicy = RunCompetitionPolicy
+
+// SchoolEntrySummaryPolicy allows organizers and co-organizers to print the entries of the schools at the competition
+var SchoolEntrySummaryPolicy = ManageCompetitionPolicy
+
+// CompetitionDocumentService prepares the printable documents of a competition for its officials. Heat sheets are
+// printed from the heats that are drawn by HeatDrawService.
+type CompetitionDocumentService struct {
+	accountRepo    IAccountRepository
+	danceRepo      IDanceRepository
+	eventEntryRepo IPartnershipEventEntryRepository
+	schoolRepo     ISchoolRepository
+	tagRepo        ICompetitionLeadTagRepository
+	heats          HeatDrawService
+	renderer       IDocumentRenderer
+}
+
+func NewCompetitionDocumentService(
+	accountRepo IAccountRepository,
+	danceRepo IDanceRepository,
+	eventEntryRepo IPartnershipEventEntryRepository,
+	schoolRepo ISchoolRepository,
+	tagRepo ICompetitionLeadTagRepository,
+	heats HeatDrawService,
+	renderer IDocumentRenderer) CompetitionDocumentService {
+	return CompetitionDocumentService{
+		accountRepo:    accountRepo,
+		danceRepo:      danceRepo,
+		eventEntryRepo: eventEntryRepo,
+		schoolRepo:     schoolRepo,
+		tagRepo:        tagRepo,
+		heats:          heats,
+		renderer:       renderer,
+	}
+}
+
+// ContentType returns the media type of the documents
+func (service CompetitionDocumentService) ContentType() string {
+	return service.renderer.ContentType()
+}
+
+// BackNumbers renders the back numbers of all the leads who have tags at the competition
+func (service CompetitionDocumentService) BackNumbers(competition Competition) ([]byte, error) {
+	collection, err := service.tagRepo.SearchCompetitionLeadTag(SearchCompetitionLeadTagCriteria{CompetitionID: competition.ID})
+	if err != nil {
+		return nil, err
+	}
+	tags := collection.Tags()
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	names := make(map[int]string)
+	numbers := make([]BackNumber, 0)
+	for _, each := range tags {
+		name, err := service.accountName(each.LeadID, names)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, BackNumber{CompetitionName: competition.Name, Tag: each.Tag, LeadName: name})
+	}
+	return service.renderer.RenderBackNumbers(numbers)
+}
+
+// HeatSheets renders the heat sheet of an event of the competition, or of all the events of the competition if eventID
+// is 0. Rounds whose heats are not drawn are left out, and events without any drawn round list all the couples who
+// entered them.
+func (service CompetitionDocumentService) HeatSheets(competition Competition, eventID int) ([]byte, error) {
+	competitionID := competition.ID
+	events, err := service.heats.eventRepo.SearchEvent(SearchEventCriteria{CompetitionID: competitionID, EventID: eventID})
+	if err != nil {
+		return nil, err
+	}
+	if eventID > 0 && len(events) != 1 {
+		return nil, errors.New(fmt.Sprintf("cannot find event with ID = %v", eventID))
+	}
+	couples, err := service.couples(competitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]HeatSheet, 0)
+	for _, event := range events {
+		sheet := HeatSheet{CompetitionName: competition.Name, EventName: documentEventName(event)}
+		if sheet.Heats, err = service.eventHeats(event, couples); err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return service.renderer.RenderHeatSheets(sheets)
+}
+
+func (service CompetitionDocumentService) eventHeats(event Event, couples map[int]DocumentCouple) ([]HeatSheetHeat, error) {
+	rounds, err := service.heats.roundRepo.SearchRound(SearchRoundCriteria{EventID: event.ID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].Order.Rank < rounds[j].Order.Rank
+	})
+	dances, err := service.eventDanceNames(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	heats := make([]HeatSheetHeat, 0)
+	for _, round := range rounds {
+		draws, err := service.heats.drawRepo.SearchRoundHeatDraw(SearchRoundHeatDrawCriteria{RoundID: round.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(draws) == 0 {
+			continue
+		}
+		drawn, err := service.heats.splitHeats(round, event.CompetitionID, draws[0])
+		if err != nil {
+			return nil, err
+		}
+		entries, err := service.heats.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: round.ID})
+		if err != nil {
+			return nil, err
+		}
+		partnerships := make(map[int]int)
+		for _, each := range entries {
+			partnerships[each.ID] = each.PartnershipID
+		}
+		for _, each := range drawn {
+			heat := HeatSheetHeat{Round: round.Order.Rank, Dance: dances[each.EventDanceID], Number: each.Number}
+			for _, entryID := range each.Couples {
+				heat.Couples = append(heat.Couples, couples[partnerships[entryID]])
+			}
+			sortDocumentCouples(heat.Couples)
+			heats = append(heats, heat)
+		}
+	}
+	if len(heats) > 0 {
+		return heats, nil
+	}
+
+	entries, err := service.eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{EventID: event.ID})
+	if err != nil {
+		return nil, err
+	}
+	entered := HeatSheetHeat{}
+	for _, each := range entries {
+		entered.Couples = append(entered.Couples, couples[each.Couple.ID])
+	}
+	sortDocumentCouples(entered.Couples)
+	return []HeatSheetHeat{entered}, nil
+}
+
+// eventDanceNames returns the names of the dances of the event by the IDs of EventDance
+func (service CompetitionDocumentService) eventDanceNames(eventID int) (map[int]string, error) {
+	eventDances, err := service.heats.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{EventID: eventID})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string)
+	for _, each := range eventDances {
+		dances, err := service.danceRepo.SearchDance(SearchDanceCriteria{DanceID: each.DanceID})
+		if err != nil {
+			return nil, err
+		}
+		if len(dances) == 1 {
+			names[each.ID] = dances[0].Name
+		}
+	}
+	return names, nil
+}
+
+// SchoolEntrySummaries renders the entries of a school at the competition, or of every school that is represented at
+// the competition if schoolID is 0
+func (service CompetitionDocumentService) SchoolEntrySummaries(competition Competition, schoolID int) ([]byte, error) {
+	competitionID := competition.ID
+	couples, err := service.couples(competitionID)
+	if err != nil {
+		return nil, err
+	}
+	competitionEntries, err := service.heats.competitionEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	partnerships := make(map[int]int)
+	for _, each := range competitionEntries {
+		partnerships[each.ID] = each.Couple.ID
+	}
+	representations, err := service.heats.representationRepo.SearchCompetitionRepresentation(SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+
+	schools := make(map[int][]int)
+	for _, each := range representations {
+		if each.SchoolID == nil || (schoolID > 0 && *each.SchoolID != schoolID) {
+			continue
+		}
+		schools[*each.SchoolID] = append(schools[*each.SchoolID], partnerships[each.PartnershipCompetitionEntryID])
+	}
+	if schoolID > 0 && len(schools) == 0 {
+		return nil, errors.New(fmt.Sprintf("school %v is not represented at this competition", schoolID))
+	}
+
+	summaries := make([]SchoolEntrySummary, 0)
+	for id, members := range schools {
+		results, err := service.schoolRepo.SearchSchool(SearchSchoolCriteria{ID: id})
+		if err != nil {
+			return nil, err
+		}
+		summary := SchoolEntrySummary{CompetitionName: competition.Name, SchoolName: fmt.Sprintf("School %v", id)}
+		if len(results) == 1 {
+			summary.SchoolName = results[0].Name
+		}
+		for _, partnershipID := range members {
+			entry := SchoolEntry{Couple: couples[partnershipID], Events: make([]string, 0)}
+			eventEntries, err := service.eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{CompetitionID: competitionID, PartnershipID: partnershipID})
+			if err != nil {
+				return nil, err
+			}
+			for _, each := range eventEntries {
+				entry.Events = append(entry.Events, documentEventName(each.Event))
+			}
+			sort.Strings(entry.Events)
+			summary.Entries = append(summary.Entries, entry)
+		}
+		sort.Slice(summary.Entries, func(i, j int) bool {
+			return lessDocumentCouple(summary.Entries[i].Couple, summary.Entries[j].Couple)
+		})
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].SchoolName < summaries[j].SchoolName
+	})
+	return service.renderer.RenderSchoolEntrySummaries(summaries)
+}
+
+// couples returns the couples who entered the competition by the IDs of their partnerships
+func (service CompetitionDocumentService) couples(competitionID int) (map[int]DocumentCouple, error) {
+	entries, err := service.heats.competitionEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	collection, err := service.tagRepo.SearchCompetitionLeadTag(SearchCompetitionLeadTagCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[int]int)
+	for _, each := range collection.Tags() {
+		tags[each.LeadID] = each.Tag
+	}
+
+	names := make(map[int]string)
+	couples := make(map[int]DocumentCouple)
+	for _, each := range entries {
+		couple := DocumentCouple{PartnershipID: each.Couple.ID, Tag: tags[each.Couple.Lead.ID]}
+		if couple.LeadName, err = service.accountName(each.Couple.Lead.ID, names); err != nil {
+			return nil, err
+		}
+		if couple.FollowName, err = service.accountName(each.Couple.Follow.ID, names); err != nil {
+			return nil, err
+		}
+		couples[each.Couple.ID] = couple
+	}
+	return couples, nil
+}
+
+func (service CompetitionDocumentService) accountName(accountID int, names map[int]string) (string, error) {
+	if name, ok := names[accountID]; ok {
+		return name, nil
+	}
+	accounts, err := service.accountRepo.SearchAccount(SearchAccountCriteria{ID: accountID})
+	if err != nil {
+		return "", err
+	}
+	if len(accounts) == 1 {
+		names[accountID] = accounts[0].FullName()
+	}
+	return names[accountID], nil
+}
+
+func documentEventName(event Event) string {
+	if event.Description != "" {
+		return event.Description
+	}
+	if name := strings.Join(strings.Fields(event.ToString()), " "); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Event %v", event.ID)
+}
+
+// lessDocumentCouple orders couples by their numbers, and couples without numbers last
+func lessDocumentCouple(a, b DocumentCouple) bool {
+	if (a.Tag == 0) != (b.Tag == 0) {
+		return b.Tag == 0
+	}
+	if a.Tag != b.Tag {
+		return a.Tag < b.Tag
+	}
+	return a.LeadName < b.LeadName
+}
+
+func sortDocumentCouples(couples []DocumentCouple) {
+	sort.Slice(couples, func(i, j int) bool {
+		return lessDocumentCouple(couples[i], couples[j])
+	})
+}
@@ -0,0 +1,209 @@
+package businesslogic_test
+
+import (
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// documentCompetition is competition 3, whose documents are printed
+func documentCompetition() businesslogic.Competition {
+	return businesslogic.Competition{ID: 3, Name: "Ohio Star Ball", CreateUserID: 41}
+}
+
+// documentCouples returns the entries of partnership 101, 102 and 103 at competition 3. Athletes 11, 12 and 13 lead
+// the couples, and athletes 21, 22 and 23 follow.
+func documentCouples() []businesslogic.PartnershipCompetitionEntry {
+	entries := make([]businesslogic.PartnershipCompetitionEntry, 0)
+	for i := 1; i <= 3; i++ {
+		entries = append(entries, businesslogic.PartnershipCompetitionEntry{ID: 200 + i, Couple: businesslogic.Partnership{
+			ID:     100 + i,
+			Lead:   businesslogic.Account{ID: 10 + i},
+			Follow: businesslogic.Account{ID: 20 + i},
+		}})
+	}
+	return entries
+}
+
+// documentTags returns the tags of competition 3, where only athlete 11 and 12 have tags
+func documentTags() businesslogic.CompetitionLeadTagCollection {
+	return leadTags(
+		businesslogic.CompetitionLeadTag{LeadID: 12, Tag: 102},
+		businesslogic.CompetitionLeadTag{LeadID: 11, Tag: 101},
+	)
+}
+
+func documentAthlete(id int) []businesslogic.Account {
+	return []businesslogic.Account{{ID: id, FirstName: "Athlete", LastName: fmt.Sprint(id)}}
+}
+
+func TestCompetitionDocumentService_BackNumbers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	renderer := mock_businesslogic.NewMockIDocumentRenderer(mockCtrl)
+	heats := businesslogic.NewHeatDrawService(
+		mock_businesslogic.NewMockIEventRepository(mockCtrl),
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl))
+	service := businesslogic.NewCompetitionDocumentService(accountRepo,
+		mock_businesslogic.NewMockIDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockISchoolRepository(mockCtrl),
+		tagRepo, heats, renderer)
+
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(documentTags(), nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 11}).Return(documentAthlete(11), nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 12}).Return(documentAthlete(12), nil)
+	renderer.EXPECT().RenderBackNumbers([]businesslogic.BackNumber{
+		{CompetitionName: "Ohio Star Ball", Tag: 101, LeadName: "Athlete 11"},
+		{CompetitionName: "Ohio Star Ball", Tag: 102, LeadName: "Athlete 12"},
+	}).Return([]byte("%PDF"), nil)
+
+	document, err := service.BackNumbers(documentCompetition())
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("%PDF"), document)
+}
+
+func TestCompetitionDocumentService_HeatSheets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	drawRepo := mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl)
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	danceRepo := mock_businesslogic.NewMockIDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	renderer := mock_businesslogic.NewMockIDocumentRenderer(mockCtrl)
+	heats := businesslogic.NewHeatDrawService(eventRepo, eventDanceRepo, roundRepo, partnershipEntryRepo,
+		competitionEntryRepo, representationRepo, drawRepo)
+	service := businesslogic.NewCompetitionDocumentService(accountRepo, danceRepo, eventEntryRepo,
+		mock_businesslogic.NewMockISchoolRepository(mockCtrl), tagRepo, heats, renderer)
+
+	// couples are listed for the sheets, and their studios are looked up again when the heats are split
+	competitionEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return(documentCouples(), nil).Times(2)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(documentTags(), nil)
+	for _, id := range []int{11, 12, 13, 21, 22, 23} {
+		accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: id}).Return(documentAthlete(id), nil)
+	}
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3}).Return([]businesslogic.Event{
+		{ID: 5, CompetitionID: 3, Description: "Gold Latin"},
+		{ID: 6, CompetitionID: 3, Description: "Bronze Standard"},
+	}, nil)
+
+	// heats of the first round of event 5 are drawn
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: 5}).Return([]businesslogic.Round{
+		{ID: 10, EventID: 5, Order: businesslogic.RoundOrder{Rank: 2}},
+		{ID: 9, EventID: 5, Order: businesslogic.RoundOrder{Rank: 1}},
+	}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1, DanceID: 7}}, nil).Times(2)
+	danceRepo.EXPECT().SearchDance(businesslogic.SearchDanceCriteria{DanceID: 7}).Return([]businesslogic.Dance{{ID: 7, Name: "Cha Cha"}}, nil)
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 9}).Return([]businesslogic.RoundHeatDraw{{RoundID: 9, Seed: 2018, FloorCapacity: 6}}, nil)
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 10}).Return([]businesslogic.RoundHeatDraw{}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 9}).Return([]businesslogic.PartnershipRoundEntry{
+		{ID: 1, PartnershipID: 103}, {ID: 2, PartnershipID: 102}, {ID: 3, PartnershipID: 101},
+	}, nil).Times(2)
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: 3}).Return([]businesslogic.PartnershipCompetitionRepresentation{}, nil)
+
+	// heats of event 6 are not drawn
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: 6}).Return([]businesslogic.Round{}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 6}).Return([]businesslogic.EventDance{}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 6}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 102}},
+	}, nil)
+
+	renderer.EXPECT().RenderHeatSheets(gomock.Any()).Do(func(sheets []businesslogic.HeatSheet) {
+		if !assert.Len(t, sheets, 2) {
+			return
+		}
+		assert.Equal(t, "Gold Latin", sheets[0].EventName)
+		if assert.Len(t, sheets[0].Heats, 1, "rounds without drawn heats should be left out") {
+			heat := sheets[0].Heats[0]
+			assert.Equal(t, 1, heat.Round)
+			assert.Equal(t, "Cha Cha", heat.Dance)
+			if assert.Len(t, heat.Couples, 3) {
+				assert.Equal(t, businesslogic.DocumentCouple{PartnershipID: 101, Tag: 101, LeadName: "Athlete 11", FollowName: "Athlete 21"}, heat.Couples[0])
+				assert.Equal(t, 0, heat.Couples[2].Tag, "couples without numbers should be listed last")
+			}
+		}
+		if assert.Len(t, sheets[1].Heats, 1, "events without drawn heats should list their entries") {
+			assert.Equal(t, 0, sheets[1].Heats[0].Round)
+			assert.Equal(t, 102, sheets[1].Heats[0].Couples[0].Tag)
+		}
+	}).Return([]byte("%PDF"), nil)
+
+	_, err := service.HeatSheets(documentCompetition(), 0)
+	assert.Nil(t, err)
+}
+
+func TestCompetitionDocumentService_SchoolEntrySummaries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	schoolRepo := mock_businesslogic.NewMockISchoolRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	renderer := mock_businesslogic.NewMockIDocumentRenderer(mockCtrl)
+	heats := businesslogic.NewHeatDrawService(
+		mock_businesslogic.NewMockIEventRepository(mockCtrl),
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		competitionEntryRepo, representationRepo,
+		mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl))
+	service := businesslogic.NewCompetitionDocumentService(accountRepo,
+		mock_businesslogic.NewMockIDanceRepository(mockCtrl), eventEntryRepo, schoolRepo, tagRepo, heats, renderer)
+
+	// both summaries list the couples, and look up the entries of the couples again to find their schools
+	school, studio := 1, 2
+	competitionEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return(documentCouples(), nil).Times(4)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(documentTags(), nil).Times(2)
+	for _, id := range []int{11, 12, 13, 21, 22, 23} {
+		accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: id}).Return(documentAthlete(id), nil).Times(2)
+	}
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: 3}).Return([]businesslogic.PartnershipCompetitionRepresentation{
+		{PartnershipCompetitionEntryID: 201, SchoolID: &school},
+		{PartnershipCompetitionEntryID: 202, StudioID: &studio},
+		{PartnershipCompetitionEntryID: 203, SchoolID: &school},
+	}, nil).Times(2)
+
+	_, err := service.SchoolEntrySummaries(documentCompetition(), 5)
+	assert.NotNil(t, err, "schools that are not represented at the competition have no entries")
+
+	schoolRepo.EXPECT().SearchSchool(businesslogic.SearchSchoolCriteria{ID: 1}).Return([]businesslogic.School{{ID: 1, Name: "Ohio State University"}}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{CompetitionID: 3, PartnershipID: 101}).Return([]businesslogic.PartnershipEventEntry{
+		{Event: businesslogic.Event{ID: 6, Description: "Bronze Standard"}},
+		{Event: businesslogic.Event{ID: 5, Description: "Gold Latin"}},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{CompetitionID: 3, PartnershipID: 103}).Return([]businesslogic.PartnershipEventEntry{}, nil)
+	renderer.EXPECT().RenderSchoolEntrySummaries(gomock.Any()).Do(func(summaries []businesslogic.SchoolEntrySummary) {
+		if assert.Len(t, summaries, 1, "couples who only represent studios should not be listed") {
+			assert.Equal(t, "Ohio State University", summaries[0].SchoolName)
+			if assert.Len(t, summaries[0].Entries, 2) {
+				assert.Equal(t, 101, summaries[0].Entries[0].Couple.Tag)
+				assert.Equal(t, []string{"Bronze Standard", "Gold Latin"}, summaries[0].Entries[0].Events)
+			}
+		}
+	}).Return([]byte("%PDF"), nil)
+
+	_, err = service.SchoolEntrySummaries(documentCompetition(), 0)
+	assert.Nil(t, err)
+}
@@ -307,13 +307,14 @@ func (service OrganizerEventService) generateTemplateEvents(templateID int) ([]E
 	return events, nil
 }
 
-func (service OrganizerEventService) GenerateEventsFromTemplate(competitionID int, templateID int) error {
+func (service OrganizerEventService) GenerateEventsFromTemplate(competition Competition, templateID int) error {
 	events, err := service.generateTemplateEvents(templateID)
 	if err != nil {
 		return err
 	}
 	for i := 0; i < len(events); i++ {
-		genErr := service.CreateEvent(&events[i])
+		events[i].CompetitionID = competition.ID
+		genErr := service.CreateEvent(competition, &events[i])
 		if genErr != nil {
 			return genErr
 		}
@@ -325,7 +326,12 @@ func (service OrganizerEventService) SearchCompetitionEventTemplate(criteria Sea
 	return service.eventTemplateRepo.SearchCompetitionEventTemplates(criteria)
 }
 
-func (service OrganizerEventService) CreateEvent(event *Event) error {
+// CreateEvent creates the event for the competition that the organizer is authorized to change. The event must belong
+// to the competition.
+func (service OrganizerEventService) CreateEvent(competition Competition, event *Event) error {
+	if event.CompetitionID != competition.ID {
+		return errors.New(fmt.Sprintf("event does not belong to competition %v", competition.ID))
+	}
 	searchResults, searchErr := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: event.CompetitionID})
 	if len(searchResults) != 1 {
 		return errors.New(fmt.Sprintf("cannot find competition with ID = %d", event.CompetitionID))
@@ -334,14 +340,11 @@ func (service OrganizerEventService) CreateEvent(event *Event) error {
 		return searchErr
 	}
 
-	competition := searchResults[0]
+	competition = searchResults[0]
 
 	// check if competition is still at the right status
 	if competition.GetStatus() != CompetitionStatusPreRegistration {
 		return errors.New("events can only be added when competition is in pre-registration")
-	} else if competition.CreateUserID != event.CreateUserID {
-		// Only the creator/owner of the competition can create events for the competition.
-		return errors.New("not authorized to create event for this competition")
 	}
 
 	validationErr := service.ValidateEvent(*event)
@@ -484,7 +487,12 @@ func (service OrganizerEventService) ValidateEvent(event Event) error {
 	return nil
 }
 
-func (service OrganizerEventService) DeleteEvent(event Event, currentUser Account) error {
+// DeleteEvent deletes the event of the competition that the organizer is authorized to change. The event must belong
+// to the competition.
+func (service OrganizerEventService) DeleteEvent(competition Competition, event Event) error {
+	if event.CompetitionID != competition.ID {
+		return errors.New(fmt.Sprintf("event %v does not belong to competition %v", event.ID, competition.ID))
+	}
 	competitions, searchCompErr := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: event.CompetitionID})
 	if searchCompErr != nil {
 		return searchCompErr
@@ -495,9 +503,6 @@ func (service OrganizerEventService) DeleteEvent(event Event, currentUser Accoun
 	if competitions[0].GetStatus() == CompetitionStatusClosed {
 		return errors.New("the competition is concluded")
 	}
-	if event.CreateUserID != currentUser.ID {
-		return errors.New("not authorized to delete this event")
-	}
 	if event.StatusID == EVENT_STATUS_RUNNING {
 		return errors.New("event is running and cannot be deleted")
 	}
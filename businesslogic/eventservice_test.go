@@ -0,0 +1,29 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOrganizerEventService_CreateAndDeleteEvent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	service := businesslogic.NewOrganizerEventService(nil, nil, competitionRepo, eventRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	competition := businesslogic.Competition{ID: 3, CreateUserID: 41}
+	competition.UpdateStatus(businesslogic.CompetitionStatusPreRegistration)
+	event := businesslogic.Event{ID: 5, CompetitionID: 4, StatusID: businesslogic.EVENT_STATUS_DRAFT}
+	assert.NotNil(t, service.DeleteEvent(competition, event), "events of other competitions should not be deleted")
+	assert.NotNil(t, service.CreateEvent(competition, &event), "events should not be created for other competitions")
+
+	event.CompetitionID = 3
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{competition}, nil)
+	eventRepo.EXPECT().DeleteEvent(event).Return(nil)
+	assert.Nil(t, service.DeleteEvent(competition, event))
+}
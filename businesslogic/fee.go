@@ -0,0 +1,530 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CompetitionFeeSchedule specifies how much athletes pay to enter a competition. Every athlete pays the base fee once,
+// and the per-event fee for each event that the athlete dances in. Fees of entries that are made before the early
+// registration deadline are discounted, and fees of entries that are made after the late registration starts are
+// surcharged. Athletes who represent a school at the competition are given the student discount. Rates are percentages.
+type CompetitionFeeSchedule struct {
+	ID                        int
+	CompetitionID             int
+	BaseFee                   float64
+	PerEventFee               float64
+	EarlyRegistrationDeadline *time.Time // no early pricing if not specified
+	EarlyDiscountRate         float64
+	LateRegistrationStart     *time.Time // no late pricing if not specified
+	LateSurchargeRate         float64
+	StudentDiscountRate       float64
+	CreateUserID              int
+	DateTimeCreated           time.Time
+	UpdateUserID              int
+	DateTimeUpdated           time.Time
+}
+
+// SearchCompetitionFeeScheduleCriteria specifies the parameters that can be used to search fee schedules
+type SearchCompetitionFeeScheduleCriteria struct {
+	CompetitionID int `schema:"competition,required"`
+}
+
+// ICompetitionFeeScheduleRepository specifies the functions that a CompetitionFeeSchedule Repository should implement
+type ICompetitionFeeScheduleRepository interface {
+	CreateCompetitionFeeSchedule(schedule *CompetitionFeeSchedule) error
+	SearchCompetitionFeeSchedule(criteria SearchCompetitionFeeScheduleCriteria) ([]CompetitionFeeSchedule, error)
+	UpdateCompetitionFeeSchedule(schedule CompetitionFeeSchedule) error
+}
+
+// Validate checks if the fees are valid, and if the early and late pricing are within the registration period of the
+// competition
+func (schedule CompetitionFeeSchedule) Validate(competition Competition) error {
+	if schedule.BaseFee < 0 || schedule.PerEventFee < 0 {
+		return errors.New("fees cannot be negative")
+	}
+	for _, rate := range []float64{schedule.EarlyDiscountRate, schedule.LateSurchargeRate, schedule.StudentDiscountRate} {
+		if rate < 0 || rate > 100 {
+			return errors.New("discount and surcharge rates must be between 0 and 100")
+		}
+	}
+	if schedule.EarlyRegistrationDeadline != nil {
+		if !schedule.EarlyRegistrationDeadline.After(competition.RegistrationOpenDateTime) ||
+			schedule.EarlyRegistrationDeadline.After(competition.RegistrationCloseDateTime) {
+			return errors.New("early registration deadline must be within the registration period of competition")
+		}
+	}
+	if schedule.LateRegistrationStart != nil {
+		if !schedule.LateRegistrationStart.After(competition.RegistrationOpenDateTime) ||
+			schedule.LateRegistrationStart.After(competition.RegistrationCloseDateTime) {
+			return errors.New("late registration must start within the registration period of competition")
+		}
+	}
+	if schedule.EarlyRegistrationDeadline != nil && schedule.LateRegistrationStart != nil &&
+		schedule.LateRegistrationStart.Before(*schedule.EarlyRegistrationDeadline) {
+		return errors.New("late registration cannot start before the early registration deadline")
+	}
+	return nil
+}
+
+// AdjustmentRate returns the percentage that is added to fees of entries that are made at the specified time. Early
+// entries have a negative rate.
+func (schedule CompetitionFeeSchedule) AdjustmentRate(registered time.Time) float64 {
+	if schedule.EarlyRegistrationDeadline != nil && registered.Before(*schedule.EarlyRegistrationDeadline) {
+		return -schedule.EarlyDiscountRate
+	}
+	if schedule.LateRegistrationStart != nil && !registered.Before(*schedule.LateRegistrationStart) {
+		return schedule.LateSurchargeRate
+	}
+	return 0
+}
+
+func roundToCent(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Item returns the invoice item of a fee for an entry that is made at the specified time
+func (schedule CompetitionFeeSchedule) Item(description string, fee float64, registered time.Time, student bool) InvoiceItem {
+	item := InvoiceItem{
+		Description: description,
+		Fee:         fee,
+		Adjustment:  roundToCent(fee * schedule.AdjustmentRate(registered) / 100),
+	}
+	if student {
+		item.StudentDiscount = roundToCent((item.Fee + item.Adjustment) * schedule.StudentDiscountRate / 100)
+	}
+	item.Amount = roundToCent(item.Fee + item.Adjustment - item.StudentDiscount)
+	return item
+}
+
+// InvoiceItem is a fee in an invoice. The amount is the fee after the early registration discount or the late
+// registration surcharge (Adjustment) and the student discount.
+type InvoiceItem struct {
+	Description     string
+	AthleteID       int
+	EventID         int // the base fee does not have an event
+	Fee             float64
+	Adjustment      float64
+	StudentDiscount float64
+	Amount          float64
+}
+
+// Invoice is the itemized registration fee of an athlete or a partnership at a competition. Invoices are generated
+// from the entries of the athlete or partnership, so they change as events are added and dropped.
+type Invoice struct {
+	CompetitionID int
+	AthleteID     int
+	PartnershipID int
+	Items         []InvoiceItem
+	Total         float64
+	AmountPaid    float64
+	Balance       float64
+}
+
+func (invoice *Invoice) addItem(item InvoiceItem) {
+	invoice.Items = append(invoice.Items, item)
+	invoice.Total = roundToCent(invoice.Total + item.Amount)
+	invoice.Balance = roundToCent(invoice.Total - invoice.AmountPaid)
+}
+
+// IsPaid returns true if the payments of the invoice have covered its total
+func (invoice Invoice) IsPaid() bool {
+	return invoice.Balance < 0.005
+}
+
+// RegistrationFeeService manages the fee schedules of competitions, and generates the invoices of athletes and
+// partnerships from their entries. It implements IRegistrationFeeCalculator so that athletes can pay their invoices.
+type RegistrationFeeService struct {
+	scheduleRepo              ICompetitionFeeScheduleRepository
+	competitionRepo           ICompetitionRepository
+	eventRepo                 IEventRepository
+	athleteEntryRepo          IAthleteCompetitionEntryRepository
+	partnershipEntryRepo      IPartnershipCompetitionEntryRepository
+	partnershipEventEntryRepo IPartnershipEventEntryRepository
+	representationRepo        IPartnershipCompetitionRepresentationRepository
+	paymentRepo               IPaymentRepository
+}
+
+func NewRegistrationFeeService(
+	scheduleRepo ICompetitionFeeScheduleRepository,
+	competitionRepo ICompetitionRepository,
+	eventRepo IEventRepository,
+	athleteEntryRepo IAthleteCompetitionEntryRepository,
+	partnershipEntryRepo IPartnershipCompetitionEntryRepository,
+	partnershipEventEntryRepo IPartnershipEventEntryRepository,
+	representationRepo IPartnershipCompetitionRepresentationRepository,
+	paymentRepo IPaymentRepository) RegistrationFeeService {
+	return RegistrationFeeService{
+		scheduleRepo:              scheduleRepo,
+		competitionRepo:           competitionRepo,
+		eventRepo:                 eventRepo,
+		athleteEntryRepo:          athleteEntryRepo,
+		partnershipEntryRepo:      partnershipEntryRepo,
+		partnershipEventEntryRepo: partnershipEventEntryRepo,
+		representationRepo:        representationRepo,
+		paymentRepo:               paymentRepo,
+	}
+}
+
+// GetFeeSchedule returns the fee schedule of a competition
+func (service RegistrationFeeService) GetFeeSchedule(competitionID int) (CompetitionFeeSchedule, error) {
+	schedules, err := service.scheduleRepo.SearchCompetitionFeeSchedule(SearchCompetitionFeeScheduleCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return CompetitionFeeSchedule{}, err
+	}
+	if len(schedules) != 1 {
+		return CompetitionFeeSchedule{}, errors.New(fmt.Sprintf("fee schedule of competition %d is not set", competitionID))
+	}
+	return schedules[0], nil
+}
+
+// SaveFeeSchedule creates or replaces the fee schedule of a competition
+func (service RegistrationFeeService) SaveFeeSchedule(currentUser Account, schedule *CompetitionFeeSchedule) error {
+	competitions, err := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: schedule.CompetitionID})
+	if err != nil {
+		return err
+	}
+	if len(competitions) != 1 {
+		return errors.New(fmt.Sprintf("cannot find competition with ID = %d", schedule.CompetitionID))
+	}
+	if err := schedule.Validate(competitions[0]); err != nil {
+		return err
+	}
+
+	existing, err := service.scheduleRepo.SearchCompetitionFeeSchedule(SearchCompetitionFeeScheduleCriteria{CompetitionID: schedule.CompetitionID})
+	if err != nil {
+		return err
+	}
+	schedule.UpdateUserID = currentUser.ID
+	schedule.DateTimeUpdated = time.Now()
+	if len(existing) > 0 {
+		schedule.ID = existing[0].ID
+		schedule.CreateUserID = existing[0].CreateUserID
+		schedule.DateTimeCreated = existing[0].DateTimeCreated
+		return service.scheduleRepo.UpdateCompetitionFeeSchedule(*schedule)
+	}
+	schedule.CreateUserID = currentUser.ID
+	schedule.DateTimeCreated = time.Now()
+	return service.scheduleRepo.CreateCompetitionFeeSchedule(schedule)
+}
+
+// competitionEvents returns the events of the competition by their IDs
+func (service RegistrationFeeService) competitionEvents(competitionID int) (map[int]Event, error) {
+	events, err := service.eventRepo.SearchEvent(SearchEventCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	output := make(map[int]Event)
+	for _, each := range events {
+		output[each.ID] = each
+	}
+	return output, nil
+}
+
+// isStudent returns true if any partnership of the athlete represents a school at the competition
+func (service RegistrationFeeService) isStudent(competitionID, athleteID int, entries []PartnershipCompetitionEntry) (bool, error) {
+	for _, each := range entries {
+		if !each.Couple.HasAthlete(athleteID) {
+			continue
+		}
+		representations, err := service.representationRepo.SearchCompetitionRepresentation(SearchPartnershipCompetitionRepresentationCriteria{
+			CompetitionID:                 competitionID,
+			PartnershipCompetitionEntryID: each.ID,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, representation := range representations {
+			if representation.SchoolID != nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// addEventItems adds the per-event fee of the athlete for each event that the partnership has entered
+func (service RegistrationFeeService) addEventItems(invoice *Invoice, schedule CompetitionFeeSchedule, events map[int]Event, partnership Partnership, athlete Account, student bool) error {
+	entries, err := service.partnershipEventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{
+		CompetitionID: schedule.CompetitionID,
+		PartnershipID: partnership.ID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, each := range entries {
+		event, ok := events[each.Event.ID]
+		if !ok {
+			continue
+		}
+		description := event.Description
+		if description == "" {
+			description = fmt.Sprintf("Event %d", event.ID)
+		}
+		item := schedule.Item(fmt.Sprintf("%v (%v)", description, athlete.FullName()), schedule.PerEventFee, each.DateTimeCreated, student)
+		item.AthleteID = athlete.ID
+		item.EventID = event.ID
+		invoice.addItem(item)
+	}
+	return nil
+}
+
+// amountPaid returns the amount that has been paid for the competition entry and has not been refunded
+func (service RegistrationFeeService) amountPaid(entryID int) (float64, error) {
+	payments, err := service.paymentRepo.SearchPayment(SearchPaymentCriteria{
+		Purpose:     PaymentPurposeRegistrationFee,
+		ReferenceID: entryID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	paid := 0.0
+	for _, each := range payments {
+		if each.StatusID == PaymentStatusSucceeded || each.StatusID == PaymentStatusRefunded {
+			paid += each.Amount - each.RefundedAmount
+		}
+	}
+	return roundToCent(paid), nil
+}
+
+// competitionFees is what the invoices of a competition are generated from
+type competitionFees struct {
+	schedule           CompetitionFeeSchedule
+	events             map[int]Event
+	partnershipEntries []PartnershipCompetitionEntry
+}
+
+func (service RegistrationFeeService) loadCompetitionFees(schedule CompetitionFeeSchedule) (competitionFees, error) {
+	fees := competitionFees{schedule: schedule}
+	events, err := service.competitionEvents(schedule.CompetitionID)
+	if err != nil {
+		return fees, err
+	}
+	fees.events = events
+	fees.partnershipEntries, err = service.partnershipEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{CompetitionID: schedule.CompetitionID})
+	return fees, err
+}
+
+// athleteInvoice generates the invoice of the competition entry of an athlete
+func (service RegistrationFeeService) athleteInvoice(fees competitionFees, entry AthleteCompetitionEntry) (Invoice, error) {
+	invoice := Invoice{CompetitionID: fees.schedule.CompetitionID, AthleteID: entry.Athlete.ID, Items: make([]InvoiceItem, 0)}
+	paid, err := service.amountPaid(entry.ID)
+	if err != nil {
+		return invoice, err
+	}
+	invoice.AmountPaid = paid
+	student, err := service.isStudent(fees.schedule.CompetitionID, entry.Athlete.ID, fees.partnershipEntries)
+	if err != nil {
+		return invoice, err
+	}
+
+	base := fees.schedule.Item(fmt.Sprintf("Registration fee (%v)", entry.Athlete.FullName()), fees.schedule.BaseFee, entry.DateTimeCreated, student)
+	base.AthleteID = entry.Athlete.ID
+	invoice.addItem(base)
+	for _, each := range fees.partnershipEntries {
+		if !each.Couple.HasAthlete(entry.Athlete.ID) {
+			continue
+		}
+		athlete := each.Couple.Lead
+		if each.Couple.Follow.ID == entry.Athlete.ID {
+			athlete = each.Couple.Follow
+		}
+		if err := service.addEventItems(&invoice, fees.schedule, fees.events, each.Couple, athlete, student); err != nil {
+			return invoice, err
+		}
+	}
+	return invoice, nil
+}
+
+// invoiceOfEntry generates the invoice of the competition entry of an athlete with the fee schedule of the competition
+func (service RegistrationFeeService) invoiceOfEntry(schedule CompetitionFeeSchedule, entry AthleteCompetitionEntry) (Invoice, error) {
+	fees, err := service.loadCompetitionFees(schedule)
+	if err != nil {
+		return Invoice{}, err
+	}
+	return service.athleteInvoice(fees, entry)
+}
+
+// competitionInvoices generates the invoices of all the athletes who have entered a competition, in the same order as
+// the returned entries. No invoices are returned if the competition does not have a fee schedule.
+func (service RegistrationFeeService) competitionInvoices(competitionID int) ([]AthleteCompetitionEntry, []Invoice, error) {
+	entries, err := service.athleteEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, nil, err
+	}
+	invoices := make([]Invoice, 0)
+	schedules, err := service.scheduleRepo.SearchCompetitionFeeSchedule(SearchCompetitionFeeScheduleCriteria{CompetitionID: competitionID})
+	if err != nil || len(schedules) != 1 {
+		return entries, invoices, err
+	}
+	fees, err := service.loadCompetitionFees(schedules[0])
+	if err != nil {
+		return entries, invoices, err
+	}
+	for _, each := range entries {
+		invoice, err := service.athleteInvoice(fees, each)
+		if err != nil {
+			return entries, invoices, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return entries, invoices, nil
+}
+
+func (service RegistrationFeeService) getAthleteEntry(competitionID, athleteID int) (AthleteCompetitionEntry, error) {
+	entries, err := service.athleteEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{
+		CompetitionID: competitionID,
+		AthleteID:     athleteID,
+	})
+	if err != nil {
+		return AthleteCompetitionEntry{}, err
+	}
+	if len(entries) != 1 {
+		return AthleteCompetitionEntry{}, errors.New(fmt.Sprintf("athlete %d has not entered competition %d", athleteID, competitionID))
+	}
+	return entries[0], nil
+}
+
+// GetAthleteInvoice returns the invoice of an athlete at a competition
+func (service RegistrationFeeService) GetAthleteInvoice(competitionID, athleteID int) (Invoice, error) {
+	schedule, err := service.GetFeeSchedule(competitionID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	entry, err := service.getAthleteEntry(competitionID, athleteID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	return service.invoiceOfEntry(schedule, entry)
+}
+
+// GetPartnershipInvoice returns the event fees of both athletes of a partnership at a competition. Base fees are paid
+// by athletes rather than partnerships, and so are payments, so the invoice only includes the events of the
+// partnership and does not have payments.
+func (service RegistrationFeeService) GetPartnershipInvoice(competitionID, partnershipID int) (Invoice, error) {
+	entry, err := service.getPartnershipEntry(competitionID, partnershipID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	return service.partnershipInvoice(competitionID, partnershipID, entry.Couple)
+}
+
+// GetOwnPartnershipInvoice returns the invoice of a partnership at a competition if the current user is one of its
+// athletes
+func (service RegistrationFeeService) GetOwnPartnershipInvoice(currentUser Account, competitionID, partnershipID int) (Invoice, error) {
+	entry, err := service.getPartnershipEntry(competitionID, partnershipID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	if !entry.Couple.HasAthlete(currentUser.ID) {
+		return Invoice{}, errors.New("not authorized to view this invoice")
+	}
+	return service.partnershipInvoice(competitionID, partnershipID, entry.Couple)
+}
+
+// getPartnershipEntry returns the competition entry of the partnership
+func (service RegistrationFeeService) getPartnershipEntry(competitionID, partnershipID int) (PartnershipCompetitionEntry, error) {
+	entries, err := service.partnershipEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{
+		CompetitionID: competitionID,
+		PartnershipID: partnershipID,
+	})
+	if err != nil {
+		return PartnershipCompetitionEntry{}, err
+	}
+	if len(entries) != 1 {
+		return PartnershipCompetitionEntry{}, errors.New(fmt.Sprintf("partnership %d has not entered competition %d", partnershipID, competitionID))
+	}
+	return entries[0], nil
+}
+
+// partnershipInvoice returns the event fees of both athletes of the partnership at the competition
+func (service RegistrationFeeService) partnershipInvoice(competitionID, partnershipID int, couple Partnership) (Invoice, error) {
+	schedule, err := service.GetFeeSchedule(competitionID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	fees, err := service.loadCompetitionFees(schedule)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	invoice := Invoice{CompetitionID: competitionID, PartnershipID: partnershipID, Items: make([]InvoiceItem, 0)}
+	for _, athlete := range []Account{couple.Lead, couple.Follow} {
+		student, err := service.isStudent(competitionID, athlete.ID, fees.partnershipEntries)
+		if err != nil {
+			return invoice, err
+		}
+		if err := service.addEventItems(&invoice, schedule, fees.events, couple, athlete, student); err != nil {
+			return invoice, err
+		}
+	}
+	return invoice, nil
+}
+
+// CalculateRegistrationFee returns the items of the invoice of the competition entry. Once part of the invoice has
+// been paid, only the balance is charged.
+func (service RegistrationFeeService) CalculateRegistrationFee(entry AthleteCompetitionEntry) ([]PaymentLineItem, error) {
+	schedule, err := service.GetFeeSchedule(entry.Competition.ID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := service.invoiceOfEntry(schedule, entry)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]PaymentLineItem, 0)
+	if invoice.IsPaid() {
+		return items, nil
+	}
+	if invoice.AmountPaid > 0 {
+		return append(items, PaymentLineItem{Name: "Balance of registration fee", UnitAmount: invoice.Balance, Quantity: 1}), nil
+	}
+	for _, each := range invoice.Items {
+		if each.Amount > 0 {
+			items = append(items, PaymentLineItem{Name: each.Description, UnitAmount: each.Amount, Quantity: 1})
+		}
+	}
+	return items, nil
+}
+
+// UpdatePaymentStatus updates whether the competition entries of the athletes are paid, after events have been added
+// or dropped. Nothing is updated if the competition does not have a fee schedule.
+func (service RegistrationFeeService) UpdatePaymentStatus(competitionID int, athleteIDs ...int) error {
+	schedules, err := service.scheduleRepo.SearchCompetitionFeeSchedule(SearchCompetitionFeeScheduleCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return err
+	}
+	if len(schedules) != 1 {
+		return nil
+	}
+	fees, err := service.loadCompetitionFees(schedules[0])
+	if err != nil {
+		return err
+	}
+	for _, athleteID := range athleteIDs {
+		entries, err := service.athleteEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{
+			CompetitionID: competitionID,
+			AthleteID:     athleteID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			invoice, err := service.athleteInvoice(fees, entry)
+			if err != nil {
+				return err
+			}
+			if entry.PaymentReceivedIndicator == invoice.IsPaid() {
+				continue
+			}
+			entry.PaymentReceivedIndicator = invoice.IsPaid()
+			entry.DateTimeUpdated = time.Now()
+			if err := service.athleteEntryRepo.UpdateEntry(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// feeCompetition is competition 3 of organizer 41, which is open for registration from January 1 to March 1
+func feeCompetition() businesslogic.Competition {
+	return businesslogic.Competition{
+		ID:                        3,
+		CreateUserID:              41,
+		RegistrationOpenDateTime:  date(time.January, 1),
+		RegistrationCloseDateTime: date(time.March, 1),
+	}
+}
+
+// feeSchedule charges 40 per athlete and 20 per event. Entries before January 15 are 25% off, entries after
+// February 15 are 50% more, and students are given 10% off.
+func feeSchedule() businesslogic.CompetitionFeeSchedule {
+	early := date(time.January, 15)
+	late := date(time.February, 15)
+	return businesslogic.CompetitionFeeSchedule{
+		ID:                        2,
+		CompetitionID:             3,
+		BaseFee:                   40,
+		PerEventFee:               20,
+		EarlyRegistrationDeadline: &early,
+		EarlyDiscountRate:         25,
+		LateRegistrationStart:     &late,
+		LateSurchargeRate:         50,
+		StudentDiscountRate:       10,
+	}
+}
+
+// leadEntry is the competition entry of athlete 12, who entered on January 10
+func leadEntry(paid bool) businesslogic.AthleteCompetitionEntry {
+	return businesslogic.AthleteCompetitionEntry{
+		ID:                       4,
+		Athlete:                  businesslogic.Account{ID: 12, FirstName: "Alice", LastName: "Smith"},
+		Competition:              businesslogic.Competition{ID: 3},
+		IsLead:                   true,
+		PaymentReceivedIndicator: paid,
+		DateTimeCreated:          date(time.January, 10),
+	}
+}
+
+// expectEntries sets up partnership 33 of athletes 12 and 13, which represents a school at competition 3, entered
+// event 5 on January 10 and event 7 on February 20. Athlete 12 has paid 40 dollars. Partnership 33 is looked up for
+// each of its athletes, times times.
+func expectFeeEntries(
+	times int,
+	paymentRepo *mock_businesslogic.MockIPaymentRepository,
+	partnershipEntryRepo *mock_businesslogic.MockIPartnershipCompetitionEntryRepository,
+	representationRepo *mock_businesslogic.MockIPartnershipCompetitionRepresentationRepository,
+	eventRepo *mock_businesslogic.MockIEventRepository,
+	partnershipEventEntryRepo *mock_businesslogic.MockIPartnershipEventEntryRepository,
+) {
+	schoolID := 1
+	couple := businesslogic.Partnership{
+		ID:     33,
+		Lead:   businesslogic.Account{ID: 12, FirstName: "Alice", LastName: "Smith"},
+		Follow: businesslogic.Account{ID: 13, FirstName: "Betty", LastName: "Jones"},
+	}
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{
+		Purpose:     businesslogic.PaymentPurposeRegistrationFee,
+		ReferenceID: 4,
+	}).Return([]businesslogic.Payment{
+		{ID: 1, Amount: 40, StatusID: businesslogic.PaymentStatusSucceeded},
+		{ID: 2, Amount: 60, StatusID: businesslogic.PaymentStatusFailed},
+	}, nil)
+	partnershipEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return([]businesslogic.PartnershipCompetitionEntry{
+		{ID: 6, Couple: couple},
+		{ID: 8, Couple: businesslogic.Partnership{ID: 34, Lead: businesslogic.Account{ID: 14}, Follow: businesslogic.Account{ID: 15}}},
+	}, nil)
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{
+		CompetitionID:                 3,
+		PartnershipCompetitionEntryID: 6,
+	}).Return([]businesslogic.PartnershipCompetitionRepresentation{{ID: 1, PartnershipCompetitionEntryID: 6, SchoolID: &schoolID}}, nil).Times(times)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3}).Return([]businesslogic.Event{
+		{ID: 5, CompetitionID: 3, Description: "Gold Waltz"},
+		{ID: 7, CompetitionID: 3, Description: "Silver Rumba"},
+	}, nil)
+	partnershipEventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{
+		CompetitionID: 3,
+		PartnershipID: 33,
+	}).Return([]businesslogic.PartnershipEventEntry{
+		{ID: 1, Event: businesslogic.Event{ID: 5}, DateTimeCreated: date(time.January, 10)},
+		{ID: 2, Event: businesslogic.Event{ID: 7}, DateTimeCreated: date(time.February, 20)},
+		{ID: 3, Event: businesslogic.Event{ID: 99}, DateTimeCreated: date(time.February, 20)},
+	}, nil).Times(times)
+}
+
+func TestCompetitionFeeSchedule_Validate(t *testing.T) {
+	schedule := feeSchedule()
+	assert.Nil(t, schedule.Validate(feeCompetition()))
+
+	schedule.StudentDiscountRate = 120
+	assert.NotNil(t, schedule.Validate(feeCompetition()), "rates cannot be more than 100%")
+
+	schedule = feeSchedule()
+	deadline := date(time.March, 10)
+	schedule.EarlyRegistrationDeadline = &deadline
+	assert.NotNil(t, schedule.Validate(feeCompetition()), "early registration deadline must be before registration closes")
+
+	schedule = feeSchedule()
+	start := date(time.January, 5)
+	schedule.LateRegistrationStart = &start
+	assert.NotNil(t, schedule.Validate(feeCompetition()), "late registration cannot start before early registration ends")
+
+	schedule = feeSchedule()
+	schedule.EarlyRegistrationDeadline = nil
+	schedule.LateRegistrationStart = nil
+	assert.Nil(t, schedule.Validate(feeCompetition()), "early and late pricing are optional")
+}
+
+func TestRegistrationFeeService_SaveFeeSchedule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	service := businesslogic.NewRegistrationFeeService(
+		scheduleRepo,
+		competitionRepo,
+		eventRepo,
+		athleteEntryRepo,
+		partnershipEntryRepo,
+		partnershipEventEntryRepo,
+		representationRepo,
+		paymentRepo,
+	)
+
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{feeCompetition()}, nil).Times(2)
+	schedule := feeSchedule()
+	schedule.ID = 0
+	schedule.StudentDiscountRate = 120
+	assert.NotNil(t, service.SaveFeeSchedule(newOrganizer(42), &schedule), "invalid fee schedules should not be saved")
+	schedule.StudentDiscountRate = 10
+
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionFeeSchedule{feeSchedule()}, nil)
+	scheduleRepo.EXPECT().UpdateCompetitionFeeSchedule(gomock.Any()).DoAndReturn(func(updated businesslogic.CompetitionFeeSchedule) error {
+		assert.Equal(t, 2, updated.ID, "existing fee schedule should be replaced")
+		assert.Equal(t, 42, updated.UpdateUserID, "co-organizers can set the fees")
+		return nil
+	})
+	assert.Nil(t, service.SaveFeeSchedule(newOrganizer(42), &schedule))
+}
+
+func TestRegistrationFeeService_GetAthleteInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	service := businesslogic.NewRegistrationFeeService(
+		scheduleRepo,
+		competitionRepo,
+		eventRepo,
+		athleteEntryRepo,
+		partnershipEntryRepo,
+		partnershipEventEntryRepo,
+		representationRepo,
+		paymentRepo,
+	)
+
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionFeeSchedule{feeSchedule()}, nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{leadEntry(true)}, nil)
+	expectFeeEntries(1, paymentRepo, partnershipEntryRepo, representationRepo, eventRepo, partnershipEventEntryRepo)
+
+	invoice, err := service.GetAthleteInvoice(3, 12)
+	assert.Nil(t, err)
+	if assert.Len(t, invoice.Items, 3, "invoice should have the base fee and the fees of events of the competition") {
+		assert.Equal(t, businesslogic.InvoiceItem{Description: "Registration fee (Alice Smith)", AthleteID: 12, Fee: 40, Adjustment: -10, StudentDiscount: 3, Amount: 27}, invoice.Items[0])
+		assert.Equal(t, businesslogic.InvoiceItem{Description: "Gold Waltz (Alice Smith)", AthleteID: 12, EventID: 5, Fee: 20, Adjustment: -5, StudentDiscount: 1.5, Amount: 13.5}, invoice.Items[1])
+		assert.Equal(t, businesslogic.InvoiceItem{Description: "Silver Rumba (Alice Smith)", AthleteID: 12, EventID: 7, Fee: 20, Adjustment: 10, StudentDiscount: 3, Amount: 27}, invoice.Items[2])
+	}
+	assert.EqualValues(t, 67.5, invoice.Total)
+	assert.EqualValues(t, 40, invoice.AmountPaid, "failed payments are not paid")
+	assert.EqualValues(t, 27.5, invoice.Balance)
+	assert.False(t, invoice.IsPaid())
+
+}
+
+func TestRegistrationFeeService_GetOwnPartnershipInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	service := businesslogic.NewRegistrationFeeService(
+		scheduleRepo,
+		competitionRepo,
+		eventRepo,
+		athleteEntryRepo,
+		partnershipEntryRepo,
+		partnershipEventEntryRepo,
+		representationRepo,
+		paymentRepo,
+	)
+
+	partnershipEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3, PartnershipID: 34}).Return([]businesslogic.PartnershipCompetitionEntry{
+		{ID: 8, Couple: businesslogic.Partnership{ID: 34, Lead: businesslogic.Account{ID: 14}, Follow: businesslogic.Account{ID: 15}}},
+	}, nil)
+	_, err := service.GetOwnPartnershipInvoice(businesslogic.Account{ID: 12}, 3, 34)
+	assert.NotNil(t, err, "athletes cannot view the invoices of partnerships of other athletes")
+}
+
+func TestRegistrationFeeService_CalculateRegistrationFee(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	service := businesslogic.NewRegistrationFeeService(
+		scheduleRepo,
+		competitionRepo,
+		eventRepo,
+		athleteEntryRepo,
+		partnershipEntryRepo,
+		partnershipEventEntryRepo,
+		representationRepo,
+		paymentRepo,
+	)
+
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionFeeSchedule{feeSchedule()}, nil)
+	expectFeeEntries(1, paymentRepo, partnershipEntryRepo, representationRepo, eventRepo, partnershipEventEntryRepo)
+
+	items, err := service.CalculateRegistrationFee(leadEntry(false))
+	assert.Nil(t, err)
+	assert.Equal(t, []businesslogic.PaymentLineItem{{Name: "Balance of registration fee", UnitAmount: 27.5, Quantity: 1}}, items, "only the balance should be charged after a payment")
+}
+
+func TestRegistrationFeeService_UpdatePaymentStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	service := businesslogic.NewRegistrationFeeService(
+		scheduleRepo,
+		competitionRepo,
+		eventRepo,
+		athleteEntryRepo,
+		partnershipEntryRepo,
+		partnershipEventEntryRepo,
+		representationRepo,
+		paymentRepo,
+	)
+
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionFeeSchedule{feeSchedule()}, nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{leadEntry(true)}, nil)
+	expectFeeEntries(1, paymentRepo, partnershipEntryRepo, representationRepo, eventRepo, partnershipEventEntryRepo)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).DoAndReturn(func(entry businesslogic.AthleteCompetitionEntry) error {
+		assert.False(t, entry.PaymentReceivedIndicator, "entry should be unpaid once added events leave a balance")
+		return nil
+	})
+	assert.Nil(t, service.UpdatePaymentStatus(3, 12))
+
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 4}).Return([]businesslogic.CompetitionFeeSchedule{}, nil)
+	assert.Nil(t, service.UpdatePaymentStatus(4, 12), "entries of competitions without fees should not be changed")
+}
@@ -0,0 +1,214 @@
+package businesslogic
+
+import (
+	"sort"
+)
+
+// ProductCategoryRevenue is what the products of a category have made at a competition. Orders and quantity include
+// the orders that were paid and refunded later.
+type ProductCategoryRevenue struct {
+	CategoryID   int
+	CategoryName string
+	Orders       int
+	Quantity     int
+	Sales        float64
+	Refunds      float64
+	Net          float64
+}
+
+// AthleteBalance is the registration fee that an athlete is invoiced at a competition, and how much of it is paid
+type AthleteBalance struct {
+	EntryID     int
+	AthleteID   int
+	AthleteName string
+	Total       float64
+	AmountPaid  float64
+	Balance     float64
+}
+
+// FinanceReport summarizes the money that a competition has received from product orders and registration fees.
+// Sales are what has been paid, and refunds are what has been paid back, so the net revenue is sales less refunds.
+type FinanceReport struct {
+	CompetitionID        int
+	ProductRevenue       []ProductCategoryRevenue
+	RegistrationInvoiced float64
+	RegistrationSales    float64
+	RegistrationRefunds  float64
+	OutstandingBalances  []AthleteBalance // athletes who have not paid their invoices in full
+	UnpaidEntries        []AthleteBalance // athletes who have entered the competition but have never paid
+	Refunds              []Payment
+	TotalSales           float64
+	TotalRefunds         float64
+	TotalNet             float64
+	TotalOutstanding     float64
+}
+
+// CompetitionFinanceService reports the finance of competitions to their organizers
+type CompetitionFinanceService struct {
+	categoryRepo IProductCategoryRepository
+	productRepo  ICompetitionProductRepository
+	orderRepo    IProductOrderRepository
+	paymentRepo  IPaymentRepository
+	fees         RegistrationFeeService
+}
+
+func NewCompetitionFinanceService(
+	categoryRepo IProductCategoryRepository,
+	productRepo ICompetitionProductRepository,
+	orderRepo IProductOrderRepository,
+	paymentRepo IPaymentRepository,
+	fees RegistrationFeeService) CompetitionFinanceService {
+	return CompetitionFinanceService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		orderRepo:    orderRepo,
+		paymentRepo:  paymentRepo,
+		fees:         fees,
+	}
+}
+
+// GetFinanceReport computes the finance report of a competition from its orders, payments and registration invoices
+func (service CompetitionFinanceService) GetFinanceReport(competitionID int) (FinanceReport, error) {
+	report := FinanceReport{
+		CompetitionID:       competitionID,
+		ProductRevenue:      make([]ProductCategoryRevenue, 0),
+		OutstandingBalances: make([]AthleteBalance, 0),
+		UnpaidEntries:       make([]AthleteBalance, 0),
+		Refunds:             make([]Payment, 0),
+	}
+	payments, err := service.paymentRepo.SearchPayment(SearchPaymentCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return report, err
+	}
+	if err := service.addProductRevenue(&report, payments); err != nil {
+		return report, err
+	}
+	if err := service.addRegistrationFees(&report, payments); err != nil {
+		return report, err
+	}
+
+	for _, each := range payments {
+		if each.RefundedAmount > 0 {
+			report.Refunds = append(report.Refunds, each)
+		}
+	}
+	for _, each := range report.ProductRevenue {
+		report.TotalSales += each.Sales
+		report.TotalRefunds += each.Refunds
+	}
+	report.TotalSales = roundToCent(report.TotalSales + report.RegistrationSales)
+	report.TotalRefunds = roundToCent(report.TotalRefunds + report.RegistrationRefunds)
+	report.TotalNet = roundToCent(report.TotalSales - report.TotalRefunds)
+	return report, nil
+}
+
+// isSettled returns true if the payment has been paid, including the payments that were refunded later
+func isSettled(payment Payment) bool {
+	return payment.StatusID == PaymentStatusSucceeded || payment.StatusID == PaymentStatusRefunded
+}
+
+func (service CompetitionFinanceService) addProductRevenue(report *FinanceReport, payments []Payment) error {
+	categories, err := service.categoryRepo.GetProductCategories()
+	if err != nil {
+		return err
+	}
+	products, err := service.productRepo.SearchCompetitionProduct(SearchCompetitionProductCriteria{CompetitionID: report.CompetitionID})
+	if err != nil {
+		return err
+	}
+	orders, err := service.orderRepo.SearchProductOrder(SearchProductOrderCriteria{CompetitionID: report.CompetitionID})
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int]string)
+	for _, each := range categories {
+		names[each.ID] = each.Name
+	}
+	productCategories := make(map[int]int)
+	for _, each := range products {
+		productCategories[each.ID] = each.CategoryID
+	}
+	revenue := make(map[int]*ProductCategoryRevenue)
+	categoryRevenue := func(categoryID int) *ProductCategoryRevenue {
+		if _, ok := revenue[categoryID]; !ok {
+			revenue[categoryID] = &ProductCategoryRevenue{CategoryID: categoryID, CategoryName: names[categoryID]}
+		}
+		return revenue[categoryID]
+	}
+
+	orderCategories := make(map[int]int)
+	for _, each := range orders {
+		orderCategories[each.ID] = productCategories[each.ProductID]
+		if each.StatusID == ProductOrderStatusPaid || each.StatusID == ProductOrderStatusRefunded {
+			category := categoryRevenue(productCategories[each.ProductID])
+			category.Orders++
+			category.Quantity += each.Quantity
+		}
+	}
+	for _, each := range payments {
+		if each.Purpose != PaymentPurposeProductOrder || !isSettled(each) {
+			continue
+		}
+		category := categoryRevenue(orderCategories[each.ReferenceID])
+		category.Sales += each.Amount
+		category.Refunds += each.RefundedAmount
+	}
+
+	for _, each := range revenue {
+		each.Sales = roundToCent(each.Sales)
+		each.Refunds = roundToCent(each.Refunds)
+		each.Net = roundToCent(each.Sales - each.Refunds)
+		report.ProductRevenue = append(report.ProductRevenue, *each)
+	}
+	sort.Slice(report.ProductRevenue, func(i, j int) bool {
+		return report.ProductRevenue[i].CategoryID < report.ProductRevenue[j].CategoryID
+	})
+	return nil
+}
+
+// addRegistrationFees adds the registration fees from the invoices of athletes. If the competition does not have a fee
+// schedule, athletes whose entries are not marked as paid are reported as unpaid.
+func (service CompetitionFinanceService) addRegistrationFees(report *FinanceReport, payments []Payment) error {
+	entries, invoices, err := service.fees.competitionInvoices(report.CompetitionID)
+	if err != nil {
+		return err
+	}
+	paidEntries := make(map[int]bool)
+	for _, each := range payments {
+		if each.Purpose != PaymentPurposeRegistrationFee || !isSettled(each) {
+			continue
+		}
+		paidEntries[each.ReferenceID] = true
+		report.RegistrationSales += each.Amount
+		report.RegistrationRefunds += each.RefundedAmount
+	}
+	report.RegistrationSales = roundToCent(report.RegistrationSales)
+	report.RegistrationRefunds = roundToCent(report.RegistrationRefunds)
+
+	for i, entry := range entries {
+		balance := AthleteBalance{
+			EntryID:     entry.ID,
+			AthleteID:   entry.Athlete.ID,
+			AthleteName: entry.Athlete.FullName(),
+		}
+		if len(invoices) == len(entries) {
+			balance.Total = invoices[i].Total
+			balance.AmountPaid = invoices[i].AmountPaid
+			balance.Balance = invoices[i].Balance
+			report.RegistrationInvoiced += balance.Total
+			if !invoices[i].IsPaid() {
+				report.OutstandingBalances = append(report.OutstandingBalances, balance)
+				report.TotalOutstanding += balance.Balance
+			}
+			if !paidEntries[entry.ID] && balance.Total > 0 {
+				report.UnpaidEntries = append(report.UnpaidEntries, balance)
+			}
+		} else if !paidEntries[entry.ID] && !entry.PaymentReceivedIndicator {
+			report.UnpaidEntries = append(report.UnpaidEntries, balance)
+		}
+	}
+	report.RegistrationInvoiced = roundToCent(report.RegistrationInvoiced)
+	report.TotalOutstanding = roundToCent(report.TotalOutstanding)
+	return nil
+}
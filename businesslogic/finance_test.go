@@ -0,0 +1,78 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCompetitionFinanceService_GetFinanceReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scheduleRepo := mock_businesslogic.NewMockICompetitionFeeScheduleRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	partnershipEventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	fees := businesslogic.NewRegistrationFeeService(scheduleRepo, competitionRepo, eventRepo, athleteEntryRepo, partnershipEntryRepo, partnershipEventEntryRepo, representationRepo, paymentRepo)
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionFinanceService(categoryRepo, productRepo, orderRepo, paymentRepo, fees)
+
+	// workshops of competition 3: order 8 is paid, order 9 is pending and order 10 has been refunded
+	categoryRepo.EXPECT().GetProductCategories().Return([]businesslogic.ProductCategory{{ID: businesslogic.ProductCategoryWorkshop, Name: "Workshop"}}, nil)
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{CompetitionID: 3}).Return([]businesslogic.ProductOrder{
+		{ID: 8, CompetitionID: 3, ProductID: 7, Quantity: 2, TotalCost: 50, StatusID: businesslogic.ProductOrderStatusPaid},
+		{ID: 9, CompetitionID: 3, ProductID: 7, Quantity: 1, TotalCost: 25, StatusID: businesslogic.ProductOrderStatusPending},
+		{ID: 10, CompetitionID: 3, ProductID: 7, Quantity: 1, TotalCost: 25, StatusID: businesslogic.ProductOrderStatusRefunded},
+	}, nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{CompetitionID: 3}).Return([]businesslogic.Payment{
+		{ID: 1, Purpose: businesslogic.PaymentPurposeRegistrationFee, ReferenceID: 4, Amount: 40, StatusID: businesslogic.PaymentStatusSucceeded},
+		{ID: 3, Purpose: businesslogic.PaymentPurposeProductOrder, ReferenceID: 8, Amount: 50, StatusID: businesslogic.PaymentStatusSucceeded},
+		{ID: 4, Purpose: businesslogic.PaymentPurposeProductOrder, ReferenceID: 10, Amount: 25, RefundedAmount: 25, StatusID: businesslogic.PaymentStatusRefunded},
+		{ID: 5, Purpose: businesslogic.PaymentPurposeProductOrder, ReferenceID: 9, Amount: 25, StatusID: businesslogic.PaymentStatusPending},
+	}, nil)
+
+	// athlete 12 has paid 40 of 67.5, and athlete 13 entered late and has never paid
+	follow := businesslogic.AthleteCompetitionEntry{
+		ID:              5,
+		Athlete:         businesslogic.Account{ID: 13, FirstName: "Betty", LastName: "Jones"},
+		Competition:     businesslogic.Competition{ID: 3},
+		DateTimeCreated: date(time.February, 20),
+	}
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3}).Return([]businesslogic.AthleteCompetitionEntry{leadEntry(false), follow}, nil)
+	scheduleRepo.EXPECT().SearchCompetitionFeeSchedule(businesslogic.SearchCompetitionFeeScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionFeeSchedule{feeSchedule()}, nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{Purpose: businesslogic.PaymentPurposeRegistrationFee, ReferenceID: 5}).Return([]businesslogic.Payment{}, nil)
+	expectFeeEntries(2, paymentRepo, partnershipEntryRepo, representationRepo, eventRepo, partnershipEventEntryRepo)
+
+	report, err := service.GetFinanceReport(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []businesslogic.ProductCategoryRevenue{
+		{CategoryID: businesslogic.ProductCategoryWorkshop, CategoryName: "Workshop", Orders: 2, Quantity: 3, Sales: 75, Refunds: 25, Net: 50},
+	}, report.ProductRevenue, "pending orders should not be revenue")
+	assert.EqualValues(t, 162, report.RegistrationInvoiced)
+	assert.EqualValues(t, 40, report.RegistrationSales)
+	if assert.Len(t, report.OutstandingBalances, 2) {
+		assert.EqualValues(t, 27.5, report.OutstandingBalances[0].Balance)
+		assert.EqualValues(t, 94.5, report.OutstandingBalances[1].Balance)
+	}
+	if assert.Len(t, report.UnpaidEntries, 1, "athletes who have paid part of their fees are not unpaid") {
+		assert.Equal(t, "Betty Jones", report.UnpaidEntries[0].AthleteName)
+	}
+	if assert.Len(t, report.Refunds, 1) {
+		assert.Equal(t, 4, report.Refunds[0].ID)
+	}
+	assert.EqualValues(t, 115, report.TotalSales)
+	assert.EqualValues(t, 25, report.TotalRefunds)
+	assert.EqualValues(t, 90, report.TotalNet)
+	assert.EqualValues(t, 122, report.TotalOutstanding)
+}
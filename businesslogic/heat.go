@@ -0,0 +1,197 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"time"
+)
+
+// RoundHeatDraw records how the couples of a round are drawn into heats. Heats are stored with the draw so they do not
+// change when couples or their affiliations change after the draw, and the seed reproduces the draw from the same
+// inputs when it is appealed.
+type RoundHeatDraw struct {
+	ID              int
+	RoundID         int
+	Seed            int64
+	FloorCapacity   int
+	Heats           []Heat
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// SearchRoundHeatDrawCriteria specifies the parameters that can be used to search RoundHeatDraw in a repository
+type SearchRoundHeatDrawCriteria struct {
+	RoundID int
+}
+
+// IRoundHeatDrawRepository specifies the functions that a RoundHeatDraw Repository should implement. Heats of the draw
+// are created, searched and replaced together with the draw.
+type IRoundHeatDrawRepository interface {
+	CreateRoundHeatDraw(draw *RoundHeatDraw) error
+	DeleteRoundHeatDraw(draw RoundHeatDraw) error
+	SearchRoundHeatDraw(criteria SearchRoundHeatDrawCriteria) ([]RoundHeatDraw, error)
+	UpdateRoundHeatDraw(draw RoundHeatDraw) error
+}
+
+// Heat is a group of couples who dance a dance of a round on the floor at the same time
+type Heat struct {
+	RoundID      int
+	EventDanceID int
+	Number       int   // 1-based
+	Couples      []int // IDs of PartnershipRoundEntry
+}
+
+// HeatDrawService draws the couples of a round into heats. Couples who represent the same studio or school at the
+// competition are kept in different heats where possible.
+type HeatDrawService struct {
+	eventRepo            IEventRepository
+	eventDanceRepo       IEventDanceRepository
+	roundRepo            IRoundRepository
+	partnershipEntryRepo IPartnershipRoundEntryRepository
+	competitionEntryRepo IPartnershipCompetitionEntryRepository
+	representationRepo   IPartnershipCompetitionRepresentationRepository
+	drawRepo             IRoundHeatDrawRepository
+}
+
+func NewHeatDrawService(
+	eventRepo IEventRepository,
+	eventDanceRepo IEventDanceRepository,
+	roundRepo IRoundRepository,
+	partnershipEntryRepo IPartnershipRoundEntryRepository,
+	competitionEntryRepo IPartnershipCompetitionEntryRepository,
+	representationRepo IPartnershipCompetitionRepresentationRepository,
+	drawRepo IRoundHeatDrawRepository) HeatDrawService {
+	return HeatDrawService{
+		eventRepo:            eventRepo,
+		eventDanceRepo:       eventDanceRepo,
+		roundRepo:            roundRepo,
+		partnershipEntryRepo: partnershipEntryRepo,
+		competitionEntryRepo: competitionEntryRepo,
+		representationRepo:   representationRepo,
+		drawRepo:             drawRepo,
+	}
+}
+
+// DrawHeats draws the couples of the round into heats of at most floorCapacity couples. A new seed is generated if
+// seed is 0. Drawing a round again replaces the previous draw.
+func (service HeatDrawService) DrawHeats(currentUser Account, competitionID, roundID int, floorCapacity int, seed int64) (RoundHeatDraw, []Heat, error) {
+	round, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo)
+	if err != nil {
+		return RoundHeatDraw{}, nil, err
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	draw := RoundHeatDraw{
+		RoundID:         round.ID,
+		Seed:            seed,
+		FloorCapacity:   floorCapacity,
+		CreateUserID:    currentUser.ID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    currentUser.ID,
+		DateTimeUpdated: time.Now(),
+	}
+	heats, err := service.splitHeats(round, competitionID, draw)
+	if err != nil {
+		return draw, nil, err
+	}
+	draw.Heats = heats
+
+	existing, err := service.drawRepo.SearchRoundHeatDraw(SearchRoundHeatDrawCriteria{RoundID: round.ID})
+	if err != nil {
+		return draw, nil, err
+	}
+	if len(existing) > 0 {
+		draw.ID = existing[0].ID
+		draw.CreateUserID = existing[0].CreateUserID
+		draw.DateTimeCreated = existing[0].DateTimeCreated
+		return draw, heats, service.drawRepo.UpdateRoundHeatDraw(draw)
+	}
+	return draw, heats, service.drawRepo.CreateRoundHeatDraw(&draw)
+}
+
+// GetHeats returns the heats of the round as they were drawn
+func (service HeatDrawService) GetHeats(roundID int) (RoundHeatDraw, []Heat, error) {
+	draws, err := service.drawRepo.SearchRoundHeatDraw(SearchRoundHeatDrawCriteria{RoundID: roundID})
+	if err != nil {
+		return RoundHeatDraw{}, nil, err
+	}
+	if len(draws) != 1 {
+		return RoundHeatDraw{}, nil, errors.New(fmt.Sprintf("heats of round %v are not drawn", roundID))
+	}
+	return draws[0], draws[0].Heats, nil
+}
+
+func (service HeatDrawService) splitHeats(round Round, competitionID int, draw RoundHeatDraw) ([]Heat, error) {
+	dances, err := service.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{EventID: round.EventID})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := service.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: round.ID})
+	if err != nil {
+		return nil, err
+	}
+	affiliations, err := service.getAffiliations(competitionID)
+	if err != nil {
+		return nil, err
+	}
+	couples := make([]scheduler.HeatCouple, 0)
+	for _, each := range entries {
+		couples = append(couples, scheduler.HeatCouple{
+			CoupleID:     each.ID,
+			Affiliations: affiliations[each.PartnershipID],
+		})
+	}
+
+	drawer, err := scheduler.NewHeatDraw(draw.Seed, draw.FloorCapacity)
+	if err != nil {
+		return nil, err
+	}
+	result, err := drawer.SplitHeats(couples, len(dances))
+	if err != nil {
+		return nil, err
+	}
+	heats := make([]Heat, 0)
+	for i, dance := range result {
+		for j, heat := range dance {
+			heats = append(heats, Heat{
+				RoundID:      round.ID,
+				EventDanceID: dances[i].ID,
+				Number:       j + 1,
+				Couples:      heat,
+			})
+		}
+	}
+	return heats, nil
+}
+
+// getAffiliations returns the studio and school that each partnership represents at the competition
+func (service HeatDrawService) getAffiliations(competitionID int) (map[int][]string, error) {
+	entries, err := service.competitionEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	partnerships := make(map[int]int)
+	for _, each := range entries {
+		partnerships[each.ID] = each.Couple.ID
+	}
+	representations, err := service.representationRepo.SearchCompetitionRepresentation(SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	affiliations := make(map[int][]string)
+	for _, each := range representations {
+		partnershipID := partnerships[each.PartnershipCompetitionEntryID]
+		if each.StudioID != nil {
+			affiliations[partnershipID] = append(affiliations[partnershipID], fmt.Sprintf("studio-%d", *each.StudioID))
+		}
+		if each.SchoolID != nil {
+			affiliations[partnershipID] = append(affiliations[partnershipID], fmt.Sprintf("school-%d", *each.SchoolID))
+		}
+	}
+	return affiliations, nil
+}
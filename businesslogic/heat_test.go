@@ -0,0 +1,160 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// heatRoundCouples returns the six couples of round 9 of event 5 at competition 3. Couples of partnership 101, 102 and
+// 103 represent studio 1, and the others represent studio 2.
+func heatRoundCouples() ([]businesslogic.PartnershipRoundEntry, []businesslogic.PartnershipCompetitionEntry, []businesslogic.PartnershipCompetitionRepresentation) {
+	roundEntries := make([]businesslogic.PartnershipRoundEntry, 0)
+	competitionEntries := make([]businesslogic.PartnershipCompetitionEntry, 0)
+	representations := make([]businesslogic.PartnershipCompetitionRepresentation, 0)
+	studios := []int{1, 2}
+	for i := 1; i <= 6; i++ {
+		roundEntries = append(roundEntries, businesslogic.PartnershipRoundEntry{ID: i, PartnershipID: 100 + i})
+		competitionEntries = append(competitionEntries, businesslogic.PartnershipCompetitionEntry{ID: 200 + i, Couple: businesslogic.Partnership{ID: 100 + i}})
+		representations = append(representations, businesslogic.PartnershipCompetitionRepresentation{
+			PartnershipCompetitionEntryID: 200 + i,
+			StudioID:                      &studios[(i-1)/3],
+		})
+	}
+	return roundEntries, competitionEntries, representations
+}
+
+func TestHeatDrawService_DrawHeats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	drawRepo := mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl)
+	service := businesslogic.NewHeatDrawService(eventRepo, eventDanceRepo, roundRepo, partnershipEntryRepo,
+		competitionEntryRepo, representationRepo, drawRepo)
+
+	roundEntries, competitionEntries, representations := heatRoundCouples()
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 9}).Return([]businesslogic.Round{{ID: 9, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 9}).Return(roundEntries, nil)
+	competitionEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return(competitionEntries, nil)
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: 3}).Return(representations, nil)
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 9}).Return([]businesslogic.RoundHeatDraw{}, nil)
+	drawRepo.EXPECT().CreateRoundHeatDraw(gomock.Any()).Do(func(draw *businesslogic.RoundHeatDraw) {
+		assert.Equal(t, int64(2018), draw.Seed)
+		assert.Equal(t, 3, draw.FloorCapacity)
+	}).Return(nil)
+
+	draw, heats, err := service.DrawHeats(newOrganizer(41), 3, 9, 3, 2018)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2018), draw.Seed)
+	assert.Len(t, heats, 4, "six couples should dance two heats in each of the two dances")
+	for _, heat := range heats {
+		assert.Len(t, heat.Couples, 3)
+		studio1 := 0
+		for _, couple := range heat.Couples {
+			if couple <= 3 {
+				studio1++
+			}
+		}
+		assert.True(t, studio1 == 1 || studio1 == 2, "couples of the same studio should be spread across heats")
+	}
+}
+
+func TestHeatDrawService_DrawHeats_Redraw(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	drawRepo := mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl)
+	service := businesslogic.NewHeatDrawService(eventRepo, eventDanceRepo, roundRepo, partnershipEntryRepo,
+		competitionEntryRepo, representationRepo, drawRepo)
+
+	roundEntries, competitionEntries, representations := heatRoundCouples()
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 9}).Return([]businesslogic.Round{{ID: 9, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 9}).Return(roundEntries, nil)
+	competitionEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return(competitionEntries, nil)
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: 3}).Return(representations, nil)
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 9}).Return([]businesslogic.RoundHeatDraw{{ID: 15, RoundID: 9, Seed: 7, FloorCapacity: 6, CreateUserID: 41}}, nil)
+	drawRepo.EXPECT().UpdateRoundHeatDraw(gomock.Any()).Do(func(draw businesslogic.RoundHeatDraw) {
+		assert.Equal(t, 15, draw.ID, "the previous draw of the round should be replaced")
+		assert.Equal(t, int64(2018), draw.Seed)
+	}).Return(nil)
+
+	_, _, err := service.DrawHeats(newOrganizer(41), 3, 9, 3, 2018)
+	assert.Nil(t, err)
+}
+
+func TestHeatDrawService_DrawHeats_OtherCompetition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	drawRepo := mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl)
+	service := businesslogic.NewHeatDrawService(eventRepo, eventDanceRepo, roundRepo, partnershipEntryRepo,
+		competitionEntryRepo, representationRepo, drawRepo)
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 9}).Return([]businesslogic.Round{{ID: 9, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+
+	_, _, err := service.DrawHeats(newOrganizer(42), 4, 9, 3, 2018)
+	assert.NotNil(t, err, "heats should not be drawn for rounds of other competitions")
+}
+
+func TestHeatDrawService_GetHeats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	competitionEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	representationRepo := mock_businesslogic.NewMockIPartnershipCompetitionRepresentationRepository(mockCtrl)
+	drawRepo := mock_businesslogic.NewMockIRoundHeatDrawRepository(mockCtrl)
+	service := businesslogic.NewHeatDrawService(eventRepo, eventDanceRepo, roundRepo, partnershipEntryRepo,
+		competitionEntryRepo, representationRepo, drawRepo)
+
+	roundEntries, competitionEntries, representations := heatRoundCouples()
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 9}).Return([]businesslogic.Round{{ID: 9, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 9}).Return(roundEntries, nil)
+	competitionEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return(competitionEntries, nil)
+	representationRepo.EXPECT().SearchCompetitionRepresentation(businesslogic.SearchPartnershipCompetitionRepresentationCriteria{CompetitionID: 3}).Return(representations, nil)
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 9}).Return([]businesslogic.RoundHeatDraw{}, nil)
+	var stored businesslogic.RoundHeatDraw
+	drawRepo.EXPECT().CreateRoundHeatDraw(gomock.Any()).Do(func(draw *businesslogic.RoundHeatDraw) {
+		stored = *draw
+	}).Return(nil)
+	draw, drawn, err := service.DrawHeats(newOrganizer(41), 3, 9, 4, 0)
+	assert.Nil(t, err)
+	assert.NotEqual(t, int64(0), draw.Seed, "a seed should be generated when it is not specified")
+	assert.Equal(t, drawn, stored.Heats, "heats should be stored with the draw")
+
+	// couples and affiliations are not searched again, so the heats do not change after the draw
+	drawRepo.EXPECT().SearchRoundHeatDraw(businesslogic.SearchRoundHeatDrawCriteria{RoundID: 9}).Return([]businesslogic.RoundHeatDraw{stored}, nil)
+	_, heats, err := service.GetHeats(9)
+	assert.Nil(t, err)
+	assert.Equal(t, drawn, heats, "heats should be returned as they were drawn")
+}
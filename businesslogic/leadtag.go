@@ -0,0 +1,491 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Numbering of lead tags, as defined in DAS.COMPETITION_LEAD_TAG_SETTING
+const (
+	LeadTagNumberingSequential = "SEQUENTIAL"
+	LeadTagNumberingBlock      = "BLOCK"
+)
+
+// Default settings of lead tags for competitions that have not specified them
+const (
+	DefaultFirstLeadTag     = 101
+	DefaultLeadTagBlockSize = 100
+)
+
+// LeadTagTakenError is returned when a tag is assigned to a lead while another lead of the competition has it
+var LeadTagTakenError = errors.New("lead tag is already assigned to another lead of the competition")
+
+// LeadTagRangeOverlapError is returned when a range is created while another range of the competition has some of its
+// tags
+var LeadTagRangeOverlapError = errors.New("range overlaps with another range of the competition")
+
+// maxLeadTagAttempts is how many times a tag is picked for a lead when other leads take the picked tags at the same time
+const maxLeadTagAttempts = 10
+
+// CompetitionLeadTagSettings specifies how the tags of leads at a competition are numbered. Sequential numbering
+// assigns the lowest free tag from the first tag. Block numbering gives each school or studio a block of BlockSize
+// tags, and assigns the lowest free tag of the block to the leads who represent it.
+type CompetitionLeadTagSettings struct {
+	ID              int
+	CompetitionID   int
+	Numbering       string
+	FirstTag        int
+	BlockSize       int
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+func (settings CompetitionLeadTagSettings) validate() error {
+	if settings.Numbering != LeadTagNumberingSequential && settings.Numbering != LeadTagNumberingBlock {
+		return errors.New(fmt.Sprintf("numbering must be either %v or %v", LeadTagNumberingSequential, LeadTagNumberingBlock))
+	}
+	if settings.FirstTag < 1 {
+		return errors.New("first tag must be positive")
+	}
+	if settings.BlockSize < 1 {
+		return errors.New("block size must be positive")
+	}
+	return nil
+}
+
+// SearchCompetitionLeadTagSettingsCriteria specifies the parameters that can be used to search CompetitionLeadTagSettings
+type SearchCompetitionLeadTagSettingsCriteria struct {
+	CompetitionID int `schema:"competition,required"`
+}
+
+// ICompetitionLeadTagSettingsRepository specifies the functions that a CompetitionLeadTagSettings Repository should
+// implement
+type ICompetitionLeadTagSettingsRepository interface {
+	CreateCompetitionLeadTagSettings(settings *CompetitionLeadTagSettings) error
+	SearchCompetitionLeadTagSettings(criteria SearchCompetitionLeadTagSettingsCriteria) ([]CompetitionLeadTagSettings, error)
+	UpdateCompetitionLeadTagSettings(settings CompetitionLeadTagSettings) error
+}
+
+// CompetitionLeadTagRange is a range of lead tags at a competition. A range of a school or studio is the block of
+// tags of the leads who represent it. A range without a school or studio is reserved, and its tags are never assigned
+// automatically, but organizers can still assign them to leads.
+type CompetitionLeadTagRange struct {
+	ID              int
+	CompetitionID   int
+	FirstTag        int
+	LastTag         int
+	SchoolID        *int
+	StudioID        *int
+	Description     string
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// IsReserved checks if the range is reserved rather than the block of a school or studio
+func (tagRange CompetitionLeadTagRange) IsReserved() bool {
+	return tagRange.SchoolID == nil && tagRange.StudioID == nil
+}
+
+// Contains checks if the tag is in the range
+func (tagRange CompetitionLeadTagRange) Contains(tag int) bool {
+	return tag >= tagRange.FirstTag && tag <= tagRange.LastTag
+}
+
+func (tagRange CompetitionLeadTagRange) overlaps(first, last int) bool {
+	return first <= tagRange.LastTag && last >= tagRange.FirstTag
+}
+
+func (tagRange CompetitionLeadTagRange) isBlockOf(group LeadTagGroup) bool {
+	if group.SchoolID > 0 {
+		return tagRange.SchoolID != nil && *tagRange.SchoolID == group.SchoolID
+	}
+	return group.StudioID > 0 && tagRange.StudioID != nil && *tagRange.StudioID == group.StudioID
+}
+
+// SearchCompetitionLeadTagRangeCriteria specifies the parameters that can be used to search CompetitionLeadTagRange
+type SearchCompetitionLeadTagRangeCriteria struct {
+	ID            int `schema:"id"`
+	CompetitionID int `schema:"competition"`
+}
+
+// ICompetitionLeadTagRangeRepository specifies the functions that a CompetitionLeadTagRange Repository should
+// implement. CreateCompetitionLeadTagRange must fail if the competition already has a block of the same school or
+// studio.
+type ICompetitionLeadTagRangeRepository interface {
+	CreateCompetitionLeadTagRange(tagRange *CompetitionLeadTagRange) error
+	DeleteCompetitionLeadTagRange(tagRange CompetitionLeadTagRange) error
+	SearchCompetitionLeadTagRange(criteria SearchCompetitionLeadTagRangeCriteria) ([]CompetitionLeadTagRange, error)
+}
+
+// LeadTagGroup is the school or studio that a lead represents at a competition. With block numbering, the school
+// decides the block of the tag of the lead, or the studio if the lead does not represent a school.
+type LeadTagGroup struct {
+	SchoolID int
+	StudioID int
+}
+
+// LeadTagService assigns tags to the leads of competitions. The tag of a lead is kept in DAS.COMPETITION_LEAD_TAG
+// and copied to the competition entry of the lead. Repositories must not allow two leads of a competition to have
+// the same tag, so when leads register at the same time, only one of them gets a tag and the others pick again.
+type LeadTagService struct {
+	athleteEntryRepo     IAthleteCompetitionEntryRepository
+	partnershipEntryRepo IPartnershipCompetitionEntryRepository
+	tagRepo              ICompetitionLeadTagRepository
+	settingsRepo         ICompetitionLeadTagSettingsRepository
+	rangeRepo            ICompetitionLeadTagRangeRepository
+}
+
+func NewLeadTagService(
+	athleteEntryRepo IAthleteCompetitionEntryRepository,
+	partnershipEntryRepo IPartnershipCompetitionEntryRepository,
+	tagRepo ICompetitionLeadTagRepository,
+	settingsRepo ICompetitionLeadTagSettingsRepository,
+	rangeRepo ICompetitionLeadTagRangeRepository) LeadTagService {
+	return LeadTagService{
+		athleteEntryRepo:     athleteEntryRepo,
+		partnershipEntryRepo: partnershipEntryRepo,
+		tagRepo:              tagRepo,
+		settingsRepo:         settingsRepo,
+		rangeRepo:            rangeRepo,
+	}
+}
+
+// GetSettings returns the lead tag settings of the competition, or the default settings if the organizer has not
+// specified them
+func (service LeadTagService) GetSettings(competitionID int) (CompetitionLeadTagSettings, error) {
+	settings, err := service.settingsRepo.SearchCompetitionLeadTagSettings(SearchCompetitionLeadTagSettingsCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return CompetitionLeadTagSettings{}, err
+	}
+	if len(settings) > 0 {
+		return settings[0], nil
+	}
+	return CompetitionLeadTagSettings{
+		CompetitionID: competitionID,
+		Numbering:     LeadTagNumberingSequential,
+		FirstTag:      DefaultFirstLeadTag,
+		BlockSize:     DefaultLeadTagBlockSize,
+	}, nil
+}
+
+// SaveSettings creates or updates the lead tag settings of a competition. Tags that have been assigned are not changed.
+func (service LeadTagService) SaveSettings(currentUser Account, settings *CompetitionLeadTagSettings) error {
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	existing, err := service.settingsRepo.SearchCompetitionLeadTagSettings(SearchCompetitionLeadTagSettingsCriteria{CompetitionID: settings.CompetitionID})
+	if err != nil {
+		return err
+	}
+	settings.UpdateUserID = currentUser.ID
+	settings.DateTimeUpdated = time.Now()
+	if len(existing) > 0 {
+		settings.ID = existing[0].ID
+		settings.CreateUserID = existing[0].CreateUserID
+		settings.DateTimeCreated = existing[0].DateTimeCreated
+		return service.settingsRepo.UpdateCompetitionLeadTagSettings(*settings)
+	}
+	settings.CreateUserID = currentUser.ID
+	settings.DateTimeCreated = time.Now()
+	return service.settingsRepo.CreateCompetitionLeadTagSettings(settings)
+}
+
+// SearchRanges returns the reserved ranges and the blocks of a competition
+func (service LeadTagService) SearchRanges(competitionID int) ([]CompetitionLeadTagRange, error) {
+	return service.rangeRepo.SearchCompetitionLeadTagRange(SearchCompetitionLeadTagRangeCriteria{CompetitionID: competitionID})
+}
+
+// CreateRange reserves a range of tags, or creates the block of a school or studio, at a competition. Ranges of a
+// competition cannot overlap.
+func (service LeadTagService) CreateRange(currentUser Account, tagRange *CompetitionLeadTagRange) error {
+	if tagRange.FirstTag < 1 || tagRange.LastTag < tagRange.FirstTag {
+		return errors.New("range must start from a positive tag and end after it starts")
+	}
+	if tagRange.SchoolID != nil && tagRange.StudioID != nil {
+		return errors.New("range can only be the block of either a school or a studio")
+	}
+	ranges, err := service.rangeRepo.SearchCompetitionLeadTagRange(SearchCompetitionLeadTagRangeCriteria{CompetitionID: tagRange.CompetitionID})
+	if err != nil {
+		return err
+	}
+	for _, each := range ranges {
+		if each.overlaps(tagRange.FirstTag, tagRange.LastTag) {
+			return errors.New(fmt.Sprintf("range overlaps with tags %v to %v", each.FirstTag, each.LastTag))
+		}
+	}
+	tagRange.CreateUserID = currentUser.ID
+	tagRange.DateTimeCreated = time.Now()
+	tagRange.UpdateUserID = currentUser.ID
+	tagRange.DateTimeUpdated = time.Now()
+	return service.rangeRepo.CreateCompetitionLeadTagRange(tagRange)
+}
+
+// DeleteRange deletes a range of the competition. Tags that have been assigned from the range are kept.
+func (service LeadTagService) DeleteRange(competitionID, rangeID int) error {
+	ranges, err := service.rangeRepo.SearchCompetitionLeadTagRange(SearchCompetitionLeadTagRangeCriteria{ID: rangeID, CompetitionID: competitionID})
+	if err != nil {
+		return err
+	}
+	if len(ranges) != 1 {
+		return errors.New(fmt.Sprintf("cannot find lead tag range with ID = %v in competition %v", rangeID, competitionID))
+	}
+	return service.rangeRepo.DeleteCompetitionLeadTagRange(ranges[0])
+}
+
+// SearchLeadTags returns the tags of the leads at a competition, ordered by tag
+func (service LeadTagService) SearchLeadTags(competitionID int) ([]CompetitionLeadTag, error) {
+	return service.searchTags(SearchCompetitionLeadTagCriteria{CompetitionID: competitionID})
+}
+
+func (service LeadTagService) searchTags(criteria SearchCompetitionLeadTagCriteria) ([]CompetitionLeadTag, error) {
+	collection, err := service.tagRepo.SearchCompetitionLeadTag(criteria)
+	if err != nil {
+		return nil, err
+	}
+	tags := collection.Tags()
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+// AssignLeadTag assigns a tag to the lead at the competition if the lead does not have one yet, and returns the tag
+// of the lead. If other leads take the tag that is picked for the lead at the same time, another tag is picked.
+func (service LeadTagService) AssignLeadTag(competitionID, leadID int, group LeadTagGroup, assignedBy int) (CompetitionLeadTag, error) {
+	settings, err := service.GetSettings(competitionID)
+	if err != nil {
+		return CompetitionLeadTag{}, err
+	}
+	for attempt := 0; attempt < maxLeadTagAttempts; attempt++ {
+		tags, err := service.searchTags(SearchCompetitionLeadTagCriteria{CompetitionID: competitionID})
+		if err != nil {
+			return CompetitionLeadTag{}, err
+		}
+		taken := make(map[int]bool)
+		for _, each := range tags {
+			if each.LeadID == leadID {
+				return each, nil
+			}
+			taken[each.Tag] = true
+		}
+		next, err := service.nextTag(settings, group, taken, assignedBy)
+		if err != nil {
+			return CompetitionLeadTag{}, err
+		}
+		tag := CompetitionLeadTag{
+			CompetitionID:   competitionID,
+			LeadID:          leadID,
+			Tag:             next,
+			CreateUserID:    assignedBy,
+			DateTimeCreated: time.Now(),
+			UpdateUserID:    assignedBy,
+			DateTimeUpdated: time.Now(),
+		}
+		err = service.tagRepo.CreateCompetitionLeadTag(&tag)
+		if err == LeadTagTakenError {
+			continue
+		}
+		if err != nil {
+			return tag, err
+		}
+		return tag, service.updateEntryTag(competitionID, leadID, tag.Tag, assignedBy)
+	}
+	return CompetitionLeadTag{}, errors.New("cannot assign a lead tag while other leads are registering, please try again")
+}
+
+// nextTag picks the lowest free tag of the block of the group, or the lowest free tag that is not in any range if the
+// competition does not number tags by blocks, the lead does not represent a school or studio, or the block is full
+func (service LeadTagService) nextTag(settings CompetitionLeadTagSettings, group LeadTagGroup, taken map[int]bool, assignedBy int) (int, error) {
+	ranges, err := service.rangeRepo.SearchCompetitionLeadTagRange(SearchCompetitionLeadTagRangeCriteria{CompetitionID: settings.CompetitionID})
+	if err != nil {
+		return 0, err
+	}
+	if settings.Numbering == LeadTagNumberingBlock && (group.SchoolID > 0 || group.StudioID > 0) {
+		block, err := service.blockOf(settings, group, ranges, taken, assignedBy)
+		if err != nil {
+			return 0, err
+		}
+		for tag := block.FirstTag; tag <= block.LastTag; tag++ {
+			if !taken[tag] {
+				return tag, nil
+			}
+		}
+	}
+	for tag := settings.FirstTag; ; tag++ {
+		if taken[tag] {
+			continue
+		}
+		inRange := false
+		for _, each := range ranges {
+			if each.Contains(tag) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return tag, nil
+		}
+	}
+}
+
+// blockOf returns the block of the group, and creates the block from the first BlockSize tags that are free and not
+// in any range if the group does not have a block yet. If other groups create blocks with the same tags at the same
+// time, the block is created from the tags that are still free.
+func (service LeadTagService) blockOf(settings CompetitionLeadTagSettings, group LeadTagGroup, ranges []CompetitionLeadTagRange, taken map[int]bool, assignedBy int) (CompetitionLeadTagRange, error) {
+	for attempt := 0; attempt < maxLeadTagAttempts; attempt++ {
+		for _, each := range ranges {
+			if each.isBlockOf(group) {
+				return each, nil
+			}
+		}
+		block := CompetitionLeadTagRange{
+			CompetitionID:   settings.CompetitionID,
+			CreateUserID:    assignedBy,
+			DateTimeCreated: time.Now(),
+			UpdateUserID:    assignedBy,
+			DateTimeUpdated: time.Now(),
+		}
+		if group.SchoolID > 0 {
+			block.SchoolID = &group.SchoolID
+		} else {
+			block.StudioID = &group.StudioID
+		}
+		for first := settings.FirstTag; ; first += settings.BlockSize {
+			last := first + settings.BlockSize - 1
+			free := true
+			for _, each := range ranges {
+				if each.overlaps(first, last) {
+					free = false
+					break
+				}
+			}
+			for tag := first; free && tag <= last; tag++ {
+				free = !taken[tag]
+			}
+			if free {
+				block.FirstTag = first
+				block.LastTag = last
+				break
+			}
+		}
+		err := service.rangeRepo.CreateCompetitionLeadTagRange(&block)
+		if err == nil {
+			return block, nil
+		}
+		// another lead of the group may have created the block, or another group may have taken the tags
+		created, searchErr := service.rangeRepo.SearchCompetitionLeadTagRange(SearchCompetitionLeadTagRangeCriteria{CompetitionID: settings.CompetitionID})
+		if searchErr != nil {
+			return block, searchErr
+		}
+		for _, each := range created {
+			if each.isBlockOf(group) {
+				return each, nil
+			}
+		}
+		if err != LeadTagRangeOverlapError {
+			return block, err
+		}
+		ranges = created
+	}
+	return CompetitionLeadTagRange{}, errors.New("cannot create a block of lead tags while other leads are registering, please try again")
+}
+
+// OverrideLeadTag assigns the tag to a lead at a competition, including tags of reserved ranges and blocks. It fails
+// with LeadTagTakenError if another lead has the tag.
+func (service LeadTagService) OverrideLeadTag(currentUser Account, competitionID, leadID, tagNumber int) (CompetitionLeadTag, error) {
+	if tagNumber < 1 {
+		return CompetitionLeadTag{}, errors.New("tag must be positive")
+	}
+	entries, err := service.athleteEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{CompetitionID: competitionID, AthleteID: leadID})
+	if err != nil {
+		return CompetitionLeadTag{}, err
+	}
+	if len(entries) != 1 {
+		return CompetitionLeadTag{}, errors.New(fmt.Sprintf("athlete %v has not entered this competition", leadID))
+	}
+	tags, err := service.searchTags(SearchCompetitionLeadTagCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return CompetitionLeadTag{}, err
+	}
+	tag := CompetitionLeadTag{
+		CompetitionID:   competitionID,
+		LeadID:          leadID,
+		CreateUserID:    currentUser.ID,
+		DateTimeCreated: time.Now(),
+	}
+	for _, each := range tags {
+		if each.Tag == tagNumber && each.LeadID != leadID {
+			return CompetitionLeadTag{}, LeadTagTakenError
+		}
+		if each.LeadID == leadID {
+			tag = each
+		}
+	}
+	tag.Tag = tagNumber
+	tag.UpdateUserID = currentUser.ID
+	tag.DateTimeUpdated = time.Now()
+	if tag.ID > 0 {
+		err = service.tagRepo.UpdateCompetitionLeadTag(tag)
+	} else {
+		err = service.tagRepo.CreateCompetitionLeadTag(&tag)
+	}
+	if err != nil {
+		return tag, err
+	}
+	return tag, service.updateEntryTag(competitionID, leadID, tagNumber, currentUser.ID)
+}
+
+// ReleaseLeadTag releases the tag of the lead at the competition, so that it can be assigned to other leads
+func (service LeadTagService) ReleaseLeadTag(competitionID, leadID int, releasedBy int) error {
+	tags, err := service.searchTags(SearchCompetitionLeadTagCriteria{CompetitionID: competitionID, LeadID: leadID})
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	for _, each := range tags {
+		if err := service.tagRepo.DeleteCompetitionLeadTag(each); err != nil {
+			return err
+		}
+	}
+	return service.updateEntryTag(competitionID, leadID, 0, releasedBy)
+}
+
+// UpdateLeadTag assigns a tag to the lead if the lead leads a partnership that has entered the competition, and
+// releases the tag of the lead otherwise
+func (service LeadTagService) UpdateLeadTag(competitionID, leadID int, group LeadTagGroup, updatedBy int) error {
+	entries, err := service.partnershipEntryRepo.SearchEntry(SearchPartnershipCompetitionEntryCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return err
+	}
+	for _, each := range entries {
+		if each.Couple.Lead.ID == leadID {
+			_, err := service.AssignLeadTag(competitionID, leadID, group, updatedBy)
+			return err
+		}
+	}
+	return service.ReleaseLeadTag(competitionID, leadID, updatedBy)
+}
+
+// updateEntryTag copies the tag of the lead to the competition entry of the lead
+func (service LeadTagService) updateEntryTag(competitionID, leadID, tag int, updatedBy int) error {
+	entries, err := service.athleteEntryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{CompetitionID: competitionID, AthleteID: leadID})
+	if err != nil {
+		return err
+	}
+	if len(entries) != 1 || entries[0].LeadTag == tag {
+		return nil
+	}
+	entry := entries[0]
+	entry.LeadTag = tag
+	entry.UpdateUserID = updatedBy
+	entry.DateTimeUpdated = time.Now()
+	return service.athleteEntryRepo.UpdateEntry(entry)
+}
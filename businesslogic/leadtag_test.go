@@ -0,0 +1,229 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func leadTags(tags ...businesslogic.CompetitionLeadTag) businesslogic.CompetitionLeadTagCollection {
+	collection := businesslogic.CompetitionLeadTagCollection{}
+	collection.SetTags(tags)
+	return collection
+}
+
+func TestLeadTagService_AssignLeadTag_Sequential(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	settingsRepo := mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl)
+	rangeRepo := mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(athleteEntryRepo, mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		tagRepo, settingsRepo, rangeRepo)
+
+	settingsRepo.EXPECT().SearchCompetitionLeadTagSettings(businesslogic.SearchCompetitionLeadTagSettingsCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagSettings{}, nil).Times(2)
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagRange{
+		{ID: 1, CompetitionID: 3, FirstTag: 103, LastTag: 110},
+	}, nil).Times(2)
+
+	// another lead takes 111 while athlete 12 is registering
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(
+		businesslogic.CompetitionLeadTag{LeadID: 20, Tag: 101},
+		businesslogic.CompetitionLeadTag{LeadID: 21, Tag: 102},
+	), nil)
+	tagRepo.EXPECT().CreateCompetitionLeadTag(gomock.Any()).Return(businesslogic.LeadTagTakenError)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(
+		businesslogic.CompetitionLeadTag{LeadID: 20, Tag: 101},
+		businesslogic.CompetitionLeadTag{LeadID: 21, Tag: 102},
+		businesslogic.CompetitionLeadTag{LeadID: 22, Tag: 111},
+	), nil)
+	tagRepo.EXPECT().CreateCompetitionLeadTag(gomock.Any()).Return(nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Do(func(entry businesslogic.AthleteCompetitionEntry) {
+		assert.Equal(t, 8, entry.ID)
+		assert.Equal(t, 112, entry.LeadTag)
+	}).Return(nil)
+
+	tag, err := service.AssignLeadTag(3, 12, businesslogic.LeadTagGroup{}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 112, tag.Tag, "reserved tags should be skipped, and tags taken at the same time should be picked again")
+
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(
+		businesslogic.CompetitionLeadTag{LeadID: 12, Tag: 112},
+	), nil)
+	tag, err = service.AssignLeadTag(3, 12, businesslogic.LeadTagGroup{}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 112, tag.Tag, "leads who have a tag should keep it")
+}
+
+func TestLeadTagService_AssignLeadTag_Block(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	settingsRepo := mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl)
+	rangeRepo := mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(athleteEntryRepo, mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		tagRepo, settingsRepo, rangeRepo)
+
+	settingsRepo.EXPECT().SearchCompetitionLeadTagSettings(businesslogic.SearchCompetitionLeadTagSettingsCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagSettings{
+		{CompetitionID: 3, Numbering: businesslogic.LeadTagNumberingBlock, FirstTag: 101, BlockSize: 100},
+	}, nil).Times(2)
+	school := 1
+	blocks := []businesslogic.CompetitionLeadTagRange{{ID: 1, CompetitionID: 3, FirstTag: 201, LastTag: 300, SchoolID: &school}}
+	tags := leadTags(businesslogic.CompetitionLeadTag{LeadID: 20, Tag: 101}, businesslogic.CompetitionLeadTag{LeadID: 21, Tag: 201})
+
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return(blocks, nil)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(tags, nil)
+	tagRepo.EXPECT().CreateCompetitionLeadTag(gomock.Any()).Return(nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Return(nil)
+	tag, err := service.AssignLeadTag(3, 12, businesslogic.LeadTagGroup{SchoolID: 1}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 202, tag.Tag, "leads should be tagged from the block of their school")
+
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return(blocks, nil)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(tags, nil)
+	rangeRepo.EXPECT().CreateCompetitionLeadTagRange(gomock.Any()).Do(func(block *businesslogic.CompetitionLeadTagRange) {
+		assert.Equal(t, 301, block.FirstTag, "new blocks should not overlap with taken tags or other blocks")
+		assert.Equal(t, 400, block.LastTag)
+		if assert.NotNil(t, block.StudioID) {
+			assert.Equal(t, 5, *block.StudioID)
+		}
+	}).Return(nil)
+	tagRepo.EXPECT().CreateCompetitionLeadTag(gomock.Any()).Return(nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Return(nil)
+	tag, err = service.AssignLeadTag(3, 12, businesslogic.LeadTagGroup{StudioID: 5}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 301, tag.Tag)
+}
+
+func TestLeadTagService_AssignLeadTag_BlockTakenAtTheSameTime(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	settingsRepo := mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl)
+	rangeRepo := mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(athleteEntryRepo, mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		tagRepo, settingsRepo, rangeRepo)
+
+	settingsRepo.EXPECT().SearchCompetitionLeadTagSettings(businesslogic.SearchCompetitionLeadTagSettingsCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagSettings{
+		{CompetitionID: 3, Numbering: businesslogic.LeadTagNumberingBlock, FirstTag: 101, BlockSize: 100},
+	}, nil)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(), nil)
+
+	// school 2 creates the first block while the lead of studio 5 is registering
+	school := 2
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagRange{}, nil)
+	rangeRepo.EXPECT().CreateCompetitionLeadTagRange(gomock.Any()).Do(func(block *businesslogic.CompetitionLeadTagRange) {
+		assert.Equal(t, 101, block.FirstTag)
+	}).Return(businesslogic.LeadTagRangeOverlapError)
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagRange{
+		{ID: 1, CompetitionID: 3, FirstTag: 101, LastTag: 200, SchoolID: &school},
+	}, nil)
+	rangeRepo.EXPECT().CreateCompetitionLeadTagRange(gomock.Any()).Do(func(block *businesslogic.CompetitionLeadTagRange) {
+		assert.Equal(t, 201, block.FirstTag, "blocks should be created again from the tags that are still free")
+		assert.Equal(t, 300, block.LastTag)
+	}).Return(nil)
+	tagRepo.EXPECT().CreateCompetitionLeadTag(gomock.Any()).Return(nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Return(nil)
+
+	tag, err := service.AssignLeadTag(3, 12, businesslogic.LeadTagGroup{StudioID: 5}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, 201, tag.Tag)
+}
+
+func TestLeadTagService_OverrideLeadTag(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(athleteEntryRepo, mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		tagRepo, mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl), mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl))
+
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(
+		businesslogic.CompetitionLeadTag{ID: 1, LeadID: 20, Tag: 7},
+		businesslogic.CompetitionLeadTag{ID: 2, LeadID: 12, Tag: 150},
+	), nil)
+	_, err := service.OverrideLeadTag(newOrganizer(41), 3, 12, 7)
+	assert.Equal(t, businesslogic.LeadTagTakenError, err, "two leads should not have the same tag")
+
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true}}, nil).Times(2)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3}).Return(leadTags(
+		businesslogic.CompetitionLeadTag{ID: 1, LeadID: 20, Tag: 7},
+		businesslogic.CompetitionLeadTag{ID: 2, LeadID: 12, Tag: 150},
+	), nil)
+	tagRepo.EXPECT().UpdateCompetitionLeadTag(gomock.Any()).Do(func(tag businesslogic.CompetitionLeadTag) {
+		assert.Equal(t, 2, tag.ID)
+		assert.Equal(t, 8, tag.Tag)
+	}).Return(nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Do(func(entry businesslogic.AthleteCompetitionEntry) {
+		assert.Equal(t, 8, entry.LeadTag)
+	}).Return(nil)
+	tag, err := service.OverrideLeadTag(newOrganizer(41), 3, 12, 8)
+	assert.Nil(t, err)
+	assert.Equal(t, 8, tag.Tag)
+}
+
+func TestLeadTagService_UpdateLeadTag_Release(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	athleteEntryRepo := mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl)
+	tagRepo := mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(athleteEntryRepo, partnershipEntryRepo,
+		tagRepo, mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl), mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl))
+
+	partnershipEntryRepo.EXPECT().SearchEntry(businesslogic.SearchPartnershipCompetitionEntryCriteria{CompetitionID: 3}).Return([]businesslogic.PartnershipCompetitionEntry{
+		{ID: 1, Couple: businesslogic.Partnership{Lead: businesslogic.Account{ID: 20}, Follow: businesslogic.Account{ID: 12}}},
+	}, nil)
+	tagRepo.EXPECT().SearchCompetitionLeadTag(businesslogic.SearchCompetitionLeadTagCriteria{CompetitionID: 3, LeadID: 12}).Return(leadTags(businesslogic.CompetitionLeadTag{ID: 2, LeadID: 12, Tag: 150}), nil)
+	tagRepo.EXPECT().DeleteCompetitionLeadTag(businesslogic.CompetitionLeadTag{ID: 2, LeadID: 12, Tag: 150}).Return(nil)
+	athleteEntryRepo.EXPECT().SearchEntry(businesslogic.SearchAthleteCompetitionEntryCriteria{CompetitionID: 3, AthleteID: 12}).Return([]businesslogic.AthleteCompetitionEntry{{ID: 8, IsLead: true, LeadTag: 150}}, nil)
+	athleteEntryRepo.EXPECT().UpdateEntry(gomock.Any()).Do(func(entry businesslogic.AthleteCompetitionEntry) {
+		assert.Equal(t, 0, entry.LeadTag)
+	}).Return(nil)
+
+	err := service.UpdateLeadTag(3, 12, businesslogic.LeadTagGroup{}, 12)
+	assert.Nil(t, err, "tags of athletes who no longer lead at the competition should be released")
+}
+
+func TestLeadTagService_CreateRange(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	rangeRepo := mock_businesslogic.NewMockICompetitionLeadTagRangeRepository(mockCtrl)
+	service := businesslogic.NewLeadTagService(mock_businesslogic.NewMockIAthleteCompetitionEntryRepository(mockCtrl), mock_businesslogic.NewMockIPartnershipCompetitionEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockICompetitionLeadTagRepository(mockCtrl), mock_businesslogic.NewMockICompetitionLeadTagSettingsRepository(mockCtrl), rangeRepo)
+
+	rangeRepo.EXPECT().SearchCompetitionLeadTagRange(businesslogic.SearchCompetitionLeadTagRangeCriteria{CompetitionID: 3}).Return([]businesslogic.CompetitionLeadTagRange{
+		{ID: 1, CompetitionID: 3, FirstTag: 1, LastTag: 20},
+	}, nil).Times(3)
+
+	err := service.CreateRange(newOrganizer(41), &businesslogic.CompetitionLeadTagRange{CompetitionID: 3, FirstTag: 30, LastTag: 25})
+	assert.NotNil(t, err, "range should not end before it starts")
+
+	err = service.CreateRange(newOrganizer(41), &businesslogic.CompetitionLeadTagRange{CompetitionID: 3, FirstTag: 15, LastTag: 25})
+	assert.NotNil(t, err, "ranges should not overlap")
+
+	rangeRepo.EXPECT().CreateCompetitionLeadTagRange(gomock.Any()).Return(nil)
+	err = service.CreateRange(newOrganizer(41), &businesslogic.CompetitionLeadTagRange{CompetitionID: 3, FirstTag: 21, LastTag: 25, Description: "judges' guests"})
+	assert.Nil(t, err)
+
+	// another range takes the tags after they are checked
+	rangeRepo.EXPECT().CreateCompetitionLeadTagRange(gomock.Any()).Return(businesslogic.LeadTagRangeOverlapError)
+	err = service.CreateRange(newOrganizer(41), &businesslogic.CompetitionLeadTagRange{CompetitionID: 3, FirstTag: 26, LastTag: 30})
+	assert.Equal(t, businesslogic.LeadTagRangeOverlapError, err)
+}
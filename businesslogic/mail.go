@@ -0,0 +1,13 @@
+package businesslogic
+
+// MailMessage is a plain text email that DAS sends to a user
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// IMailer specifies the functions that a mail service should implement
+type IMailer interface {
+	SendMail(message MailMessage) error
+}
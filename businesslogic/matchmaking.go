@@ -0,0 +1,285 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Factors that partner suggestions are scored by
+const (
+	PartnerFactorRole        = "role"
+	PartnerFactorStyle       = "style"
+	PartnerFactorProficiency = "proficiency"
+	PartnerFactorHeight      = "height"
+	PartnerFactorSchool      = "school"
+)
+
+// Default range of the height difference between the lead and the follow of suggested partners, in centimeters
+const (
+	DefaultPartnerMinHeightDifference = 0
+	DefaultPartnerMaxHeightDifference = 20
+)
+
+// PartnerSuggestionSettings specifies how partners are suggested to an athlete who is looking for a partner at a
+// competition. The height difference is the height of the lead less the height of the follow, in centimeters, and
+// the default range is used if neither end of the range is specified. Athletes of the same gender are only suggested
+// if AllowSameSex is true.
+type PartnerSuggestionSettings struct {
+	CompetitionID       int     `schema:"competition,required"`
+	AllowSameSex        bool    `schema:"sameSex"`
+	MinHeightDifference float64 `schema:"minHeightDifference"`
+	MaxHeightDifference float64 `schema:"maxHeightDifference"`
+	Limit               int     `schema:"limit"`
+}
+
+// PartnerSuggestionFactor is one of the reasons why a partner is suggested, and how much it adds to the score
+type PartnerSuggestionFactor struct {
+	Name        string
+	Score       int
+	Description string
+}
+
+// PartnerSuggestion is the TBA entry of an athlete who is a compatible partner of the current user. Suggestions with
+// higher scores are better matches.
+type PartnerSuggestion struct {
+	Entry   CompetitionTBAEntry
+	Score   int
+	Factors []PartnerSuggestionFactor
+}
+
+func (suggestion *PartnerSuggestion) addFactor(name string, score int, description string) {
+	suggestion.Factors = append(suggestion.Factors, PartnerSuggestionFactor{Name: name, Score: score, Description: description})
+	suggestion.Score += score
+}
+
+// PartnerSuggestionService ranks the TBA entries of a competition by how well the athletes who posted them would
+// partner with the current user. Only athletes who dance the opposite role and the same style, are within one
+// proficiency level of the current user, are not partners of the current user already, and have not blacklisted or
+// been blacklisted by the current user are suggested.
+type PartnerSuggestionService struct {
+	schoolRepo ISchoolRepository
+	studioRepo IStudioRepository
+	tba        CompetitionTBAService
+}
+
+func NewPartnerSuggestionService(schoolRepo ISchoolRepository, studioRepo IStudioRepository, tba CompetitionTBAService) PartnerSuggestionService {
+	return PartnerSuggestionService{
+		schoolRepo: schoolRepo,
+		studioRepo: studioRepo,
+		tba:        tba,
+	}
+}
+
+// SuggestPartners suggests partners to the current user from the TBA entries of the competition, based on the TBA
+// entry that the current user has posted for it. Suggestions are ordered from the best match, and the earliest entry
+// comes first among matches of the same score.
+func (service PartnerSuggestionService) SuggestPartners(currentUser Account, settings PartnerSuggestionSettings) ([]PartnerSuggestion, error) {
+	suggestions := make([]PartnerSuggestion, 0)
+	if settings.MinHeightDifference == 0 && settings.MaxHeightDifference == 0 {
+		settings.MinHeightDifference = DefaultPartnerMinHeightDifference
+		settings.MaxHeightDifference = DefaultPartnerMaxHeightDifference
+	}
+	if settings.MinHeightDifference > settings.MaxHeightDifference {
+		return suggestions, errors.New("minimum height difference cannot be greater than the maximum")
+	}
+	own, err := service.tba.tbaRepo.SearchCompetitionTBAEntry(SearchCompetitionTBAEntryCriteria{
+		AccountID:     currentUser.ID,
+		CompetitionID: settings.CompetitionID,
+	})
+	if err != nil {
+		return suggestions, err
+	}
+	if len(own) != 1 {
+		return suggestions, errors.New("you must post a TBA entry for this competition to get partner suggestions")
+	}
+	entry := own[0]
+
+	blocked, err := service.tba.blockedAccounts(currentUser.ID)
+	if err != nil {
+		return suggestions, err
+	}
+	partnerships, err := currentUser.GetAllPartnerships(service.tba.partnershipRepo)
+	if err != nil {
+		return suggestions, err
+	}
+	for _, each := range partnerships {
+		blocked[each.Lead.ID] = true
+		blocked[each.Follow.ID] = true
+	}
+	blocked[currentUser.ID] = true
+
+	partnerRole := TBARoleLead
+	if entry.Role == TBARoleLead {
+		partnerRole = TBARoleFollow
+	}
+	candidates, err := service.tba.tbaRepo.SearchCompetitionTBAEntry(SearchCompetitionTBAEntryCriteria{
+		CompetitionID: settings.CompetitionID,
+		Role:          partnerRole,
+	})
+	if err != nil {
+		return suggestions, err
+	}
+
+	cities := make(map[string]int)
+	for _, candidate := range candidates {
+		if blocked[candidate.AccountID] {
+			continue
+		}
+		if suggestion, ok := service.score(currentUser, entry, candidate, settings, cities); ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Entry.DateTimeCreated.Before(suggestions[j].Entry.DateTimeCreated)
+	})
+	if settings.Limit > 0 && len(suggestions) > settings.Limit {
+		suggestions = suggestions[:settings.Limit]
+	}
+	return suggestions, nil
+}
+
+// score scores the candidate as a partner of the athlete who posted the entry, and returns false if the candidate is
+// not a compatible partner
+func (service PartnerSuggestionService) score(currentUser Account, entry, candidate CompetitionTBAEntry, settings PartnerSuggestionSettings, cities map[string]int) (PartnerSuggestion, bool) {
+	suggestion := PartnerSuggestion{Entry: candidate, Factors: make([]PartnerSuggestionFactor, 0)}
+
+	if !strings.EqualFold(entry.Style, candidate.Style) {
+		return suggestion, false
+	}
+	proficiency, compatible := proficiencyDistance(entry.Proficiency, candidate.Proficiency)
+	if !compatible {
+		return suggestion, false
+	}
+	sameSex := false
+	if knownGender(currentUser.UserGenderID) {
+		partner := GetAccountByID(candidate.AccountID, service.tba.accountRepo)
+		sameSex = partner.UserGenderID == currentUser.UserGenderID
+	}
+	if sameSex && !settings.AllowSameSex {
+		return suggestion, false
+	}
+
+	if sameSex {
+		suggestion.addFactor(PartnerFactorRole, 25, fmt.Sprintf("dances %v, the opposite of your role, in a same-sex partnership", candidate.Role))
+	} else {
+		suggestion.addFactor(PartnerFactorRole, 25, fmt.Sprintf("dances %v, the opposite of your role", candidate.Role))
+	}
+	suggestion.addFactor(PartnerFactorStyle, 25, fmt.Sprintf("also dances %v", candidate.Style))
+	if proficiency == 0 {
+		suggestion.addFactor(PartnerFactorProficiency, 20, fmt.Sprintf("same proficiency: %v", candidate.Proficiency))
+	} else {
+		suggestion.addFactor(PartnerFactorProficiency, 10, fmt.Sprintf("proficiency %v is one level from your %v", candidate.Proficiency, entry.Proficiency))
+	}
+
+	lead, follow := entry, candidate
+	if entry.Role == TBARoleFollow {
+		lead, follow = candidate, entry
+	}
+	leadHeight, leadOK := heightInCentimeters(lead.Height)
+	followHeight, followOK := heightInCentimeters(follow.Height)
+	if !leadOK || !followOK {
+		suggestion.addFactor(PartnerFactorHeight, 0, "height difference is unknown")
+	} else if difference := leadHeight - followHeight; difference >= settings.MinHeightDifference && difference <= settings.MaxHeightDifference {
+		suggestion.addFactor(PartnerFactorHeight, 20, fmt.Sprintf("lead is %v cm taller than follow, within %v to %v cm",
+			math.Round(difference), settings.MinHeightDifference, settings.MaxHeightDifference))
+	} else {
+		suggestion.addFactor(PartnerFactorHeight, 0, fmt.Sprintf("lead is %v cm taller than follow, outside %v to %v cm",
+			math.Round(difference), settings.MinHeightDifference, settings.MaxHeightDifference))
+	}
+
+	if candidate.School != "" && strings.EqualFold(strings.TrimSpace(entry.School), strings.TrimSpace(candidate.School)) {
+		suggestion.addFactor(PartnerFactorSchool, 10, fmt.Sprintf("same school or studio: %v", candidate.School))
+	} else if city := service.cityOf(entry.School, cities); city > 0 && city == service.cityOf(candidate.School, cities) {
+		suggestion.addFactor(PartnerFactorSchool, 5, fmt.Sprintf("%v is in the same city as your school or studio", candidate.School))
+	}
+	return suggestion, true
+}
+
+func knownGender(gender int) bool {
+	return gender == GENDER_MALE || gender == GENDER_FEMALE
+}
+
+// proficiencyDistance returns how many levels the proficiencies are apart, and whether they are close enough for a
+// partnership. Proficiencies that are not collegiate levels are only compatible with the same proficiency.
+func proficiencyDistance(proficiency, other string) (int, bool) {
+	if strings.EqualFold(proficiency, other) {
+		return 0, true
+	}
+	level, otherLevel := -1, -1
+	for i, each := range YCNPointScale.Levels {
+		if strings.EqualFold(each, proficiency) {
+			level = i
+		}
+		if strings.EqualFold(each, other) {
+			otherLevel = i
+		}
+	}
+	if level < 0 || otherLevel < 0 {
+		return 0, false
+	}
+	distance := level - otherLevel
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance, distance <= 1
+}
+
+// cityOf returns the city of the school or studio with the name, or 0 if it is not a known school or studio
+func (service PartnerSuggestionService) cityOf(name string, cities map[string]int) int {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0
+	}
+	if city, ok := cities[name]; ok {
+		return city
+	}
+	cities[name] = 0
+	if schools, err := service.schoolRepo.SearchSchool(SearchSchoolCriteria{Name: name}); err == nil && len(schools) > 0 {
+		cities[name] = schools[0].CityID
+	} else if studios, err := service.studioRepo.SearchStudio(SearchStudioCriteria{Name: name}); err == nil && len(studios) > 0 {
+		cities[name] = studios[0].CityID
+	}
+	return cities[name]
+}
+
+var (
+	heightCentimeterPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:cm|centimeters?)?$`)
+	heightMeterPattern      = regexp.MustCompile(`^(\d(?:\.\d+)?)\s*(?:m|meters?)$`)
+	heightFeetPattern       = regexp.MustCompile(`^(\d)\s*(?:'|ft|feet|foot)\s*(?:(\d+(?:\.\d+)?)\s*(?:"|''|in|inch|inches)?)?$`)
+	heightInchPattern       = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:"|in|inch|inches)$`)
+)
+
+// heightInCentimeters parses the height of a TBA entry, such as 170 cm, 1.7 m, 5'7" and 67 in. Numbers without a unit
+// are centimeters.
+func heightInCentimeters(height string) (float64, bool) {
+	height = strings.ToLower(strings.TrimSpace(height))
+	if match := heightCentimeterPattern.FindStringSubmatch(height); match != nil {
+		centimeters, _ := strconv.ParseFloat(match[1], 64)
+		return centimeters, centimeters >= 100
+	}
+	if match := heightMeterPattern.FindStringSubmatch(height); match != nil {
+		meters, _ := strconv.ParseFloat(match[1], 64)
+		return meters * 100, meters >= 1
+	}
+	if match := heightFeetPattern.FindStringSubmatch(height); match != nil {
+		feet, _ := strconv.ParseFloat(match[1], 64)
+		inches := 0.0
+		if match[2] != "" {
+			inches, _ = strconv.ParseFloat(match[2], 64)
+		}
+		return (feet*12 + inches) * 2.54, feet >= 3
+	}
+	if match := heightInchPattern.FindStringSubmatch(height); match != nil {
+		inches, _ := strconv.ParseFloat(match[1], 64)
+		return inches * 2.54, inches >= 36
+	}
+	return 0, false
+}
@@ -0,0 +1,129 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// expectSuggestionEntries sets up the TBA entry of athlete 12, who leads at competition 3 and is already partnered with
+// athlete 45, and the entries of the follows who are looking for partners at competition 3
+func expectSuggestionEntries(
+	tbaRepo *mock_businesslogic.MockICompetitionTBAEntryRepository,
+	blacklistRepo *mock_businesslogic.MockIPartnershipRequestBlacklistRepository,
+	partnershipRepo *mock_businesslogic.MockIPartnershipRepository,
+	follows ...businesslogic.CompetitionTBAEntry,
+) {
+	lead := newTBAEntry(1, 12, businesslogic.TBARoleLead)
+	lead.Height = "180 cm"
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{AccountID: 12, CompetitionID: 3}).Return([]businesslogic.CompetitionTBAEntry{lead}, nil)
+	expectBlacklist(blacklistRepo)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{LeadID: 12}).Return([]businesslogic.Partnership{
+		{ID: 7, Lead: businesslogic.Account{ID: 12}, Follow: businesslogic.Account{ID: 45}},
+	}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{FollowID: 12}).Return([]businesslogic.Partnership{}, nil)
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{CompetitionID: 3, Role: businesslogic.TBARoleFollow}).Return(follows, nil)
+}
+
+func newFollowEntry(id, accountID int, proficiency, style, height, school string) businesslogic.CompetitionTBAEntry {
+	entry := newTBAEntry(id, accountID, businesslogic.TBARoleFollow)
+	entry.Proficiency = proficiency
+	entry.Style = style
+	entry.Height = height
+	entry.School = school
+	return entry
+}
+
+func TestPartnerSuggestionService_SuggestPartners(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	schoolRepo := mock_businesslogic.NewMockISchoolRepository(mockCtrl)
+	studioRepo := mock_businesslogic.NewMockIStudioRepository(mockCtrl)
+	tbaService := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo, tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+	service := businesslogic.NewPartnerSuggestionService(schoolRepo, studioRepo, tbaService)
+
+	_, err := service.SuggestPartners(newAthlete(12), businesslogic.PartnerSuggestionSettings{CompetitionID: 3, MinHeightDifference: 10, MaxHeightDifference: 5})
+	assert.NotNil(t, err, "should not accept an empty range of height difference")
+
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{AccountID: 12, CompetitionID: 3}).Return([]businesslogic.CompetitionTBAEntry{}, nil)
+	_, err = service.SuggestPartners(newAthlete(12), businesslogic.PartnerSuggestionSettings{CompetitionID: 3})
+	assert.NotNil(t, err, "athletes who are not looking for partners should not get suggestions")
+
+	expectSuggestionEntries(tbaRepo, blacklistRepo, partnershipRepo,
+		newFollowEntry(2, 20, "Gold", "Latin", "168 cm", "University of Texas"),
+		newFollowEntry(3, 44, "Gold", "Latin", "150 cm", "SMU"),
+		newFollowEntry(4, 41, "Silver", "latin", `5'4"`, "Texas State University"),
+		newFollowEntry(5, 40, "Gold", "Latin", "168 cm", "University of Texas"),
+		newFollowEntry(6, 42, "Bronze", "Latin", "165 cm", "University of Texas"),
+		newFollowEntry(7, 43, "Gold", "Standard", "165 cm", "University of Texas"),
+		newFollowEntry(8, 45, "Gold", "Latin", "165 cm", "University of Texas"),
+	)
+	schoolRepo.EXPECT().SearchSchool(businesslogic.SearchSchoolCriteria{Name: "University of Texas"}).Return([]businesslogic.School{{ID: 1, CityID: 5}}, nil)
+	schoolRepo.EXPECT().SearchSchool(businesslogic.SearchSchoolCriteria{Name: "SMU"}).Return([]businesslogic.School{}, nil)
+	studioRepo.EXPECT().SearchStudio(businesslogic.SearchStudioCriteria{Name: "SMU"}).Return([]businesslogic.Studio{{ID: 2, CityID: 9}}, nil)
+	schoolRepo.EXPECT().SearchSchool(businesslogic.SearchSchoolCriteria{Name: "Texas State University"}).Return([]businesslogic.School{{ID: 3, CityID: 5}}, nil)
+
+	suggestions, err := service.SuggestPartners(newAthlete(12), businesslogic.PartnerSuggestionSettings{CompetitionID: 3})
+	assert.Nil(t, err)
+	if assert.Len(t, suggestions, 3, "blocked, existing partners, other styles and distant proficiencies should not be suggested") {
+		assert.Equal(t, 5, suggestions[0].Entry.ID)
+		assert.Equal(t, 100, suggestions[0].Score)
+		assert.Equal(t, 4, suggestions[1].Entry.ID, "5'4\" is within the height range, and schools in the same city are nearby")
+		assert.Equal(t, 85, suggestions[1].Score)
+		assert.Equal(t, 3, suggestions[2].Entry.ID)
+		assert.Equal(t, 70, suggestions[2].Score)
+		factors := make(map[string]int)
+		for _, each := range suggestions[2].Factors {
+			factors[each.Name] = each.Score
+		}
+		assert.Equal(t, map[string]int{
+			businesslogic.PartnerFactorRole:        25,
+			businesslogic.PartnerFactorStyle:       25,
+			businesslogic.PartnerFactorProficiency: 20,
+			businesslogic.PartnerFactorHeight:      0,
+		}, factors, "height difference of 30 cm is outside the default range")
+	}
+}
+
+func TestPartnerSuggestionService_SuggestPartners_SameSex(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	schoolRepo := mock_businesslogic.NewMockISchoolRepository(mockCtrl)
+	studioRepo := mock_businesslogic.NewMockIStudioRepository(mockCtrl)
+	tbaService := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo, tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+	service := businesslogic.NewPartnerSuggestionService(schoolRepo, studioRepo, tbaService)
+
+	lead := newAthlete(12)
+	lead.UserGenderID = businesslogic.GENDER_MALE
+	follow := newAthlete(40)
+	follow.UserGenderID = businesslogic.GENDER_MALE
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 40}).Return([]businesslogic.Account{follow}, nil).Times(2)
+
+	expectSuggestionEntries(tbaRepo, blacklistRepo, partnershipRepo, newFollowEntry(5, 40, "Gold", "Latin", "170 cm", "University of Texas"))
+	suggestions, err := service.SuggestPartners(lead, businesslogic.PartnerSuggestionSettings{CompetitionID: 3})
+	assert.Nil(t, err)
+	assert.Len(t, suggestions, 0, "athletes of the same gender should not be suggested unless same-sex partnerships are allowed")
+
+	expectSuggestionEntries(tbaRepo, blacklistRepo, partnershipRepo, newFollowEntry(5, 40, "Gold", "Latin", "170 cm", "University of Texas"))
+	suggestions, err = service.SuggestPartners(lead, businesslogic.PartnerSuggestionSettings{CompetitionID: 3, AllowSameSex: true})
+	assert.Nil(t, err)
+	if assert.Len(t, suggestions, 1) {
+		assert.Contains(t, suggestions[0].Factors[0].Description, "same-sex")
+	}
+}
@@ -182,8 +182,30 @@ func (service OrganizerProvisionService) UpdateOrganizerCompetitionProvision(upd
 	return nil
 }
 
+// OrganizerStripeSettings contains the keys of the Stripe account that an organizer receives payments with. The
+// restricted key is used to create checkout sessions and refunds, and the webhook secret is used to verify the events
+// that Stripe sends about the payments.
 type OrganizerStripeSettings struct {
 	ID                  int
+	OrganizerID         int
 	StripePublicKey     string
 	StripeRestrictedKey string
+	StripeWebhookSecret string
+	Currency            string
+	CreateUserID        int
+	DateTimeCreated     time.Time
+	UpdateUserID        int
+	DateTimeUpdated     time.Time
+}
+
+// SearchOrganizerStripeSettingsCriteria specifies the parameters that can be used to search OrganizerStripeSettings
+type SearchOrganizerStripeSettingsCriteria struct {
+	OrganizerID int
+}
+
+// IOrganizerStripeSettingsRepository specifies the functions that an OrganizerStripeSettings Repository should implement
+type IOrganizerStripeSettingsRepository interface {
+	CreateOrganizerStripeSettings(settings *OrganizerStripeSettings) error
+	SearchOrganizerStripeSettings(criteria SearchOrganizerStripeSettingsCriteria) ([]OrganizerStripeSettings, error)
+	UpdateOrganizerStripeSettings(settings OrganizerStripeSettings) error
 }
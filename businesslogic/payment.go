@@ -0,0 +1,512 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status of payments, as defined in DAS.PAYMENT_STATUS
+const (
+	PaymentStatusPending   = 1
+	PaymentStatusSucceeded = 2
+	PaymentStatusFailed    = 3
+	PaymentStatusRefunded  = 4
+)
+
+// Purposes of payments. Each purpose has an IPaymentHandler that confirms what is paid for.
+const (
+	PaymentPurposeProductOrder    = "product_order"
+	PaymentPurposeRegistrationFee = "registration_fee"
+)
+
+// Types of events that payment gateways report
+const (
+	PaymentEventSucceeded = "payment.succeeded"
+	PaymentEventFailed    = "payment.failed"
+)
+
+// PaymentSignatureError is returned when the signature of a payment event cannot be verified
+var PaymentSignatureError = errors.New("signature of payment event is invalid")
+
+// PaymentLineItem is an item that is paid for. UnitAmount is in the major unit of the currency, such as dollars.
+type PaymentLineItem struct {
+	Name       string
+	UnitAmount float64
+	Quantity   int
+}
+
+// PaymentCharge is what a user pays for, and the competition that receives the payment
+type PaymentCharge struct {
+	CompetitionID int
+	Items         []PaymentLineItem
+}
+
+// Total returns the total amount of the charge
+func (charge PaymentCharge) Total() float64 {
+	total := 0.0
+	for _, each := range charge.Items {
+		total += each.UnitAmount * float64(each.Quantity)
+	}
+	return total
+}
+
+// PaymentCheckoutRequest is the request to create a checkout session with a payment gateway. Reference is returned in
+// the events of the session.
+type PaymentCheckoutRequest struct {
+	Reference  string
+	Currency   string
+	Items      []PaymentLineItem
+	SuccessURL string
+	CancelURL  string
+}
+
+// PaymentCheckoutSession is a checkout session of a payment gateway. Users pay on the page at URL.
+type PaymentCheckoutSession struct {
+	ID  string
+	URL string
+}
+
+// PaymentEvent is an event that a payment gateway reports about a checkout session. Type is empty if the event is not
+// about the result of a payment.
+type PaymentEvent struct {
+	Type      string
+	SessionID string
+	PaymentID string
+	Reference string
+	Amount    float64
+}
+
+// PaymentRefundRequest is the request to refund a payment. An Amount of 0 refunds the whole payment. Requests with the
+// same IdempotencyKey are only refunded once by the gateway.
+type PaymentRefundRequest struct {
+	PaymentID      string
+	Amount         float64
+	Currency       string
+	IdempotencyKey string
+}
+
+// PaymentRefund is a refund that a payment gateway has made
+type PaymentRefund struct {
+	ID     string
+	Amount float64
+	Status string
+}
+
+// IPaymentGateway specifies the functions that a payment gateway should implement. Payments are made to the account of
+// the organizer who owns the settings.
+type IPaymentGateway interface {
+	CreateCheckoutSession(settings OrganizerStripeSettings, request PaymentCheckoutRequest) (PaymentCheckoutSession, error)
+	VerifyWebhook(settings OrganizerStripeSettings, payload []byte, signature string) (PaymentEvent, error)
+	Refund(settings OrganizerStripeSettings, request PaymentRefundRequest) (PaymentRefund, error)
+}
+
+// Payment is a payment that a user makes to the organizer of a competition through a payment gateway
+type Payment struct {
+	ID               int
+	OrganizerID      int
+	CompetitionID    int
+	AccountID        int
+	Purpose          string
+	ReferenceID      int
+	Amount           float64
+	RefundedAmount   float64
+	Currency         string
+	StatusID         int
+	GatewaySessionID string
+	GatewayPaymentID string
+	CreateUserID     int
+	DateTimeCreated  time.Time
+	UpdateUserID     int
+	DateTimeUpdated  time.Time
+}
+
+// SearchPaymentCriteria specifies the parameters that can be used to search Payment
+type SearchPaymentCriteria struct {
+	ID               int    `schema:"id"`
+	CompetitionID    int    `schema:"competitionId"`
+	AccountID        int    `schema:"-"`
+	Purpose          string `schema:"purpose"`
+	ReferenceID      int    `schema:"reference"`
+	StatusID         int    `schema:"status"`
+	GatewaySessionID string `schema:"-"`
+}
+
+// PaymentRefundConflictError is returned when a payment is refunded by another request at the same time
+var PaymentRefundConflictError = errors.New("payment is being refunded by another request")
+
+// IPaymentRepository specifies the functions that a Payment Repository should implement. AddPaymentRefund adds amount
+// to the refunded amount of the payment in a single statement, and marks the payment as refunded once it is fully
+// refunded. It returns PaymentRefundConflictError if the refunded amount of the payment is no longer refundedAmount.
+type IPaymentRepository interface {
+	CreatePayment(payment *Payment) error
+	SearchPayment(criteria SearchPaymentCriteria) ([]Payment, error)
+	UpdatePayment(payment Payment) error
+	AddPaymentRefund(paymentID int, refundedAmount, amount float64, updateUserID int) error
+}
+
+// IPaymentHandler prepares and settles what a purpose of payments pays for. PreparePayment returns what the current
+// user pays for the reference. ConfirmPayment is called once the payment succeeds, and CancelPayment is called when
+// the payment fails or is fully refunded. Both must be idempotent: they are called again for the same payment when the
+// gateway delivers the event again, including after an earlier call failed.
+type IPaymentHandler interface {
+	PreparePayment(currentUser Account, referenceID int) (PaymentCharge, error)
+	ConfirmPayment(payment Payment) error
+	CancelPayment(payment Payment) error
+}
+
+// PaymentService manages the payments that users make to organizers. What is paid for is only confirmed by the
+// handler of its purpose once the payment gateway reports that the payment succeeded.
+type PaymentService struct {
+	gateway         IPaymentGateway
+	competitionRepo ICompetitionRepository
+	settingsRepo    IOrganizerStripeSettingsRepository
+	paymentRepo     IPaymentRepository
+	handlers        map[string]IPaymentHandler
+}
+
+func NewPaymentService(
+	gateway IPaymentGateway,
+	competitionRepo ICompetitionRepository,
+	settingsRepo IOrganizerStripeSettingsRepository,
+	paymentRepo IPaymentRepository,
+	handlers map[string]IPaymentHandler) PaymentService {
+	return PaymentService{
+		gateway:         gateway,
+		competitionRepo: competitionRepo,
+		settingsRepo:    settingsRepo,
+		paymentRepo:     paymentRepo,
+		handlers:        handlers,
+	}
+}
+
+func (service PaymentService) getHandler(purpose string) (IPaymentHandler, error) {
+	handler, ok := service.handlers[purpose]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("payment purpose %v is not supported", purpose))
+	}
+	return handler, nil
+}
+
+func (service PaymentService) getStripeSettings(organizerID int) (OrganizerStripeSettings, error) {
+	settings, err := service.settingsRepo.SearchOrganizerStripeSettings(SearchOrganizerStripeSettingsCriteria{OrganizerID: organizerID})
+	if err != nil {
+		return OrganizerStripeSettings{}, err
+	}
+	if len(settings) != 1 {
+		return OrganizerStripeSettings{}, errors.New("organizer does not accept online payments")
+	}
+	return settings[0], nil
+}
+
+func (service PaymentService) getPayment(criteria SearchPaymentCriteria) (Payment, error) {
+	payments, err := service.paymentRepo.SearchPayment(criteria)
+	if err != nil {
+		return Payment{}, err
+	}
+	if len(payments) != 1 {
+		return Payment{}, errors.New("cannot find payment")
+	}
+	return payments[0], nil
+}
+
+// GetStripeSettings returns the Stripe settings of the current user
+func (service PaymentService) GetStripeSettings(currentUser Account) (OrganizerStripeSettings, error) {
+	if !currentUser.HasRole(AccountTypeOrganizer) {
+		return OrganizerStripeSettings{}, errors.New("not authorized to manage payment settings")
+	}
+	return service.getStripeSettings(currentUser.ID)
+}
+
+// SaveStripeSettings creates or replaces the Stripe settings of the current user
+func (service PaymentService) SaveStripeSettings(currentUser Account, settings OrganizerStripeSettings) error {
+	if !currentUser.HasRole(AccountTypeOrganizer) {
+		return errors.New("not authorized to manage payment settings")
+	}
+	if settings.StripePublicKey == "" || settings.StripeRestrictedKey == "" || settings.StripeWebhookSecret == "" {
+		return errors.New("public key, restricted key and webhook secret are required")
+	}
+	if settings.Currency == "" {
+		settings.Currency = "usd"
+	}
+	settings.Currency = strings.ToLower(settings.Currency)
+	if len(settings.Currency) != 3 {
+		return errors.New("currency must be a three-letter ISO code")
+	}
+	existing, err := service.settingsRepo.SearchOrganizerStripeSettings(SearchOrganizerStripeSettingsCriteria{OrganizerID: currentUser.ID})
+	if err != nil {
+		return err
+	}
+	settings.OrganizerID = currentUser.ID
+	settings.UpdateUserID = currentUser.ID
+	settings.DateTimeUpdated = time.Now()
+	if len(existing) == 0 {
+		settings.CreateUserID = currentUser.ID
+		settings.DateTimeCreated = time.Now()
+		return service.settingsRepo.CreateOrganizerStripeSettings(&settings)
+	}
+	settings.ID = existing[0].ID
+	return service.settingsRepo.UpdateOrganizerStripeSettings(settings)
+}
+
+// StartCheckout creates a pending payment for what the current user pays for, and a checkout session with the
+// payment gateway of the competition organizer. The user pays on the page of the returned session. Handlers decide
+// what is left to pay, since a reference can be paid more than once, such as the balance of a registration fee after
+// events are added.
+func (service PaymentService) StartCheckout(currentUser Account, purpose string, referenceID int, successURL, cancelURL string) (Payment, PaymentCheckoutSession, error) {
+	handler, err := service.getHandler(purpose)
+	if err != nil {
+		return Payment{}, PaymentCheckoutSession{}, err
+	}
+	charge, err := handler.PreparePayment(currentUser, referenceID)
+	if err != nil {
+		return Payment{}, PaymentCheckoutSession{}, err
+	}
+	if charge.Total() <= 0 {
+		return Payment{}, PaymentCheckoutSession{}, errors.New("there is nothing to pay")
+	}
+
+	competitions, err := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: charge.CompetitionID})
+	if err != nil {
+		return Payment{}, PaymentCheckoutSession{}, err
+	}
+	if len(competitions) != 1 {
+		return Payment{}, PaymentCheckoutSession{}, errors.New(fmt.Sprintf("cannot find competition with ID = %d", charge.CompetitionID))
+	}
+	settings, err := service.getStripeSettings(competitions[0].CreateUserID)
+	if err != nil {
+		return Payment{}, PaymentCheckoutSession{}, err
+	}
+
+	payment := Payment{
+		OrganizerID:     settings.OrganizerID,
+		CompetitionID:   charge.CompetitionID,
+		AccountID:       currentUser.ID,
+		Purpose:         purpose,
+		ReferenceID:     referenceID,
+		Amount:          charge.Total(),
+		Currency:        settings.Currency,
+		StatusID:        PaymentStatusPending,
+		CreateUserID:    currentUser.ID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    currentUser.ID,
+		DateTimeUpdated: time.Now(),
+	}
+	if err := service.paymentRepo.CreatePayment(&payment); err != nil {
+		return payment, PaymentCheckoutSession{}, err
+	}
+	session, err := service.gateway.CreateCheckoutSession(settings, PaymentCheckoutRequest{
+		Reference:  strconv.Itoa(payment.ID),
+		Currency:   settings.Currency,
+		Items:      charge.Items,
+		SuccessURL: successURL,
+		CancelURL:  cancelURL,
+	})
+	if err != nil {
+		payment.StatusID = PaymentStatusFailed
+		payment.DateTimeUpdated = time.Now()
+		service.paymentRepo.UpdatePayment(payment)
+		return payment, session, err
+	}
+	payment.GatewaySessionID = session.ID
+	payment.DateTimeUpdated = time.Now()
+	return payment, session, service.paymentRepo.UpdatePayment(payment)
+}
+
+// HandleWebhook verifies an event that the payment gateway of an organizer sends, and settles the payment that the
+// event is about. What the payment pays for is confirmed again when a succeeded event is delivered again, so that a
+// confirmation that failed is completed when the gateway retries the event.
+func (service PaymentService) HandleWebhook(organizerID int, payload []byte, signature string) error {
+	settings, err := service.getStripeSettings(organizerID)
+	if err != nil {
+		return err
+	}
+	event, err := service.gateway.VerifyWebhook(settings, payload, signature)
+	if err != nil {
+		return err
+	}
+	if event.Type != PaymentEventSucceeded && event.Type != PaymentEventFailed {
+		return nil
+	}
+	payment, err := service.getPayment(SearchPaymentCriteria{GatewaySessionID: event.SessionID})
+	if err != nil {
+		return err
+	}
+	if payment.OrganizerID != organizerID {
+		return errors.New("payment is not made to this organizer")
+	}
+	handler, err := service.getHandler(payment.Purpose)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case PaymentEventSucceeded:
+		if payment.StatusID == PaymentStatusSucceeded {
+			return handler.ConfirmPayment(payment)
+		}
+		if payment.StatusID != PaymentStatusPending {
+			return errors.New(fmt.Sprintf("payment %v succeeded after it was settled", payment.ID))
+		}
+		if math.Abs(event.Amount-payment.Amount) > 0.005 {
+			return errors.New(fmt.Sprintf("payment %v is for %v, but %v was paid", payment.ID, payment.Amount, event.Amount))
+		}
+		payment.StatusID = PaymentStatusSucceeded
+		payment.GatewayPaymentID = event.PaymentID
+		payment.DateTimeUpdated = time.Now()
+		if err := service.paymentRepo.UpdatePayment(payment); err != nil {
+			return err
+		}
+		return handler.ConfirmPayment(payment)
+	default:
+		if payment.StatusID != PaymentStatusPending {
+			return nil
+		}
+		payment.StatusID = PaymentStatusFailed
+		payment.DateTimeUpdated = time.Now()
+		if err := service.paymentRepo.UpdatePayment(payment); err != nil {
+			return err
+		}
+		return handler.CancelPayment(payment)
+	}
+}
+
+// RefundPayment refunds the amount of a payment of the competition to the user. An amount of 0 refunds what has not
+// been refunded. What the payment paid for is cancelled once the payment is fully refunded.
+func (service PaymentService) RefundPayment(currentUser Account, competitionID, paymentID int, amount float64) (Payment, error) {
+	payment, err := service.getPayment(SearchPaymentCriteria{ID: paymentID, CompetitionID: competitionID})
+	if err != nil {
+		return payment, err
+	}
+	if payment.StatusID != PaymentStatusSucceeded {
+		return payment, errors.New("only succeeded payments can be refunded")
+	}
+	remaining := payment.Amount - payment.RefundedAmount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 0 || amount > remaining+0.005 {
+		return payment, errors.New(fmt.Sprintf("refund must be between 0 and %v", remaining))
+	}
+	handler, err := service.getHandler(payment.Purpose)
+	if err != nil {
+		return payment, err
+	}
+	settings, err := service.getStripeSettings(payment.OrganizerID)
+	if err != nil {
+		return payment, err
+	}
+
+	// the key identifies the refund that follows what has been refunded, so retries of the same refund are only
+	// refunded once by the gateway, and only one of concurrent refunds is recorded
+	if _, err := service.gateway.Refund(settings, PaymentRefundRequest{
+		PaymentID:      payment.GatewayPaymentID,
+		Amount:         amount,
+		Currency:       payment.Currency,
+		IdempotencyKey: fmt.Sprintf("payment-%d-refund-%.2f", payment.ID, payment.RefundedAmount),
+	}); err != nil {
+		return payment, err
+	}
+	if err := service.paymentRepo.AddPaymentRefund(payment.ID, payment.RefundedAmount, amount, currentUser.ID); err != nil {
+		log.Printf("[error] recording refund of %v for payment %v: %v", amount, payment.ID, err)
+		return payment, err
+	}
+	if payment, err = service.getPayment(SearchPaymentCriteria{ID: paymentID}); err != nil {
+		return payment, err
+	}
+	if payment.StatusID == PaymentStatusRefunded {
+		return payment, handler.CancelPayment(payment)
+	}
+	return payment, nil
+}
+
+// SearchOwnPayments returns the payments of the current user
+func (service PaymentService) SearchOwnPayments(currentUser Account, criteria SearchPaymentCriteria) ([]Payment, error) {
+	criteria.AccountID = currentUser.ID
+	return service.paymentRepo.SearchPayment(criteria)
+}
+
+// SearchCompetitionPayments returns the payments of a competition
+func (service PaymentService) SearchCompetitionPayments(criteria SearchPaymentCriteria) ([]Payment, error) {
+	if criteria.CompetitionID < 1 {
+		return nil, errors.New("competition must be specified")
+	}
+	return service.paymentRepo.SearchPayment(criteria)
+}
+
+// IRegistrationFeeCalculator calculates the fee that an athlete pays for a competition entry
+type IRegistrationFeeCalculator interface {
+	CalculateRegistrationFee(entry AthleteCompetitionEntry) ([]PaymentLineItem, error)
+}
+
+// RegistrationFeePaymentHandler handles the payments of registration fees. The competition entry of the athlete is
+// marked as paid once the payment succeeds.
+type RegistrationFeePaymentHandler struct {
+	entryRepo  IAthleteCompetitionEntryRepository
+	calculator IRegistrationFeeCalculator
+}
+
+func NewRegistrationFeePaymentHandler(entryRepo IAthleteCompetitionEntryRepository, calculator IRegistrationFeeCalculator) RegistrationFeePaymentHandler {
+	return RegistrationFeePaymentHandler{
+		entryRepo:  entryRepo,
+		calculator: calculator,
+	}
+}
+
+func (handler RegistrationFeePaymentHandler) getEntry(entryID int) (AthleteCompetitionEntry, error) {
+	entries, err := handler.entryRepo.SearchEntry(SearchAthleteCompetitionEntryCriteria{ID: entryID})
+	if err != nil {
+		return AthleteCompetitionEntry{}, err
+	}
+	if len(entries) != 1 {
+		return AthleteCompetitionEntry{}, errors.New(fmt.Sprintf("cannot find competition entry with ID = %v", entryID))
+	}
+	return entries[0], nil
+}
+
+// PreparePayment returns the registration fee of the competition entry of the current user
+func (handler RegistrationFeePaymentHandler) PreparePayment(currentUser Account, entryID int) (PaymentCharge, error) {
+	entry, err := handler.getEntry(entryID)
+	if err != nil {
+		return PaymentCharge{}, err
+	}
+	if entry.Athlete.ID != currentUser.ID {
+		return PaymentCharge{}, errors.New("not authorized to pay for this competition entry")
+	}
+	if entry.PaymentReceivedIndicator {
+		return PaymentCharge{}, errors.New("registration fee has been paid")
+	}
+	items, err := handler.calculator.CalculateRegistrationFee(entry)
+	if err != nil {
+		return PaymentCharge{}, err
+	}
+	return PaymentCharge{CompetitionID: entry.Competition.ID, Items: items}, nil
+}
+
+// ConfirmPayment marks the competition entry as paid
+func (handler RegistrationFeePaymentHandler) ConfirmPayment(payment Payment) error {
+	return handler.setPaid(payment.ReferenceID, true)
+}
+
+// CancelPayment marks the competition entry as unpaid
+func (handler RegistrationFeePaymentHandler) CancelPayment(payment Payment) error {
+	return handler.setPaid(payment.ReferenceID, false)
+}
+
+func (handler RegistrationFeePaymentHandler) setPaid(entryID int, paid bool) error {
+	entry, err := handler.getEntry(entryID)
+	if err != nil {
+		return err
+	}
+	if entry.PaymentReceivedIndicator == paid {
+		return nil
+	}
+	entry.PaymentReceivedIndicator = paid
+	entry.DateTimeOfPayment = time.Now()
+	entry.DateTimeUpdated = time.Now()
+	return handler.entryRepo.UpdateEntry(entry)
+}
@@ -0,0 +1,204 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/DancesportSoftware/das/payment/fake"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPaymentService_StartCheckout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gateway := fake.NewPaymentGateway()
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	settingsRepo := mock_businesslogic.NewMockIOrganizerStripeSettingsRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	handler := mock_businesslogic.NewMockIPaymentHandler(mockCtrl)
+	service := businesslogic.NewPaymentService(gateway, competitionRepo, settingsRepo, paymentRepo,
+		map[string]businesslogic.IPaymentHandler{businesslogic.PaymentPurposeProductOrder: handler})
+
+	user := businesslogic.Account{ID: 12}
+	handler.EXPECT().PreparePayment(user, 8).Return(businesslogic.PaymentCharge{
+		CompetitionID: 3,
+		Items:         []businesslogic.PaymentLineItem{{Name: "Rumba Workshop", UnitAmount: 25, Quantity: 2}},
+	}, nil)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{{ID: 3, CreateUserID: 41}}, nil)
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{
+		{ID: 1, OrganizerID: 41, StripeRestrictedKey: "rk_test", StripeWebhookSecret: "whsec_test", Currency: "usd"},
+	}, nil)
+	paymentRepo.EXPECT().CreatePayment(gomock.Any()).DoAndReturn(func(payment *businesslogic.Payment) error {
+		payment.ID = 9
+		return nil
+	})
+	paymentRepo.EXPECT().UpdatePayment(gomock.Any()).Return(nil)
+
+	payment, session, err := service.StartCheckout(user, businesslogic.PaymentPurposeProductOrder, 8, "https://das/success", "https://das/cancel")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 50, payment.Amount)
+	assert.Equal(t, businesslogic.PaymentStatusPending, payment.StatusID, "payment should be pending until the gateway reports it")
+	assert.Equal(t, session.ID, payment.GatewaySessionID)
+
+	created, ok := gateway.Session(session.ID)
+	assert.True(t, ok)
+	assert.Equal(t, "9", created.Request.Reference, "checkout should reference the payment")
+	assert.Equal(t, "rk_test", created.Settings.StripeRestrictedKey, "checkout should use the keys of the organizer")
+
+	_, _, err = service.StartCheckout(user, "donation", 1, "", "")
+	assert.NotNil(t, err, "should not pay for unsupported purposes")
+}
+
+func TestPaymentService_HandleWebhook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gateway := fake.NewPaymentGateway()
+	settingsRepo := mock_businesslogic.NewMockIOrganizerStripeSettingsRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	handler := mock_businesslogic.NewMockIPaymentHandler(mockCtrl)
+	service := businesslogic.NewPaymentService(gateway, mock_businesslogic.NewMockICompetitionRepository(mockCtrl), settingsRepo, paymentRepo,
+		map[string]businesslogic.IPaymentHandler{businesslogic.PaymentPurposeProductOrder: handler})
+
+	settings := businesslogic.OrganizerStripeSettings{ID: 1, OrganizerID: 41, StripeRestrictedKey: "rk_test", StripeWebhookSecret: "whsec_test", Currency: "usd"}
+	session, _ := gateway.CreateCheckoutSession(settings, businesslogic.PaymentCheckoutRequest{
+		Reference: "9",
+		Currency:  "usd",
+		Items:     []businesslogic.PaymentLineItem{{Name: "Rumba Workshop", UnitAmount: 25, Quantity: 2}},
+	})
+	payload, signature, _ := gateway.Pay(session.ID)
+	pending := businesslogic.Payment{
+		ID:               9,
+		OrganizerID:      41,
+		CompetitionID:    3,
+		Purpose:          businesslogic.PaymentPurposeProductOrder,
+		ReferenceID:      8,
+		Amount:           50,
+		Currency:         "usd",
+		StatusID:         businesslogic.PaymentStatusPending,
+		GatewaySessionID: session.ID,
+	}
+
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	err := service.HandleWebhook(41, payload, "forged")
+	assert.Equal(t, businesslogic.PaymentSignatureError, err)
+
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{GatewaySessionID: session.ID}).Return([]businesslogic.Payment{pending}, nil)
+	paymentRepo.EXPECT().UpdatePayment(gomock.Any()).DoAndReturn(func(payment businesslogic.Payment) error {
+		assert.Equal(t, businesslogic.PaymentStatusSucceeded, payment.StatusID)
+		assert.Equal(t, "pi_"+session.ID, payment.GatewayPaymentID)
+		return nil
+	})
+	handler.EXPECT().ConfirmPayment(gomock.Any()).Return(nil)
+	assert.Nil(t, service.HandleWebhook(41, payload, signature))
+
+	// the gateway delivers the same event again, which confirms the payment again without changing it
+	paid := pending
+	paid.StatusID = businesslogic.PaymentStatusSucceeded
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{GatewaySessionID: session.ID}).Return([]businesslogic.Payment{paid}, nil)
+	handler.EXPECT().ConfirmPayment(paid).Return(nil)
+	assert.Nil(t, service.HandleWebhook(41, payload, signature), "should confirm payments that are delivered again")
+}
+
+func TestPaymentService_HandleWebhook_Failed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gateway := fake.NewPaymentGateway()
+	settingsRepo := mock_businesslogic.NewMockIOrganizerStripeSettingsRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	handler := mock_businesslogic.NewMockIPaymentHandler(mockCtrl)
+	service := businesslogic.NewPaymentService(gateway, mock_businesslogic.NewMockICompetitionRepository(mockCtrl), settingsRepo, paymentRepo,
+		map[string]businesslogic.IPaymentHandler{businesslogic.PaymentPurposeProductOrder: handler})
+
+	settings := businesslogic.OrganizerStripeSettings{ID: 1, OrganizerID: 41, StripeRestrictedKey: "rk_test", StripeWebhookSecret: "whsec_test", Currency: "usd"}
+	session, _ := gateway.CreateCheckoutSession(settings, businesslogic.PaymentCheckoutRequest{Reference: "9", Currency: "usd"})
+	payload, signature, _ := gateway.Fail(session.ID)
+
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{GatewaySessionID: session.ID}).Return([]businesslogic.Payment{{
+		ID:               9,
+		OrganizerID:      41,
+		Purpose:          businesslogic.PaymentPurposeProductOrder,
+		ReferenceID:      8,
+		Amount:           50,
+		StatusID:         businesslogic.PaymentStatusPending,
+		GatewaySessionID: session.ID,
+	}}, nil)
+	paymentRepo.EXPECT().UpdatePayment(gomock.Any()).DoAndReturn(func(payment businesslogic.Payment) error {
+		assert.Equal(t, businesslogic.PaymentStatusFailed, payment.StatusID)
+		return nil
+	})
+	handler.EXPECT().CancelPayment(gomock.Any()).Return(nil)
+	assert.Nil(t, service.HandleWebhook(41, payload, signature))
+}
+
+func TestPaymentService_RefundPayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gateway := fake.NewPaymentGateway()
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	settingsRepo := mock_businesslogic.NewMockIOrganizerStripeSettingsRepository(mockCtrl)
+	paymentRepo := mock_businesslogic.NewMockIPaymentRepository(mockCtrl)
+	handler := mock_businesslogic.NewMockIPaymentHandler(mockCtrl)
+	service := businesslogic.NewPaymentService(gateway, competitionRepo, settingsRepo, paymentRepo,
+		map[string]businesslogic.IPaymentHandler{businesslogic.PaymentPurposeProductOrder: handler})
+
+	settings := businesslogic.OrganizerStripeSettings{ID: 1, OrganizerID: 41, StripeRestrictedKey: "rk_test", StripeWebhookSecret: "whsec_test", Currency: "usd"}
+	paid := businesslogic.Payment{
+		ID:               9,
+		OrganizerID:      41,
+		CompetitionID:    3,
+		Purpose:          businesslogic.PaymentPurposeProductOrder,
+		ReferenceID:      8,
+		Amount:           50,
+		Currency:         "usd",
+		StatusID:         businesslogic.PaymentStatusSucceeded,
+		GatewayPaymentID: "pi_1",
+	}
+
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9, CompetitionID: 4}).Return([]businesslogic.Payment{}, nil)
+	_, err := service.RefundPayment(newOrganizer(41), 4, 9, 0)
+	assert.NotNil(t, err, "should not refund payments of other competitions")
+
+	// partial refund keeps the order
+	partiallyRefunded := paid
+	partiallyRefunded.RefundedAmount = 20
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9, CompetitionID: 3}).Return([]businesslogic.Payment{paid}, nil)
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().AddPaymentRefund(9, 0.0, 20.0, 41).Return(nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9}).Return([]businesslogic.Payment{partiallyRefunded}, nil)
+	refunded, err := service.RefundPayment(newOrganizer(41), 3, 9, 20)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 20, refunded.RefundedAmount)
+	assert.Equal(t, businesslogic.PaymentStatusSucceeded, refunded.StatusID)
+
+	// another refund of the same amount at the same time is refunded by the gateway once, and is not recorded
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9, CompetitionID: 3}).Return([]businesslogic.Payment{paid}, nil)
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().AddPaymentRefund(9, 0.0, 20.0, 41).Return(businesslogic.PaymentRefundConflictError)
+	_, err = service.RefundPayment(newOrganizer(41), 3, 9, 20)
+	assert.Equal(t, businesslogic.PaymentRefundConflictError, err)
+	assert.Len(t, gateway.Refunds(), 1, "should not refund twice")
+
+	// full refund of the rest cancels the order
+	fullyRefunded := paid
+	fullyRefunded.RefundedAmount = 50
+	fullyRefunded.StatusID = businesslogic.PaymentStatusRefunded
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9, CompetitionID: 3}).Return([]businesslogic.Payment{partiallyRefunded}, nil)
+	settingsRepo.EXPECT().SearchOrganizerStripeSettings(businesslogic.SearchOrganizerStripeSettingsCriteria{OrganizerID: 41}).Return([]businesslogic.OrganizerStripeSettings{settings}, nil)
+	paymentRepo.EXPECT().AddPaymentRefund(9, 20.0, 30.0, 41).Return(nil)
+	paymentRepo.EXPECT().SearchPayment(businesslogic.SearchPaymentCriteria{ID: 9}).Return([]businesslogic.Payment{fullyRefunded}, nil)
+	handler.EXPECT().CancelPayment(fullyRefunded).Return(nil)
+	refunded, err = service.RefundPayment(newOrganizer(41), 3, 9, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, businesslogic.PaymentStatusRefunded, refunded.StatusID)
+	assert.Len(t, gateway.Refunds(), 2)
+	assert.EqualValues(t, 30, gateway.Refunds()[1].Amount)
+	assert.Equal(t, "payment-9-refund-20.00", gateway.Refunds()[1].IdempotencyKey)
+}
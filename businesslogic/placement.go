@@ -3,13 +3,15 @@ package businesslogic
 import "time"
 
 // Placement defines the minimal data for a dance placement: Round + Dance + Adjudicator + Partnership + Placement together
-// defines a unique placement
+// defines a unique placement. In a preliminary round, the placement is the callback of the adjudicator.
 type Placement struct {
 	ID                        int
+	RoundID                   int
+	EventDanceID              int
 	AdjudicatorRoundEntryID   int
 	PartnershipRoundEntryID   int
-	RoundDanceID              int
 	PreliminaryRoundIndicator bool
+	Callback                  bool
 	Placement                 int
 	CreateUserID              int
 	DateTimeCreated           time.Time
@@ -19,9 +21,13 @@ type Placement struct {
 
 // SearchPlacementCriteria specifies the parameters that can be used to search Placement in a repository
 type SearchPlacementCriteria struct {
-	CompetitionID int
-	EventID       int
-	PartnershipID int
+	CompetitionID           int
+	EventID                 int
+	PartnershipID           int
+	RoundID                 int
+	EventDanceID            int
+	AdjudicatorRoundEntryID int
+	PartnershipRoundEntryID int
 }
 
 // IPlacementRepository specifies the functions that a Placement Repository should implement
@@ -0,0 +1,182 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CompetitionAccessDeniedError is returned when a policy does not allow the account to act on the competition
+var CompetitionAccessDeniedError = errors.New("not authorized to access this competition")
+
+// CompetitionPolicy specifies who may take an action on a competition, and when. The organizer who created the
+// competition is allowed if OwnerAllowed is true. Officials of the competition are allowed if their role is one of
+// OfficialRoles, the account still has the role, and the position is effective at the time of the request. Organizers
+// who are assigned to the competition as officials are its co-organizers. Administrators of DAS are allowed if
+// Administrators is true.
+//
+// Policies are checked by the controllers before services are called, so services act on the competition that the
+// controller authorized, and check that the records of a request belong to that competition.
+type CompetitionPolicy struct {
+	Name           string
+	OfficialRoles  []int
+	Statuses       []int // statuses of the competition in which the action is allowed. All statuses are allowed if empty.
+	OwnerAllowed   bool
+	Administrators bool
+}
+
+// ManageCompetitionPolicy allows organizers and co-organizers to view and manage the data of the competition at any
+// time, such as payments, finance and entries
+var ManageCompetitionPolicy = CompetitionPolicy{
+	Name:          "manage competition",
+	OwnerAllowed:  true,
+	OfficialRoles: []int{AccountTypeOrganizer},
+}
+
+// ChangeCompetitionPolicy allows organizers and co-organizers to change the settings of the competition until the
+// competition is closed or cancelled
+var ChangeCompetitionPolicy = CompetitionPolicy{
+	Name:          "change competition",
+	OwnerAllowed:  true,
+	OfficialRoles: []int{AccountTypeOrganizer},
+	Statuses: []int{
+		CompetitionStatusPreRegistration,
+		CompetitionStatusOpenRegistration,
+		CompetitionStatusClosedRegistration,
+		CompetitionStatusInProgress,
+		CompetitionStatusProcessing,
+	},
+}
+
+// RunCompetitionPolicy allows the officials who run the floor to use the documents of the competition from the
+// close of registration until the results are processed
+var RunCompetitionPolicy = CompetitionPolicy{
+	Name:          "run competition",
+	OwnerAllowed:  true,
+	OfficialRoles: []int{AccountTypeOrganizer, AccountTypeScrutineer, AccountTypeDeckCaptain},
+	Statuses: []int{
+		CompetitionStatusClosedRegistration,
+		CompetitionStatusInProgress,
+		CompetitionStatusProcessing,
+	},
+}
+
+// ViewCompetitionSchedulePolicy allows all officials of the competition to view its schedule
+var ViewCompetitionSchedulePolicy = CompetitionPolicy{
+	Name:         "view competition schedule",
+	OwnerAllowed: true,
+	OfficialRoles: []int{
+		AccountTypeOrganizer,
+		AccountTypeAdjudicator,
+		AccountTypeScrutineer,
+		AccountTypeDeckCaptain,
+		AccountTypeEmcee,
+	},
+}
+
+// RunRoundsPolicy allows organizers, co-organizers and scrutineers of the competition to advance couples between
+// rounds and draw the heats of rounds
+var RunRoundsPolicy = CompetitionPolicy{
+	Name:          "run rounds",
+	OwnerAllowed:  true,
+	OfficialRoles: []int{AccountTypeOrganizer, AccountTypeScrutineer},
+}
+
+// ScoreCompetitionPolicy allows the scrutineers of the competition to manage the marks and results of its rounds.
+// The organizer who created the competition must also be appointed as a scrutineer to score it.
+var ScoreCompetitionPolicy = CompetitionPolicy{
+	Name:          "score competition",
+	OfficialRoles: []int{AccountTypeScrutineer},
+}
+
+// RefundPaymentPolicy allows organizers, co-organizers and administrators of DAS to refund the payments of the
+// competition at any time
+var RefundPaymentPolicy = CompetitionPolicy{
+	Name:           "refund payments",
+	OwnerAllowed:   true,
+	OfficialRoles:  []int{AccountTypeOrganizer},
+	Administrators: true,
+}
+
+// AllowsStatus checks if the action is allowed when the competition is in the specified status
+func (policy CompetitionPolicy) AllowsStatus(statusID int) bool {
+	if len(policy.Statuses) == 0 {
+		return true
+	}
+	for _, each := range policy.Statuses {
+		if each == statusID {
+			return true
+		}
+	}
+	return false
+}
+
+// CompetitionAuthorizationService answers whether an account may take an action on a competition
+type CompetitionAuthorizationService struct {
+	competitionRepo ICompetitionRepository
+	officialRepo    ICompetitionOfficialRepository
+	Now             func() time.Time
+}
+
+func NewCompetitionAuthorizationService(competitionRepo ICompetitionRepository, officialRepo ICompetitionOfficialRepository) CompetitionAuthorizationService {
+	return CompetitionAuthorizationService{
+		competitionRepo: competitionRepo,
+		officialRepo:    officialRepo,
+		Now:             time.Now,
+	}
+}
+
+// Authorize returns the competition if the policy allows the account to act on it. Officials are not checked if the
+// service does not have an official repository.
+func (service CompetitionAuthorizationService) Authorize(account Account, competitionID int, policy CompetitionPolicy) (Competition, error) {
+	competitions, err := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: competitionID})
+	if err != nil {
+		return Competition{}, err
+	}
+	if len(competitions) != 1 {
+		return Competition{}, errors.New(fmt.Sprintf("cannot find competition with ID = %d", competitionID))
+	}
+	competition := competitions[0]
+
+	allowed := policy.OwnerAllowed && account.HasRole(AccountTypeOrganizer) && competition.CreateUserID == account.ID
+	if policy.Administrators && account.HasRole(AccountTypeAdministrator) {
+		allowed = true
+	}
+	if !allowed {
+		if allowed, err = service.isOfficial(account, competitionID, policy.OfficialRoles); err != nil {
+			return competition, err
+		}
+	}
+	if !allowed {
+		return competition, CompetitionAccessDeniedError
+	}
+	if !policy.AllowsStatus(competition.GetStatus()) {
+		return competition, errors.New(fmt.Sprintf("cannot %v in the current status of the competition", policy.Name))
+	}
+	return competition, nil
+}
+
+// isOfficial checks if the account has an effective position at the competition with one of the roles
+func (service CompetitionAuthorizationService) isOfficial(account Account, competitionID int, roles []int) (bool, error) {
+	if service.officialRepo == nil {
+		return false, nil
+	}
+	for _, role := range roles {
+		if !account.HasRole(role) {
+			continue
+		}
+		officials, err := service.officialRepo.SearchCompetitionOfficial(SearchCompetitionOfficialCriteria{
+			CompetitionID:  competitionID,
+			OfficialRoleID: role,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, each := range officials {
+			if each.Official.ID == account.ID && each.ActiveAt(service.Now()) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,209 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// activeOfficials returns the positions of the accounts who serve with the role from yesterday until tomorrow
+func activeOfficials(roleID int, accountIDs ...int) []businesslogic.CompetitionOfficial {
+	officials := make([]businesslogic.CompetitionOfficial, 0)
+	for _, each := range accountIDs {
+		officials = append(officials, businesslogic.CompetitionOfficial{
+			Official:       businesslogic.Account{ID: each},
+			OfficialRoleID: roleID,
+			EffectiveFrom:  time.Now().AddDate(0, 0, -1),
+			EffectiveUntil: time.Now().AddDate(0, 0, 1),
+		})
+	}
+	return officials
+}
+
+func newAccount(id int, roles ...int) businesslogic.Account {
+	account := businesslogic.Account{ID: id}
+	accountRoles := make([]businesslogic.AccountRole, 0)
+	for _, each := range roles {
+		accountRoles = append(accountRoles, businesslogic.AccountRole{AccountID: id, AccountTypeID: each})
+	}
+	account.SetRoles(accountRoles)
+	return account
+}
+
+func TestCompetitionAuthorizationService_Authorize_Organizer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competition := businesslogic.Competition{ID: 3, CreateUserID: 41}
+	competition.UpdateStatus(businesslogic.CompetitionStatusOpenRegistration)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{competition}, nil).Times(2)
+
+	authorized, err := service.Authorize(newOrganizer(41), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Nil(t, err, "the organizer who created the competition should manage it without being an official")
+	assert.Equal(t, 3, authorized.ID)
+
+	_, err = service.Authorize(newOrganizer(41), 3, businesslogic.RunCompetitionPolicy)
+	assert.NotNil(t, err, "competitions should not be run before registration is closed")
+}
+
+func TestCompetitionAuthorizationService_Authorize_CoOrganizer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competition := businesslogic.Competition{ID: 3, CreateUserID: 41}
+	competition.UpdateStatus(businesslogic.CompetitionStatusOpenRegistration)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{competition}, nil).Times(4)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeOrganizer,
+	}).Return(activeOfficials(businesslogic.AccountTypeOrganizer, 42), nil).Times(3)
+
+	_, err := service.Authorize(newOrganizer(42), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Nil(t, err, "co-organizers should manage the competition")
+
+	_, err = service.Authorize(newOrganizer(42), 3, businesslogic.ChangeCompetitionPolicy)
+	assert.Nil(t, err, "co-organizers should change the competition")
+
+	_, err = service.Authorize(newOrganizer(43), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "organizers of other competitions should not manage the competition")
+
+	_, err = service.Authorize(newAccount(42, businesslogic.AccountTypeAthlete), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "co-organizers who are no longer organizers should not manage the competition")
+}
+
+func TestCompetitionAuthorizationService_Authorize_Officials(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competition := businesslogic.Competition{ID: 3, CreateUserID: 41}
+	competition.UpdateStatus(businesslogic.CompetitionStatusInProgress)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{competition}, nil).Times(4)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeScrutineer,
+	}).Return(activeOfficials(businesslogic.AccountTypeScrutineer, 11), nil).Times(2)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeDeckCaptain,
+	}).Return(activeOfficials(businesslogic.AccountTypeDeckCaptain, 12), nil)
+
+	_, err := service.Authorize(newScrutineer(11), 3, businesslogic.RunCompetitionPolicy)
+	assert.Nil(t, err)
+
+	_, err = service.Authorize(newAccount(12, businesslogic.AccountTypeDeckCaptain), 3, businesslogic.RunCompetitionPolicy)
+	assert.Nil(t, err)
+
+	_, err = service.Authorize(newScrutineer(11), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "scrutineers should not manage the competition")
+
+	_, err = service.Authorize(newScrutineer(12), 3, businesslogic.RunCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "officials should only act in the roles they are assigned")
+}
+
+func TestCompetitionAuthorizationService_Authorize_EffectivePeriod(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{{ID: 3, CreateUserID: 41}}, nil).Times(2)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeEmcee,
+	}).Return([]businesslogic.CompetitionOfficial{{
+		Official:       businesslogic.Account{ID: 13},
+		OfficialRoleID: businesslogic.AccountTypeEmcee,
+		EffectiveFrom:  time.Now().AddDate(0, 0, -7),
+		EffectiveUntil: time.Now().AddDate(0, 0, -1),
+	}}, nil).Times(2)
+
+	_, err := service.Authorize(newAccount(13, businesslogic.AccountTypeEmcee), 3, businesslogic.ViewCompetitionSchedulePolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "officials should not act after their positions end")
+
+	service.Now = func() time.Time { return time.Now().AddDate(0, 0, -2) }
+	_, err = service.Authorize(newAccount(13, businesslogic.AccountTypeEmcee), 3, businesslogic.ViewCompetitionSchedulePolicy)
+	assert.Nil(t, err)
+}
+
+func TestCompetitionAuthorizationService_Authorize_ScoreCompetition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{{ID: 3, CreateUserID: 41}}, nil).Times(3)
+	officialRepo.EXPECT().SearchCompetitionOfficial(businesslogic.SearchCompetitionOfficialCriteria{
+		CompetitionID:  3,
+		OfficialRoleID: businesslogic.AccountTypeScrutineer,
+	}).Return(activeOfficials(businesslogic.AccountTypeScrutineer, 11), nil)
+
+	_, err := service.Authorize(newScrutineer(11), 3, businesslogic.ScoreCompetitionPolicy)
+	assert.Nil(t, err, "scrutineers of the competition should score it")
+
+	_, err = service.Authorize(newOrganizer(42), 3, businesslogic.ScoreCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "co-organizers should not score the competition")
+
+	_, err = service.Authorize(newAccount(12, businesslogic.AccountTypeDeckCaptain), 3, businesslogic.ScoreCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "deck captains should not score the competition")
+}
+
+func TestCompetitionAuthorizationService_Authorize_ScoreOwnCompetition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	// account 41 created the competition, and is both an organizer and a scrutineer
+	owner := newAccount(41, businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{{ID: 3, CreateUserID: 41}}, nil).Times(3)
+	scrutineers := businesslogic.SearchCompetitionOfficialCriteria{CompetitionID: 3, OfficialRoleID: businesslogic.AccountTypeScrutineer}
+	officialRepo.EXPECT().SearchCompetitionOfficial(scrutineers).Return(activeOfficials(businesslogic.AccountTypeScrutineer, 11), nil)
+
+	_, err := service.Authorize(owner, 3, businesslogic.ScoreCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "organizers should not score their own competitions without being appointed as scrutineers")
+
+	_, err = service.Authorize(owner, 3, businesslogic.RunRoundsPolicy)
+	assert.Nil(t, err, "organizers should run the rounds of their own competitions")
+
+	officialRepo.EXPECT().SearchCompetitionOfficial(scrutineers).Return(activeOfficials(businesslogic.AccountTypeScrutineer, 41), nil)
+	_, err = service.Authorize(owner, 3, businesslogic.ScoreCompetitionPolicy)
+	assert.Nil(t, err, "organizers who are appointed as scrutineers should score their own competitions")
+}
+
+func TestCompetitionAuthorizationService_Authorize_Administrator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	officialRepo := mock_businesslogic.NewMockICompetitionOfficialRepository(mockCtrl)
+	service := businesslogic.NewCompetitionAuthorizationService(competitionRepo, officialRepo)
+
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{{ID: 3, CreateUserID: 41}}, nil).Times(2)
+
+	_, err := service.Authorize(newAccount(1, businesslogic.AccountTypeAdministrator), 3, businesslogic.RefundPaymentPolicy)
+	assert.Nil(t, err, "administrators should refund payments of any competition")
+
+	_, err = service.Authorize(newAccount(1, businesslogic.AccountTypeAdministrator), 3, businesslogic.ManageCompetitionPolicy)
+	assert.Equal(t, businesslogic.CompetitionAccessDeniedError, err, "administrators should only act where the policy allows them")
+}
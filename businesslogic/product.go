@@ -0,0 +1,416 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Categories of competition products, as defined in DAS.COMPETITION_PRODUCT_CATEGORY
+const (
+	ProductCategoryAthleteCompetitionPass   = 1
+	ProductCategoryAthleteEventPass         = 2
+	ProductCategorySpectatorCompetitionPass = 3
+	ProductCategorySpectatorEventPass       = 4
+	ProductCategorySpectatorSessionPass     = 5
+	ProductCategoryPrivateLesson            = 6
+	ProductCategoryWorkshop                 = 7
+)
+
+// Status of competition products, as defined in DAS.COMPETITION_PRODUCT_STATUS
+const (
+	ProductStatusDraft        = 1
+	ProductStatusActive       = 2
+	ProductStatusDiscontinued = 3
+)
+
+// Status of product orders, as defined in DAS.COMPETITION_PRODUCT_ORDER_STATUS
+const (
+	ProductOrderStatusPending   = 1
+	ProductOrderStatusPaid      = 2
+	ProductOrderStatusCancelled = 3
+	ProductOrderStatusRefunded  = 4
+)
+
+// ProductSoldOutError is returned when an order asks for more products than are available
+var ProductSoldOutError = errors.New("not enough products are available for this order")
+
+// ProductCategory is the type of product that competitions sell, such as passes, lessons and workshops
+type ProductCategory struct {
+	ID              int
+	Name            string
+	Description     string
+	DateTimeCreated time.Time
+	DateTimeUpdated time.Time
+}
+
+// IProductCategoryRepository specifies the functions that a ProductCategory Repository should implement
+type IProductCategoryRepository interface {
+	GetProductCategories() ([]ProductCategory, error)
+}
+
+// CompetitionProduct is a product that a competition sells. A MaximumAmount of 0 means that the inventory of the
+// product is unlimited. Products can be ordered from the first day to the last day of the effective window.
+type CompetitionProduct struct {
+	ID              int
+	CompetitionID   int
+	CategoryID      int
+	StatusID        int
+	Title           string
+	Detail          string
+	Cost            float64
+	MaximumAmount   int
+	AvailableAmount int
+	EffectiveStart  time.Time
+	EffectiveEnd    time.Time
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// IsUnlimited checks if the inventory of the product is unlimited
+func (product CompetitionProduct) IsUnlimited() bool {
+	return product.MaximumAmount == 0
+}
+
+// CanOrder checks if the quantity of the product can be ordered at the specified time
+func (product CompetitionProduct) CanOrder(quantity int, at time.Time) error {
+	if product.StatusID != ProductStatusActive {
+		return errors.New("product is not available for order")
+	}
+	start := truncateToDate(product.EffectiveStart)
+	end := truncateToDate(product.EffectiveEnd).AddDate(0, 0, 1)
+	if at.Before(start) || !at.Before(end) {
+		return errors.New("product can only be ordered during its effective window")
+	}
+	if quantity < 1 {
+		return errors.New("quantity must be at least 1")
+	}
+	if !product.IsUnlimited() && quantity > product.AvailableAmount {
+		return ProductSoldOutError
+	}
+	return nil
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// SearchCompetitionProductCriteria specifies the parameters that can be used to search CompetitionProduct
+type SearchCompetitionProductCriteria struct {
+	ID            int `schema:"id"`
+	CompetitionID int `schema:"competitionId"`
+	CategoryID    int `schema:"category"`
+	StatusID      int `schema:"status"`
+}
+
+// ICompetitionProductRepository specifies the functions that a CompetitionProduct Repository should implement.
+// UpdateCompetitionProduct must adjust the available amount by the change of the maximum amount in the same
+// statement, so that the update does not race with orders.
+type ICompetitionProductRepository interface {
+	CreateCompetitionProduct(product *CompetitionProduct) error
+	DeleteCompetitionProduct(product CompetitionProduct) error
+	SearchCompetitionProduct(criteria SearchCompetitionProductCriteria) ([]CompetitionProduct, error)
+	UpdateCompetitionProduct(product CompetitionProduct) error
+}
+
+// ProductOrder is an order of a product by a user. Orders that cost money are pending until they are paid, and hold
+// the products that they order in the meantime.
+type ProductOrder struct {
+	ID              int
+	CompetitionID   int
+	ProductID       int
+	UserAccountID   int
+	Quantity        int
+	TotalCost       float64
+	StatusID        int
+	DateTimeCreated time.Time
+	DateTimeUpdated time.Time
+}
+
+// SearchProductOrderCriteria specifies the parameters that can be used to search ProductOrder
+type SearchProductOrderCriteria struct {
+	ID            int `schema:"id"`
+	CompetitionID int `schema:"competitionId"`
+	ProductID     int `schema:"product"`
+	UserAccountID int
+}
+
+// IProductOrderRepository specifies the functions that a ProductOrder Repository should implement.
+// CreateProductOrder must decrement the available amount of the product and create the order atomically, and return
+// ProductSoldOutError without creating the order if the product does not have enough inventory left.
+// CancelProductOrder must change the status of the order and return its quantity to the available amount atomically,
+// and return an error without changing anything if the order is already cancelled or refunded.
+type IProductOrderRepository interface {
+	CreateProductOrder(order *ProductOrder) error
+	SearchProductOrder(criteria SearchProductOrderCriteria) ([]ProductOrder, error)
+	UpdateProductOrder(order ProductOrder) error
+	CancelProductOrder(order ProductOrder) error
+}
+
+// CompetitionProductService manages the products that organizers sell at their competitions, and the orders of users
+type CompetitionProductService struct {
+	categoryRepo IProductCategoryRepository
+	productRepo  ICompetitionProductRepository
+	orderRepo    IProductOrderRepository
+}
+
+func NewCompetitionProductService(
+	categoryRepo IProductCategoryRepository,
+	productRepo ICompetitionProductRepository,
+	orderRepo IProductOrderRepository) CompetitionProductService {
+	return CompetitionProductService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		orderRepo:    orderRepo,
+	}
+}
+
+// GetProductCategories returns all the categories of products
+func (service CompetitionProductService) GetProductCategories() ([]ProductCategory, error) {
+	return service.categoryRepo.GetProductCategories()
+}
+
+// SearchProducts searches the products of competitions that are available for order
+func (service CompetitionProductService) SearchProducts(criteria SearchCompetitionProductCriteria) ([]CompetitionProduct, error) {
+	criteria.StatusID = ProductStatusActive
+	return service.productRepo.SearchCompetitionProduct(criteria)
+}
+
+func (service CompetitionProductService) validateProduct(product CompetitionProduct) error {
+	if product.Title == "" {
+		return errors.New("title of product must be specified")
+	}
+	if product.Cost < 0 {
+		return errors.New("cost of product cannot be negative")
+	}
+	if product.MaximumAmount < 0 {
+		return errors.New("maximum amount of product cannot be negative")
+	}
+	if product.EffectiveEnd.Before(product.EffectiveStart) {
+		return errors.New("effective window of product must end after it starts")
+	}
+	if product.StatusID < ProductStatusDraft || product.StatusID > ProductStatusDiscontinued {
+		return errors.New("invalid product status")
+	}
+	categories, err := service.categoryRepo.GetProductCategories()
+	if err != nil {
+		return err
+	}
+	for _, each := range categories {
+		if each.ID == product.CategoryID {
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("cannot find product category with ID = %v", product.CategoryID))
+}
+
+// CreateProduct creates a product for a competition. All the products are available when the product is created.
+func (service CompetitionProductService) CreateProduct(currentUser Account, product *CompetitionProduct) error {
+	if product.StatusID == 0 {
+		product.StatusID = ProductStatusDraft
+	}
+	if err := service.validateProduct(*product); err != nil {
+		return err
+	}
+	product.AvailableAmount = product.MaximumAmount
+	product.CreateUserID = currentUser.ID
+	product.DateTimeCreated = time.Now()
+	product.UpdateUserID = currentUser.ID
+	product.DateTimeUpdated = time.Now()
+	return service.productRepo.CreateCompetitionProduct(product)
+}
+
+// SearchCompetitionProducts searches all the products of a competition, including the products that are not available
+// for order
+func (service CompetitionProductService) SearchCompetitionProducts(criteria SearchCompetitionProductCriteria) ([]CompetitionProduct, error) {
+	if criteria.CompetitionID < 1 {
+		return nil, errors.New("competition must be specified")
+	}
+	return service.productRepo.SearchCompetitionProduct(criteria)
+}
+
+// getCompetitionProduct returns the product if it is a product of the competition
+func (service CompetitionProductService) getCompetitionProduct(competitionID, productID int) (CompetitionProduct, error) {
+	products, err := service.productRepo.SearchCompetitionProduct(SearchCompetitionProductCriteria{ID: productID, CompetitionID: competitionID})
+	if err != nil {
+		return CompetitionProduct{}, err
+	}
+	if len(products) != 1 {
+		return CompetitionProduct{}, errors.New(fmt.Sprintf("cannot find product with ID = %v in competition %v", productID, competitionID))
+	}
+	return products[0], nil
+}
+
+// UpdateProduct updates a product of a competition. When the maximum amount changes, the available amount changes by
+// the same amount, and the maximum amount cannot be less than the amount that has been sold. Products cannot be
+// changed between unlimited and limited inventory once they are ordered.
+func (service CompetitionProductService) UpdateProduct(currentUser Account, product CompetitionProduct) error {
+	existing, err := service.getCompetitionProduct(product.CompetitionID, product.ID)
+	if err != nil {
+		return err
+	}
+	if err := service.validateProduct(product); err != nil {
+		return err
+	}
+	if existing.IsUnlimited() != product.IsUnlimited() {
+		orders, err := service.orderRepo.SearchProductOrder(SearchProductOrderCriteria{ProductID: product.ID})
+		if err != nil {
+			return err
+		}
+		if len(orders) > 0 {
+			return errors.New("inventory of a product cannot be limited or unlimited after it is ordered")
+		}
+	} else if !product.IsUnlimited() && product.MaximumAmount < existing.MaximumAmount-existing.AvailableAmount {
+		return errors.New("maximum amount cannot be less than the amount that has been sold")
+	}
+	product.UpdateUserID = currentUser.ID
+	product.DateTimeUpdated = time.Now()
+	return service.productRepo.UpdateCompetitionProduct(product)
+}
+
+// DeleteProduct deletes a product that has not been ordered. Products that have been ordered should be discontinued
+// instead, so that their orders are kept.
+func (service CompetitionProductService) DeleteProduct(competitionID, productID int) error {
+	product, err := service.getCompetitionProduct(competitionID, productID)
+	if err != nil {
+		return err
+	}
+	orders, err := service.orderRepo.SearchProductOrder(SearchProductOrderCriteria{ProductID: productID})
+	if err != nil {
+		return err
+	}
+	if len(orders) > 0 {
+		return errors.New("product has been ordered and can only be discontinued")
+	}
+	return service.productRepo.DeleteCompetitionProduct(product)
+}
+
+// PlaceOrder orders the quantity of the product for the current user. The order fails with ProductSoldOutError if
+// other orders have taken the inventory, even if they are placed at the same time. Orders of free products are paid
+// when they are placed, and other orders are pending until their payment succeeds.
+func (service CompetitionProductService) PlaceOrder(currentUser Account, productID, quantity int) (ProductOrder, error) {
+	order := ProductOrder{}
+	if currentUser.ID < 1 {
+		return order, errors.New("user must be logged in to order products")
+	}
+	products, err := service.productRepo.SearchCompetitionProduct(SearchCompetitionProductCriteria{ID: productID})
+	if err != nil {
+		return order, err
+	}
+	if len(products) != 1 {
+		return order, errors.New(fmt.Sprintf("cannot find product with ID = %v", productID))
+	}
+	product := products[0]
+	if err := product.CanOrder(quantity, time.Now()); err != nil {
+		return order, err
+	}
+
+	order = ProductOrder{
+		CompetitionID:   product.CompetitionID,
+		ProductID:       product.ID,
+		UserAccountID:   currentUser.ID,
+		Quantity:        quantity,
+		TotalCost:       product.Cost * float64(quantity),
+		StatusID:        ProductOrderStatusPending,
+		DateTimeCreated: time.Now(),
+		DateTimeUpdated: time.Now(),
+	}
+	if order.TotalCost == 0 {
+		order.StatusID = ProductOrderStatusPaid
+	}
+	err = service.orderRepo.CreateProductOrder(&order)
+	return order, err
+}
+
+// SearchOwnOrders returns the orders of the current user
+func (service CompetitionProductService) SearchOwnOrders(currentUser Account, criteria SearchProductOrderCriteria) ([]ProductOrder, error) {
+	criteria.UserAccountID = currentUser.ID
+	return service.orderRepo.SearchProductOrder(criteria)
+}
+
+// SearchCompetitionOrders returns the orders of a competition
+func (service CompetitionProductService) SearchCompetitionOrders(criteria SearchProductOrderCriteria) ([]ProductOrder, error) {
+	if criteria.CompetitionID < 1 {
+		return nil, errors.New("competition must be specified")
+	}
+	return service.orderRepo.SearchProductOrder(criteria)
+}
+
+func (service CompetitionProductService) getOrder(orderID int) (ProductOrder, error) {
+	orders, err := service.orderRepo.SearchProductOrder(SearchProductOrderCriteria{ID: orderID})
+	if err != nil {
+		return ProductOrder{}, err
+	}
+	if len(orders) != 1 {
+		return ProductOrder{}, errors.New(fmt.Sprintf("cannot find product order with ID = %v", orderID))
+	}
+	return orders[0], nil
+}
+
+// PreparePayment returns what the current user pays for a pending order of the user. The price is the cost of the
+// order when it was placed.
+func (service CompetitionProductService) PreparePayment(currentUser Account, orderID int) (PaymentCharge, error) {
+	order, err := service.getOrder(orderID)
+	if err != nil {
+		return PaymentCharge{}, err
+	}
+	if order.UserAccountID != currentUser.ID {
+		return PaymentCharge{}, errors.New("not authorized to pay for this order")
+	}
+	if order.StatusID != ProductOrderStatusPending {
+		return PaymentCharge{}, errors.New("only pending orders can be paid")
+	}
+	products, err := service.productRepo.SearchCompetitionProduct(SearchCompetitionProductCriteria{ID: order.ProductID})
+	if err != nil {
+		return PaymentCharge{}, err
+	}
+	if len(products) != 1 {
+		return PaymentCharge{}, errors.New(fmt.Sprintf("cannot find product with ID = %v", order.ProductID))
+	}
+	return PaymentCharge{
+		CompetitionID: order.CompetitionID,
+		Items: []PaymentLineItem{{
+			Name:       products[0].Title,
+			UnitAmount: order.TotalCost / float64(order.Quantity),
+			Quantity:   order.Quantity,
+		}},
+	}, nil
+}
+
+// ConfirmPayment marks the order that the payment pays for as paid
+func (service CompetitionProductService) ConfirmPayment(payment Payment) error {
+	order, err := service.getOrder(payment.ReferenceID)
+	if err != nil {
+		return err
+	}
+	if order.StatusID == ProductOrderStatusPaid {
+		return nil
+	}
+	if order.StatusID != ProductOrderStatusPending {
+		return errors.New(fmt.Sprintf("product order %v is paid after it is cancelled", order.ID))
+	}
+	order.StatusID = ProductOrderStatusPaid
+	order.DateTimeUpdated = time.Now()
+	return service.orderRepo.UpdateProductOrder(order)
+}
+
+// CancelPayment cancels the order that the payment paid for, and releases its products. Paid orders are refunded,
+// and pending orders are cancelled.
+func (service CompetitionProductService) CancelPayment(payment Payment) error {
+	order, err := service.getOrder(payment.ReferenceID)
+	if err != nil {
+		return err
+	}
+	switch order.StatusID {
+	case ProductOrderStatusPending:
+		order.StatusID = ProductOrderStatusCancelled
+	case ProductOrderStatusPaid:
+		order.StatusID = ProductOrderStatusRefunded
+	default:
+		return nil
+	}
+	order.DateTimeUpdated = time.Now()
+	return service.orderRepo.CancelProductOrder(order)
+}
@@ -0,0 +1,187 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newWorkshop() businesslogic.CompetitionProduct {
+	return businesslogic.CompetitionProduct{
+		ID:              7,
+		CompetitionID:   3,
+		CategoryID:      businesslogic.ProductCategoryWorkshop,
+		StatusID:        businesslogic.ProductStatusActive,
+		Title:           "Rumba Workshop",
+		Cost:            25,
+		MaximumAmount:   20,
+		AvailableAmount: 5,
+		EffectiveStart:  time.Now().AddDate(0, 0, -1),
+		EffectiveEnd:    time.Now(),
+	}
+}
+
+func TestCompetitionProduct_CanOrder(t *testing.T) {
+	product := newWorkshop()
+	assert.Nil(t, product.CanOrder(5, time.Now()))
+	assert.Equal(t, businesslogic.ProductSoldOutError, product.CanOrder(6, time.Now()), "should not order more than available")
+	assert.NotNil(t, product.CanOrder(0, time.Now()), "should order at least one product")
+	assert.NotNil(t, product.CanOrder(1, time.Now().AddDate(0, 0, 1)), "should not order after the effective window")
+	assert.NotNil(t, product.CanOrder(1, time.Now().AddDate(0, 0, -2)), "should not order before the effective window")
+
+	product.MaximumAmount = 0
+	assert.Nil(t, product.CanOrder(100, time.Now()), "should order any quantity of unlimited products")
+
+	product.StatusID = businesslogic.ProductStatusDraft
+	assert.NotNil(t, product.CanOrder(1, time.Now()), "should not order products that are not active")
+}
+
+func TestCompetitionProductService_CreateProduct(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionProductService(categoryRepo, productRepo, orderRepo)
+
+	product := newWorkshop()
+	product.ID = 0
+	product.StatusID = 0
+	categoryRepo.EXPECT().GetProductCategories().Return([]businesslogic.ProductCategory{{ID: businesslogic.ProductCategoryWorkshop}}, nil)
+	productRepo.EXPECT().CreateCompetitionProduct(gomock.Any()).Return(nil)
+	err := service.CreateProduct(newOrganizer(41), &product)
+	assert.Nil(t, err)
+	assert.Equal(t, businesslogic.ProductStatusDraft, product.StatusID, "should create products as draft")
+	assert.Equal(t, 20, product.AvailableAmount, "all products should be available when created")
+}
+
+func TestCompetitionProductService_UpdateProduct(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionProductService(categoryRepo, productRepo, orderRepo)
+
+	// 15 of 20 products have been sold
+	update := newWorkshop()
+	update.MaximumAmount = 10
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	categoryRepo.EXPECT().GetProductCategories().Return([]businesslogic.ProductCategory{{ID: businesslogic.ProductCategoryWorkshop}}, nil)
+	err := service.UpdateProduct(newOrganizer(41), update)
+	assert.NotNil(t, err, "should not reduce maximum amount below the amount that has been sold")
+
+	update.MaximumAmount = 0
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	categoryRepo.EXPECT().GetProductCategories().Return([]businesslogic.ProductCategory{{ID: businesslogic.ProductCategoryWorkshop}}, nil)
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ProductID: 7}).Return([]businesslogic.ProductOrder{{ID: 1}}, nil)
+	err = service.UpdateProduct(newOrganizer(41), update)
+	assert.NotNil(t, err, "should not make inventory unlimited after the product is ordered")
+
+	update.MaximumAmount = 15
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	categoryRepo.EXPECT().GetProductCategories().Return([]businesslogic.ProductCategory{{ID: businesslogic.ProductCategoryWorkshop}}, nil)
+	productRepo.EXPECT().UpdateCompetitionProduct(gomock.Any()).Return(nil)
+	err = service.UpdateProduct(newOrganizer(41), update)
+	assert.Nil(t, err)
+}
+
+func TestCompetitionProductService_DeleteProduct(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionProductService(categoryRepo, productRepo, orderRepo)
+
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 4}).Return([]businesslogic.CompetitionProduct{}, nil)
+	err := service.DeleteProduct(4, 7)
+	assert.NotNil(t, err, "should not delete products of other competitions")
+
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ProductID: 7}).Return([]businesslogic.ProductOrder{{ID: 1}}, nil)
+	err = service.DeleteProduct(3, 7)
+	assert.NotNil(t, err, "should not delete products that have been ordered")
+
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7, CompetitionID: 3}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ProductID: 7}).Return([]businesslogic.ProductOrder{}, nil)
+	productRepo.EXPECT().DeleteCompetitionProduct(gomock.Any()).Return(nil)
+	err = service.DeleteProduct(3, 7)
+	assert.Nil(t, err)
+}
+
+func TestCompetitionProductService_PlaceOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionProductService(categoryRepo, productRepo, orderRepo)
+	user := businesslogic.Account{ID: 12}
+
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	orderRepo.EXPECT().CreateProductOrder(gomock.Any()).DoAndReturn(func(order *businesslogic.ProductOrder) error {
+		order.ID = 100
+		return nil
+	})
+	order, err := service.PlaceOrder(user, 7, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, order.ID)
+	assert.Equal(t, 12, order.UserAccountID)
+	assert.EqualValues(t, 50, order.TotalCost)
+	assert.Equal(t, businesslogic.ProductOrderStatusPending, order.StatusID, "order should be pending until it is paid")
+
+	// another order took the remaining products after this one was checked
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	orderRepo.EXPECT().CreateProductOrder(gomock.Any()).Return(businesslogic.ProductSoldOutError)
+	_, err = service.PlaceOrder(user, 7, 5)
+	assert.Equal(t, businesslogic.ProductSoldOutError, err)
+
+	_, err = service.PlaceOrder(businesslogic.Account{}, 7, 1)
+	assert.NotNil(t, err, "should not order products without logging in")
+}
+
+func TestCompetitionProductService_ProductOrderPayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	categoryRepo := mock_businesslogic.NewMockIProductCategoryRepository(mockCtrl)
+	productRepo := mock_businesslogic.NewMockICompetitionProductRepository(mockCtrl)
+	orderRepo := mock_businesslogic.NewMockIProductOrderRepository(mockCtrl)
+	service := businesslogic.NewCompetitionProductService(categoryRepo, productRepo, orderRepo)
+	order := businesslogic.ProductOrder{ID: 8, CompetitionID: 3, ProductID: 7, UserAccountID: 12, Quantity: 2, TotalCost: 50, StatusID: businesslogic.ProductOrderStatusPending}
+	payment := businesslogic.Payment{Purpose: businesslogic.PaymentPurposeProductOrder, ReferenceID: 8}
+
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ID: 8}).Return([]businesslogic.ProductOrder{order}, nil)
+	_, err := service.PreparePayment(businesslogic.Account{ID: 13}, 8)
+	assert.NotNil(t, err, "should not pay for orders of other users")
+
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ID: 8}).Return([]businesslogic.ProductOrder{order}, nil)
+	productRepo.EXPECT().SearchCompetitionProduct(businesslogic.SearchCompetitionProductCriteria{ID: 7}).Return([]businesslogic.CompetitionProduct{newWorkshop()}, nil)
+	charge, err := service.PreparePayment(businesslogic.Account{ID: 12}, 8)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 50, charge.Total())
+
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ID: 8}).Return([]businesslogic.ProductOrder{order}, nil)
+	orderRepo.EXPECT().UpdateProductOrder(gomock.Any()).DoAndReturn(func(update businesslogic.ProductOrder) error {
+		assert.Equal(t, businesslogic.ProductOrderStatusPaid, update.StatusID)
+		return nil
+	})
+	assert.Nil(t, service.ConfirmPayment(payment))
+
+	// refunding a paid order releases its products
+	order.StatusID = businesslogic.ProductOrderStatusPaid
+	orderRepo.EXPECT().SearchProductOrder(businesslogic.SearchProductOrderCriteria{ID: 8}).Return([]businesslogic.ProductOrder{order}, nil)
+	orderRepo.EXPECT().CancelProductOrder(gomock.Any()).DoAndReturn(func(update businesslogic.ProductOrder) error {
+		assert.Equal(t, businesslogic.ProductOrderStatusRefunded, update.StatusID)
+		return nil
+	})
+	assert.Nil(t, service.CancelPayment(payment))
+}
@@ -0,0 +1,469 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CollegiateFederationAbbreviation is the abbreviation of the federation whose events earn proficiency points
+const CollegiateFederationAbbreviation = "COL"
+
+// Actions that are recorded in the history of proficiency point overrides
+const (
+	ProficiencyPointOverrideActionGrant  = "GRANT"
+	ProficiencyPointOverrideActionRevoke = "REVOKE"
+)
+
+// ProficiencyPointScale specifies how athletes earn proficiency points from the finals of collegiate events, and when
+// they point out of a level. Levels are ordered from the lowest to the highest. Points earned at a level count double
+// at each level below it, and athletes who have PointOutThreshold points or more at a level can no longer enter that
+// level. The highest level cannot be pointed out of.
+type ProficiencyPointScale struct {
+	PlacementPoints       []int // points of the 1st, 2nd, ... place of the final
+	QuarterFinalistPoints int   // points of the other finalists if the final was reached through a quarter-final
+	PointOutThreshold     int
+	Levels                []string
+}
+
+// YCNPointScale is the point scale of the Youth Collegiate Network: 3 points for the 1st place, 2 for the 2nd, 1 for
+// the 3rd, and 1 for the 4th to the 6th place if the event had a quarter-final. Athletes point out at 7 points.
+var YCNPointScale = ProficiencyPointScale{
+	PlacementPoints:       []int{3, 2, 1},
+	QuarterFinalistPoints: 1,
+	PointOutThreshold:     7,
+	Levels:                []string{"Bronze", "Silver", "Gold", "Novice", "Pre-Championship", "Championship"},
+}
+
+// Points returns the points of a placement in the final of an event that had the specified number of rounds,
+// including the final
+func (scale ProficiencyPointScale) Points(placement, rounds int) int {
+	if placement < 1 {
+		return 0
+	}
+	if placement <= len(scale.PlacementPoints) {
+		return scale.PlacementPoints[placement-1]
+	}
+	if placement <= 6 && rounds >= 3 {
+		return scale.QuarterFinalistPoints
+	}
+	return 0
+}
+
+// levelOf returns the index of the level in the scale, or -1 if the proficiency does not earn points
+func (scale ProficiencyPointScale) levelOf(proficiency string) int {
+	for i, each := range scale.Levels {
+		if each == proficiency {
+			return i
+		}
+	}
+	return -1
+}
+
+// ProficiencyPoint is the points that an athlete earned from the final of an event
+type ProficiencyPoint struct {
+	AthleteID     int
+	PartnershipID int
+	CompetitionID int
+	EventID       int
+	StyleID       int
+	ProficiencyID int
+	Level         string
+	Placement     int
+	Points        int
+}
+
+// ProficiencyPointSummary is the points that an athlete has at a level of a style. Total includes the points that
+// were earned at higher levels, which count double at each level below.
+type ProficiencyPointSummary struct {
+	AthleteID  int
+	StyleID    int
+	Level      string
+	Earned     int
+	Total      int
+	PointedOut bool
+	Overridden bool // an administrator allowed the athlete to enter the level even though the athlete pointed out
+}
+
+// ProficiencyPointOverride allows an athlete to enter a level of a style that the athlete has pointed out of. Overrides
+// are revoked, not deleted, so that the history of an override stays complete.
+type ProficiencyPointOverride struct {
+	ID              int
+	AthleteID       int
+	StyleID         int
+	ProficiencyID   int
+	Reason          string
+	Active          bool
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// SearchProficiencyPointOverrideCriteria specifies the parameters that can be used to search ProficiencyPointOverride
+type SearchProficiencyPointOverrideCriteria struct {
+	ID            int
+	AthleteID     int
+	StyleID       int
+	ProficiencyID int
+	ActiveOnly    bool
+}
+
+// IProficiencyPointOverrideRepository specifies the functions that a ProficiencyPointOverride Repository should
+// implement
+type IProficiencyPointOverrideRepository interface {
+	CreateProficiencyPointOverride(override *ProficiencyPointOverride) error
+	SearchProficiencyPointOverride(criteria SearchProficiencyPointOverrideCriteria) ([]ProficiencyPointOverride, error)
+	UpdateProficiencyPointOverride(override ProficiencyPointOverride) error
+}
+
+// ProficiencyPointOverrideHistoryEntry records an administrator granting or revoking an override
+type ProficiencyPointOverrideHistoryEntry struct {
+	ID              int
+	OverrideID      int
+	AthleteID       int
+	Action          string
+	Note            string
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// SearchProficiencyPointOverrideHistoryCriteria specifies the parameters that can be used to search the history of
+// overrides
+type SearchProficiencyPointOverrideHistoryCriteria struct {
+	OverrideID int
+	AthleteID  int
+}
+
+// IProficiencyPointOverrideHistoryRepository specifies the functions that a repository of override history should
+// implement
+type IProficiencyPointOverrideHistoryRepository interface {
+	CreateProficiencyPointOverrideHistory(entry *ProficiencyPointOverrideHistoryEntry) error
+	SearchProficiencyPointOverrideHistory(criteria SearchProficiencyPointOverrideHistoryCriteria) ([]ProficiencyPointOverrideHistoryEntry, error)
+}
+
+// ProficiencyPointService accumulates the proficiency points of athletes from the stored results of collegiate events,
+// and manages the overrides that allow athletes to enter levels they have pointed out of
+type ProficiencyPointService struct {
+	scale           ProficiencyPointScale
+	partnershipRepo IPartnershipRepository
+	roundEntryRepo  IPartnershipRoundEntryRepository
+	roundRepo       IRoundRepository
+	resultRepo      IRoundResultRepository
+	eventRepo       IEventRepository
+	federationRepo  IFederationRepository
+	proficiencyRepo IProficiencyRepository
+	overrideRepo    IProficiencyPointOverrideRepository
+	historyRepo     IProficiencyPointOverrideHistoryRepository
+}
+
+func NewProficiencyPointService(
+	scale ProficiencyPointScale,
+	partnershipRepo IPartnershipRepository,
+	roundEntryRepo IPartnershipRoundEntryRepository,
+	roundRepo IRoundRepository,
+	resultRepo IRoundResultRepository,
+	eventRepo IEventRepository,
+	federationRepo IFederationRepository,
+	proficiencyRepo IProficiencyRepository,
+	overrideRepo IProficiencyPointOverrideRepository,
+	historyRepo IProficiencyPointOverrideHistoryRepository) ProficiencyPointService {
+	return ProficiencyPointService{
+		scale:           scale,
+		partnershipRepo: partnershipRepo,
+		roundEntryRepo:  roundEntryRepo,
+		roundRepo:       roundRepo,
+		resultRepo:      resultRepo,
+		eventRepo:       eventRepo,
+		federationRepo:  federationRepo,
+		proficiencyRepo: proficiencyRepo,
+		overrideRepo:    overrideRepo,
+		historyRepo:     historyRepo,
+	}
+}
+
+// getLevel returns the level of the event in the point scale, or an empty string if the event is not a collegiate
+// event or its proficiency does not earn points
+func (service ProficiencyPointService) getLevel(event Event) (string, error) {
+	federations, err := service.federationRepo.SearchFederation(SearchFederationCriteria{ID: event.FederationID})
+	if err != nil {
+		return "", err
+	}
+	if len(federations) != 1 || federations[0].Abbreviation != CollegiateFederationAbbreviation {
+		return "", nil
+	}
+	proficiencies, err := service.proficiencyRepo.SearchProficiency(SearchProficiencyCriteria{ProficiencyID: event.ProficiencyID})
+	if err != nil {
+		return "", err
+	}
+	if len(proficiencies) != 1 || service.scale.levelOf(proficiencies[0].Name) < 0 {
+		return "", nil
+	}
+	return proficiencies[0].Name, nil
+}
+
+// GetAthletePoints returns the points that the athlete earned from the finals of collegiate events, with all the
+// partners of the athlete
+func (service ProficiencyPointService) GetAthletePoints(athleteID int) ([]ProficiencyPoint, error) {
+	partnerships, err := Account{ID: athleteID}.GetAllPartnerships(service.partnershipRepo)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]ProficiencyPoint, 0)
+	for _, partnership := range partnerships {
+		entries, err := service.roundEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{PartnershipID: partnership.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			point, err := service.getEntryPoint(entry)
+			if err != nil {
+				return nil, err
+			}
+			if point.Points > 0 {
+				point.AthleteID = athleteID
+				point.PartnershipID = partnership.ID
+				points = append(points, point)
+			}
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].EventID < points[j].EventID })
+	return points, nil
+}
+
+// getEntryPoint returns the points of a round entry. Only the overall result of a final earns points.
+func (service ProficiencyPointService) getEntryPoint(entry PartnershipRoundEntry) (ProficiencyPoint, error) {
+	point := ProficiencyPoint{}
+	results, err := service.resultRepo.SearchRoundResult(SearchRoundResultCriteria{
+		RoundID:                 entry.RoundEntry.RoundID,
+		PartnershipRoundEntryID: entry.ID,
+	})
+	if err != nil {
+		return point, err
+	}
+	for _, each := range results {
+		if each.EventDanceID == 0 && !each.PreliminaryRoundIndicator {
+			point.Placement = each.Placement
+		}
+	}
+	if point.Placement < 1 {
+		return point, nil
+	}
+
+	rounds, err := service.roundRepo.SearchRound(SearchRoundCriteria{ID: entry.RoundEntry.RoundID})
+	if err != nil || len(rounds) != 1 {
+		return point, errors.New(fmt.Sprintf("cannot find round with ID = %v", entry.RoundEntry.RoundID))
+	}
+	events, err := service.eventRepo.SearchEvent(SearchEventCriteria{EventID: rounds[0].EventID})
+	if err != nil || len(events) != 1 {
+		return point, errors.New(fmt.Sprintf("cannot find event with ID = %v", rounds[0].EventID))
+	}
+	level, err := service.getLevel(events[0])
+	if err != nil || level == "" {
+		return point, err
+	}
+	eventRounds, err := service.roundRepo.SearchRound(SearchRoundCriteria{EventID: events[0].ID})
+	if err != nil {
+		return point, err
+	}
+
+	point.CompetitionID = events[0].CompetitionID
+	point.EventID = events[0].ID
+	point.StyleID = events[0].StyleID
+	point.ProficiencyID = events[0].ProficiencyID
+	point.Level = level
+	point.Points = service.scale.Points(point.Placement, len(eventRounds))
+	return point, nil
+}
+
+// GetPointSummary returns the points of the athlete at each level of each style that the athlete has earned points in
+func (service ProficiencyPointService) GetPointSummary(athleteID int) ([]ProficiencyPointSummary, error) {
+	points, err := service.GetAthletePoints(athleteID)
+	if err != nil {
+		return nil, err
+	}
+	earned := make(map[int][]int) // key: style, value: points earned at each level
+	for _, each := range points {
+		if earned[each.StyleID] == nil {
+			earned[each.StyleID] = make([]int, len(service.scale.Levels))
+		}
+		earned[each.StyleID][service.scale.levelOf(each.Level)] += each.Points
+	}
+	overrides, err := service.overrideRepo.SearchProficiencyPointOverride(SearchProficiencyPointOverrideCriteria{
+		AthleteID:  athleteID,
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	overridden := make(map[[2]int]bool) // key: style, index of level
+	for _, each := range overrides {
+		proficiencies, err := service.proficiencyRepo.SearchProficiency(SearchProficiencyCriteria{ProficiencyID: each.ProficiencyID})
+		if err != nil {
+			return nil, err
+		}
+		if len(proficiencies) == 1 {
+			overridden[[2]int{each.StyleID, service.scale.levelOf(proficiencies[0].Name)}] = true
+		}
+	}
+
+	styles := make([]int, 0)
+	for each := range earned {
+		styles = append(styles, each)
+	}
+	sort.Ints(styles)
+
+	summaries := make([]ProficiencyPointSummary, 0)
+	for _, style := range styles {
+		total := 0
+		levels := make([]ProficiencyPointSummary, len(service.scale.Levels))
+		for i := len(service.scale.Levels) - 1; i >= 0; i-- {
+			total = total*2 + earned[style][i]
+			levels[i] = ProficiencyPointSummary{
+				AthleteID:  athleteID,
+				StyleID:    style,
+				Level:      service.scale.Levels[i],
+				Earned:     earned[style][i],
+				Total:      total,
+				PointedOut: i < len(service.scale.Levels)-1 && total >= service.scale.PointOutThreshold,
+				Overridden: overridden[[2]int{style, i}],
+			}
+		}
+		summaries = append(summaries, levels...)
+	}
+	return summaries, nil
+}
+
+// IsPointedOut checks if the athlete has pointed out of the level of the event and does not have an override for it
+func (service ProficiencyPointService) IsPointedOut(athleteID int, event Event) (bool, error) {
+	level, err := service.getLevel(event)
+	if err != nil || level == "" {
+		return false, err
+	}
+	summaries, err := service.GetPointSummary(athleteID)
+	if err != nil {
+		return false, err
+	}
+	for _, each := range summaries {
+		if each.StyleID == event.StyleID && each.Level == level {
+			return each.PointedOut && !each.Overridden, nil
+		}
+	}
+	return false, nil
+}
+
+// GrantOverride allows the athlete to enter a level of a style that the athlete has pointed out of. Only
+// administrators can grant overrides, and a reason is required for the audit trail.
+func (service ProficiencyPointService) GrantOverride(currentUser Account, override *ProficiencyPointOverride) error {
+	if !currentUser.HasRole(AccountTypeAdministrator) {
+		return errors.New("only administrators can override proficiency points")
+	}
+	if override.AthleteID < 1 || override.StyleID < 1 || override.ProficiencyID < 1 {
+		return errors.New("athlete, style and proficiency must be specified")
+	}
+	if override.Reason == "" {
+		return errors.New("reason of the override must be specified")
+	}
+	existing, err := service.overrideRepo.SearchProficiencyPointOverride(SearchProficiencyPointOverrideCriteria{
+		AthleteID:     override.AthleteID,
+		StyleID:       override.StyleID,
+		ProficiencyID: override.ProficiencyID,
+		ActiveOnly:    true,
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return errors.New("athlete already has an active override for this level")
+	}
+
+	override.Active = true
+	override.CreateUserID = currentUser.ID
+	override.DateTimeCreated = time.Now()
+	override.UpdateUserID = currentUser.ID
+	override.DateTimeUpdated = time.Now()
+	if err := service.overrideRepo.CreateProficiencyPointOverride(override); err != nil {
+		return err
+	}
+	return service.recordHistory(currentUser, *override, ProficiencyPointOverrideActionGrant, override.Reason)
+}
+
+// RevokeOverride deactivates an override. The note explains why the override is revoked.
+func (service ProficiencyPointService) RevokeOverride(currentUser Account, overrideID int, note string) error {
+	if !currentUser.HasRole(AccountTypeAdministrator) {
+		return errors.New("only administrators can override proficiency points")
+	}
+	overrides, err := service.overrideRepo.SearchProficiencyPointOverride(SearchProficiencyPointOverrideCriteria{ID: overrideID})
+	if err != nil {
+		return err
+	}
+	if len(overrides) != 1 {
+		return errors.New(fmt.Sprintf("cannot find override with ID = %v", overrideID))
+	}
+	override := overrides[0]
+	if !override.Active {
+		return errors.New("override has already been revoked")
+	}
+	override.Active = false
+	override.UpdateUserID = currentUser.ID
+	override.DateTimeUpdated = time.Now()
+	if err := service.overrideRepo.UpdateProficiencyPointOverride(override); err != nil {
+		return err
+	}
+	return service.recordHistory(currentUser, override, ProficiencyPointOverrideActionRevoke, note)
+}
+
+// SearchOverrideHistory returns the audit trail of overrides. Only administrators can see the audit trail.
+func (service ProficiencyPointService) SearchOverrideHistory(currentUser Account, criteria SearchProficiencyPointOverrideHistoryCriteria) ([]ProficiencyPointOverrideHistoryEntry, error) {
+	if !currentUser.HasRole(AccountTypeAdministrator) {
+		return nil, errors.New("only administrators can view the history of overrides")
+	}
+	return service.historyRepo.SearchProficiencyPointOverrideHistory(criteria)
+}
+
+func (service ProficiencyPointService) recordHistory(currentUser Account, override ProficiencyPointOverride, action, note string) error {
+	return service.historyRepo.CreateProficiencyPointOverrideHistory(&ProficiencyPointOverrideHistoryEntry{
+		OverrideID:      override.ID,
+		AthleteID:       override.AthleteID,
+		Action:          action,
+		Note:            note,
+		CreateUserID:    currentUser.ID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    currentUser.ID,
+		DateTimeUpdated: time.Now(),
+	})
+}
+
+// ProficiencyPointOutRule prevents athletes from entering collegiate events at levels they have pointed out of, unless
+// an administrator has granted an override
+type ProficiencyPointOutRule struct {
+	service ProficiencyPointService
+}
+
+func NewProficiencyPointOutRule(service ProficiencyPointService) ProficiencyPointOutRule {
+	return ProficiencyPointOutRule{service: service}
+}
+
+func (rule ProficiencyPointOutRule) Apply(registration EventRegistrationForm) error {
+	violations := make([]EligibilityViolation, 0)
+	for _, event := range registration.EventsAdded {
+		for _, athlete := range []Account{registration.Couple.Lead, registration.Couple.Follow} {
+			pointedOut, err := rule.service.IsPointedOut(athlete.ID, event)
+			if err != nil {
+				return err
+			}
+			if pointedOut {
+				violations = append(violations, EligibilityViolation{
+					Reason:    EligibilityReasonPointedOut,
+					EventID:   event.ID,
+					AthleteID: athlete.ID,
+					Message:   fmt.Sprintf("athlete %v has pointed out of the level of event %v", athlete.ID, event.ID),
+				})
+			}
+		}
+	}
+	return newEligibilityError(violations)
+}
@@ -0,0 +1,252 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// expectAthleteResults sets up athlete 1, who won a Silver Latin event that had a quarter-final, placed 2nd in another
+// Silver Latin event, won a Gold Latin event, and was recalled from a preliminary round of a Gold Latin event. The
+// results are expected to be searched the specified times. Federation and proficiency of the events are set up by the
+// tests, since they are also searched for the events that athletes register for.
+func expectAthleteResults(
+	times int,
+	partnershipRepo *mock_businesslogic.MockIPartnershipRepository,
+	roundEntryRepo *mock_businesslogic.MockIPartnershipRoundEntryRepository,
+	roundRepo *mock_businesslogic.MockIRoundRepository,
+	resultRepo *mock_businesslogic.MockIRoundResultRepository,
+	eventRepo *mock_businesslogic.MockIEventRepository) {
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{LeadID: 1}).Return([]businesslogic.Partnership{{ID: 101}}, nil).Times(times)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{FollowID: 1}).Return([]businesslogic.Partnership{}, nil).Times(times)
+	roundEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{PartnershipID: 101}).Return([]businesslogic.PartnershipRoundEntry{
+		{ID: 501, PartnershipID: 101, RoundEntry: businesslogic.RoundEntry{RoundID: 63}},
+		{ID: 502, PartnershipID: 101, RoundEntry: businesslogic.RoundEntry{RoundID: 71}},
+		{ID: 503, PartnershipID: 101, RoundEntry: businesslogic.RoundEntry{RoundID: 81}},
+		{ID: 504, PartnershipID: 101, RoundEntry: businesslogic.RoundEntry{RoundID: 91}},
+	}, nil).Times(times)
+
+	finals := []struct {
+		roundID, entryID, eventID, proficiencyID, rounds, placement int
+	}{
+		{63, 501, 6, 103, 3, 1},
+		{71, 502, 7, 103, 1, 2},
+		{81, 503, 8, 104, 1, 1},
+	}
+	for _, each := range finals {
+		resultRepo.EXPECT().SearchRoundResult(businesslogic.SearchRoundResultCriteria{RoundID: each.roundID, PartnershipRoundEntryID: each.entryID}).Return([]businesslogic.RoundResult{
+			{RoundID: each.roundID, EventDanceID: 1, PartnershipRoundEntryID: each.entryID, Placement: 4},
+			{RoundID: each.roundID, PartnershipRoundEntryID: each.entryID, Placement: each.placement},
+		}, nil).Times(times)
+		roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: each.roundID}).Return([]businesslogic.Round{{ID: each.roundID, EventID: each.eventID}}, nil).Times(times)
+		eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: each.eventID}).Return([]businesslogic.Event{{ID: each.eventID, CompetitionID: 3, FederationID: 1, StyleID: 2, ProficiencyID: each.proficiencyID}}, nil).Times(times)
+		roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: each.eventID}).Return(make([]businesslogic.Round, each.rounds), nil).Times(times)
+	}
+	resultRepo.EXPECT().SearchRoundResult(businesslogic.SearchRoundResultCriteria{RoundID: 91, PartnershipRoundEntryID: 504}).Return([]businesslogic.RoundResult{
+		{RoundID: 91, PartnershipRoundEntryID: 504, PreliminaryRoundIndicator: true, Recalled: true},
+	}, nil).Times(times)
+}
+
+func TestProficiencyPointScale_Points(t *testing.T) {
+	scale := businesslogic.YCNPointScale
+	assert.Equal(t, 3, scale.Points(1, 1))
+	assert.Equal(t, 2, scale.Points(2, 1))
+	assert.Equal(t, 1, scale.Points(3, 1))
+	assert.Equal(t, 0, scale.Points(4, 2), "4th place earns no points without a quarter-final")
+	assert.Equal(t, 1, scale.Points(6, 3))
+	assert.Equal(t, 0, scale.Points(7, 3))
+	assert.Equal(t, 0, scale.Points(0, 3))
+}
+
+func TestProficiencyPointService_GetPointSummary(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	roundEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	federationRepo := mock_businesslogic.NewMockIFederationRepository(mockCtrl)
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	overrideRepo := mock_businesslogic.NewMockIProficiencyPointOverrideRepository(mockCtrl)
+	historyRepo := mock_businesslogic.NewMockIProficiencyPointOverrideHistoryRepository(mockCtrl)
+	service := businesslogic.NewProficiencyPointService(businesslogic.YCNPointScale, partnershipRepo, roundEntryRepo,
+		roundRepo, resultRepo, eventRepo, federationRepo, proficiencyRepo, overrideRepo, historyRepo)
+
+	// points are searched once for the points, and once for the summary
+	expectAthleteResults(2, partnershipRepo, roundEntryRepo, roundRepo, resultRepo, eventRepo)
+	federationRepo.EXPECT().SearchFederation(businesslogic.SearchFederationCriteria{ID: 1}).Return([]businesslogic.Federation{{ID: 1, Abbreviation: businesslogic.CollegiateFederationAbbreviation}}, nil).Times(6)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 103}).Return([]businesslogic.Proficiency{{ID: 103, Name: "Silver"}}, nil).Times(4)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 104}).Return([]businesslogic.Proficiency{{ID: 104, Name: "Gold"}}, nil).Times(2)
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{AthleteID: 1, ActiveOnly: true}).Return([]businesslogic.ProficiencyPointOverride{}, nil)
+
+	points, err := service.GetAthletePoints(1)
+	assert.Nil(t, err)
+	assert.Len(t, points, 3, "preliminary rounds should not earn points")
+
+	summaries, err := service.GetPointSummary(1)
+	assert.Nil(t, err)
+	levels := make(map[string]businesslogic.ProficiencyPointSummary)
+	for _, each := range summaries {
+		assert.Equal(t, 2, each.StyleID)
+		levels[each.Level] = each
+	}
+	assert.Equal(t, 3, levels["Gold"].Total)
+	assert.False(t, levels["Gold"].PointedOut)
+	assert.Equal(t, 5, levels["Silver"].Earned)
+	assert.Equal(t, 11, levels["Silver"].Total, "points at Gold should count double at Silver")
+	assert.True(t, levels["Silver"].PointedOut)
+	assert.Equal(t, 22, levels["Bronze"].Total)
+	assert.True(t, levels["Bronze"].PointedOut)
+}
+
+func TestProficiencyPointOutRule_Apply(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	roundEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	federationRepo := mock_businesslogic.NewMockIFederationRepository(mockCtrl)
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	overrideRepo := mock_businesslogic.NewMockIProficiencyPointOverrideRepository(mockCtrl)
+	historyRepo := mock_businesslogic.NewMockIProficiencyPointOverrideHistoryRepository(mockCtrl)
+	service := businesslogic.NewProficiencyPointService(businesslogic.YCNPointScale, partnershipRepo, roundEntryRepo,
+		roundRepo, resultRepo, eventRepo, federationRepo, proficiencyRepo, overrideRepo, historyRepo)
+
+	// each of the three events is checked for both athletes. Levels of the events are searched for each check, and
+	// levels of the results of athlete 1 are searched for each summary of athlete 1.
+	expectAthleteResults(3, partnershipRepo, roundEntryRepo, roundRepo, resultRepo, eventRepo)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{LeadID: 2}).Return([]businesslogic.Partnership{}, nil).Times(3)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{FollowID: 2}).Return([]businesslogic.Partnership{}, nil).Times(3)
+	federationRepo.EXPECT().SearchFederation(businesslogic.SearchFederationCriteria{ID: 1}).Return([]businesslogic.Federation{{ID: 1, Abbreviation: businesslogic.CollegiateFederationAbbreviation}}, nil).Times(15)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 103}).Return([]businesslogic.Proficiency{{ID: 103, Name: "Silver"}}, nil).Times(10)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 104}).Return([]businesslogic.Proficiency{{ID: 104, Name: "Gold"}}, nil).Times(5)
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{AthleteID: 1, ActiveOnly: true}).Return([]businesslogic.ProficiencyPointOverride{}, nil).Times(3)
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{AthleteID: 2, ActiveOnly: true}).Return([]businesslogic.ProficiencyPointOverride{}, nil).Times(3)
+
+	rule := businesslogic.NewProficiencyPointOutRule(service)
+	form := businesslogic.EventRegistrationForm{
+		Couple: businesslogic.Partnership{ID: 102, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 2}},
+		EventsAdded: []businesslogic.Event{
+			{ID: 11, FederationID: 1, StyleID: 2, ProficiencyID: 103},
+			{ID: 12, FederationID: 1, StyleID: 2, ProficiencyID: 104},
+			{ID: 13, FederationID: 1, StyleID: 1, ProficiencyID: 103},
+		},
+	}
+	err := rule.Apply(form)
+	assert.IsType(t, businesslogic.EventEligibilityError{}, err)
+	violations := err.(businesslogic.EventEligibilityError).Violations
+	assert.Len(t, violations, 1, "only the lead has pointed out of Silver Latin")
+	assert.Equal(t, businesslogic.EligibilityReasonPointedOut, violations[0].Reason)
+	assert.Equal(t, 11, violations[0].EventID)
+	assert.Equal(t, 1, violations[0].AthleteID)
+}
+
+func TestProficiencyPointOutRule_Apply_Overridden(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	roundEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	federationRepo := mock_businesslogic.NewMockIFederationRepository(mockCtrl)
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	overrideRepo := mock_businesslogic.NewMockIProficiencyPointOverrideRepository(mockCtrl)
+	historyRepo := mock_businesslogic.NewMockIProficiencyPointOverrideHistoryRepository(mockCtrl)
+	service := businesslogic.NewProficiencyPointService(businesslogic.YCNPointScale, partnershipRepo, roundEntryRepo,
+		roundRepo, resultRepo, eventRepo, federationRepo, proficiencyRepo, overrideRepo, historyRepo)
+
+	// athlete 1 leads and follows in the couple, so the event is checked twice
+	expectAthleteResults(2, partnershipRepo, roundEntryRepo, roundRepo, resultRepo, eventRepo)
+	federationRepo.EXPECT().SearchFederation(businesslogic.SearchFederationCriteria{ID: 1}).Return([]businesslogic.Federation{{ID: 1, Abbreviation: businesslogic.CollegiateFederationAbbreviation}}, nil).Times(8)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 103}).Return([]businesslogic.Proficiency{{ID: 103, Name: "Silver"}}, nil).Times(8)
+	proficiencyRepo.EXPECT().SearchProficiency(businesslogic.SearchProficiencyCriteria{ProficiencyID: 104}).Return([]businesslogic.Proficiency{{ID: 104, Name: "Gold"}}, nil).Times(2)
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{AthleteID: 1, ActiveOnly: true}).Return([]businesslogic.ProficiencyPointOverride{
+		{ID: 1, AthleteID: 1, StyleID: 2, ProficiencyID: 103, Active: true},
+	}, nil).Times(2)
+
+	form := businesslogic.EventRegistrationForm{
+		Couple:      businesslogic.Partnership{ID: 102, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 1}},
+		EventsAdded: []businesslogic.Event{{ID: 11, FederationID: 1, StyleID: 2, ProficiencyID: 103}},
+	}
+	assert.Nil(t, businesslogic.NewProficiencyPointOutRule(service).Apply(form))
+}
+
+func TestProficiencyPointService_GrantOverride(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	roundEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	federationRepo := mock_businesslogic.NewMockIFederationRepository(mockCtrl)
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	overrideRepo := mock_businesslogic.NewMockIProficiencyPointOverrideRepository(mockCtrl)
+	historyRepo := mock_businesslogic.NewMockIProficiencyPointOverrideHistoryRepository(mockCtrl)
+	service := businesslogic.NewProficiencyPointService(businesslogic.YCNPointScale, partnershipRepo, roundEntryRepo,
+		roundRepo, resultRepo, eventRepo, federationRepo, proficiencyRepo, overrideRepo, historyRepo)
+
+	override := businesslogic.ProficiencyPointOverride{AthleteID: 1, StyleID: 2, ProficiencyID: 103, Reason: "returning after injury"}
+	assert.NotNil(t, service.GrantOverride(newOrganizer(41), &override), "only administrators can grant overrides")
+
+	admin := businesslogic.Account{ID: 9}
+	admin.SetRoles([]businesslogic.AccountRole{{AccountID: 9, AccountTypeID: businesslogic.AccountTypeAdministrator}})
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{
+		AthleteID: 1, StyleID: 2, ProficiencyID: 103, ActiveOnly: true,
+	}).Return([]businesslogic.ProficiencyPointOverride{}, nil)
+	overrideRepo.EXPECT().CreateProficiencyPointOverride(gomock.Any()).DoAndReturn(func(override *businesslogic.ProficiencyPointOverride) error {
+		override.ID = 5
+		return nil
+	})
+	historyRepo.EXPECT().CreateProficiencyPointOverrideHistory(gomock.Any()).DoAndReturn(func(entry *businesslogic.ProficiencyPointOverrideHistoryEntry) error {
+		assert.Equal(t, 5, entry.OverrideID)
+		assert.Equal(t, businesslogic.ProficiencyPointOverrideActionGrant, entry.Action)
+		assert.Equal(t, 9, entry.CreateUserID)
+		return nil
+	})
+	assert.Nil(t, service.GrantOverride(admin, &override))
+	assert.True(t, override.Active)
+}
+
+func TestProficiencyPointService_RevokeOverride(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	roundEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	federationRepo := mock_businesslogic.NewMockIFederationRepository(mockCtrl)
+	proficiencyRepo := mock_businesslogic.NewMockIProficiencyRepository(mockCtrl)
+	overrideRepo := mock_businesslogic.NewMockIProficiencyPointOverrideRepository(mockCtrl)
+	historyRepo := mock_businesslogic.NewMockIProficiencyPointOverrideHistoryRepository(mockCtrl)
+	service := businesslogic.NewProficiencyPointService(businesslogic.YCNPointScale, partnershipRepo, roundEntryRepo,
+		roundRepo, resultRepo, eventRepo, federationRepo, proficiencyRepo, overrideRepo, historyRepo)
+
+	admin := businesslogic.Account{ID: 9}
+	admin.SetRoles([]businesslogic.AccountRole{{AccountID: 9, AccountTypeID: businesslogic.AccountTypeAdministrator}})
+	overrideRepo.EXPECT().SearchProficiencyPointOverride(businesslogic.SearchProficiencyPointOverrideCriteria{ID: 5}).Return([]businesslogic.ProficiencyPointOverride{
+		{ID: 5, AthleteID: 1, StyleID: 2, ProficiencyID: 103, Active: true},
+	}, nil)
+	overrideRepo.EXPECT().UpdateProficiencyPointOverride(gomock.Any()).DoAndReturn(func(override businesslogic.ProficiencyPointOverride) error {
+		assert.False(t, override.Active)
+		return nil
+	})
+	historyRepo.EXPECT().CreateProficiencyPointOverrideHistory(gomock.Any()).DoAndReturn(func(entry *businesslogic.ProficiencyPointOverrideHistoryEntry) error {
+		assert.Equal(t, businesslogic.ProficiencyPointOverrideActionRevoke, entry.Action)
+		assert.Equal(t, "granted by mistake", entry.Note)
+		return nil
+	})
+	assert.Nil(t, service.RevokeOverride(admin, 5, "granted by mistake"))
+}
@@ -22,6 +22,20 @@ type PartnershipCompetitionRepresentation struct {
 	DateTimeUpdated               time.Time
 }
 
+// SearchPartnershipCompetitionRepresentationCriteria specifies the parameters that can be used to search the
+// representation of partnerships at a competition
+type SearchPartnershipCompetitionRepresentationCriteria struct {
+	CompetitionID                 int
+	PartnershipCompetitionEntryID int
+}
+
+// IPartnershipCompetitionRepresentationRepository specifies the functions that need to be implemented to store and
+// search PartnershipCompetitionRepresentation
+type IPartnershipCompetitionRepresentationRepository interface {
+	CreateCompetitionRepresentation(representation *PartnershipCompetitionRepresentation) error
+	SearchCompetitionRepresentation(criteria SearchPartnershipCompetitionRepresentationCriteria) ([]PartnershipCompetitionRepresentation, error)
+}
+
 // EventRegistrationForm specifies the data needed to create/update/drop event registration
 type EventRegistrationForm struct {
 	Competition        Competition
@@ -48,6 +62,9 @@ type CompetitionRegistrationService struct {
 	partnershipCompetitionEntryService PartnershipCompetitionEntryService
 	athleteEventEntryService           AthleteEventEntryService
 	coupleEventEntryService            PartnershipEventEntryService
+	EligibilityRules                   IRule                   // events are not checked for eligibility if no rules are specified
+	Fees                               *RegistrationFeeService // payment status of entries is not updated if no fee service is specified
+	LeadTags                           *LeadTagService         // leads are tagged by NextAvailableLeadTag if no lead tag service is specified
 }
 
 func NewCompetitionRegistrationService(
@@ -92,6 +109,11 @@ func (service CompetitionRegistrationService) CreateAndUpdateRegistration(curren
 		return errors.New("registration can no longer be updated or you are not authorized")
 	}
 
+	// events that the partnership is not eligible for are rejected before any entry is changed
+	if err := service.checkEventEligibility(registration); err != nil {
+		return err
+	}
+
 	// create/delete partnership competition entry, depends on the registration form
 	if err := service.CreateAndUpdatePartnershipCompetitionEntry(currentUser, registration); err != nil {
 		return err
@@ -105,6 +127,21 @@ func (service CompetitionRegistrationService) CreateAndUpdateRegistration(curren
 		return err
 	}
 
+	// invoices change as events are added and dropped, so entries that were paid may have a balance again
+	if service.Fees != nil {
+		if err := service.Fees.UpdatePaymentStatus(registration.Competition.ID, registration.Couple.Lead.ID, registration.Couple.Follow.ID); err != nil {
+			return err
+		}
+	}
+
+	// the lead keeps a tag while leading any partnership at the competition, and the tag is released once the lead drops out
+	if service.LeadTags != nil {
+		group := LeadTagGroup{SchoolID: registration.SchoolRepresented.ID, StudioID: registration.StudioRepresented.ID}
+		if err := service.LeadTags.UpdateLeadTag(registration.Competition.ID, registration.Couple.Lead.ID, group, currentUser.ID); err != nil {
+			return err
+		}
+	}
+
 	// TODO: update attendance of a competition based on athlete competition entry
 
 	// for scrutineer and organizer, check if they are either invited officials of the competition
@@ -113,10 +150,6 @@ func (service CompetitionRegistrationService) CreateAndUpdateRegistration(curren
 	// check if current user already has registration
 
 	// if has existing registration, get the existing registration
-
-	// TODO: when new athlete entry is added, check if there is a tag of lead is assigned. If not, assign a default lead
-	// 		tag and insert it to the repo
-	//		If the lead later drops out the competition, the tag spot should be released for other lead to use
 	return nil
 }
 
@@ -199,18 +232,7 @@ func (service CompetitionRegistrationService) ValidateEventRegistration(currentU
 	// check if dropped events are added
 
 	// check event entries, and see if this partnership is still eligible for entering these events
-	// TODO: eligibility check
-	for _, each := range registration.EventsAdded {
-		eventEntry := PartnershipEventEntry{
-			Couple: registration.Couple,
-			Event:  each,
-		}
-		eligibilityErr := checkEventEligibility(eventEntry)
-		if eligibilityErr != nil {
-			return eligibilityErr
-		}
-	}
-	return nil
+	return service.checkEventEligibility(registration)
 }
 
 func (service CompetitionRegistrationService) SearchCompetitionEntries(criteria SearchEntryCriteria) (CompetitionEntryList, error) {
@@ -299,9 +321,12 @@ func (service CompetitionRegistrationService) CreateAndUpdateAthleteCompetitionE
 		return err
 	}
 	if len(searchLeadCompEntryResult) == 0 {
-		nextTag, tagErr := service.AthleteCompetitionEntryRepo.NextAvailableLeadTag(registration.Competition)
-		if tagErr != nil {
-			return tagErr
+		nextTag := 0
+		if service.LeadTags == nil {
+			var tagErr error
+			if nextTag, tagErr = service.AthleteCompetitionEntryRepo.NextAvailableLeadTag(registration.Competition); tagErr != nil {
+				return tagErr
+			}
 		}
 		entry := AthleteCompetitionEntry{
 			Competition:              registration.Competition,
@@ -479,9 +504,13 @@ func (service CompetitionRegistrationService) updateAttendance(competitionId int
 	return service.CompetitionRepository.UpdateCompetition(Competition{ID: competitionId, Attendance: len(athleteEntries)})
 }
 
-func checkEventEligibility(entry PartnershipEventEntry) error {
-	// TODO: implement this method
-	return errors.New("not implemented")
+// checkEventEligibility applies the eligibility rules of the service to the registration. The returned error is an
+// EventEligibilityError if the registration violates any rule.
+func (service CompetitionRegistrationService) checkEventEligibility(registration EventRegistrationForm) error {
+	if service.EligibilityRules == nil || len(registration.EventsAdded) == 0 {
+		return nil
+	}
+	return service.EligibilityRules.Apply(registration)
 }
 
 // GetEventRegistration get event registration for the provided competition and partnership
@@ -26,6 +26,7 @@ type Round struct {
 
 // SearchRoundCriteria specifies the parameters that can be used to search Rounds in a Repository
 type SearchRoundCriteria struct {
+	ID            int
 	CompetitionID int
 	EventID       int
 	RoundOrderID  int
@@ -40,12 +40,15 @@ type IPartnershipRoundEntryRepository interface {
 // AdjudicatorRoundEntry defines the Round Entry of an Adjudicator
 type AdjudicatorRoundEntry struct {
 	ID                 int
-	AdjudicatorEntryID int
+	AdjudicatorEntryID int // the account ID of the adjudicator
 	RoundEntry         RoundEntry
 }
 
 // SearchAdjudicatorRoundEntryCriteria specifies the parameters that can be used to search the Round Entry of Adjudicator
 type SearchAdjudicatorRoundEntryCriteria struct {
+	ID            int `schema:"entry"`
+	RoundID       int `schema:"round"`
+	AdjudicatorID int `schema:"adjudicator"`
 }
 
 // IAdjudicatorRoundEntryRepository specifies the functions that need to be implemented to perform CRUD operations
@@ -0,0 +1,265 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"log"
+	"sort"
+	"time"
+)
+
+// RoundGenerationSettings specifies the parameters that are used to estimate the rounds of an event
+type RoundGenerationSettings struct {
+	FloorCapacity   int // maximum number of couples on the floor at the same time
+	RecallRate      int // percentage of couples that are recalled to the next round
+	TargetFinalSize int // preferred number of couples in the final round
+	DanceDuration   int // seconds
+}
+
+// DefaultRoundGenerationSettings is used when organizers do not specify how rounds should be estimated
+var DefaultRoundGenerationSettings = RoundGenerationSettings{
+	FloorCapacity:   12,
+	RecallRate:      50,
+	TargetFinalSize: 6,
+	DanceDuration:   90,
+}
+
+// RoundGenerationService creates the rounds of an event when the event starts, and moves the recalled couples of a
+// preliminary round to the next round.
+type RoundGenerationService struct {
+	eventRepo            IEventRepository
+	eventDanceRepo       IEventDanceRepository
+	roundRepo            IRoundRepository
+	eventEntryRepo       IPartnershipEventEntryRepository
+	partnershipEntryRepo IPartnershipRoundEntryRepository
+	resultRepo           IRoundResultRepository
+}
+
+func NewRoundGenerationService(
+	eventRepo IEventRepository,
+	eventDanceRepo IEventDanceRepository,
+	roundRepo IRoundRepository,
+	eventEntryRepo IPartnershipEventEntryRepository,
+	partnershipEntryRepo IPartnershipRoundEntryRepository,
+	resultRepo IRoundResultRepository) RoundGenerationService {
+	return RoundGenerationService{
+		eventRepo:            eventRepo,
+		eventDanceRepo:       eventDanceRepo,
+		roundRepo:            roundRepo,
+		eventEntryRepo:       eventEntryRepo,
+		partnershipEntryRepo: partnershipEntryRepo,
+		resultRepo:           resultRepo,
+	}
+}
+
+// getEvent returns the event if it belongs to the competition
+func (service RoundGenerationService) getEvent(competitionID, eventID int) (Event, error) {
+	events, err := service.eventRepo.SearchEvent(SearchEventCriteria{CompetitionID: competitionID, EventID: eventID})
+	if err != nil {
+		return Event{}, err
+	}
+	if len(events) != 1 {
+		return Event{}, errors.New(fmt.Sprintf("cannot find event with ID = %d in competition %d", eventID, competitionID))
+	}
+	return events[0], nil
+}
+
+// StartEvent changes the status of the event to running, creates the estimated number of rounds for the couples who
+// have checked in, and enters all of them in the first round. Starting an event that failed to start partway resumes
+// from the rounds and entries that are already created, so the event is never left open with a partial set of rounds.
+func (service RoundGenerationService) StartEvent(currentUser Account, competitionID, eventID int, settings RoundGenerationSettings) ([]Round, error) {
+	event, err := service.getEvent(competitionID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.StatusID != EVENT_STATUS_OPEN {
+		return nil, errors.New("only events that are open can be started")
+	}
+	rounds, err := service.roundRepo.SearchRound(SearchRoundCriteria{EventID: event.ID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Order.Rank < rounds[j].Order.Rank })
+
+	entries, err := service.eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{EventID: event.ID})
+	if err != nil {
+		return nil, err
+	}
+	couples := make([]PartnershipEventEntry, 0)
+	for _, each := range entries {
+		if each.CheckedIn {
+			couples = append(couples, each)
+		}
+	}
+	dances, err := service.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{EventID: event.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	rosters, err := estimateRoundEntries(len(couples), len(dances), settings)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(rounds); i < len(rosters); i++ {
+		round := Round{
+			EventID:         event.ID,
+			Order:           RoundOrder{ID: i + 1, Rank: i + 1},
+			CreateUserID:    currentUser.ID,
+			DateTimeCreated: time.Now(),
+			UpdateUserID:    currentUser.ID,
+			DateTimeUpdated: time.Now(),
+		}
+		if err := service.roundRepo.CreateRound(&round); err != nil {
+			log.Printf("[error] creating round %d of event %d: %v", i+1, event.ID, err)
+			return rounds, err
+		}
+		rounds = append(rounds, round)
+	}
+
+	entered, err := service.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: rounds[0].ID})
+	if err != nil {
+		return rounds, err
+	}
+	inFirstRound := make(map[int]bool)
+	for _, each := range entered {
+		inFirstRound[each.PartnershipID] = true
+	}
+	for _, each := range couples {
+		if inFirstRound[each.Couple.ID] {
+			continue
+		}
+		if err := service.createRoundEntry(rounds[0], each.Couple.ID, currentUser.ID); err != nil {
+			return rounds, err
+		}
+	}
+
+	event.StatusID = EVENT_STATUS_RUNNING
+	event.UpdateUserID = currentUser.ID
+	event.DateTimeUpdated = time.Now()
+	return rounds, service.eventRepo.UpdateEvent(event)
+}
+
+// estimateRoundEntries estimates the number of couples in each round, from the first round to the final
+func estimateRoundEntries(couples, dances int, settings RoundGenerationSettings) ([]int, error) {
+	if couples < 1 {
+		return nil, errors.New("no couple has checked in for this event")
+	}
+	estimator := scheduler.EventScheduler{}
+	for _, err := range []error{
+		estimator.SetTotalEntries(couples),
+		estimator.SetTotalDances(dances),
+		estimator.SetFloorCapacity(settings.FloorCapacity),
+		estimator.SetRecallRate(settings.RecallRate),
+		estimator.SetTargetFinalSize(settings.TargetFinalSize),
+		estimator.SetDanceDuration(settings.DanceDuration),
+	} {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return estimator.EstimateRoundEntries()
+}
+
+func (service RoundGenerationService) createRoundEntry(round Round, partnershipID, currentUserID int) error {
+	entry := PartnershipRoundEntry{
+		PartnershipID: partnershipID,
+		RoundEntry: RoundEntry{
+			RoundID:         round.ID,
+			CreateUserID:    currentUserID,
+			DateTimeCreated: time.Now(),
+			UpdateUserID:    currentUserID,
+			DateTimeUpdated: time.Now(),
+		},
+	}
+	if err := service.partnershipEntryRepo.CreatePartnershipRoundEntry(&entry); err != nil {
+		log.Printf("[error] entering partnership %d in round %d: %v", partnershipID, round.ID, err)
+		return err
+	}
+	return nil
+}
+
+// AdvanceRecalledCouples enters the couples who are recalled from the preliminary round in the next round. The next
+// round is created if more rounds are needed than estimated. Couples who are already in the next round are skipped,
+// so recalls can be advanced again after the result of the round is recomputed.
+func (service RoundGenerationService) AdvanceRecalledCouples(currentUser Account, competitionID, roundID int) (Round, error) {
+	rounds, err := service.roundRepo.SearchRound(SearchRoundCriteria{ID: roundID})
+	if err != nil {
+		return Round{}, err
+	}
+	if len(rounds) != 1 {
+		return Round{}, errors.New(fmt.Sprintf("round %v does not exist", roundID))
+	}
+	round := rounds[0]
+	event, err := service.getEvent(competitionID, round.EventID)
+	if err != nil {
+		return Round{}, err
+	}
+	if event.StatusID != EVENT_STATUS_RUNNING {
+		return Round{}, errors.New("recalls can only be advanced when the event is running")
+	}
+
+	results, err := service.resultRepo.SearchRoundResult(SearchRoundResultCriteria{RoundID: round.ID})
+	if err != nil {
+		return Round{}, err
+	}
+	recalled := make(map[int]bool)
+	for _, each := range results {
+		if each.PreliminaryRoundIndicator && each.Recalled {
+			recalled[each.PartnershipRoundEntryID] = true
+		}
+	}
+	if len(recalled) == 0 {
+		return Round{}, errors.New("recalls of this round are not computed")
+	}
+
+	next, err := service.getNextRound(round, currentUser.ID)
+	if err != nil {
+		return next, err
+	}
+	advanced, err := service.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: next.ID})
+	if err != nil {
+		return next, err
+	}
+	inNextRound := make(map[int]bool)
+	for _, each := range advanced {
+		inNextRound[each.PartnershipID] = true
+	}
+
+	entries, err := service.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: round.ID})
+	if err != nil {
+		return next, err
+	}
+	for _, each := range entries {
+		if !recalled[each.ID] || inNextRound[each.PartnershipID] {
+			continue
+		}
+		if err := service.createRoundEntry(next, each.PartnershipID, currentUser.ID); err != nil {
+			return next, err
+		}
+	}
+	return next, nil
+}
+
+// getNextRound returns the round that follows the specified round in the same event, creating it if it does not exist
+func (service RoundGenerationService) getNextRound(round Round, currentUserID int) (Round, error) {
+	rounds, err := service.roundRepo.SearchRound(SearchRoundCriteria{EventID: round.EventID})
+	if err != nil {
+		return Round{}, err
+	}
+	for _, each := range rounds {
+		if each.Order.Rank == round.Order.Rank+1 {
+			return each, nil
+		}
+	}
+	next := Round{
+		EventID:         round.EventID,
+		Order:           RoundOrder{ID: round.Order.Rank + 1, Rank: round.Order.Rank + 1},
+		CreateUserID:    currentUserID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    currentUserID,
+		DateTimeUpdated: time.Now(),
+	}
+	return next, service.roundRepo.CreateRound(&next)
+}
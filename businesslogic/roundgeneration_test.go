@@ -0,0 +1,185 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newOrganizer(id int) businesslogic.Account {
+	account := businesslogic.Account{ID: id}
+	account.SetRoles([]businesslogic.AccountRole{{AccountID: id, AccountTypeID: businesslogic.AccountTypeOrganizer}})
+	return account
+}
+
+func TestRoundGenerationService_StartEvent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewRoundGenerationService(eventRepo, eventDanceRepo,
+		roundRepo, eventEntryRepo, partnershipEntryRepo, resultRepo)
+
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3, EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3, StatusID: businesslogic.EVENT_STATUS_OPEN}}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: 5}).Return([]businesslogic.Round{}, nil)
+
+	entries := make([]businesslogic.PartnershipEventEntry, 0)
+	for i := 1; i <= 26; i++ {
+		entries = append(entries, businesslogic.PartnershipEventEntry{
+			ID:        i,
+			Couple:    businesslogic.Partnership{ID: 100 + i},
+			CheckedIn: i <= 24,
+		})
+	}
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 5}).Return(entries, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+
+	roundID := 0
+	roundRepo.EXPECT().CreateRound(gomock.Any()).Do(func(round *businesslogic.Round) {
+		roundID++
+		round.ID = roundID
+	}).Return(nil).Times(3)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 1}).Return([]businesslogic.PartnershipRoundEntry{}, nil)
+	partnershipEntryRepo.EXPECT().CreatePartnershipRoundEntry(gomock.Any()).Do(func(entry *businesslogic.PartnershipRoundEntry) {
+		assert.Equal(t, 1, entry.RoundEntry.RoundID, "checked-in couples should be entered in the first round")
+		assert.True(t, entry.PartnershipID <= 124, "couples who have not checked in should not be entered")
+	}).Return(nil).Times(24)
+	eventRepo.EXPECT().UpdateEvent(gomock.Any()).Do(func(event businesslogic.Event) {
+		assert.Equal(t, businesslogic.EVENT_STATUS_RUNNING, event.StatusID)
+	}).Return(nil)
+
+	rounds, err := service.StartEvent(newOrganizer(41), 3, 5, businesslogic.DefaultRoundGenerationSettings)
+	assert.Nil(t, err)
+	assert.Len(t, rounds, 3, "24 couples should dance a first round, a semi-final and a final")
+	assert.Equal(t, 3, rounds[2].Order.Rank)
+}
+
+func TestRoundGenerationService_StartEvent_Resume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewRoundGenerationService(eventRepo, eventDanceRepo,
+		roundRepo, eventEntryRepo, partnershipEntryRepo, resultRepo)
+
+	// an earlier attempt created the first round and entered two couples before it failed
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3, EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3, StatusID: businesslogic.EVENT_STATUS_OPEN}}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: 5}).Return([]businesslogic.Round{
+		{ID: 1, EventID: 5, Order: businesslogic.RoundOrder{ID: 1, Rank: 1}},
+	}, nil)
+	entries := make([]businesslogic.PartnershipEventEntry, 0)
+	for i := 1; i <= 24; i++ {
+		entries = append(entries, businesslogic.PartnershipEventEntry{ID: i, Couple: businesslogic.Partnership{ID: 100 + i}, CheckedIn: true})
+	}
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 5}).Return(entries, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+
+	roundID := 1
+	roundRepo.EXPECT().CreateRound(gomock.Any()).Do(func(round *businesslogic.Round) {
+		roundID++
+		round.ID = roundID
+		assert.Equal(t, roundID, round.Order.Rank, "only the missing rounds should be created")
+	}).Return(nil).Times(2)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 1}).Return([]businesslogic.PartnershipRoundEntry{
+		{ID: 11, PartnershipID: 101},
+		{ID: 12, PartnershipID: 102},
+	}, nil)
+	partnershipEntryRepo.EXPECT().CreatePartnershipRoundEntry(gomock.Any()).Do(func(entry *businesslogic.PartnershipRoundEntry) {
+		assert.True(t, entry.PartnershipID > 102, "couples who are already entered should not be entered again")
+	}).Return(nil).Times(22)
+	eventRepo.EXPECT().UpdateEvent(gomock.Any()).Return(nil)
+
+	rounds, err := service.StartEvent(newOrganizer(41), 3, 5, businesslogic.DefaultRoundGenerationSettings)
+	assert.Nil(t, err)
+	assert.Len(t, rounds, 3)
+}
+
+func TestRoundGenerationService_StartEvent_OtherCompetition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewRoundGenerationService(eventRepo, eventDanceRepo,
+		roundRepo, eventEntryRepo, partnershipEntryRepo, resultRepo)
+
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 4, EventID: 5}).Return([]businesslogic.Event{}, nil)
+	_, err := service.StartEvent(newOrganizer(41), 4, 5, businesslogic.DefaultRoundGenerationSettings)
+	assert.NotNil(t, err, "events of other competitions should not be started")
+}
+
+func TestRoundGenerationService_StartEvent_NotOpen(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewRoundGenerationService(eventRepo, eventDanceRepo,
+		roundRepo, eventEntryRepo, partnershipEntryRepo, resultRepo)
+
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3, EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3, StatusID: businesslogic.EVENT_STATUS_RUNNING}}, nil)
+	_, err := service.StartEvent(newOrganizer(41), 3, 5, businesslogic.DefaultRoundGenerationSettings)
+	assert.NotNil(t, err, "a running event should not be started again")
+}
+
+func TestRoundGenerationService_AdvanceRecalledCouples(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewRoundGenerationService(eventRepo, eventDanceRepo,
+		roundRepo, eventEntryRepo, partnershipEntryRepo, resultRepo)
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 1}).Return([]businesslogic.Round{{ID: 1, EventID: 5, Order: businesslogic.RoundOrder{Rank: 1}}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3, EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3, StatusID: businesslogic.EVENT_STATUS_RUNNING}}, nil)
+	resultRepo.EXPECT().SearchRoundResult(businesslogic.SearchRoundResultCriteria{RoundID: 1}).Return([]businesslogic.RoundResult{
+		{PartnershipRoundEntryID: 11, PreliminaryRoundIndicator: true, Recalled: true},
+		{PartnershipRoundEntryID: 12, PreliminaryRoundIndicator: true, Recalled: true},
+		{PartnershipRoundEntryID: 13, PreliminaryRoundIndicator: true, Recalled: false},
+	}, nil)
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{EventID: 5}).Return([]businesslogic.Round{
+		{ID: 1, EventID: 5, Order: businesslogic.RoundOrder{Rank: 1}},
+		{ID: 2, EventID: 5, Order: businesslogic.RoundOrder{Rank: 2}},
+	}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 2}).Return([]businesslogic.PartnershipRoundEntry{
+		{ID: 21, PartnershipID: 101},
+	}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 1}).Return([]businesslogic.PartnershipRoundEntry{
+		{ID: 11, PartnershipID: 101},
+		{ID: 12, PartnershipID: 102},
+		{ID: 13, PartnershipID: 103},
+	}, nil)
+	partnershipEntryRepo.EXPECT().CreatePartnershipRoundEntry(gomock.Any()).Do(func(entry *businesslogic.PartnershipRoundEntry) {
+		assert.Equal(t, 102, entry.PartnershipID, "only recalled couples who are not yet advanced should be entered")
+		assert.Equal(t, 2, entry.RoundEntry.RoundID)
+	}).Return(nil)
+
+	next, err := service.AdvanceRecalledCouples(newOrganizer(41), 3, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, next.ID)
+}
@@ -0,0 +1,324 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"log"
+	"sort"
+	"time"
+)
+
+// EventRoundSchedule is the estimated time of a round in the timetable of the competition. Since the timetable is
+// built before rounds are created, the round is identified by its order in the event.
+type EventRoundSchedule struct {
+	ID                        int
+	EventID                   int
+	RoundOrder                int // 1-based, the final is the last round of the event
+	PreliminaryRoundIndicator bool
+	Floor                     int
+	EstimatedStartTime        time.Time
+	EstimatedEndTime          time.Time
+	ActualStartTime           *time.Time
+	Published                 bool // rebuilding the timetable replaces published rounds with unpublished ones
+	CreateUserID              int
+	DateTimeCreated           time.Time
+	UpdateUserID              int
+	DateTimeUpdated           time.Time
+}
+
+// SearchEventRoundScheduleCriteria specifies the parameters that can be used to search EventRoundSchedule in a
+// repository
+type SearchEventRoundScheduleCriteria struct {
+	CompetitionID int
+	EventID       int
+}
+
+// IEventRoundScheduleRepository specifies the functions that an EventRoundSchedule Repository should implement.
+// ReplaceEventRoundSchedule replaces the timetable of all the events of a competition at once, so a failure does not
+// leave a partial timetable, and PublishEventRoundSchedule publishes all the rounds of the timetable at once.
+type IEventRoundScheduleRepository interface {
+	CreateEventRoundSchedule(schedule *EventRoundSchedule) error
+	DeleteEventRoundSchedule(schedule EventRoundSchedule) error
+	SearchEventRoundSchedule(criteria SearchEventRoundScheduleCriteria) ([]EventRoundSchedule, error)
+	UpdateEventRoundSchedule(schedule EventRoundSchedule) error
+	ReplaceEventRoundSchedule(competitionID int, schedules []EventRoundSchedule) error
+	PublishEventRoundSchedule(competitionID, updateUserID int) error
+}
+
+// CompetitionScheduleSettings specifies how the rounds of all the events of a competition are scheduled. Rounds of
+// each event are estimated with RoundGenerationSettings, and consecutive rounds of the same event are at least
+// RoundInterval apart.
+type CompetitionScheduleSettings struct {
+	RoundGenerationSettings
+	Sessions      []scheduler.Session
+	BreakRule     scheduler.BreakRule
+	RoundInterval time.Duration
+}
+
+// CompetitionSchedule is the timetable of a competition and the conflicts that organizers should resolve before the
+// timetable is published
+type CompetitionSchedule struct {
+	Rounds    []EventRoundSchedule
+	Conflicts []scheduler.ScheduleConflict
+}
+
+// DefaultMinimumRest is the rest that athletes should have between two rounds of different events
+const DefaultMinimumRest = 10 * time.Minute
+
+// AthleteScheduleConflict is a pair of scheduled rounds of an athlete that either overlap, or leave the athlete less
+// rest than required. Rest is negative when the rounds overlap.
+type AthleteScheduleConflict struct {
+	AthleteID int
+	First     EventRoundSchedule
+	Second    EventRoundSchedule
+	Clash     bool
+	Rest      time.Duration
+}
+
+// CompetitionScheduleService builds the timetable of all the events of a competition
+type CompetitionScheduleService struct {
+	eventRepo       IEventRepository
+	eventDanceRepo  IEventDanceRepository
+	eventEntryRepo  IPartnershipEventEntryRepository
+	partnershipRepo IPartnershipRepository
+	scheduleRepo    IEventRoundScheduleRepository
+}
+
+func NewCompetitionScheduleService(
+	eventRepo IEventRepository,
+	eventDanceRepo IEventDanceRepository,
+	eventEntryRepo IPartnershipEventEntryRepository,
+	partnershipRepo IPartnershipRepository,
+	scheduleRepo IEventRoundScheduleRepository) CompetitionScheduleService {
+	return CompetitionScheduleService{
+		eventRepo:       eventRepo,
+		eventDanceRepo:  eventDanceRepo,
+		eventEntryRepo:  eventEntryRepo,
+		partnershipRepo: partnershipRepo,
+		scheduleRepo:    scheduleRepo,
+	}
+}
+
+// BuildSchedule estimates the rounds of every event that has entries, orders them into a timetable, and replaces the
+// stored timetable of the competition. Couples and athletes who dance in rounds that overlap are reported as
+// conflicts of the schedule.
+func (service CompetitionScheduleService) BuildSchedule(currentUser Account, competitionID int, settings CompetitionScheduleSettings) (CompetitionSchedule, error) {
+	events, err := service.getScheduleEvents(competitionID, settings.RoundGenerationSettings)
+	if err != nil {
+		return CompetitionSchedule{}, err
+	}
+	competitionScheduler, err := scheduler.NewCompetitionScheduler(
+		settings.FloorCapacity,
+		time.Duration(settings.DanceDuration)*time.Second,
+		settings.RoundInterval,
+		settings.BreakRule,
+		settings.Sessions)
+	if err != nil {
+		return CompetitionSchedule{}, err
+	}
+	timetable, err := competitionScheduler.Schedule(events)
+	if err != nil {
+		return CompetitionSchedule{}, err
+	}
+
+	schedule := CompetitionSchedule{
+		Rounds:    make([]EventRoundSchedule, 0),
+		Conflicts: scheduler.FindConflicts(events, timetable),
+	}
+	for _, each := range timetable {
+		schedule.Rounds = append(schedule.Rounds, EventRoundSchedule{
+			EventID:                   each.EventID,
+			RoundOrder:                each.Round,
+			PreliminaryRoundIndicator: !each.Final,
+			Floor:                     each.Floor,
+			EstimatedStartTime:        each.Start,
+			EstimatedEndTime:          each.End,
+			CreateUserID:              currentUser.ID,
+			DateTimeCreated:           time.Now(),
+			UpdateUserID:              currentUser.ID,
+			DateTimeUpdated:           time.Now(),
+		})
+	}
+	return schedule, service.replaceSchedule(competitionID, schedule.Rounds)
+}
+
+// GetSchedule returns the stored timetable of the competition in the order of estimated start time
+func (service CompetitionScheduleService) GetSchedule(competitionID int) ([]EventRoundSchedule, error) {
+	schedule, err := service.scheduleRepo.SearchEventRoundSchedule(SearchEventRoundScheduleCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].EstimatedStartTime.Before(schedule[j].EstimatedStartTime)
+	})
+	return schedule, nil
+}
+
+// PublishSchedule publishes the stored timetable of the competition. The timetable cannot be published while an
+// athlete dances in rounds that overlap. Rounds that leave athletes less rest than recommended do not block publishing,
+// since organizers may accept them.
+func (service CompetitionScheduleService) PublishSchedule(currentUser Account, competitionID int) error {
+	conflicts, err := service.AnalyzeAthleteConflicts(competitionID, 0)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return errors.New(fmt.Sprintf("cannot publish the schedule while %d clashes of athletes remain", len(conflicts)))
+	}
+	schedule, err := service.GetSchedule(competitionID)
+	if err != nil {
+		return err
+	}
+	if len(schedule) == 0 {
+		return errors.New("the schedule of this competition is not built")
+	}
+	if err := service.scheduleRepo.PublishEventRoundSchedule(competitionID, currentUser.ID); err != nil {
+		log.Printf("[error] publishing schedule of competition %v: %v", competitionID, err)
+		return err
+	}
+	return nil
+}
+
+// AnalyzeAthleteConflicts checks the stored timetable of the competition for athletes who dance in rounds that overlap,
+// or who have less than minimumRest between two rounds of different events. An athlete may dance in several
+// partnerships, so athletes are checked across all the partnerships they are entered with.
+func (service CompetitionScheduleService) AnalyzeAthleteConflicts(competitionID int, minimumRest time.Duration) ([]AthleteScheduleConflict, error) {
+	if minimumRest < 0 {
+		return nil, errors.New("minimum rest must not be negative")
+	}
+	schedule, err := service.GetSchedule(competitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rounds := make(map[[2]int]EventRoundSchedule) // key: event, order of round
+	timetable := make([]scheduler.ScheduledRound, 0)
+	scheduled := make(map[int]bool)
+	for _, each := range schedule {
+		rounds[[2]int{each.EventID, each.RoundOrder}] = each
+		scheduled[each.EventID] = true
+		timetable = append(timetable, scheduler.ScheduledRound{
+			EventID: each.EventID,
+			Round:   each.RoundOrder,
+			Final:   !each.PreliminaryRoundIndicator,
+			Floor:   each.Floor,
+			Start:   each.EstimatedStartTime,
+			End:     each.EstimatedEndTime,
+		})
+	}
+	eventIDs := make([]int, 0)
+	for each := range scheduled {
+		eventIDs = append(eventIDs, each)
+	}
+	sort.Ints(eventIDs)
+
+	partnerships := make(map[int]Partnership)
+	events := make([]scheduler.ScheduleEvent, 0)
+	for _, eventID := range eventIDs {
+		entries, err := service.eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{EventID: eventID})
+		if err != nil {
+			return nil, err
+		}
+		event, err := service.getEntrants(eventID, entries, partnerships)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	conflicts := make([]AthleteScheduleConflict, 0)
+	for _, each := range scheduler.FindAthleteConflicts(events, timetable, minimumRest) {
+		conflicts = append(conflicts, AthleteScheduleConflict{
+			AthleteID: each.AthleteID,
+			First:     rounds[[2]int{each.First.EventID, each.First.Round}],
+			Second:    rounds[[2]int{each.Second.EventID, each.Second.Round}],
+			Clash:     each.Clash,
+			Rest:      each.Rest,
+		})
+	}
+	return conflicts, nil
+}
+
+// getScheduleEvents collects the dances, estimated rounds and entrants of the events of the competition. Events are
+// scheduled in the order of their IDs, and events without entries are not scheduled.
+func (service CompetitionScheduleService) getScheduleEvents(competitionID int, settings RoundGenerationSettings) ([]scheduler.ScheduleEvent, error) {
+	events, err := service.eventRepo.SearchEvent(SearchEventCriteria{CompetitionID: competitionID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	partnerships := make(map[int]Partnership)
+	scheduleEvents := make([]scheduler.ScheduleEvent, 0)
+	for _, event := range events {
+		entries, err := service.eventEntryRepo.SearchPartnershipEventEntry(SearchPartnershipEventEntryCriteria{EventID: event.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		dances, err := service.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{EventID: event.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(dances) == 0 {
+			return nil, errors.New(fmt.Sprintf("event %v does not have any dance", event.ID))
+		}
+		rosters, err := estimateRoundEntries(len(entries), len(dances), settings)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduleEvent, err := service.getEntrants(event.ID, entries, partnerships)
+		if err != nil {
+			return nil, err
+		}
+		scheduleEvent.Dances = len(dances)
+		scheduleEvent.RoundEntries = rosters
+		scheduleEvents = append(scheduleEvents, scheduleEvent)
+	}
+	return scheduleEvents, nil
+}
+
+// getEntrants collects the couples and athletes who are entered in the event
+func (service CompetitionScheduleService) getEntrants(eventID int, entries []PartnershipEventEntry, partnerships map[int]Partnership) (scheduler.ScheduleEvent, error) {
+	scheduleEvent := scheduler.ScheduleEvent{
+		EventID:  eventID,
+		Couples:  make([]int, 0),
+		Athletes: make([]int, 0),
+	}
+	for _, entry := range entries {
+		partnership, err := service.getPartnership(entry.Couple.ID, partnerships)
+		if err != nil {
+			return scheduleEvent, err
+		}
+		scheduleEvent.Couples = append(scheduleEvent.Couples, partnership.ID)
+		scheduleEvent.Athletes = append(scheduleEvent.Athletes, partnership.Lead.ID, partnership.Follow.ID)
+	}
+	return scheduleEvent, nil
+}
+
+func (service CompetitionScheduleService) getPartnership(partnershipID int, cache map[int]Partnership) (Partnership, error) {
+	if partnership, ok := cache[partnershipID]; ok {
+		return partnership, nil
+	}
+	results, err := service.partnershipRepo.SearchPartnership(SearchPartnershipCriteria{PartnershipID: partnershipID})
+	if err != nil {
+		return Partnership{}, err
+	}
+	if len(results) != 1 {
+		return Partnership{}, errors.New(fmt.Sprintf("cannot find partnership with ID = %d", partnershipID))
+	}
+	cache[partnershipID] = results[0]
+	return results[0], nil
+}
+
+func (service CompetitionScheduleService) replaceSchedule(competitionID int, rounds []EventRoundSchedule) error {
+	if err := service.scheduleRepo.ReplaceEventRoundSchedule(competitionID, rounds); err != nil {
+		log.Printf("[error] replacing schedule of competition %v: %v", competitionID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newScheduleSettings(floors int) businesslogic.CompetitionScheduleSettings {
+	start := time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)
+	return businesslogic.CompetitionScheduleSettings{
+		RoundGenerationSettings: businesslogic.DefaultRoundGenerationSettings,
+		Sessions:                []scheduler.Session{{Start: start, End: start.Add(8 * time.Hour), Floors: floors}},
+		RoundInterval:           10 * time.Minute,
+	}
+}
+
+// expectScheduleEvents sets up event 5 with partnership 101 and 102, and event 6 with partnership 103 and 102 at
+// competition 3. Athlete 1 leads in both partnership 101 and 103.
+func expectScheduleEvents(
+	eventRepo *mock_businesslogic.MockIEventRepository,
+	eventDanceRepo *mock_businesslogic.MockIEventDanceRepository,
+	eventEntryRepo *mock_businesslogic.MockIPartnershipEventEntryRepository,
+	partnershipRepo *mock_businesslogic.MockIPartnershipRepository) {
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{CompetitionID: 3}).Return([]businesslogic.Event{{ID: 6}, {ID: 5}, {ID: 7}}, nil)
+
+	entries := map[int][]int{5: {101, 102}, 6: {103, 102}, 7: {}}
+	for eventID, partnerships := range entries {
+		eventEntries := make([]businesslogic.PartnershipEventEntry, 0)
+		for _, each := range partnerships {
+			eventEntries = append(eventEntries, businesslogic.PartnershipEventEntry{Couple: businesslogic.Partnership{ID: each}})
+		}
+		eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: eventID}).Return(eventEntries, nil)
+	}
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 5}).Return([]businesslogic.EventDance{{ID: 1}, {ID: 2}}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventID: 6}).Return([]businesslogic.EventDance{{ID: 3}}, nil)
+
+	leads := map[int]int{101: 1, 102: 2, 103: 1}
+	for partnershipID, leadID := range leads {
+		partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: partnershipID}).Return([]businesslogic.Partnership{{
+			ID:     partnershipID,
+			Lead:   businesslogic.Account{ID: leadID},
+			Follow: businesslogic.Account{ID: partnershipID},
+		}}, nil).Times(1)
+	}
+}
+
+func TestCompetitionScheduleService_BuildSchedule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	scheduleRepo := mock_businesslogic.NewMockIEventRoundScheduleRepository(mockCtrl)
+	service := businesslogic.NewCompetitionScheduleService(eventRepo, eventDanceRepo, eventEntryRepo,
+		partnershipRepo, scheduleRepo)
+
+	expectScheduleEvents(eventRepo, eventDanceRepo, eventEntryRepo, partnershipRepo)
+	scheduleRepo.EXPECT().ReplaceEventRoundSchedule(3, gomock.Any()).Do(func(competitionID int, schedules []businesslogic.EventRoundSchedule) {
+		assert.Len(t, schedules, 2, "the timetable should be replaced as a whole")
+	}).Return(nil)
+
+	schedule, err := service.BuildSchedule(newOrganizer(41), 3, newScheduleSettings(1))
+	assert.Nil(t, err)
+	assert.Len(t, schedule.Rounds, 2, "events without entries should not be scheduled")
+	assert.Equal(t, 5, schedule.Rounds[0].EventID, "events should be scheduled in the order of their IDs")
+	assert.Equal(t, 1, schedule.Rounds[0].RoundOrder)
+	assert.False(t, schedule.Rounds[0].PreliminaryRoundIndicator, "two couples should dance a final only")
+	assert.Equal(t, schedule.Rounds[0].EstimatedEndTime, schedule.Rounds[1].EstimatedStartTime)
+	assert.Empty(t, schedule.Conflicts, "rounds on a single floor should not overlap")
+}
+
+func TestCompetitionScheduleService_BuildSchedule_Conflicts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	scheduleRepo := mock_businesslogic.NewMockIEventRoundScheduleRepository(mockCtrl)
+	service := businesslogic.NewCompetitionScheduleService(eventRepo, eventDanceRepo, eventEntryRepo,
+		partnershipRepo, scheduleRepo)
+
+	expectScheduleEvents(eventRepo, eventDanceRepo, eventEntryRepo, partnershipRepo)
+	scheduleRepo.EXPECT().ReplaceEventRoundSchedule(3, gomock.Any()).Return(nil)
+
+	schedule, err := service.BuildSchedule(newOrganizer(41), 3, newScheduleSettings(2))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, schedule.Rounds[1].Floor)
+	assert.Len(t, schedule.Conflicts, 1)
+	assert.Equal(t, []int{102}, schedule.Conflicts[0].Couples)
+	assert.Equal(t, []int{1, 2, 102}, schedule.Conflicts[0].Athletes)
+}
+
+func TestCompetitionScheduleService_AnalyzeAthleteConflicts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	scheduleRepo := mock_businesslogic.NewMockIEventRoundScheduleRepository(mockCtrl)
+	service := businesslogic.NewCompetitionScheduleService(eventRepo, eventDanceRepo, eventEntryRepo,
+		partnershipRepo, scheduleRepo)
+
+	start := time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)
+	scheduleRepo.EXPECT().SearchEventRoundSchedule(businesslogic.SearchEventRoundScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.EventRoundSchedule{
+		{ID: 12, EventID: 6, RoundOrder: 1, EstimatedStartTime: start.Add(15 * time.Minute), EstimatedEndTime: start.Add(20 * time.Minute)},
+		{ID: 11, EventID: 5, RoundOrder: 1, EstimatedStartTime: start, EstimatedEndTime: start.Add(10 * time.Minute)},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 5}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 101}},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 6}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 103}},
+	}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 101}).Return([]businesslogic.Partnership{{ID: 101, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 2}}}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 103}).Return([]businesslogic.Partnership{{ID: 103, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 3}}}, nil)
+
+	conflicts, err := service.AnalyzeAthleteConflicts(3, businesslogic.DefaultMinimumRest)
+	assert.Nil(t, err)
+	assert.Len(t, conflicts, 1, "athlete 1 dances with two partners and rests only 5 minutes")
+	assert.Equal(t, 1, conflicts[0].AthleteID)
+	assert.Equal(t, 11, conflicts[0].First.ID)
+	assert.Equal(t, 12, conflicts[0].Second.ID)
+	assert.False(t, conflicts[0].Clash)
+	assert.Equal(t, 5*time.Minute, conflicts[0].Rest)
+}
+
+func TestCompetitionScheduleService_PublishSchedule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	scheduleRepo := mock_businesslogic.NewMockIEventRoundScheduleRepository(mockCtrl)
+	service := businesslogic.NewCompetitionScheduleService(eventRepo, eventDanceRepo, eventEntryRepo,
+		partnershipRepo, scheduleRepo)
+
+	start := time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)
+	schedule := []businesslogic.EventRoundSchedule{
+		{ID: 11, EventID: 5, RoundOrder: 1, EstimatedStartTime: start, EstimatedEndTime: start.Add(10 * time.Minute)},
+		{ID: 12, EventID: 6, RoundOrder: 1, EstimatedStartTime: start.Add(10 * time.Minute), EstimatedEndTime: start.Add(20 * time.Minute)},
+	}
+	scheduleRepo.EXPECT().SearchEventRoundSchedule(businesslogic.SearchEventRoundScheduleCriteria{CompetitionID: 3}).Return(schedule, nil).Times(2)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 5}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 101}},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 6}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 103}},
+	}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 101}).Return([]businesslogic.Partnership{{ID: 101, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 2}}}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 103}).Return([]businesslogic.Partnership{{ID: 103, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 3}}}, nil)
+	scheduleRepo.EXPECT().PublishEventRoundSchedule(3, 41).Return(nil)
+
+	err := service.PublishSchedule(newOrganizer(41), 3)
+	assert.Nil(t, err, "rounds back to back should not block publishing")
+}
+
+func TestCompetitionScheduleService_PublishSchedule_Clash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	eventEntryRepo := mock_businesslogic.NewMockIPartnershipEventEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	scheduleRepo := mock_businesslogic.NewMockIEventRoundScheduleRepository(mockCtrl)
+	service := businesslogic.NewCompetitionScheduleService(eventRepo, eventDanceRepo, eventEntryRepo,
+		partnershipRepo, scheduleRepo)
+
+	start := time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)
+	scheduleRepo.EXPECT().SearchEventRoundSchedule(businesslogic.SearchEventRoundScheduleCriteria{CompetitionID: 3}).Return([]businesslogic.EventRoundSchedule{
+		{ID: 11, EventID: 5, RoundOrder: 1, EstimatedStartTime: start, EstimatedEndTime: start.Add(10 * time.Minute)},
+		{ID: 12, EventID: 6, RoundOrder: 1, Floor: 2, EstimatedStartTime: start.Add(5 * time.Minute), EstimatedEndTime: start.Add(15 * time.Minute)},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 5}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 101}},
+	}, nil)
+	eventEntryRepo.EXPECT().SearchPartnershipEventEntry(businesslogic.SearchPartnershipEventEntryCriteria{EventID: 6}).Return([]businesslogic.PartnershipEventEntry{
+		{Couple: businesslogic.Partnership{ID: 103}},
+	}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 101}).Return([]businesslogic.Partnership{{ID: 101, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 2}}}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{PartnershipID: 103}).Return([]businesslogic.Partnership{{ID: 103, Lead: businesslogic.Account{ID: 1}, Follow: businesslogic.Account{ID: 3}}}, nil)
+
+	err := service.PublishSchedule(newOrganizer(41), 3)
+	assert.NotNil(t, err, "the schedule should not be published while athlete 1 dances in two rounds at the same time")
+}
@@ -0,0 +1,235 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/core/skating"
+	"log"
+	"sort"
+	"time"
+)
+
+const (
+	// SCORESHEET_STATUS_OPEN allows scrutineers and adjudicators to enter and correct marks of the round
+	SCORESHEET_STATUS_OPEN = 1
+	// SCORESHEET_STATUS_LOCKED prevents marks from being changed, and results can be calculated from the marks
+	SCORESHEET_STATUS_LOCKED = 2
+)
+
+// Scoresheet keeps track of the marking of a round. A round must be opened by a scrutineer before any mark can be
+// entered, and must be locked before results are calculated.
+type Scoresheet struct {
+	ID                        int
+	RoundID                   int
+	PreliminaryRoundIndicator bool
+	RecallSize                int
+	Status                    int
+	ScrutineerID              int
+	DateTimeLocked            *time.Time
+	CreateUserID              int
+	DateTimeCreated           time.Time
+	UpdateUserID              int
+	DateTimeUpdated           time.Time
+}
+
+// IsOpen checks if marks of the round can still be changed
+func (scoresheet Scoresheet) IsOpen() bool {
+	return scoresheet.Status == SCORESHEET_STATUS_OPEN
+}
+
+// SearchScoresheetCriteria specifies the parameters that can be used to search Scoresheet in a repository
+type SearchScoresheetCriteria struct {
+	ID      int
+	RoundID int
+}
+
+// IScoresheetRepository specifies the functions that a Scoresheet Repository should implement
+type IScoresheetRepository interface {
+	CreateScoresheet(scoresheet *Scoresheet) error
+	DeleteScoresheet(scoresheet Scoresheet) error
+	SearchScoresheet(criteria SearchScoresheetCriteria) ([]Scoresheet, error)
+	UpdateScoresheet(scoresheet Scoresheet) error
+}
+
+// RoundResult is the result of a couple in a round that is calculated from the placements of adjudicators. The result
+// of each dance has an EventDanceID, and the overall result of the round has an EventDanceID of 0.
+type RoundResult struct {
+	ID                        int
+	RoundID                   int
+	EventDanceID              int
+	PartnershipRoundEntryID   int
+	PreliminaryRoundIndicator bool
+	RecallMarks               int
+	Recalled                  bool
+	Placement                 int
+	PlacementValue            float64
+	Rule                      int
+	CreateUserID              int
+	DateTimeCreated           time.Time
+	UpdateUserID              int
+	DateTimeUpdated           time.Time
+}
+
+// SearchRoundResultCriteria specifies the parameters that can be used to search RoundResult in a repository
+type SearchRoundResultCriteria struct {
+	RoundID                 int
+	PartnershipRoundEntryID int
+}
+
+// IRoundResultRepository specifies the functions that a RoundResult Repository should implement
+type IRoundResultRepository interface {
+	CreateRoundResult(result *RoundResult) error
+	DeleteRoundResult(result RoundResult) error
+	ReplaceRoundResults(roundID int, results []RoundResult) error
+	SearchRoundResult(criteria SearchRoundResultCriteria) ([]RoundResult, error)
+	UpdateRoundResult(result RoundResult) error
+}
+
+// ScoresheetService calculates the results of rounds from the placements submitted by adjudicators, and stores the
+// results in the repository
+type ScoresheetService struct {
+	placementRepo IPlacementRepository
+	resultRepo    IRoundResultRepository
+}
+
+func NewScoresheetService(placementRepo IPlacementRepository, resultRepo IRoundResultRepository) ScoresheetService {
+	return ScoresheetService{
+		placementRepo: placementRepo,
+		resultRepo:    resultRepo,
+	}
+}
+
+// GetRoundScoreSheet loads the placements of the round and organizes them into the score sheet of each dance. Dances
+// are ordered by EventDanceID, and couples are identified by their PartnershipRoundEntryID.
+func (service ScoresheetService) GetRoundScoreSheet(roundID int) (skating.RoundScoreSheet, []int, error) {
+	placements, err := service.placementRepo.SearchPlacement(SearchPlacementCriteria{RoundID: roundID})
+	if err != nil {
+		return skating.RoundScoreSheet{}, nil, err
+	}
+	if len(placements) == 0 {
+		return skating.RoundScoreSheet{}, nil, errors.New(fmt.Sprintf("no placement is submitted for round %v", roundID))
+	}
+
+	couples := make(map[int]bool)
+	dances := make(map[int]map[int][]Placement) // key: EventDanceID, AdjudicatorRoundEntryID
+	for _, each := range placements {
+		couples[each.PartnershipRoundEntryID] = true
+		if dances[each.EventDanceID] == nil {
+			dances[each.EventDanceID] = make(map[int][]Placement)
+		}
+		dances[each.EventDanceID][each.AdjudicatorRoundEntryID] = append(dances[each.EventDanceID][each.AdjudicatorRoundEntryID], each)
+	}
+
+	danceIDs := make([]int, 0)
+	for id := range dances {
+		danceIDs = append(danceIDs, id)
+	}
+	sort.Ints(danceIDs)
+
+	roundSheet := skating.NewRoundScoreSheet()
+	for _, danceID := range danceIDs {
+		adjudicators := make([]int, 0)
+		for id := range dances[danceID] {
+			adjudicators = append(adjudicators, id)
+		}
+		sort.Ints(adjudicators)
+
+		danceSheet := skating.NewDanceScoreSheet()
+		for _, adjudicator := range adjudicators {
+			marks := skating.NewJudgeMarks(len(couples))
+			marks.SetAdjudicator(adjudicator)
+			for _, each := range dances[danceID][adjudicator] {
+				if each.PreliminaryRoundIndicator {
+					marks.AddCallback(each.PartnershipRoundEntryID, each.Callback)
+				} else if placeErr := marks.AddPlacement(each.PartnershipRoundEntryID, each.Placement); placeErr != nil {
+					return skating.RoundScoreSheet{}, danceIDs, placeErr
+				}
+			}
+			danceSheet.AddJudgeMarks(marks)
+		}
+		roundSheet.AddDanceSheet(danceSheet)
+	}
+	return roundSheet, danceIDs, nil
+}
+
+// CalculateFinalRoundResult calculates the results of a final round with the Skating System, and replaces the stored
+// results of the round with the newly calculated results.
+func (service ScoresheetService) CalculateFinalRoundResult(roundID, currentUserID int) (skating.RoundTabulation, error) {
+	roundSheet, danceIDs, err := service.GetRoundScoreSheet(roundID)
+	if err != nil {
+		return skating.RoundTabulation{}, err
+	}
+	tabulation, err := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+	if err != nil {
+		return tabulation, err
+	}
+
+	results := make([]RoundResult, 0)
+	for i, dance := range tabulation.Dances {
+		for _, each := range dance.Couples {
+			result := newRoundResult(roundID, each.CoupleID, currentUserID)
+			result.EventDanceID = danceIDs[i]
+			result.Placement = each.Placement
+			result.PlacementValue = each.PlacementValue
+			result.Rule = each.Rule
+			results = append(results, result)
+		}
+	}
+	for _, each := range tabulation.Couples {
+		result := newRoundResult(roundID, each.CoupleID, currentUserID)
+		result.Placement = each.Placement
+		result.PlacementValue = each.PlacementValue
+		result.Rule = each.Rule
+		results = append(results, result)
+	}
+	return tabulation, service.replaceRoundResults(roundID, results)
+}
+
+// CalculatePreliminaryRoundResult totals the callbacks of a preliminary round and recalls the couples for the
+// requested recall size. If couples are tied at the cutoff, tied couples are recalled only when recallTied is true.
+// Stored results of the round are replaced with the newly calculated results.
+func (service ScoresheetService) CalculatePreliminaryRoundResult(roundID, recallSize int, recallTied bool, currentUserID int) (skating.RecallTabulation, error) {
+	roundSheet, _, err := service.GetRoundScoreSheet(roundID)
+	if err != nil {
+		return skating.RecallTabulation{}, err
+	}
+	tabulation, err := roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, recallSize)
+	if err != nil {
+		return tabulation, err
+	}
+
+	recalled := make(map[int]bool)
+	for _, each := range tabulation.GetRecalledCouples(recallTied) {
+		recalled[each] = true
+	}
+	results := make([]RoundResult, 0)
+	for _, each := range tabulation.Couples {
+		result := newRoundResult(roundID, each.CoupleID, currentUserID)
+		result.PreliminaryRoundIndicator = true
+		result.RecallMarks = each.Marks
+		result.Recalled = recalled[each.CoupleID]
+		result.Placement = each.Rank
+		result.PlacementValue = float64(each.Rank)
+		results = append(results, result)
+	}
+	return tabulation, service.replaceRoundResults(roundID, results)
+}
+
+func newRoundResult(roundID, partnershipRoundEntryID, currentUserID int) RoundResult {
+	return RoundResult{
+		RoundID:                 roundID,
+		PartnershipRoundEntryID: partnershipRoundEntryID,
+		CreateUserID:            currentUserID,
+		DateTimeCreated:         time.Now(),
+		UpdateUserID:            currentUserID,
+		DateTimeUpdated:         time.Now(),
+	}
+}
+
+func (service ScoresheetService) replaceRoundResults(roundID int, results []RoundResult) error {
+	if err := service.resultRepo.ReplaceRoundResults(roundID, results); err != nil {
+		log.Printf("[error] replacing results of round %v: %v", roundID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package businesslogic_test
+
+import (
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/core/skating"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// finalRoundPlacements creates the placements of a final round with one dance and two adjudicators
+func finalRoundPlacements() []businesslogic.Placement {
+	return []businesslogic.Placement{
+		{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21, PartnershipRoundEntryID: 101, Placement: 1},
+		{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21, PartnershipRoundEntryID: 102, Placement: 2},
+		{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 22, PartnershipRoundEntryID: 101, Placement: 1},
+		{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 22, PartnershipRoundEntryID: 102, Placement: 2},
+	}
+}
+
+func TestScoresheetService_CalculateFinalRoundResult(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewScoresheetService(placementRepo, resultRepo)
+
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7}).Return(finalRoundPlacements(), nil)
+	created := make([]businesslogic.RoundResult, 0)
+	resultRepo.EXPECT().ReplaceRoundResults(7, gomock.Any()).Do(func(roundID int, results []businesslogic.RoundResult) {
+		created = results
+	}).Return(nil)
+
+	tabulation, err := service.CalculateFinalRoundResult(7, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {2, 102}}, tabulation.GetPlacements())
+	assert.Equal(t, 3, created[0].EventDanceID, "results of each dance should be stored with the dance")
+	assert.Equal(t, 0, created[2].EventDanceID, "overall result of the round should not have a dance")
+	assert.Equal(t, skating.RULE_9, created[2].Rule)
+	assert.Len(t, created, 4)
+}
+
+func TestScoresheetService_CalculateFinalRoundResult_InvalidPlacements(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewScoresheetService(placementRepo, resultRepo)
+
+	placements := finalRoundPlacements()
+	placements[1].Placement = 1
+	placementRepo.EXPECT().SearchPlacement(gomock.Any()).Return(placements, nil)
+	_, err := service.CalculateFinalRoundResult(7, 1)
+	assert.NotNil(t, err, "should not calculate results when an adjudicator gives the same placement twice")
+
+	placementRepo.EXPECT().SearchPlacement(gomock.Any()).Return(nil, errors.New("database error"))
+	_, err = service.CalculateFinalRoundResult(7, 1)
+	assert.NotNil(t, err)
+}
+
+func TestScoresheetService_CalculatePreliminaryRoundResult(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewScoresheetService(placementRepo, resultRepo)
+
+	placementRepo.EXPECT().SearchPlacement(gomock.Any()).Return([]businesslogic.Placement{
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 21, PartnershipRoundEntryID: 101, PreliminaryRoundIndicator: true, Callback: true},
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 21, PartnershipRoundEntryID: 102, PreliminaryRoundIndicator: true, Callback: false},
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 21, PartnershipRoundEntryID: 103, PreliminaryRoundIndicator: true, Callback: true},
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 22, PartnershipRoundEntryID: 101, PreliminaryRoundIndicator: true, Callback: true},
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 22, PartnershipRoundEntryID: 102, PreliminaryRoundIndicator: true, Callback: true},
+		{RoundID: 5, EventDanceID: 3, AdjudicatorRoundEntryID: 22, PartnershipRoundEntryID: 103, PreliminaryRoundIndicator: true, Callback: false},
+	}, nil)
+	recalled := make(map[int]bool)
+	resultRepo.EXPECT().ReplaceRoundResults(5, gomock.Any()).Do(func(roundID int, results []businesslogic.RoundResult) {
+		for _, each := range results {
+			recalled[each.PartnershipRoundEntryID] = each.Recalled
+		}
+	}).Return(nil)
+
+	tabulation, err := service.CalculatePreliminaryRoundResult(5, 2, false, 1)
+	assert.Nil(t, err)
+	assert.True(t, tabulation.HasCutoffTie(), "couple 102 and 103 are tied for the 2nd spot")
+	assert.Equal(t, map[int]bool{101: true, 102: false, 103: false}, recalled, "tied couples should not be recalled unless requested")
+}
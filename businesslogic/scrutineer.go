@@ -0,0 +1,357 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/core/skating"
+	"log"
+	"time"
+)
+
+// ScoresheetLockedError is returned when marks of a locked round are changed
+var ScoresheetLockedError = errors.New("scoresheet of this round is locked")
+
+// JudgeMarksSubmission is the marks of an adjudicator in a dance of a round. Only PartnershipRoundEntryID, Callback and
+// Placement of each Placement are used: callbacks for preliminary rounds and placements for final rounds.
+type JudgeMarksSubmission struct {
+	RoundID                 int
+	EventDanceID            int
+	AdjudicatorRoundEntryID int
+	Placements              []Placement
+}
+
+// scoresheetMarker validates and stores marks of adjudicators. It is shared by scrutineers, who enter marks on behalf
+// of adjudicators, and by adjudicators who mark electronically. Scrutineers enter the marks as they are written on the
+// paper scoresheet, therefore only electronic marking requires the exact number of recalls.
+type scoresheetMarker struct {
+	eventDanceRepo       IEventDanceRepository
+	adjudicatorEntryRepo IAdjudicatorRoundEntryRepository
+	partnershipEntryRepo IPartnershipRoundEntryRepository
+	placementRepo        IPlacementRepository
+	validateRecall       bool
+}
+
+// save replaces the marks that the adjudicator previously gave in the dance with the submitted marks
+func (marker scoresheetMarker) save(round Round, scoresheet Scoresheet, submission JudgeMarksSubmission, currentUserID int) error {
+	if !scoresheet.IsOpen() {
+		return ScoresheetLockedError
+	}
+
+	dances, err := marker.eventDanceRepo.SearchEventDance(SearchEventDanceCriteria{
+		EventDanceID: submission.EventDanceID,
+		EventID:      round.EventID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(dances) != 1 {
+		return errors.New(fmt.Sprintf("dance %v is not danced in round %v", submission.EventDanceID, round.ID))
+	}
+
+	adjudicators, err := marker.adjudicatorEntryRepo.SearchAdjudicatorRoundEntry(SearchAdjudicatorRoundEntryCriteria{
+		ID:      submission.AdjudicatorRoundEntryID,
+		RoundID: round.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(adjudicators) != 1 {
+		return errors.New(fmt.Sprintf("adjudicator entry %v does not adjudicate round %v", submission.AdjudicatorRoundEntryID, round.ID))
+	}
+
+	couples, err := marker.partnershipEntryRepo.SearchPartnershipRoundEntry(SearchPartnershipRoundEntryCriteria{RoundID: round.ID})
+	if err != nil {
+		return err
+	}
+	if err := validateJudgeMarks(scoresheet, couples, submission.Placements, marker.validateRecall); err != nil {
+		return err
+	}
+
+	existing, err := marker.placementRepo.SearchPlacement(SearchPlacementCriteria{
+		RoundID:                 round.ID,
+		EventDanceID:            submission.EventDanceID,
+		AdjudicatorRoundEntryID: submission.AdjudicatorRoundEntryID,
+	})
+	if err != nil {
+		return err
+	}
+	previous := make(map[int]Placement)
+	for _, each := range existing {
+		previous[each.PartnershipRoundEntryID] = each
+	}
+
+	for _, each := range submission.Placements {
+		placement, found := previous[each.PartnershipRoundEntryID]
+		if !found {
+			placement = Placement{
+				RoundID:                   round.ID,
+				EventDanceID:              submission.EventDanceID,
+				AdjudicatorRoundEntryID:   submission.AdjudicatorRoundEntryID,
+				PartnershipRoundEntryID:   each.PartnershipRoundEntryID,
+				PreliminaryRoundIndicator: scoresheet.PreliminaryRoundIndicator,
+				CreateUserID:              currentUserID,
+				DateTimeCreated:           time.Now(),
+			}
+		}
+		delete(previous, each.PartnershipRoundEntryID)
+		placement.Callback = scoresheet.PreliminaryRoundIndicator && each.Callback
+		if !scoresheet.PreliminaryRoundIndicator {
+			placement.Placement = each.Placement
+		}
+		placement.UpdateUserID = currentUserID
+		placement.DateTimeUpdated = time.Now()
+
+		if found {
+			err = marker.placementRepo.UpdatePlacement(placement)
+		} else {
+			err = marker.placementRepo.CreatePlacement(&placement)
+		}
+		if err != nil {
+			log.Printf("[error] saving marks of adjudicator entry %v in round %v: %v", submission.AdjudicatorRoundEntryID, round.ID, err)
+			return err
+		}
+	}
+
+	// marks of couples that are no longer in the submission are removed
+	for _, each := range previous {
+		if err := marker.placementRepo.DeletePlacement(each); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateJudgeMarks checks that all marks are given to couples of the round. In a final round, every couple must
+// receive a different placement from the adjudicator. In a preliminary round, the adjudicator must recall the
+// requested number of couples if validateRecall is true.
+func validateJudgeMarks(scoresheet Scoresheet, couples []PartnershipRoundEntry, placements []Placement, validateRecall bool) error {
+	inRound := make(map[int]bool)
+	for _, each := range couples {
+		inRound[each.ID] = true
+	}
+	marks := skating.NewJudgeMarks(len(couples))
+	for _, each := range placements {
+		if !inRound[each.PartnershipRoundEntryID] {
+			return errors.New(fmt.Sprintf("couple %v is not in this round", each.PartnershipRoundEntryID))
+		}
+		if scoresheet.PreliminaryRoundIndicator {
+			marks.AddCallback(each.PartnershipRoundEntryID, each.Callback)
+		} else if err := marks.AddPlacement(each.PartnershipRoundEntryID, each.Placement); err != nil {
+			return err
+		}
+	}
+	if !scoresheet.PreliminaryRoundIndicator {
+		return marks.ValidatePlacements()
+	}
+	if validateRecall {
+		return marks.ValidateRecall(scoresheet.RecallSize)
+	}
+	return nil
+}
+
+// ScrutineerService provides the functions that scrutineers use to manage the scoresheets of rounds. Rounds must belong
+// to the competition that the scrutineer is authorized to score.
+type ScrutineerService struct {
+	eventRepo         IEventRepository
+	roundRepo         IRoundRepository
+	scoresheetRepo    IScoresheetRepository
+	placementRepo     IPlacementRepository
+	marker            scoresheetMarker
+	scoresheetService ScoresheetService
+}
+
+func NewScrutineerService(
+	eventRepo IEventRepository,
+	roundRepo IRoundRepository,
+	eventDanceRepo IEventDanceRepository,
+	adjudicatorEntryRepo IAdjudicatorRoundEntryRepository,
+	partnershipEntryRepo IPartnershipRoundEntryRepository,
+	scoresheetRepo IScoresheetRepository,
+	placementRepo IPlacementRepository,
+	resultRepo IRoundResultRepository) ScrutineerService {
+	return ScrutineerService{
+		eventRepo:      eventRepo,
+		roundRepo:      roundRepo,
+		scoresheetRepo: scoresheetRepo,
+		placementRepo:  placementRepo,
+		marker: scoresheetMarker{
+			eventDanceRepo:       eventDanceRepo,
+			adjudicatorEntryRepo: adjudicatorEntryRepo,
+			partnershipEntryRepo: partnershipEntryRepo,
+			placementRepo:        placementRepo,
+		},
+		scoresheetService: NewScoresheetService(placementRepo, resultRepo),
+	}
+}
+
+// getRoundCompetition returns the round and the ID of the competition that the round belongs to
+func getRoundCompetition(roundID int, roundRepo IRoundRepository, eventRepo IEventRepository) (Round, int, error) {
+	rounds, err := roundRepo.SearchRound(SearchRoundCriteria{ID: roundID})
+	if err != nil {
+		return Round{}, 0, err
+	}
+	if len(rounds) != 1 {
+		return Round{}, 0, errors.New(fmt.Sprintf("round %v does not exist", roundID))
+	}
+	events, err := eventRepo.SearchEvent(SearchEventCriteria{EventID: rounds[0].EventID})
+	if err != nil {
+		return rounds[0], 0, err
+	}
+	if len(events) != 1 {
+		return rounds[0], 0, errors.New(fmt.Sprintf("event of round %v does not exist", roundID))
+	}
+	return rounds[0], events[0].CompetitionID, nil
+}
+
+// getCompetitionRound returns the round if it belongs to the competition
+func getCompetitionRound(competitionID, roundID int, roundRepo IRoundRepository, eventRepo IEventRepository) (Round, error) {
+	round, roundCompetitionID, err := getRoundCompetition(roundID, roundRepo, eventRepo)
+	if err != nil {
+		return round, err
+	}
+	if roundCompetitionID != competitionID {
+		return round, errors.New(fmt.Sprintf("round %v does not belong to competition %v", roundID, competitionID))
+	}
+	return round, nil
+}
+
+// getScoresheet returns the scoresheet of the round, or an error if the round is not opened
+func getScoresheet(roundID int, repo IScoresheetRepository) (Scoresheet, error) {
+	scoresheets, err := repo.SearchScoresheet(SearchScoresheetCriteria{RoundID: roundID})
+	if err != nil {
+		return Scoresheet{}, err
+	}
+	if len(scoresheets) != 1 {
+		return Scoresheet{}, errors.New(fmt.Sprintf("round %v is not opened for marking", roundID))
+	}
+	return scoresheets[0], nil
+}
+
+// OpenRound opens the round for marking. Opening a round that is already open updates its recall size.
+func (service ScrutineerService) OpenRound(currentUser Account, competitionID, roundID int, preliminary bool, recallSize int) (Scoresheet, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return Scoresheet{}, err
+	}
+	if preliminary && recallSize < 1 {
+		return Scoresheet{}, errors.New("recall size of a preliminary round must be specified")
+	}
+
+	scoresheets, err := service.scoresheetRepo.SearchScoresheet(SearchScoresheetCriteria{RoundID: roundID})
+	if err != nil {
+		return Scoresheet{}, err
+	}
+	if len(scoresheets) == 1 {
+		scoresheet := scoresheets[0]
+		if !scoresheet.IsOpen() {
+			return scoresheet, ScoresheetLockedError
+		}
+		if scoresheet.PreliminaryRoundIndicator != preliminary {
+			return scoresheet, errors.New("type of an opened round cannot be changed")
+		}
+		scoresheet.RecallSize = recallSize
+		scoresheet.UpdateUserID = currentUser.ID
+		scoresheet.DateTimeUpdated = time.Now()
+		return scoresheet, service.scoresheetRepo.UpdateScoresheet(scoresheet)
+	}
+
+	scoresheet := Scoresheet{
+		RoundID:                   roundID,
+		PreliminaryRoundIndicator: preliminary,
+		RecallSize:                recallSize,
+		Status:                    SCORESHEET_STATUS_OPEN,
+		ScrutineerID:              currentUser.ID,
+		CreateUserID:              currentUser.ID,
+		DateTimeCreated:           time.Now(),
+		UpdateUserID:              currentUser.ID,
+		DateTimeUpdated:           time.Now(),
+	}
+	if !preliminary {
+		scoresheet.RecallSize = 0
+	}
+	return scoresheet, service.scoresheetRepo.CreateScoresheet(&scoresheet)
+}
+
+// EnterMarks enters or corrects the marks of an adjudicator in a dance of an open round
+func (service ScrutineerService) EnterMarks(currentUser Account, competitionID int, submission JudgeMarksSubmission) error {
+	round, err := getCompetitionRound(competitionID, submission.RoundID, service.roundRepo, service.eventRepo)
+	if err != nil {
+		return err
+	}
+	scoresheet, err := getScoresheet(submission.RoundID, service.scoresheetRepo)
+	if err != nil {
+		return err
+	}
+	return service.marker.save(round, scoresheet, submission, currentUser.ID)
+}
+
+// GetScoresheet returns the scoresheet of the round
+func (service ScrutineerService) GetScoresheet(competitionID, roundID int) (Scoresheet, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return Scoresheet{}, err
+	}
+	return getScoresheet(roundID, service.scoresheetRepo)
+}
+
+// GetMarks returns all the marks that are entered in the round
+func (service ScrutineerService) GetMarks(competitionID, roundID int) (Scoresheet, []Placement, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return Scoresheet{}, nil, err
+	}
+	scoresheet, err := getScoresheet(roundID, service.scoresheetRepo)
+	if err != nil {
+		return scoresheet, nil, err
+	}
+	placements, err := service.placementRepo.SearchPlacement(SearchPlacementCriteria{RoundID: roundID})
+	return scoresheet, placements, err
+}
+
+// LockRound prevents marks of the round from being changed
+func (service ScrutineerService) LockRound(currentUser Account, competitionID, roundID int) (Scoresheet, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return Scoresheet{}, err
+	}
+	scoresheet, err := getScoresheet(roundID, service.scoresheetRepo)
+	if err != nil {
+		return scoresheet, err
+	}
+	if !scoresheet.IsOpen() {
+		return scoresheet, ScoresheetLockedError
+	}
+	locked := time.Now()
+	scoresheet.Status = SCORESHEET_STATUS_LOCKED
+	scoresheet.DateTimeLocked = &locked
+	scoresheet.UpdateUserID = currentUser.ID
+	scoresheet.DateTimeUpdated = locked
+	return scoresheet, service.scoresheetRepo.UpdateScoresheet(scoresheet)
+}
+
+// ComputeFinalRoundResult calculates and stores the result of a locked final round
+func (service ScrutineerService) ComputeFinalRoundResult(currentUser Account, competitionID, roundID int) (skating.RoundTabulation, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return skating.RoundTabulation{}, err
+	}
+	scoresheet, err := getScoresheet(roundID, service.scoresheetRepo)
+	if err != nil {
+		return skating.RoundTabulation{}, err
+	}
+	if scoresheet.IsOpen() || scoresheet.PreliminaryRoundIndicator {
+		return skating.RoundTabulation{}, errors.New("result can only be computed for a locked final round")
+	}
+	return service.scoresheetService.CalculateFinalRoundResult(roundID, currentUser.ID)
+}
+
+// ComputePreliminaryRoundResult calculates and stores the recalls of a locked preliminary round. If couples are tied
+// at the cutoff, the chairman decides whether the tied couples are recalled.
+func (service ScrutineerService) ComputePreliminaryRoundResult(currentUser Account, competitionID, roundID int, recallTied bool) (skating.RecallTabulation, error) {
+	if _, err := getCompetitionRound(competitionID, roundID, service.roundRepo, service.eventRepo); err != nil {
+		return skating.RecallTabulation{}, err
+	}
+	scoresheet, err := getScoresheet(roundID, service.scoresheetRepo)
+	if err != nil {
+		return skating.RecallTabulation{}, err
+	}
+	if scoresheet.IsOpen() || !scoresheet.PreliminaryRoundIndicator {
+		return skating.RecallTabulation{}, errors.New("recalls can only be computed for a locked preliminary round")
+	}
+	return service.scoresheetService.CalculatePreliminaryRoundResult(roundID, scoresheet.RecallSize, recallTied, currentUser.ID)
+}
@@ -0,0 +1,233 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newScrutineer(id int) businesslogic.Account {
+	account := businesslogic.Account{ID: id}
+	account.SetRoles([]businesslogic.AccountRole{{AccountID: id, AccountTypeID: businesslogic.AccountTypeScrutineer}})
+	return account
+}
+
+func TestScrutineerService_OpenRound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		scoresheetRepo,
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{}, nil)
+	scoresheetRepo.EXPECT().CreateScoresheet(gomock.Any()).Return(nil)
+
+	scoresheet, err := service.OpenRound(newScrutineer(11), 3, 7, true, 12)
+	assert.Nil(t, err)
+	assert.True(t, scoresheet.IsOpen())
+	assert.Equal(t, 12, scoresheet.RecallSize)
+	assert.Equal(t, 11, scoresheet.ScrutineerID)
+}
+
+func TestScrutineerService_OpenRound_OtherCompetition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIScoresheetRepository(mockCtrl),
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+
+	_, err := service.OpenRound(newScrutineer(11), 4, 7, false, 0)
+	assert.NotNil(t, err, "rounds of other competitions should not be opened")
+}
+
+func TestScrutineerService_EnterMarks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo, eventDanceRepo, adjudicatorEntryRepo,
+		partnershipEntryRepo, scoresheetRepo, placementRepo, mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventDanceID: 3, EventID: 5}).Return([]businesslogic.EventDance{{ID: 3, EventID: 5}}, nil)
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{ID: 21, RoundID: 7}).Return([]businesslogic.AdjudicatorRoundEntry{{ID: 21}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 7}).Return([]businesslogic.PartnershipRoundEntry{{ID: 101}, {ID: 102}}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7, EventDanceID: 3, AdjudicatorRoundEntryID: 21}).Return([]businesslogic.Placement{
+		{ID: 1, PartnershipRoundEntryID: 101, Placement: 2},
+		{ID: 2, PartnershipRoundEntryID: 103, Placement: 1},
+	}, nil)
+	placementRepo.EXPECT().UpdatePlacement(gomock.Any()).Do(func(placement businesslogic.Placement) {
+		assert.Equal(t, 1, placement.Placement, "previous mark should be corrected")
+	}).Return(nil)
+	placementRepo.EXPECT().CreatePlacement(gomock.Any()).Return(nil)
+	placementRepo.EXPECT().DeletePlacement(gomock.Any()).Do(func(placement businesslogic.Placement) {
+		assert.Equal(t, 2, placement.ID, "marks of couples not in the submission should be removed")
+	}).Return(nil)
+
+	err := service.EnterMarks(newScrutineer(11), 3, businesslogic.JudgeMarksSubmission{
+		RoundID:                 7,
+		EventDanceID:            3,
+		AdjudicatorRoundEntryID: 21,
+		Placements: []businesslogic.Placement{
+			{PartnershipRoundEntryID: 101, Placement: 1},
+			{PartnershipRoundEntryID: 102, Placement: 2},
+		},
+	})
+	assert.Nil(t, err)
+}
+
+func TestScrutineerService_EnterMarks_InvalidPlacements(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	eventDanceRepo := mock_businesslogic.NewMockIEventDanceRepository(mockCtrl)
+	adjudicatorEntryRepo := mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl)
+	partnershipEntryRepo := mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo, eventDanceRepo, adjudicatorEntryRepo,
+		partnershipEntryRepo, scoresheetRepo,
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	eventDanceRepo.EXPECT().SearchEventDance(businesslogic.SearchEventDanceCriteria{EventDanceID: 3, EventID: 5}).Return([]businesslogic.EventDance{{ID: 3, EventID: 5}}, nil)
+	adjudicatorEntryRepo.EXPECT().SearchAdjudicatorRoundEntry(businesslogic.SearchAdjudicatorRoundEntryCriteria{ID: 21, RoundID: 7}).Return([]businesslogic.AdjudicatorRoundEntry{{ID: 21}}, nil)
+	partnershipEntryRepo.EXPECT().SearchPartnershipRoundEntry(businesslogic.SearchPartnershipRoundEntryCriteria{RoundID: 7}).Return([]businesslogic.PartnershipRoundEntry{{ID: 101}, {ID: 102}}, nil)
+
+	err := service.EnterMarks(newScrutineer(11), 3, businesslogic.JudgeMarksSubmission{
+		RoundID:                 7,
+		EventDanceID:            3,
+		AdjudicatorRoundEntryID: 21,
+		Placements: []businesslogic.Placement{
+			{PartnershipRoundEntryID: 101, Placement: 1},
+			{PartnershipRoundEntryID: 102, Placement: 1},
+		},
+	})
+	assert.NotNil(t, err, "couples in a final round should not share a placement from the same adjudicator")
+}
+
+func TestScrutineerService_EnterMarks_LockedRound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		scoresheetRepo,
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_LOCKED},
+	}, nil)
+
+	err := service.EnterMarks(newScrutineer(11), 3, businesslogic.JudgeMarksSubmission{RoundID: 7})
+	assert.Equal(t, businesslogic.ScoresheetLockedError, err)
+}
+
+func TestScrutineerService_LockRound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		scoresheetRepo,
+		mock_businesslogic.NewMockIPlacementRepository(mockCtrl),
+		mock_businesslogic.NewMockIRoundResultRepository(mockCtrl))
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil)
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	scoresheetRepo.EXPECT().UpdateScoresheet(gomock.Any()).Return(nil)
+
+	scoresheet, err := service.LockRound(newScrutineer(11), 3, 7)
+	assert.Nil(t, err)
+	assert.False(t, scoresheet.IsOpen())
+	assert.NotNil(t, scoresheet.DateTimeLocked)
+}
+
+func TestScrutineerService_ComputeFinalRoundResult(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eventRepo := mock_businesslogic.NewMockIEventRepository(mockCtrl)
+	roundRepo := mock_businesslogic.NewMockIRoundRepository(mockCtrl)
+	scoresheetRepo := mock_businesslogic.NewMockIScoresheetRepository(mockCtrl)
+	placementRepo := mock_businesslogic.NewMockIPlacementRepository(mockCtrl)
+	resultRepo := mock_businesslogic.NewMockIRoundResultRepository(mockCtrl)
+	service := businesslogic.NewScrutineerService(eventRepo, roundRepo,
+		mock_businesslogic.NewMockIEventDanceRepository(mockCtrl),
+		mock_businesslogic.NewMockIAdjudicatorRoundEntryRepository(mockCtrl),
+		mock_businesslogic.NewMockIPartnershipRoundEntryRepository(mockCtrl),
+		scoresheetRepo, placementRepo, resultRepo)
+
+	roundRepo.EXPECT().SearchRound(businesslogic.SearchRoundCriteria{ID: 7}).Return([]businesslogic.Round{{ID: 7, EventID: 5}}, nil).Times(2)
+	eventRepo.EXPECT().SearchEvent(businesslogic.SearchEventCriteria{EventID: 5}).Return([]businesslogic.Event{{ID: 5, CompetitionID: 3}}, nil).Times(2)
+
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN},
+	}, nil)
+	_, err := service.ComputeFinalRoundResult(newScrutineer(11), 3, 7)
+	assert.NotNil(t, err, "results should not be computed before the round is locked")
+
+	scoresheetRepo.EXPECT().SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7}).Return([]businesslogic.Scoresheet{
+		{ID: 1, RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_LOCKED},
+	}, nil)
+	placementRepo.EXPECT().SearchPlacement(businesslogic.SearchPlacementCriteria{RoundID: 7}).Return(finalRoundPlacements(), nil)
+	resultRepo.EXPECT().ReplaceRoundResults(7, gomock.Any()).Return(nil)
+
+	tabulation, err := service.ComputeFinalRoundResult(newScrutineer(11), 3, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {2, 102}}, tabulation.GetPlacements())
+}
@@ -0,0 +1,270 @@
+package businesslogic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Roles that athletes can look for a partner as, as stored in DAS.COMPETITION_ENTRY_TBA
+const (
+	TBARoleLead   = "Lead"
+	TBARoleFollow = "Follow"
+)
+
+// CompetitionTBAEntry is a "looking for partner" posting of an athlete who wants to compete at a competition but
+// does not have a partner yet. Role is the role that the athlete dances, so the athlete looks for a partner of the
+// opposite role.
+type CompetitionTBAEntry struct {
+	ID              int
+	AccountID       int
+	CompetitionID   int
+	Role            string
+	Proficiency     string
+	Style           string
+	Height          string
+	School          string
+	ContactEmail    string
+	ContactPhone    string
+	MiscInfo        string
+	CreateUserID    int
+	DateTimeCreated time.Time
+	UpdateUserID    int
+	DateTimeUpdated time.Time
+}
+
+// PartnershipRole returns the partnership role that the athlete of the entry dances
+func (entry CompetitionTBAEntry) PartnershipRole() int {
+	if entry.Role == TBARoleLead {
+		return PartnershipRoleLead
+	}
+	return PartnershipRoleFollow
+}
+
+func (entry CompetitionTBAEntry) validate() error {
+	if entry.CompetitionID < 1 {
+		return errors.New("competition must be specified")
+	}
+	if entry.Role != TBARoleLead && entry.Role != TBARoleFollow {
+		return errors.New(fmt.Sprintf("role must be either %v or %v", TBARoleLead, TBARoleFollow))
+	}
+	if entry.Proficiency == "" {
+		return errors.New("proficiency must be specified")
+	}
+	if entry.Style == "" {
+		return errors.New("style must be specified")
+	}
+	return nil
+}
+
+// SearchCompetitionTBAEntryCriteria specifies the parameters that can be used to search CompetitionTBAEntry. School
+// matches the entries whose school contains it, regardless of case.
+type SearchCompetitionTBAEntryCriteria struct {
+	ID            int    `schema:"id"`
+	AccountID     int    `schema:"account"`
+	CompetitionID int    `schema:"competition"`
+	Role          string `schema:"role"`
+	Proficiency   string `schema:"proficiency"`
+	Style         string `schema:"style"`
+	School        string `schema:"school"`
+}
+
+// ICompetitionTBAEntryRepository specifies the functions that a CompetitionTBAEntry Repository should implement
+type ICompetitionTBAEntryRepository interface {
+	CreateCompetitionTBAEntry(entry *CompetitionTBAEntry) error
+	DeleteCompetitionTBAEntry(entry CompetitionTBAEntry) error
+	SearchCompetitionTBAEntry(criteria SearchCompetitionTBAEntryCriteria) ([]CompetitionTBAEntry, error)
+	UpdateCompetitionTBAEntry(entry CompetitionTBAEntry) error
+}
+
+// CompetitionTBAService allows athletes to look for partners at competitions. Athletes who have blacklisted each other,
+// in either direction, never see each other's entries and cannot send partnership requests through them.
+type CompetitionTBAService struct {
+	accountRepo     IAccountRepository
+	competitionRepo ICompetitionRepository
+	tbaRepo         ICompetitionTBAEntryRepository
+	partnershipRepo IPartnershipRepository
+	requestRepo     IPartnershipRequestRepository
+	blacklistRepo   IPartnershipRequestBlacklistRepository
+}
+
+func NewCompetitionTBAService(
+	accountRepo IAccountRepository,
+	competitionRepo ICompetitionRepository,
+	tbaRepo ICompetitionTBAEntryRepository,
+	partnershipRepo IPartnershipRepository,
+	requestRepo IPartnershipRequestRepository,
+	blacklistRepo IPartnershipRequestBlacklistRepository) CompetitionTBAService {
+	return CompetitionTBAService{
+		accountRepo:     accountRepo,
+		competitionRepo: competitionRepo,
+		tbaRepo:         tbaRepo,
+		partnershipRepo: partnershipRepo,
+		requestRepo:     requestRepo,
+		blacklistRepo:   blacklistRepo,
+	}
+}
+
+// blockedAccounts returns the IDs of the accounts that the account has blacklisted, and of the accounts that have
+// blacklisted the account
+func (service CompetitionTBAService) blockedAccounts(accountID int) (map[int]bool, error) {
+	blocked := make(map[int]bool)
+	reported, err := service.blacklistRepo.SearchPartnershipRequestBlacklist(SearchPartnershipRequestBlacklistCriteria{ReporterID: accountID})
+	if err != nil {
+		return blocked, err
+	}
+	for _, each := range reported {
+		blocked[each.BlockedUser.ID] = true
+	}
+	reporters, err := service.blacklistRepo.SearchPartnershipRequestBlacklist(SearchPartnershipRequestBlacklistCriteria{BlockedUserID: accountID})
+	if err != nil {
+		return blocked, err
+	}
+	for _, each := range reporters {
+		blocked[each.Reporter.ID] = true
+	}
+	return blocked, nil
+}
+
+// SearchEntries searches the TBA entries of a competition, leaving out the entries of athletes who have blacklisted
+// the current user or are blacklisted by the current user
+func (service CompetitionTBAService) SearchEntries(currentUser Account, criteria SearchCompetitionTBAEntryCriteria) ([]CompetitionTBAEntry, error) {
+	visible := make([]CompetitionTBAEntry, 0)
+	if criteria.CompetitionID < 1 && criteria.AccountID != currentUser.ID {
+		return visible, errors.New("competition must be specified")
+	}
+	blocked, err := service.blockedAccounts(currentUser.ID)
+	if err != nil {
+		return visible, err
+	}
+	entries, err := service.tbaRepo.SearchCompetitionTBAEntry(criteria)
+	if err != nil {
+		return visible, err
+	}
+	for _, each := range entries {
+		if !blocked[each.AccountID] {
+			visible = append(visible, each)
+		}
+	}
+	return visible, nil
+}
+
+// PostEntry posts a TBA entry of the current user for a competition that has not closed its registration. Athletes
+// can only have one entry for each competition.
+func (service CompetitionTBAService) PostEntry(currentUser Account, entry *CompetitionTBAEntry) error {
+	if !currentUser.HasRole(AccountTypeAthlete) {
+		return errors.New("only athletes can look for partners")
+	}
+	entry.AccountID = currentUser.ID
+	if err := entry.validate(); err != nil {
+		return err
+	}
+	competitions, err := service.competitionRepo.SearchCompetition(SearchCompetitionCriteria{ID: entry.CompetitionID})
+	if err != nil {
+		return err
+	}
+	if len(competitions) != 1 {
+		return errors.New(fmt.Sprintf("cannot find competition with ID = %v", entry.CompetitionID))
+	}
+	if status := competitions[0].GetStatus(); status != CompetitionStatusPreRegistration && status != CompetitionStatusOpenRegistration {
+		return errors.New("registration of this competition is closed")
+	}
+	existing, err := service.tbaRepo.SearchCompetitionTBAEntry(SearchCompetitionTBAEntryCriteria{
+		AccountID:     currentUser.ID,
+		CompetitionID: entry.CompetitionID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return errors.New("you are already looking for a partner at this competition")
+	}
+	entry.CreateUserID = currentUser.ID
+	entry.DateTimeCreated = time.Now()
+	entry.UpdateUserID = currentUser.ID
+	entry.DateTimeUpdated = time.Now()
+	return service.tbaRepo.CreateCompetitionTBAEntry(entry)
+}
+
+func (service CompetitionTBAService) getEntry(entryID int) (CompetitionTBAEntry, error) {
+	entries, err := service.tbaRepo.SearchCompetitionTBAEntry(SearchCompetitionTBAEntryCriteria{ID: entryID})
+	if err != nil {
+		return CompetitionTBAEntry{}, err
+	}
+	if len(entries) != 1 {
+		return CompetitionTBAEntry{}, errors.New(fmt.Sprintf("cannot find TBA entry with ID = %v", entryID))
+	}
+	return entries[0], nil
+}
+
+func (service CompetitionTBAService) getOwnEntry(currentUser Account, entryID int) (CompetitionTBAEntry, error) {
+	entry, err := service.getEntry(entryID)
+	if err != nil {
+		return entry, err
+	}
+	if entry.AccountID != currentUser.ID {
+		return entry, errors.New("not authorized to change this TBA entry")
+	}
+	return entry, nil
+}
+
+// UpdateEntry updates a TBA entry of the current user. The competition of an entry cannot be changed.
+func (service CompetitionTBAService) UpdateEntry(currentUser Account, entry CompetitionTBAEntry) error {
+	existing, err := service.getOwnEntry(currentUser, entry.ID)
+	if err != nil {
+		return err
+	}
+	entry.AccountID = existing.AccountID
+	entry.CompetitionID = existing.CompetitionID
+	if err := entry.validate(); err != nil {
+		return err
+	}
+	entry.UpdateUserID = currentUser.ID
+	entry.DateTimeUpdated = time.Now()
+	return service.tbaRepo.UpdateCompetitionTBAEntry(entry)
+}
+
+// DeleteEntry deletes a TBA entry of the current user, usually after the user has found a partner
+func (service CompetitionTBAService) DeleteEntry(currentUser Account, entryID int) error {
+	entry, err := service.getOwnEntry(currentUser, entryID)
+	if err != nil {
+		return err
+	}
+	return service.tbaRepo.DeleteCompetitionTBAEntry(entry)
+}
+
+// SendPartnershipRequest sends a partnership request from the current user to the athlete who posted the TBA entry.
+// The current user dances the role opposite to the role of the entry. Entries of athletes who have blacklisted the
+// current user, or are blacklisted by the current user, cannot be found.
+func (service CompetitionTBAService) SendPartnershipRequest(currentUser Account, entryID int, message string) (PartnershipRequest, error) {
+	request := PartnershipRequest{}
+	entry, err := service.getEntry(entryID)
+	if err != nil {
+		return request, err
+	}
+	blocked, err := service.blockedAccounts(currentUser.ID)
+	if err != nil {
+		return request, err
+	}
+	if blocked[entry.AccountID] {
+		return request, errors.New(fmt.Sprintf("cannot find TBA entry with ID = %v", entryID))
+	}
+
+	request = PartnershipRequest{
+		SenderID:        currentUser.ID,
+		RecipientID:     entry.AccountID,
+		SenderRole:      PartnershipRoleLead,
+		RecipientRole:   entry.PartnershipRole(),
+		Message:         message,
+		Status:          PartnershipRequestStatusPending,
+		CreateUserID:    currentUser.ID,
+		DateTimeCreated: time.Now(),
+		UpdateUserID:    currentUser.ID,
+		DateTimeUpdated: time.Now(),
+	}
+	if request.RecipientRole == PartnershipRoleLead {
+		request.SenderRole = PartnershipRoleFollow
+	}
+	err = CreatePartnershipRequest(request, service.partnershipRepo, service.requestRepo, service.accountRepo, service.blacklistRepo)
+	return request, err
+}
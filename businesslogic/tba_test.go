@@ -0,0 +1,169 @@
+package businesslogic_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mock/businesslogic"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newAthlete(id int) businesslogic.Account {
+	account := businesslogic.Account{ID: id}
+	account.SetRoles([]businesslogic.AccountRole{{AccountID: id, AccountTypeID: businesslogic.AccountTypeAthlete}})
+	return account
+}
+
+// expectBlacklist sets up the blacklist of athlete 12, who has blocked athlete 20 and is blocked by athlete 30
+func expectBlacklist(blacklistRepo *mock_businesslogic.MockIPartnershipRequestBlacklistRepository) {
+	blacklistRepo.EXPECT().SearchPartnershipRequestBlacklist(businesslogic.SearchPartnershipRequestBlacklistCriteria{ReporterID: 12}).Return([]businesslogic.PartnershipRequestBlacklistEntry{
+		{Reporter: businesslogic.Account{ID: 12}, BlockedUser: businesslogic.Account{ID: 20}},
+	}, nil)
+	blacklistRepo.EXPECT().SearchPartnershipRequestBlacklist(businesslogic.SearchPartnershipRequestBlacklistCriteria{BlockedUserID: 12}).Return([]businesslogic.PartnershipRequestBlacklistEntry{
+		{Reporter: businesslogic.Account{ID: 30}, BlockedUser: businesslogic.Account{ID: 12}},
+	}, nil)
+}
+
+func newTBAEntry(id, accountID int, role string) businesslogic.CompetitionTBAEntry {
+	return businesslogic.CompetitionTBAEntry{
+		ID:            id,
+		AccountID:     accountID,
+		CompetitionID: 3,
+		Role:          role,
+		Proficiency:   "Gold",
+		Style:         "Latin",
+		Height:        "170 cm",
+		School:        "University of Texas",
+	}
+}
+
+func TestCompetitionTBAService_SearchEntries(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	service := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo,
+		tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+
+	_, err := service.SearchEntries(newAthlete(12), businesslogic.SearchCompetitionTBAEntryCriteria{})
+	assert.NotNil(t, err, "should not search entries of all competitions")
+
+	criteria := businesslogic.SearchCompetitionTBAEntryCriteria{CompetitionID: 3, Style: "Latin"}
+	expectBlacklist(blacklistRepo)
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(criteria).Return([]businesslogic.CompetitionTBAEntry{
+		newTBAEntry(1, 12, businesslogic.TBARoleLead),
+		newTBAEntry(2, 20, businesslogic.TBARoleFollow),
+		newTBAEntry(3, 30, businesslogic.TBARoleFollow),
+		newTBAEntry(4, 40, businesslogic.TBARoleFollow),
+	}, nil)
+
+	entries, err := service.SearchEntries(newAthlete(12), criteria)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 2, "entries of athletes who blocked or are blocked by the user should be hidden") {
+		assert.Equal(t, 1, entries[0].ID)
+		assert.Equal(t, 4, entries[1].ID)
+	}
+}
+
+func TestCompetitionTBAService_PostEntry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	service := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo,
+		tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+
+	entry := newTBAEntry(0, 0, businesslogic.TBARoleLead)
+	assert.NotNil(t, service.PostEntry(newOrganizer(41), &entry), "only athletes can look for partners")
+
+	entry.Role = "Either"
+	assert.NotNil(t, service.PostEntry(newAthlete(12), &entry), "role must be lead or follow")
+
+	entry.Role = businesslogic.TBARoleLead
+	closed := businesslogic.Competition{ID: 3}
+	closed.UpdateStatus(businesslogic.CompetitionStatusClosedRegistration)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{closed}, nil)
+	assert.NotNil(t, service.PostEntry(newAthlete(12), &entry), "should not look for partners after registration is closed")
+
+	open := businesslogic.Competition{ID: 3}
+	open.UpdateStatus(businesslogic.CompetitionStatusOpenRegistration)
+	competitionRepo.EXPECT().SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: 3}).Return([]businesslogic.Competition{open}, nil).Times(2)
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{AccountID: 12, CompetitionID: 3}).Return([]businesslogic.CompetitionTBAEntry{newTBAEntry(1, 12, businesslogic.TBARoleLead)}, nil)
+	assert.NotNil(t, service.PostEntry(newAthlete(12), &entry), "athletes can only have one entry for each competition")
+
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{AccountID: 12, CompetitionID: 3}).Return([]businesslogic.CompetitionTBAEntry{}, nil)
+	tbaRepo.EXPECT().CreateCompetitionTBAEntry(gomock.Any()).Return(nil)
+	assert.Nil(t, service.PostEntry(newAthlete(12), &entry))
+	assert.Equal(t, 12, entry.AccountID)
+	assert.Equal(t, 12, entry.CreateUserID)
+}
+
+func TestCompetitionTBAService_UpdateEntry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	service := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo,
+		tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{ID: 4}).Return([]businesslogic.CompetitionTBAEntry{newTBAEntry(4, 40, businesslogic.TBARoleFollow)}, nil).Times(2)
+	update := newTBAEntry(4, 12, businesslogic.TBARoleLead)
+	assert.NotNil(t, service.UpdateEntry(newAthlete(12), update), "should not update entries of other athletes")
+
+	update.CompetitionID = 9
+	tbaRepo.EXPECT().UpdateCompetitionTBAEntry(gomock.Any()).Do(func(entry businesslogic.CompetitionTBAEntry) {
+		assert.Equal(t, 40, entry.AccountID)
+		assert.Equal(t, 3, entry.CompetitionID, "competition of an entry should not change")
+		assert.Equal(t, businesslogic.TBARoleLead, entry.Role)
+	}).Return(nil)
+	assert.Nil(t, service.UpdateEntry(newAthlete(40), update))
+}
+
+func TestCompetitionTBAService_SendPartnershipRequest(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accountRepo := mock_businesslogic.NewMockIAccountRepository(mockCtrl)
+	competitionRepo := mock_businesslogic.NewMockICompetitionRepository(mockCtrl)
+	tbaRepo := mock_businesslogic.NewMockICompetitionTBAEntryRepository(mockCtrl)
+	partnershipRepo := mock_businesslogic.NewMockIPartnershipRepository(mockCtrl)
+	requestRepo := mock_businesslogic.NewMockIPartnershipRequestRepository(mockCtrl)
+	blacklistRepo := mock_businesslogic.NewMockIPartnershipRequestBlacklistRepository(mockCtrl)
+	service := businesslogic.NewCompetitionTBAService(accountRepo, competitionRepo,
+		tbaRepo, partnershipRepo, requestRepo, blacklistRepo)
+
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{ID: 3}).Return([]businesslogic.CompetitionTBAEntry{newTBAEntry(3, 30, businesslogic.TBARoleFollow)}, nil)
+	expectBlacklist(blacklistRepo)
+	_, err := service.SendPartnershipRequest(newAthlete(12), 3, "Hi")
+	assert.NotNil(t, err, "should not send requests to athletes who have blocked the user")
+
+	tbaRepo.EXPECT().SearchCompetitionTBAEntry(businesslogic.SearchCompetitionTBAEntryCriteria{ID: 4}).Return([]businesslogic.CompetitionTBAEntry{newTBAEntry(4, 40, businesslogic.TBARoleFollow)}, nil)
+	expectBlacklist(blacklistRepo)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 12}).Return([]businesslogic.Account{newAthlete(12)}, nil)
+	accountRepo.EXPECT().SearchAccount(businesslogic.SearchAccountCriteria{ID: 40}).Return([]businesslogic.Account{newAthlete(40)}, nil)
+	blacklistRepo.EXPECT().SearchPartnershipRequestBlacklist(businesslogic.SearchPartnershipRequestBlacklistCriteria{ReporterID: 40}).Return([]businesslogic.PartnershipRequestBlacklistEntry{}, nil)
+	partnershipRepo.EXPECT().SearchPartnership(businesslogic.SearchPartnershipCriteria{LeadID: 12, FollowID: 40}).Return([]businesslogic.Partnership{}, nil)
+	requestRepo.EXPECT().SearchPartnershipRequest(gomock.Any()).Return([]businesslogic.PartnershipRequest{}, nil)
+	requestRepo.EXPECT().CreatePartnershipRequest(gomock.Any()).Return(nil)
+
+	request, err := service.SendPartnershipRequest(newAthlete(12), 4, "Hi")
+	assert.Nil(t, err)
+	assert.Equal(t, 40, request.RecipientID)
+	assert.Equal(t, businesslogic.PartnershipRoleLead, request.SenderRole, "sender should dance the opposite role of the entry")
+	assert.Equal(t, businesslogic.PartnershipRoleFollow, request.RecipientRole)
+}
@@ -54,6 +54,9 @@ func init() {
 	AccountRepository.Database = PostgresDatabase
 	AccountTypeRepository.Database = PostgresDatabase
 	AccountRoleRepository.Database = PostgresDatabase
+	AccountSecurityRepository.Database = PostgresDatabase
+	AccountSessionRepository.Database = PostgresDatabase
+	AccountSecurityTokenRepository.Database = PostgresDatabase
 	GenderRepository.Database = PostgresDatabase
 	UserPreferenceRepository.Database = PostgresDatabase
 	RoleApplicationRepository.Database = PostgresDatabase
@@ -78,18 +81,47 @@ func init() {
 	// competition
 	CompetitionStatusRepository.Database = PostgresDatabase
 	CompetitionRepository.Database = PostgresDatabase
+	ProductCategoryRepository.Database = PostgresDatabase
+	CompetitionProductRepository.Database = PostgresDatabase
+	ProductOrderRepository.Database = PostgresDatabase
+
+	// payment
+	OrganizerStripeSettingsRepository.Database = PostgresDatabase
+	PaymentRepository.Database = PostgresDatabase
+	CompetitionFeeScheduleRepository.Database = PostgresDatabase
 
 	// event
 	EventRepository.Database = PostgresDatabase
 	EventMetaRepository.Database = PostgresDatabase
 	EventDanceRepository.Database = PostgresDatabase
 	CompetitionEventTemplateRepository.Database = PostgresDatabase
+	RoundRepository.Database = PostgresDatabase
+	RoundHeatDrawRepository.Database = PostgresDatabase
+	EventRoundScheduleRepository.Database = PostgresDatabase
 
 	// competition entry
 	AthleteCompetitionEntryRepository.Database = PostgresDatabase
 	PartnershipCompetitionEntryRepository.Database = PostgresDatabase
+	PartnershipCompetitionRepresentationRepository.Database = PostgresDatabase
+	CompetitionTBAEntryRepository.Database = PostgresDatabase
+	CompetitionLeadTagRepository.Database = PostgresDatabase
+	CompetitionLeadTagSettingsRepository.Database = PostgresDatabase
+	CompetitionLeadTagRangeRepository.Database = PostgresDatabase
 
 	// event entry
 	AthleteEventEntryRepository.Database = PostgresDatabase
 	PartnershipEventEntryRepository.Database = PostgresDatabase
+
+	// round entry
+	PartnershipRoundEntryRepository.Database = PostgresDatabase
+	AdjudicatorRoundEntryRepository.Database = PostgresDatabase
+
+	// scoresheet
+	PlacementRepository.Database = PostgresDatabase
+	RoundResultRepository.Database = PostgresDatabase
+	ScoresheetRepository.Database = PostgresDatabase
+
+	// collegiate
+	ProficiencyPointOverrideRepository.Database = PostgresDatabase
+	ProficiencyPointOverrideHistoryRepository.Database = PostgresDatabase
 }
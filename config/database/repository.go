@@ -4,13 +4,16 @@ package database
 
 import (
 	"github.com/DancesportSoftware/das/dataaccess/accountdal"
+	"github.com/DancesportSoftware/das/dataaccess/collegiatedal"
 	"github.com/DancesportSoftware/das/dataaccess/competition"
 	"github.com/DancesportSoftware/das/dataaccess/entrydal"
 	"github.com/DancesportSoftware/das/dataaccess/eventdal"
 	"github.com/DancesportSoftware/das/dataaccess/organizer"
 	"github.com/DancesportSoftware/das/dataaccess/partnershipdal"
+	"github.com/DancesportSoftware/das/dataaccess/paymentdal"
 	"github.com/DancesportSoftware/das/dataaccess/provision"
 	"github.com/DancesportSoftware/das/dataaccess/referencedal"
+	"github.com/DancesportSoftware/das/dataaccess/scoresheetdal"
 	"github.com/Masterminds/squirrel"
 )
 
@@ -73,6 +76,18 @@ var AccountRoleRepository = accountdal.PostgresAccountRoleRepository{
 	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 }
 
+var AccountSecurityRepository = accountdal.PostgresAccountSecurityRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var AccountSessionRepository = accountdal.PostgresAccountSessionRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var AccountSecurityTokenRepository = accountdal.PostgresAccountSecurityTokenRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
 var UserPreferenceRepository = accountdal.PostgresUserPreferenceRepository{
 	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 }
@@ -135,6 +150,30 @@ var CompetitionRepository = competition.PostgresCompetitionRepository{
 	SqlBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 }
 
+var ProductCategoryRepository = competition.PostgresProductCategoryRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionProductRepository = competition.PostgresCompetitionProductRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var ProductOrderRepository = competition.PostgresProductOrderRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var OrganizerStripeSettingsRepository = paymentdal.PostgresOrganizerStripeSettingsRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var PaymentRepository = paymentdal.PostgresPaymentRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionFeeScheduleRepository = paymentdal.PostgresCompetitionFeeScheduleRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
 var CompetitionOfficialRepository = organizer.PostgresCompetitionOfficialRepository{
 	SqlBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 }
@@ -174,3 +213,63 @@ var AthleteEventEntryRepository = entrydal.PostgresAthleteEventEntryRepository{
 var PartnershipEventEntryRepository = entrydal.PostgresPartnershipEventEntryRepository{
 	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 }
+
+var PlacementRepository = scoresheetdal.PostgresPlacementRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var RoundResultRepository = scoresheetdal.PostgresRoundResultRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var ScoresheetRepository = scoresheetdal.PostgresScoresheetRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var RoundRepository = eventdal.PostgresRoundRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var PartnershipRoundEntryRepository = entrydal.PostgresPartnershipRoundEntryRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var AdjudicatorRoundEntryRepository = entrydal.PostgresAdjudicatorRoundEntryRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var PartnershipCompetitionRepresentationRepository = entrydal.PostgresPartnershipCompetitionRepresentationRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionTBAEntryRepository = entrydal.PostgresCompetitionTBAEntryRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionLeadTagRepository = entrydal.PostgresCompetitionLeadTagRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionLeadTagSettingsRepository = entrydal.PostgresCompetitionLeadTagSettingsRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var CompetitionLeadTagRangeRepository = entrydal.PostgresCompetitionLeadTagRangeRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var RoundHeatDrawRepository = eventdal.PostgresRoundHeatDrawRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var EventRoundScheduleRepository = eventdal.PostgresEventRoundScheduleRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var ProficiencyPointOverrideRepository = collegiatedal.PostgresProficiencyPointOverrideRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+var ProficiencyPointOverrideHistoryRepository = collegiatedal.PostgresProficiencyPointOverrideHistoryRepository{
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
@@ -0,0 +1,61 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/account"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiLocalAuthenticationEndpointV1_0 = "/api/v1.0/auth"
+
+var localAuthenticationServer = account.NewLocalAuthenticationServer(middleware.LocalAuthenticationStrategy, database.AccountRoleRepository)
+
+var signUpController = util.DasController{
+	Name:         "SignUpController",
+	Description:  "Create an account with a password in DAS",
+	Method:       http.MethodPost,
+	Endpoint:     apiLocalAuthenticationEndpointV1_0 + "/signup",
+	Handler:      localAuthenticationServer.SignUpHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var loginController = util.DasController{
+	Name:         "LoginController",
+	Description:  "Log in with email and password",
+	Method:       http.MethodPost,
+	Endpoint:     apiLocalAuthenticationEndpointV1_0 + "/login",
+	Handler:      localAuthenticationServer.LoginHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var refreshTokenController = util.DasController{
+	Name:         "RefreshTokenController",
+	Description:  "Exchange a refresh token for new tokens",
+	Method:       http.MethodPost,
+	Endpoint:     apiLocalAuthenticationEndpointV1_0 + "/refresh",
+	Handler:      localAuthenticationServer.RefreshHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var logoutController = util.DasController{
+	Name:         "LogoutController",
+	Description:  "Revoke the session of the access token",
+	Method:       http.MethodPost,
+	Endpoint:     apiLocalAuthenticationEndpointV1_0 + "/logout",
+	Handler:      middleware.ForgetCurrentUser(localAuthenticationServer.LogoutHandler),
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+// LocalAuthenticationControllerGroup contains the endpoints of the local authentication strategy. It is only
+// registered when DAS is the identity provider.
+var LocalAuthenticationControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		signUpController,
+		loginController,
+		refreshTokenController,
+		logoutController,
+	},
+}
@@ -0,0 +1,77 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/account"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/payment/stripe"
+	"net/http"
+)
+
+const apiPaymentEndpointV1_0 = "/api/v1.0/account/payment"
+
+var registrationFeeService = businesslogic.NewRegistrationFeeService(
+	database.CompetitionFeeScheduleRepository,
+	database.CompetitionRepository,
+	database.EventRepository,
+	database.AthleteCompetitionEntryRepository,
+	database.PartnershipCompetitionEntryRepository,
+	database.PartnershipEventEntryRepository,
+	database.PartnershipCompetitionRepresentationRepository,
+	database.PaymentRepository,
+)
+
+var paymentService = businesslogic.NewPaymentService(
+	stripe.NewPaymentGateway(),
+	database.CompetitionRepository,
+	database.OrganizerStripeSettingsRepository,
+	database.PaymentRepository,
+	map[string]businesslogic.IPaymentHandler{
+		businesslogic.PaymentPurposeProductOrder:    competitionProductService,
+		businesslogic.PaymentPurposeRegistrationFee: businesslogic.NewRegistrationFeePaymentHandler(database.AthleteCompetitionEntryRepository, registrationFeeService),
+	},
+)
+
+var paymentServer = account.NewPaymentServer(middleware.AuthenticationStrategy, paymentService)
+
+var startCheckoutController = util.DasController{
+	Name:        "StartCheckoutController",
+	Description: "Pay for an order or a registration with the payment gateway of the organizer",
+	Method:      http.MethodPost,
+	Endpoint:    apiPaymentEndpointV1_0 + "/checkout",
+	Handler:     paymentServer.StartCheckoutHandler,
+	AllowedRoles: []int{
+		businesslogic.AccountTypeAthlete,
+		businesslogic.AccountTypeAdjudicator,
+		businesslogic.AccountTypeScrutineer,
+		businesslogic.AccountTypeOrganizer,
+		businesslogic.AccountTypeDeckCaptain,
+		businesslogic.AccountTypeEmcee,
+	},
+}
+
+var searchPaymentController = util.DasController{
+	Name:        "SearchPaymentController",
+	Description: "Search the payments of the current user",
+	Method:      http.MethodGet,
+	Endpoint:    apiPaymentEndpointV1_0,
+	Handler:     paymentServer.SearchPaymentHandler,
+	AllowedRoles: []int{
+		businesslogic.AccountTypeAthlete,
+		businesslogic.AccountTypeAdjudicator,
+		businesslogic.AccountTypeScrutineer,
+		businesslogic.AccountTypeOrganizer,
+		businesslogic.AccountTypeDeckCaptain,
+		businesslogic.AccountTypeEmcee,
+	},
+}
+
+// PaymentControllerGroup contains the controllers that users pay with
+var PaymentControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		startCheckoutController,
+		searchPaymentController,
+	},
+}
@@ -0,0 +1,60 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/account"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiProductOrderEndpointV1_0 = "/api/v1.0/account/product/order"
+
+var competitionProductService = businesslogic.NewCompetitionProductService(
+	database.ProductCategoryRepository,
+	database.CompetitionProductRepository,
+	database.ProductOrderRepository,
+)
+
+var productOrderServer = account.NewProductOrderServer(middleware.AuthenticationStrategy, competitionProductService)
+
+var placeProductOrderController = util.DasController{
+	Name:        "PlaceProductOrderController",
+	Description: "Order a product of a competition",
+	Method:      http.MethodPost,
+	Endpoint:    apiProductOrderEndpointV1_0,
+	Handler:     productOrderServer.PlaceOrderHandler,
+	AllowedRoles: []int{
+		businesslogic.AccountTypeAthlete,
+		businesslogic.AccountTypeAdjudicator,
+		businesslogic.AccountTypeScrutineer,
+		businesslogic.AccountTypeOrganizer,
+		businesslogic.AccountTypeDeckCaptain,
+		businesslogic.AccountTypeEmcee,
+	},
+}
+
+var searchProductOrderController = util.DasController{
+	Name:        "SearchProductOrderController",
+	Description: "Search the product orders of the current user",
+	Method:      http.MethodGet,
+	Endpoint:    apiProductOrderEndpointV1_0,
+	Handler:     productOrderServer.SearchOrderHandler,
+	AllowedRoles: []int{
+		businesslogic.AccountTypeAthlete,
+		businesslogic.AccountTypeAdjudicator,
+		businesslogic.AccountTypeScrutineer,
+		businesslogic.AccountTypeOrganizer,
+		businesslogic.AccountTypeDeckCaptain,
+		businesslogic.AccountTypeEmcee,
+	},
+}
+
+// ProductOrderControllerGroup contains the controllers that order competition products
+var ProductOrderControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		placeProductOrderController,
+		searchProductOrderController,
+	},
+}
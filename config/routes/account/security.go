@@ -0,0 +1,68 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/account"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiAccountPasswordEndpointV1_0 = "/api/v1.0/account/password"
+
+var accountSecurityServer = account.NewAccountSecurityServer(middleware.LocalAuthenticationStrategy, middleware.AccountSecurityService)
+
+var updatePasswordController = util.DasController{
+	Name:        "UpdatePasswordController",
+	Description: "Change the password of the current user",
+	Method:      http.MethodPut,
+	Endpoint:    apiAccountPasswordEndpointV1_0,
+	Handler:     accountSecurityServer.UpdatePasswordHandler,
+	AllowedRoles: []int{
+		businesslogic.AccountTypeAthlete,
+		businesslogic.AccountTypeAdjudicator,
+		businesslogic.AccountTypeScrutineer,
+		businesslogic.AccountTypeOrganizer,
+		businesslogic.AccountTypeDeckCaptain,
+		businesslogic.AccountTypeEmcee,
+		businesslogic.AccountTypeAdministrator,
+	},
+}
+
+var forgotPasswordController = util.DasController{
+	Name:         "ForgotPasswordController",
+	Description:  "Send a password reset link to the email of an account",
+	Method:       http.MethodPost,
+	Endpoint:     apiAccountPasswordEndpointV1_0 + "/forgot",
+	Handler:      accountSecurityServer.ForgotPasswordHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var resetPasswordController = util.DasController{
+	Name:         "ResetPasswordController",
+	Description:  "Reset the password of an account with the token of a password reset link",
+	Method:       http.MethodPost,
+	Endpoint:     apiAccountPasswordEndpointV1_0 + "/reset",
+	Handler:      accountSecurityServer.ResetPasswordHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var unlockAccountController = util.DasController{
+	Name:         "UnlockAccountController",
+	Description:  "Unlock an account with the token of an unlock link",
+	Method:       http.MethodPost,
+	Endpoint:     "/api/v1.0/account/unlock",
+	Handler:      accountSecurityServer.UnlockAccountHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+// AccountSecurityControllerGroup contains the endpoints that manage passwords of the local authentication strategy.
+// It is only registered when DAS is the identity provider.
+var AccountSecurityControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		updatePasswordController,
+		forgotPasswordController,
+		resetPasswordController,
+		unlockAccountController,
+	},
+}
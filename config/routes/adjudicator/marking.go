@@ -0,0 +1,51 @@
+package adjudicator
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/adjudicator"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var adjudicatorService = businesslogic.NewAdjudicatorService(
+	database.EventRepository,
+	database.RoundRepository,
+	database.CompetitionOfficialRepository,
+	database.EventDanceRepository,
+	database.AdjudicatorRoundEntryRepository,
+	database.PartnershipRoundEntryRepository,
+	database.ScoresheetRepository,
+	database.PlacementRepository,
+)
+
+var adjudicatorMarkingServer = adjudicator.NewAdjudicatorMarkingServer(middleware.AuthenticationStrategy, adjudicatorService)
+
+const apiAdjudicatorRoundEndpointV1_0 = "/api/v1.0/adjudicator/round"
+
+var getHeatListController = util.DasController{
+	Name:         "GetAdjudicatorHeatListController",
+	Description:  "Get the heat lists of the rounds that the adjudicator is marking",
+	Method:       http.MethodGet,
+	Endpoint:     apiAdjudicatorRoundEndpointV1_0 + "/heatlist",
+	Handler:      adjudicatorMarkingServer.GetHeatListHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdjudicator},
+}
+
+var submitMarksController = util.DasController{
+	Name:         "SubmitAdjudicatorMarksController",
+	Description:  "Submit the recalls or placements of the adjudicator in a dance",
+	Method:       http.MethodPut,
+	Endpoint:     apiAdjudicatorRoundEndpointV1_0 + "/marks",
+	Handler:      adjudicatorMarkingServer.SubmitMarksHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdjudicator},
+}
+
+// AdjudicatorMarkingControllerGroup contains the controllers that adjudicators use to mark rounds electronically
+var AdjudicatorMarkingControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		getHeatListController,
+		submitMarksController,
+	},
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/admin"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiAdminProficiencyPointEndpoint = "/api/v1.0/admin/proficiency/point"
+const apiAdminProficiencyPointOverrideEndpoint = "/api/v1.0/admin/proficiency/override"
+
+var proficiencyPointService = businesslogic.NewProficiencyPointService(
+	businesslogic.YCNPointScale,
+	database.PartnershipRepository,
+	database.PartnershipRoundEntryRepository,
+	database.RoundRepository,
+	database.RoundResultRepository,
+	database.EventRepository,
+	database.FederationRepository,
+	database.ProficiencyRepository,
+	database.ProficiencyPointOverrideRepository,
+	database.ProficiencyPointOverrideHistoryRepository,
+)
+
+var proficiencyPointOverrideServer = admin.NewProficiencyPointOverrideServer(
+	middleware.AuthenticationStrategy,
+	database.AccountRepository,
+	proficiencyPointService,
+)
+
+var getAthleteProficiencyPointsController = util.DasController{
+	Name:         "GetAthleteProficiencyPointsController",
+	Description:  "Admin gets the collegiate proficiency points of an athlete",
+	Method:       http.MethodGet,
+	Endpoint:     apiAdminProficiencyPointEndpoint,
+	Handler:      proficiencyPointOverrideServer.GetAthleteProficiencyPointsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdministrator},
+}
+
+var grantProficiencyPointOverrideController = util.DasController{
+	Name:         "GrantProficiencyPointOverrideController",
+	Description:  "Admin allows an athlete to enter a level that the athlete has pointed out of",
+	Method:       http.MethodPost,
+	Endpoint:     apiAdminProficiencyPointOverrideEndpoint,
+	Handler:      proficiencyPointOverrideServer.GrantOverrideHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdministrator},
+}
+
+var revokeProficiencyPointOverrideController = util.DasController{
+	Name:         "RevokeProficiencyPointOverrideController",
+	Description:  "Admin revokes an override of proficiency points",
+	Method:       http.MethodDelete,
+	Endpoint:     apiAdminProficiencyPointOverrideEndpoint,
+	Handler:      proficiencyPointOverrideServer.RevokeOverrideHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdministrator},
+}
+
+var getProficiencyPointOverrideHistoryController = util.DasController{
+	Name:         "GetProficiencyPointOverrideHistoryController",
+	Description:  "Admin gets the audit trail of the overrides of an athlete",
+	Method:       http.MethodGet,
+	Endpoint:     apiAdminProficiencyPointOverrideEndpoint + "/history",
+	Handler:      proficiencyPointOverrideServer.GetOverrideHistoryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAdministrator},
+}
+
+// ManageProficiencyPointControllerGroup is a collection of handler functions for administrators to manage the
+// collegiate proficiency points of athletes
+var ManageProficiencyPointControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		getAthleteProficiencyPointsController,
+		grantProficiencyPointOverrideController,
+		revokeProficiencyPointOverrideController,
+		getProficiencyPointOverrideHistoryController,
+	},
+}
@@ -0,0 +1,43 @@
+package competition
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/controller/competition"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiCompetitionProductEndpointV1_0 = "/api/v1.0/competition/product"
+
+var publicProductServer = competition.NewPublicProductServer(businesslogic.NewCompetitionProductService(
+	database.ProductCategoryRepository,
+	database.CompetitionProductRepository,
+	database.ProductOrderRepository,
+))
+
+var searchCompetitionProductController = util.DasController{
+	Name:         "SearchCompetitionProductController",
+	Description:  "Search products of competitions that are available for order",
+	Method:       http.MethodGet,
+	Endpoint:     apiCompetitionProductEndpointV1_0,
+	Handler:      publicProductServer.SearchProductHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var getProductCategoryController = util.DasController{
+	Name:         "GetProductCategoryController",
+	Description:  "Get all categories of competition products",
+	Method:       http.MethodGet,
+	Endpoint:     apiCompetitionProductEndpointV1_0 + "/category",
+	Handler:      publicProductServer.GetProductCategoryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+// PublicProductViewControllerGroup contains the controllers that browse the products of competitions
+var PublicProductViewControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		searchCompetitionProductController,
+		getProductCategoryController,
+	},
+}
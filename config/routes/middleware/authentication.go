@@ -1,9 +1,90 @@
 package middleware
 
 import (
+	"github.com/DancesportSoftware/das/auth"
 	"github.com/DancesportSoftware/das/auth/firebase"
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/DancesportSoftware/das/auth/oidc"
+	"github.com/DancesportSoftware/das/businesslogic"
 	"github.com/DancesportSoftware/das/config/database"
 	"github.com/DancesportSoftware/das/env"
+	"github.com/DancesportSoftware/das/mail/file"
+	"log"
+	"net/http"
+	"time"
 )
 
-var AuthenticationStrategy = firebase.NewFirebaseAuthenticationStrategy(env.FirebaseAuthCredential, database.AccountRepository)
+// LocalAuthenticationStrategy is the strategy that issues tokens of DAS. It is nil unless AUTH_STRATEGY is "local".
+var LocalAuthenticationStrategy *local.LocalAuthenticationStrategy
+
+// AccountSecurityService manages the passwords of accounts of the local authentication strategy
+var AccountSecurityService = newAccountSecurityService()
+
+func newAccountSecurityService() businesslogic.AccountSecurityService {
+	service := businesslogic.NewAccountSecurityService(
+		database.AccountRepository,
+		database.AccountSecurityRepository,
+		database.AccountSecurityTokenRepository,
+		database.AccountSessionRepository,
+		local.NewPBKDF2PasswordHasher(),
+		file.NewMailer(env.MailDirectory),
+	)
+	service.WebAppURL = env.WebAppURL
+	service.ForgetAccount = currentUserCache.ForgetAccount
+	return service
+}
+
+// currentUserCache is created without a strategy, because the local strategy depends on AccountSecurityService,
+// which forgets the accounts of this cache
+var currentUserCache = auth.NewCachingAuthenticationStrategy(nil, auth.DefaultCurrentUserCacheDuration)
+
+// AuthenticationStrategy is the identity provider of DAS, selected by AUTH_STRATEGY. The account of a request is
+// taken from the request context once the request is authorized, and is cached briefly for the same token.
+var AuthenticationStrategy auth.IAuthenticationStrategy = currentUserCache.WithStrategy(newAuthenticationStrategy())
+
+// ForgetCurrentUser removes the account of the token of the request from the cache after the handler, so that tokens
+// that the handler revokes stop working right away
+func ForgetCurrentUser(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		currentUserCache.Forget(r)
+	}
+}
+
+func newAuthenticationStrategy() auth.IAuthenticationStrategy {
+	switch env.AuthStrategy {
+	case env.AuthStrategyFirebase:
+		return firebase.NewFirebaseAuthenticationStrategy(env.FirebaseAuthCredential, database.AccountRepository)
+	case env.AuthStrategyLocal:
+		strategy := local.NewLocalAuthenticationStrategy(database.AccountRepository, database.AccountSessionRepository,
+			AccountSecurityService, newTokenSigner())
+		if env.HmacValidHours > 0 {
+			strategy.AccessTokenValidity = time.Duration(env.HmacValidHours) * time.Hour
+		}
+		LocalAuthenticationStrategy = &strategy
+		return strategy
+	case env.AuthStrategyOIDC:
+		if env.OIDCIssuer == "" || env.OIDCClientID == "" {
+			log.Fatalf("[fatal] %v and %v must be defined for OpenID Connect authentication", env.VarOIDCIssuer, env.VarOIDCClientID)
+		}
+		return oidc.NewOIDCAuthenticationStrategy(env.OIDCIssuer, env.OIDCClientID, database.AccountRepository)
+	}
+	log.Fatalf("[fatal] unknown authentication strategy %v", env.AuthStrategy)
+	return nil
+}
+
+// newTokenSigner signs tokens with RS256 if an RSA key is provided, and with HS256 otherwise
+func newTokenSigner() local.ISigner {
+	if env.RSASigningKey != "" {
+		key, err := local.ParseRSAPrivateKey(env.RSASigningKey)
+		if err != nil {
+			log.Fatalf("[fatal] cannot parse %v: %v", env.VarRSASigningKey, err)
+		}
+		return local.NewRSASigner(key)
+	}
+	if len(env.HmacSigningKey) < 32 {
+		log.Fatalf("[fatal] %v or %v must be defined for local authentication, and HMAC keys must have at least 32 characters",
+			env.VarRSASigningKey, env.VarHMACSigningKey)
+	}
+	return local.NewHMACSigner([]byte(env.HmacSigningKey))
+}
@@ -1,18 +1,22 @@
 package middleware
 
 import (
+	"github.com/DancesportSoftware/das/auth"
 	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
 	"github.com/DancesportSoftware/das/controller/util"
 	"log"
 	"net/http"
 )
 
-func getRequestUserRole(r *http.Request) ([]int, error) {
+// authenticateRequest returns a copy of the request that carries the account of the request in its context, so that
+// handlers do not need to authenticate the request again
+func authenticateRequest(r *http.Request) (*http.Request, []int, error) {
 	account, err := AuthenticationStrategy.GetCurrentUser(r)
 	if err != nil {
-		return nil, err
+		return r, nil, err
 	}
-	return account.GetRoles(), nil
+	return r.WithContext(auth.WithCurrentUser(r.Context(), account)), account.GetRoles(), nil
 }
 
 func allowUnauthorizedRequest(roles []int) bool {
@@ -36,7 +40,7 @@ func AuthorizeMultipleRoles(h http.HandlerFunc, roles []int) http.HandlerFunc {
 			return
 		}
 
-		userRoles, authErr := getRequestUserRole(r)
+		r, userRoles, authErr := authenticateRequest(r)
 		if authErr != nil && !allowNoAuth {
 			log.Printf("[error] authentication error occurred when the %s requires a role: %v", r.RequestURI, roles)
 			util.RespondJsonResult(w, http.StatusUnauthorized, authErr.Error(), nil)
@@ -69,3 +73,41 @@ func AuthorizeMultipleRoles(h http.HandlerFunc, roles []int) http.HandlerFunc {
 		}
 	}
 }
+
+// CompetitionAuthorizationService checks the policies of the controllers that act on competitions
+var CompetitionAuthorizationService = businesslogic.NewCompetitionAuthorizationService(database.CompetitionRepository, database.CompetitionOfficialRepository)
+
+// AuthorizeCompetitionPolicy checks if the policy allows the current user to act on the competition that the request
+// identifies with competitionIDKey. If not, the handler function will not be executed. If the competition is optional,
+// requests that do not identify a competition are passed to the handler without being checked. The request must be
+// authenticated by AuthorizeMultipleRoles first. The handler receives the competition that is authorized in the request
+// context.
+func AuthorizeCompetitionPolicy(h http.HandlerFunc, policy businesslogic.CompetitionPolicy, competitionIDKey string, optional bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		currentUser, ok := auth.CurrentUser(r.Context())
+		if !ok {
+			util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
+			return
+		}
+		id, err := util.CompetitionID(r, competitionIDKey)
+		if err == util.CompetitionIDMissingError && optional {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+			return
+		}
+		competition, err := CompetitionAuthorizationService.Authorize(currentUser, id, policy)
+		if err != nil {
+			if err == businesslogic.CompetitionAccessDeniedError {
+				log.Printf("[error] policy \"%v\" denied account %d access to competition %d", policy.Name, currentUser.ID, id)
+				util.RespondJsonResult(w, http.StatusForbidden, err.Error(), nil)
+			} else {
+				util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+			}
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(util.WithCompetition(r.Context(), competition)))
+	}
+}
@@ -47,12 +47,14 @@ var searchCompetitionController = util.DasController{
 }
 
 var updateCompetitionController = util.DasController{
-	Name:         "UpdateCompetitionController",
-	Description:  "Organizer updates a competition in DAS",
-	Method:       http.MethodPut,
-	Endpoint:     apiOrganizerCompetitionEndpoint,
-	Handler:      organizerCompetitionServer.OrganizerUpdateCompetitionHandler,
-	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Name:             "UpdateCompetitionController",
+	Description:      "Organizer updates a competition in DAS",
+	Method:           http.MethodPut,
+	Endpoint:         apiOrganizerCompetitionEndpoint,
+	Handler:          organizerCompetitionServer.OrganizerUpdateCompetitionHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ChangeCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
 }
 
 var OrganizerCompetitionManagementControllerGroup = util.DasControllerGroup{
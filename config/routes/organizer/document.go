@@ -0,0 +1,65 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/document/pdf"
+	"net/http"
+)
+
+const apiOrganizerDocumentEndpoint = "/api/v1.0/organizer/competition/document"
+
+var organizerDocumentServer = organizer.NewOrganizerDocumentServer(
+	middleware.AuthenticationStrategy,
+	businesslogic.NewCompetitionDocumentService(
+		database.AccountRepository,
+		database.DanceRepository,
+		database.PartnershipEventEntryRepository,
+		database.SchoolRepository,
+		database.CompetitionLeadTagRepository,
+		heatDrawService,
+		pdf.NewRenderer(),
+	),
+)
+
+var printBackNumbersController = util.DasController{
+	Name:         "PrintBackNumbersController",
+	Description:  "Organizer, scrutineer or deck captain prints the back numbers of the leads at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerDocumentEndpoint + "/backnumber",
+	Handler:      organizerDocumentServer.BackNumbersHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer, businesslogic.AccountTypeDeckCaptain},
+	Policy:       &businesslogic.BackNumbersPolicy,
+}
+
+var printHeatSheetController = util.DasController{
+	Name:         "PrintHeatSheetController",
+	Description:  "Organizer, scrutineer or deck captain prints the heat sheets of an event or all events of a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerDocumentEndpoint + "/heatsheet",
+	Handler:      organizerDocumentServer.HeatSheetHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer, businesslogic.AccountTypeDeckCaptain},
+	Policy:       &businesslogic.HeatSheetPolicy,
+}
+
+var printSchoolEntrySummaryController = util.DasController{
+	Name:         "PrintSchoolEntrySummaryController",
+	Description:  "Organizer prints the entries of a school or all schools at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerDocumentEndpoint + "/school",
+	Handler:      organizerDocumentServer.SchoolEntrySummaryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.SchoolEntrySummaryPolicy,
+}
+
+// OrganizerDocumentControllerGroup contains the controllers that print documents of competitions
+var OrganizerDocumentControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		printBackNumbersController,
+		printHeatSheetController,
+		printSchoolEntrySummaryController,
+	},
+}
@@ -36,6 +36,7 @@ var createEventController = util.DasController{
 	Endpoint:     apiOrganizerEventEndpointV1_0,
 	Handler:      organizerEventServer.CreateEventHandler,
 	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
 }
 
 var deleteEventController = util.DasController{
@@ -45,15 +46,19 @@ var deleteEventController = util.DasController{
 	Endpoint:     apiOrganizerEventEndpointV1_0,
 	Handler:      organizerEventServer.DeleteEventHandler,
 	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
 }
 
 var searchEventController = util.DasController{
-	Name:         "SearchEventController",
-	Description:  "Organizer searches a event in DAS",
-	Method:       http.MethodGet,
-	Endpoint:     apiOrganizerEventEndpointV1_0,
-	Handler:      organizerEventServer.SearchEventHandler,
-	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Name:                "SearchEventController",
+	Description:         "Organizer searches a event in DAS",
+	Method:              http.MethodGet,
+	Endpoint:            apiOrganizerEventEndpointV1_0,
+	Handler:             organizerEventServer.SearchEventHandler,
+	AllowedRoles:        []int{businesslogic.AccountTypeOrganizer},
+	Policy:              &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey:    "competitionId",
+	CompetitionOptional: true,
 }
 
 var updateEventController = util.DasController{
@@ -63,6 +68,7 @@ var updateEventController = util.DasController{
 	Endpoint:     apiOrganizerEventEndpointV1_0,
 	Handler:      organizerEventServer.UpdateEventHandler,
 	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
 }
 
 var OrganizerEventManagementControllerGroup = util.DasControllerGroup{
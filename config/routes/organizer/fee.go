@@ -0,0 +1,51 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var registrationFeeService = businesslogic.NewRegistrationFeeService(
+	database.CompetitionFeeScheduleRepository,
+	database.CompetitionRepository,
+	database.EventRepository,
+	database.AthleteCompetitionEntryRepository,
+	database.PartnershipCompetitionEntryRepository,
+	database.PartnershipEventEntryRepository,
+	database.PartnershipCompetitionRepresentationRepository,
+	database.PaymentRepository,
+)
+
+var organizerFeeServer = organizer.NewOrganizerFeeServer(middleware.AuthenticationStrategy, registrationFeeService)
+
+var saveFeeScheduleController = util.DasController{
+	Name:         "SaveFeeScheduleController",
+	Description:  "Organizer sets the registration fees of a competition",
+	Method:       http.MethodPut,
+	Endpoint:     "/api/v1.0/organizer/competition/fee",
+	Handler:      organizerFeeServer.SaveFeeScheduleHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var getCompetitionInvoiceController = util.DasController{
+	Name:         "GetCompetitionInvoiceController",
+	Description:  "Organizer gets the invoice of an athlete or a partnership at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     "/api/v1.0/organizer/competition/invoice",
+	Handler:      organizerFeeServer.GetInvoiceHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
+
+// OrganizerFeeManagementControllerGroup contains the controllers that manage the registration fees of competitions
+var OrganizerFeeManagementControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		saveFeeScheduleController,
+		getCompetitionInvoiceController,
+	},
+}
@@ -0,0 +1,31 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var competitionFinanceService = businesslogic.NewCompetitionFinanceService(
+	database.ProductCategoryRepository,
+	database.CompetitionProductRepository,
+	database.ProductOrderRepository,
+	database.PaymentRepository,
+	registrationFeeService,
+)
+
+var organizerFinanceServer = organizer.NewOrganizerFinanceServer(middleware.AuthenticationStrategy, competitionFinanceService)
+
+// GetFinanceReportController returns the revenue, refunds and outstanding registration fees of a competition
+var GetFinanceReportController = util.DasController{
+	Name:         "GetFinanceReportController",
+	Description:  "Organizer gets the finance report of a competition as JSON or CSV",
+	Method:       http.MethodGet,
+	Endpoint:     "/api/v1.0/organizer/competition/finance",
+	Handler:      organizerFinanceServer.GetFinanceReportHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
@@ -16,16 +16,108 @@ var organizerLeadTagServer = organizer.NewOrganizerLeadTagServer(middleware.Auth
 const apiOrganizerLeadTagEndpointV1_0 = "/api/v1.0/organizer/competition/leads"
 
 var getAllLeadController = util.DasController{
-	Name:         "GetAllLeadsController",
-	Description:  "Get all the leads of a competition",
+	Name:             "GetAllLeadsController",
+	Description:      "Get all the leads of a competition",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerLeadTagEndpointV1_0,
+	Handler:          organizerLeadTagServer.GetAllLeadEntries,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+var leadTagAssignmentServer = organizer.NewOrganizerLeadTagAssignmentServer(
+	middleware.AuthenticationStrategy,
+	businesslogic.NewLeadTagService(
+		database.AthleteCompetitionEntryRepository,
+		database.PartnershipCompetitionEntryRepository,
+		database.CompetitionLeadTagRepository,
+		database.CompetitionLeadTagSettingsRepository,
+		database.CompetitionLeadTagRangeRepository,
+	),
+)
+
+const apiOrganizerLeadTagAssignmentEndpoint = "/api/v1.0/organizer/competition/leadtag"
+
+var getLeadTagSettingsController = util.DasController{
+	Name:         "GetLeadTagSettingsController",
+	Description:  "Organizer gets how lead tags of a competition are numbered",
 	Method:       http.MethodGet,
-	Endpoint:     apiOrganizerLeadTagEndpointV1_0,
-	Handler:      organizerLeadTagServer.GetAllLeadEntries,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint + "/setting",
+	Handler:      leadTagAssignmentServer.GetLeadTagSettingsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
+
+var saveLeadTagSettingsController = util.DasController{
+	Name:         "SaveLeadTagSettingsController",
+	Description:  "Organizer sets how lead tags of a competition are numbered",
+	Method:       http.MethodPut,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint + "/setting",
+	Handler:      leadTagAssignmentServer.SaveLeadTagSettingsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var searchLeadTagRangeController = util.DasController{
+	Name:         "SearchLeadTagRangeController",
+	Description:  "Organizer searches reserved ranges and school or studio blocks of lead tags of a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint + "/range",
+	Handler:      leadTagAssignmentServer.SearchLeadTagRangeHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
+
+var createLeadTagRangeController = util.DasController{
+	Name:         "CreateLeadTagRangeController",
+	Description:  "Organizer reserves a range of lead tags, or creates the block of a school or studio",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint + "/range",
+	Handler:      leadTagAssignmentServer.CreateLeadTagRangeHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var deleteLeadTagRangeController = util.DasController{
+	Name:         "DeleteLeadTagRangeController",
+	Description:  "Organizer deletes a range of lead tags",
+	Method:       http.MethodDelete,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint + "/range",
+	Handler:      leadTagAssignmentServer.DeleteLeadTagRangeHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var searchLeadTagController = util.DasController{
+	Name:         "SearchLeadTagController",
+	Description:  "Organizer searches the tags of leads at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint,
+	Handler:      leadTagAssignmentServer.SearchLeadTagHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
+
+var overrideLeadTagController = util.DasController{
+	Name:         "OverrideLeadTagController",
+	Description:  "Organizer assigns a tag to a lead at a competition",
+	Method:       http.MethodPut,
+	Endpoint:     apiOrganizerLeadTagAssignmentEndpoint,
+	Handler:      leadTagAssignmentServer.OverrideLeadTagHandler,
 	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
 }
 
 var OrganizerLeadTagManagementControllerGroup = util.DasControllerGroup{
 	Controllers: []util.DasController{
 		getAllLeadController,
+		getLeadTagSettingsController,
+		saveLeadTagSettingsController,
+		searchLeadTagRangeController,
+		createLeadTagRangeController,
+		deleteLeadTagRangeController,
+		searchLeadTagController,
+		overrideLeadTagController,
 	},
 }
@@ -0,0 +1,91 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/payment/stripe"
+	"net/http"
+)
+
+var paymentService = businesslogic.NewPaymentService(
+	stripe.NewPaymentGateway(),
+	database.CompetitionRepository,
+	database.OrganizerStripeSettingsRepository,
+	database.PaymentRepository,
+	map[string]businesslogic.IPaymentHandler{
+		businesslogic.PaymentPurposeProductOrder:    competitionProductService,
+		businesslogic.PaymentPurposeRegistrationFee: businesslogic.NewRegistrationFeePaymentHandler(database.AthleteCompetitionEntryRepository, registrationFeeService),
+	},
+)
+
+var organizerPaymentServer = organizer.NewOrganizerPaymentServer(
+	middleware.AuthenticationStrategy,
+	database.AccountRepository,
+	paymentService,
+	stripe.SignatureHeader,
+)
+
+const apiOrganizerPaymentEndpointV1_0 = "/api/v1.0/organizer/payment"
+
+var getStripeSettingsController = util.DasController{
+	Name:         "GetStripeSettingsController",
+	Description:  "Organizer gets the Stripe account that receives payments",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerPaymentEndpointV1_0 + "/stripe",
+	Handler:      organizerPaymentServer.GetStripeSettingsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+}
+
+var saveStripeSettingsController = util.DasController{
+	Name:         "SaveStripeSettingsController",
+	Description:  "Organizer sets the Stripe account that receives payments",
+	Method:       http.MethodPut,
+	Endpoint:     apiOrganizerPaymentEndpointV1_0 + "/stripe",
+	Handler:      organizerPaymentServer.SaveStripeSettingsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+}
+
+var searchOrganizerPaymentController = util.DasController{
+	Name:             "SearchOrganizerPaymentController",
+	Description:      "Organizer searches the payments of a competition",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerPaymentEndpointV1_0,
+	Handler:          organizerPaymentServer.SearchPaymentHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+var refundPaymentController = util.DasController{
+	Name:         "RefundPaymentController",
+	Description:  "Organizer refunds a payment",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerPaymentEndpointV1_0 + "/refund",
+	Handler:      organizerPaymentServer.RefundPaymentHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeAdministrator},
+	Policy:       &businesslogic.RefundPaymentPolicy,
+}
+
+// the payment gateway authenticates with the signature of the event instead of a user token
+var paymentWebhookController = util.DasController{
+	Name:         "PaymentWebhookController",
+	Description:  "Payment gateway of an organizer reports the result of payments",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerPaymentEndpointV1_0 + "/webhook",
+	Handler:      organizerPaymentServer.WebhookHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+// OrganizerPaymentManagementControllerGroup contains the controllers that manage the payments that organizers receive
+var OrganizerPaymentManagementControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		getStripeSettingsController,
+		saveStripeSettingsController,
+		searchOrganizerPaymentController,
+		refundPaymentController,
+		paymentWebhookController,
+	},
+}
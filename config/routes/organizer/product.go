@@ -0,0 +1,83 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var competitionProductService = businesslogic.NewCompetitionProductService(
+	database.ProductCategoryRepository,
+	database.CompetitionProductRepository,
+	database.ProductOrderRepository,
+)
+
+var organizerProductServer = organizer.NewOrganizerProductServer(middleware.AuthenticationStrategy, competitionProductService)
+
+const apiOrganizerProductEndpointV1_0 = "/api/v1.0/organizer/competition/product"
+
+var searchOrganizerProductController = util.DasController{
+	Name:             "SearchOrganizerProductController",
+	Description:      "Organizer searches all the products of a competition",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerProductEndpointV1_0,
+	Handler:          organizerProductServer.SearchProductHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+var createOrganizerProductController = util.DasController{
+	Name:         "CreateOrganizerProductController",
+	Description:  "Organizer creates a product for a competition",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerProductEndpointV1_0,
+	Handler:      organizerProductServer.CreateProductHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var updateOrganizerProductController = util.DasController{
+	Name:         "UpdateOrganizerProductController",
+	Description:  "Organizer updates a product of a competition",
+	Method:       http.MethodPut,
+	Endpoint:     apiOrganizerProductEndpointV1_0,
+	Handler:      organizerProductServer.UpdateProductHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var deleteOrganizerProductController = util.DasController{
+	Name:         "DeleteOrganizerProductController",
+	Description:  "Organizer deletes a product that has not been ordered",
+	Method:       http.MethodDelete,
+	Endpoint:     apiOrganizerProductEndpointV1_0,
+	Handler:      organizerProductServer.DeleteProductHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var searchOrganizerProductOrderController = util.DasController{
+	Name:             "SearchOrganizerProductOrderController",
+	Description:      "Organizer searches the product orders of a competition",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerProductEndpointV1_0 + "/order",
+	Handler:          organizerProductServer.SearchOrderHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+// OrganizerProductManagementControllerGroup contains the controllers that manage the products of competitions
+var OrganizerProductManagementControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		searchOrganizerProductController,
+		createOrganizerProductController,
+		updateOrganizerProductController,
+		deleteOrganizerProductController,
+		searchOrganizerProductOrderController,
+	},
+}
@@ -0,0 +1,83 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var roundGenerationService = businesslogic.NewRoundGenerationService(
+	database.EventRepository,
+	database.EventDanceRepository,
+	database.RoundRepository,
+	database.PartnershipEventEntryRepository,
+	database.PartnershipRoundEntryRepository,
+	database.RoundResultRepository,
+)
+
+var heatDrawService = businesslogic.NewHeatDrawService(
+	database.EventRepository,
+	database.EventDanceRepository,
+	database.RoundRepository,
+	database.PartnershipRoundEntryRepository,
+	database.PartnershipCompetitionEntryRepository,
+	database.PartnershipCompetitionRepresentationRepository,
+	database.RoundHeatDrawRepository,
+)
+
+var organizerRoundServer = organizer.NewOrganizerRoundServer(middleware.AuthenticationStrategy, roundGenerationService, heatDrawService)
+
+const apiOrganizerRoundEndpointV1_0 = "/api/v1.0/organizer/round"
+
+var startEventController = util.DasController{
+	Name:         "StartEventController",
+	Description:  "Organizer starts an event and creates its rounds",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerEventEndpointV1_0 + "/start",
+	Handler:      organizerRoundServer.StartEventHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ManageCompetitionPolicy,
+}
+
+var advanceRecalledCouplesController = util.DasController{
+	Name:         "AdvanceRecalledCouplesController",
+	Description:  "Enter the recalled couples of a round in the next round",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerRoundEndpointV1_0 + "/advance",
+	Handler:      organizerRoundServer.AdvanceRecalledCouplesHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.RunRoundsPolicy,
+}
+
+var drawHeatsController = util.DasController{
+	Name:         "DrawHeatsController",
+	Description:  "Draw the couples of a round into heats",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerRoundEndpointV1_0 + "/heat",
+	Handler:      organizerRoundServer.DrawHeatsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.RunRoundsPolicy,
+}
+
+var getHeatsController = util.DasController{
+	Name:         "GetHeatsController",
+	Description:  "Get the heats of a round",
+	Method:       http.MethodGet,
+	Endpoint:     apiOrganizerRoundEndpointV1_0 + "/heat",
+	Handler:      organizerRoundServer.GetHeatsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeScrutineer, businesslogic.AccountTypeDeckCaptain, businesslogic.AccountTypeEmcee},
+}
+
+// OrganizerRoundManagementControllerGroup contains the controllers that create rounds, advance couples between rounds
+// and draw the heats of rounds
+var OrganizerRoundManagementControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		startEventController,
+		advanceRecalledCouplesController,
+		drawHeatsController,
+		getHeatsController,
+	},
+}
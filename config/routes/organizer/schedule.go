@@ -0,0 +1,75 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/organizer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var competitionScheduleService = businesslogic.NewCompetitionScheduleService(
+	database.EventRepository,
+	database.EventDanceRepository,
+	database.PartnershipEventEntryRepository,
+	database.PartnershipRepository,
+	database.EventRoundScheduleRepository,
+)
+
+var organizerScheduleServer = organizer.NewOrganizerScheduleServer(middleware.AuthenticationStrategy, competitionScheduleService)
+
+const apiOrganizerScheduleEndpointV1_0 = "/api/v1.0/organizer/competition/schedule"
+
+var buildScheduleController = util.DasController{
+	Name:         "BuildScheduleController",
+	Description:  "Organizer builds the timetable of all events of a competition",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerScheduleEndpointV1_0,
+	Handler:      organizerScheduleServer.BuildScheduleHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var publishScheduleController = util.DasController{
+	Name:         "PublishScheduleController",
+	Description:  "Organizer publishes the timetable of a competition after the clashes of athletes are resolved",
+	Method:       http.MethodPost,
+	Endpoint:     apiOrganizerScheduleEndpointV1_0 + "/publish",
+	Handler:      organizerScheduleServer.PublishScheduleHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeOrganizer},
+	Policy:       &businesslogic.ChangeCompetitionPolicy,
+}
+
+var getScheduleController = util.DasController{
+	Name:             "GetScheduleController",
+	Description:      "Get the timetable of a competition",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerScheduleEndpointV1_0,
+	Handler:          organizerScheduleServer.GetScheduleHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer, businesslogic.AccountTypeAdjudicator, businesslogic.AccountTypeScrutineer, businesslogic.AccountTypeDeckCaptain, businesslogic.AccountTypeEmcee},
+	Policy:           &businesslogic.ViewCompetitionSchedulePolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+var getAthleteConflictsController = util.DasController{
+	Name:             "GetAthleteConflictsController",
+	Description:      "Organizer checks the timetable of a competition for athletes who dance at the same time or without enough rest",
+	Method:           http.MethodGet,
+	Endpoint:         apiOrganizerScheduleEndpointV1_0 + "/conflict",
+	Handler:          organizerScheduleServer.GetAthleteConflictsHandler,
+	AllowedRoles:     []int{businesslogic.AccountTypeOrganizer},
+	Policy:           &businesslogic.ManageCompetitionPolicy,
+	CompetitionIDKey: "competitionId",
+}
+
+// OrganizerScheduleManagementControllerGroup contains the controllers that build and check the timetable of
+// competitions
+var OrganizerScheduleManagementControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		buildScheduleController,
+		publishScheduleController,
+		getScheduleController,
+		getAthleteConflictsController,
+	},
+}
@@ -0,0 +1,95 @@
+package partnership
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/partnership/tba"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiCompetitionTBAEndpoint = "/api/v1.0/athlete/partnership/tba"
+
+var competitionTBAService = businesslogic.NewCompetitionTBAService(
+	database.AccountRepository,
+	database.CompetitionRepository,
+	database.CompetitionTBAEntryRepository,
+	database.PartnershipRepository,
+	database.PartnershipRequestRepository,
+	database.PartnershipRequestBlacklistRepository,
+)
+
+var competitionTBAServer = tba.NewCompetitionTBAServer(middleware.AuthenticationStrategy, competitionTBAService)
+
+var partnerSuggestionServer = tba.NewPartnerSuggestionServer(
+	middleware.AuthenticationStrategy,
+	businesslogic.NewPartnerSuggestionService(database.SchoolRepository, database.StudioRepository, competitionTBAService),
+)
+
+var searchTBAEntryController = util.DasController{
+	Name:         "SearchTBAEntryController",
+	Description:  "Search athletes who are looking for partners at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiCompetitionTBAEndpoint,
+	Handler:      competitionTBAServer.SearchTBAEntryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+var createTBAEntryController = util.DasController{
+	Name:         "CreateTBAEntryController",
+	Description:  "Athlete posts that the athlete is looking for a partner at a competition",
+	Method:       http.MethodPost,
+	Endpoint:     apiCompetitionTBAEndpoint,
+	Handler:      competitionTBAServer.CreateTBAEntryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+var updateTBAEntryController = util.DasController{
+	Name:         "UpdateTBAEntryController",
+	Description:  "Athlete updates a TBA entry of the athlete",
+	Method:       http.MethodPut,
+	Endpoint:     apiCompetitionTBAEndpoint,
+	Handler:      competitionTBAServer.UpdateTBAEntryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+var deleteTBAEntryController = util.DasController{
+	Name:         "DeleteTBAEntryController",
+	Description:  "Athlete deletes a TBA entry of the athlete",
+	Method:       http.MethodDelete,
+	Endpoint:     apiCompetitionTBAEndpoint,
+	Handler:      competitionTBAServer.DeleteTBAEntryHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+var createTBAPartnershipRequestController = util.DasController{
+	Name:         "CreateTBAPartnershipRequestController",
+	Description:  "Athlete sends a partnership request to the athlete who posted a TBA entry",
+	Method:       http.MethodPost,
+	Endpoint:     apiCompetitionTBAEndpoint + "/request",
+	Handler:      competitionTBAServer.CreateTBAPartnershipRequestHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+var suggestPartnerController = util.DasController{
+	Name:         "SuggestPartnerController",
+	Description:  "Suggest compatible partners to an athlete who is looking for a partner at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     apiCompetitionTBAEndpoint + "/suggestion",
+	Handler:      partnerSuggestionServer.SuggestPartnerHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
+
+// CompetitionTBAControllerGroup contains a collection of HTTP request handler functions for athletes who look for
+// partners at competitions
+var CompetitionTBAControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		searchTBAEntryController,
+		createTBAEntryController,
+		updateTBAEntryController,
+		deleteTBAEntryController,
+		createTBAPartnershipRequestController,
+		suggestPartnerController,
+	},
+}
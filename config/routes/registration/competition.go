@@ -1,12 +1,16 @@
 package registration
 
 import (
+	"encoding/json"
 	"github.com/DancesportSoftware/das/businesslogic"
 	"github.com/DancesportSoftware/das/config/database"
 	"github.com/DancesportSoftware/das/config/routes/middleware"
 	"github.com/DancesportSoftware/das/controller"
 	"github.com/DancesportSoftware/das/controller/athlete"
 	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/env"
+	"io/ioutil"
+	"log"
 	"net/http"
 )
 
@@ -21,7 +25,14 @@ var athleteCompetitionRegistrationServer = athlete.CompetitionRegistrationServer
 	IPartnershipEventEntryRepository:       database.PartnershipEventEntryRepository,
 	IEventRepository:                       database.EventRepository,
 	IAuthenticationStrategy:                middleware.AuthenticationStrategy,
-	Service: businesslogic.NewCompetitionRegistrationService(
+	Service:                                newCompetitionRegistrationService(),
+}
+
+// newCompetitionRegistrationService creates the registration service with the eligibility rules of each federation and
+// division. Entries are marked as unpaid when added events leave a balance on their invoices, and leads are tagged by
+// the lead tag settings of the competition.
+func newCompetitionRegistrationService() businesslogic.CompetitionRegistrationService {
+	service := businesslogic.NewCompetitionRegistrationService(
 		database.AccountRepository,
 		database.PartnershipRepository,
 		database.CompetitionRepository,
@@ -30,9 +41,47 @@ var athleteCompetitionRegistrationServer = athlete.CompetitionRegistrationServer
 		database.AthleteEventEntryRepository,
 		database.PartnershipCompetitionEntryRepository,
 		database.PartnershipEventEntryRepository,
-	),
+	)
+	factory := businesslogic.NewEligibilityRuleFactory(
+		database.AccountRepository,
+		database.AgeRepository,
+		database.ProficiencyRepository,
+		database.EventRepository,
+		database.PartnershipEventEntryRepository,
+		database.AthleteCompetitionEntryRepository,
+		proficiencyPointService,
+	)
+	service.EligibilityRules = factory.NewEngine(loadEligibilityRuleSettings(env.EligibilityRules))
+	service.Fees = &registrationFeeService
+	service.LeadTags = &leadTagService
+	return service
+}
+
+// loadEligibilityRuleSettings reads the eligibility rules of each federation and division from a JSON file. The default
+// rules are used if the file is not specified.
+func loadEligibilityRuleSettings(path string) []businesslogic.EligibilityRuleSettings {
+	if path == "" {
+		return businesslogic.DefaultEligibilityRuleSettings
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("[fatal] cannot read %v from %v: %v", env.VarEligibilityRules, path, err)
+	}
+	settings := make([]businesslogic.EligibilityRuleSettings, 0)
+	if err := json.Unmarshal(content, &settings); err != nil {
+		log.Fatalf("[fatal] cannot parse %v from %v: %v", env.VarEligibilityRules, path, err)
+	}
+	return settings
 }
 
+var leadTagService = businesslogic.NewLeadTagService(
+	database.AthleteCompetitionEntryRepository,
+	database.PartnershipCompetitionEntryRepository,
+	database.CompetitionLeadTagRepository,
+	database.CompetitionLeadTagSettingsRepository,
+	database.CompetitionLeadTagRangeRepository,
+)
+
 var createCompetitionRegistrationController = util.DasController{
 	Name:         "CreateCompetitionRegistrationController",
 	Description:  "Athlete creates competition and event registration",
@@ -151,6 +200,9 @@ var CompetitionRegistrationControllerGroup = util.DasControllerGroup{
 	Controllers: []util.DasController{
 		createCompetitionRegistrationController,
 		getPartnershipRegistrationController,
+		getProficiencyPointsController,
+		getFeeScheduleController,
+		getInvoiceController,
 		searchCompetitionEntryController,
 		searchEventEntryController,
 		searchCompetitionEntryByAthleteController,
@@ -0,0 +1,41 @@
+package registration
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/athlete"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var registrationFeeService = businesslogic.NewRegistrationFeeService(
+	database.CompetitionFeeScheduleRepository,
+	database.CompetitionRepository,
+	database.EventRepository,
+	database.AthleteCompetitionEntryRepository,
+	database.PartnershipCompetitionEntryRepository,
+	database.PartnershipEventEntryRepository,
+	database.PartnershipCompetitionRepresentationRepository,
+	database.PaymentRepository,
+)
+
+var athleteFeeServer = athlete.NewAthleteFeeServer(middleware.AuthenticationStrategy, registrationFeeService)
+
+var getFeeScheduleController = util.DasController{
+	Name:         "GetFeeScheduleController",
+	Description:  "Get the registration fees of a competition",
+	Method:       http.MethodGet,
+	Endpoint:     "/api/v1.0/competition/fee",
+	Handler:      athleteFeeServer.GetFeeScheduleHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeNoAuth},
+}
+
+var getInvoiceController = util.DasController{
+	Name:         "GetInvoiceController",
+	Description:  "Athlete gets the invoice of the athlete or a partnership of the athlete at a competition",
+	Method:       http.MethodGet,
+	Endpoint:     "/api/v1.0/athlete/competition/invoice",
+	Handler:      athleteFeeServer.GetInvoiceHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
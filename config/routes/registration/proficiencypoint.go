@@ -0,0 +1,39 @@
+package registration
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/athlete"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+const apiAthleteProficiencyPointEndpoint = "/api/v1.0/athlete/proficiency/point"
+
+var proficiencyPointService = businesslogic.NewProficiencyPointService(
+	businesslogic.YCNPointScale,
+	database.PartnershipRepository,
+	database.PartnershipRoundEntryRepository,
+	database.RoundRepository,
+	database.RoundResultRepository,
+	database.EventRepository,
+	database.FederationRepository,
+	database.ProficiencyRepository,
+	database.ProficiencyPointOverrideRepository,
+	database.ProficiencyPointOverrideHistoryRepository,
+)
+
+var athleteProficiencyPointServer = athlete.NewAthleteProficiencyPointServer(
+	middleware.AuthenticationStrategy,
+	proficiencyPointService,
+)
+
+var getProficiencyPointsController = util.DasController{
+	Name:         "GetProficiencyPointsController",
+	Description:  "Athlete gets the collegiate proficiency points and the levels that the athlete has pointed out of",
+	Method:       http.MethodGet,
+	Endpoint:     apiAthleteProficiencyPointEndpoint,
+	Handler:      athleteProficiencyPointServer.GetProficiencyPointsHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeAthlete},
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/DancesportSoftware/das/businesslogic"
 	"github.com/DancesportSoftware/das/config/routes/account"
+	"github.com/DancesportSoftware/das/config/routes/adjudicator"
 	"github.com/DancesportSoftware/das/config/routes/admin"
 	"github.com/DancesportSoftware/das/config/routes/competition"
 	"github.com/DancesportSoftware/das/config/routes/middleware"
@@ -11,6 +12,7 @@ import (
 	"github.com/DancesportSoftware/das/config/routes/partnership"
 	"github.com/DancesportSoftware/das/config/routes/reference"
 	"github.com/DancesportSoftware/das/config/routes/registration"
+	"github.com/DancesportSoftware/das/config/routes/scrutineer"
 	"github.com/DancesportSoftware/das/controller/util"
 	"github.com/gorilla/mux"
 	"log"
@@ -54,11 +56,37 @@ func addDasController(router *mux.Router, handler util.DasController) {
 	if handler.AllowedRoles == nil {
 		log.Fatalf("Alloed Roles of %s is required\n", handler.Name)
 	}
+	handlerFunc := handler.Handler
+	if handler.Policy != nil {
+		for _, role := range handler.Policy.OfficialRoles {
+			if !allowsRole(handler.AllowedRoles, role) {
+				log.Fatalf("Allowed Roles of %s must include role %d of its policy\n", handler.Name, role)
+			}
+		}
+		if handler.Policy.Administrators && !allowsRole(handler.AllowedRoles, businesslogic.AccountTypeAdministrator) {
+			log.Fatalf("Allowed Roles of %s must include administrators that its policy allows\n", handler.Name)
+		}
+		if allowsRole(handler.AllowedRoles, businesslogic.AccountTypeNoAuth) {
+			log.Fatalf("%s cannot allow unauthenticated requests and specify a policy\n", handler.Name)
+		}
+		handlerFunc = middleware.AuthorizeCompetitionPolicy(handler.Handler, *handler.Policy, handler.CompetitionIDKey, handler.CompetitionOptional)
+	} else if handler.CompetitionIDKey != "" || handler.CompetitionOptional {
+		log.Fatalf("%s cannot identify its competition without a policy\n", handler.Name)
+	}
 	router.
 		Methods(handler.Method, http.MethodOptions).
 		Path(handler.Endpoint).
 		Name(handler.Description).
-		Handler(middleware.SetResponseHeader(middleware.AuthorizeMultipleRoles(handler.Handler, handler.AllowedRoles)))
+		Handler(middleware.SetResponseHeader(middleware.AuthorizeMultipleRoles(handlerFunc, handler.AllowedRoles)))
+}
+
+func allowsRole(roles []int, role int) bool {
+	for _, each := range roles {
+		if each == role {
+			return true
+		}
+	}
+	return false
 }
 
 func addDasControllerGroup(router *mux.Router, group util.DasControllerGroup) {
@@ -111,11 +139,17 @@ func NewDasRouter() *mux.Router {
 
 	// account
 	addDasControllerGroup(router, account.AccountControllerGroup)
+	if middleware.LocalAuthenticationStrategy != nil {
+		addDasControllerGroup(router, account.LocalAuthenticationControllerGroup)
+		addDasControllerGroup(router, account.AccountSecurityControllerGroup)
+	}
 	addDasController(router, account.AccountTypeController)
 	addDasController(router, account.GenderController)
 	addDasController(router, account.RoleController)
 	addDasControllerGroup(router, account.UserPreferenceControllerGroup)
 	addDasControllerGroup(router, account.RoleApplicationControllerGroup)
+	addDasControllerGroup(router, account.ProductOrderControllerGroup)
+	addDasControllerGroup(router, account.PaymentControllerGroup)
 
 	// partnership request blacklist
 	addDasController(router, partnership.GetPartnershipBlacklistReasonController)
@@ -129,6 +163,9 @@ func NewDasRouter() *mux.Router {
 	// partnership
 	addDasControllerGroup(router, partnership.PartnershipControllerGroup)
 
+	// partner search
+	addDasControllerGroup(router, partnership.CompetitionTBAControllerGroup)
+
 	// organizer (multi-user shared: organizer, admin)
 	addDasControllerGroup(router, organizer.OrganizerProvisionControllerGroup)
 
@@ -139,6 +176,13 @@ func NewDasRouter() *mux.Router {
 	addDasControllerGroup(router, organizer.OrganizerCompetitionOfficialInvitationControllerGroup)
 	addDasControllerGroup(router, organizer.OrganizerCompetitionEventTemplateControllerGroup)
 	addDasControllerGroup(router, organizer.OrganizerLeadTagManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerRoundManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerScheduleManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerProductManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerPaymentManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerFeeManagementControllerGroup)
+	addDasControllerGroup(router, organizer.OrganizerDocumentControllerGroup)
+	addDasController(router, organizer.GetFinanceReportController)
 
 	// competition
 	addDasController(router, competition.GetCompetitionStatusController)
@@ -147,19 +191,23 @@ func NewDasRouter() *mux.Router {
 	addDasControllerGroup(router, registration.CompetitionRegistrationControllerGroup)
 
 	// scrutineer
+	addDasControllerGroup(router, scrutineer.ScrutineerScoresheetControllerGroup)
 
 	// emcee
 
 	// deck captain
 
 	// adjudicator
+	addDasControllerGroup(router, adjudicator.AdjudicatorMarkingControllerGroup)
 
 	// administrator
 	addDasControllerGroup(router, admin.AdminManageUserControllerGroup)
 	addDasControllerGroup(router, admin.ManageOrganizerProvisionControllerGroup)
+	addDasControllerGroup(router, admin.ManageProficiencyPointControllerGroup)
 
 	// public only
 	addDasControllerGroup(router, competition.PublicCompetitionViewControllerGroup)
+	addDasControllerGroup(router, competition.PublicProductViewControllerGroup)
 	addDasControllerGroup(router, account.SearchProfileControllerGroup)
 
 	log.Println("[info] finishing controller initialization")
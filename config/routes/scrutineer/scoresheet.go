@@ -0,0 +1,86 @@
+package scrutineer
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/config/database"
+	"github.com/DancesportSoftware/das/config/routes/middleware"
+	"github.com/DancesportSoftware/das/controller/scrutineer"
+	"github.com/DancesportSoftware/das/controller/util"
+	"net/http"
+)
+
+var scrutineerService = businesslogic.NewScrutineerService(
+	database.EventRepository,
+	database.RoundRepository,
+	database.EventDanceRepository,
+	database.AdjudicatorRoundEntryRepository,
+	database.PartnershipRoundEntryRepository,
+	database.ScoresheetRepository,
+	database.PlacementRepository,
+	database.RoundResultRepository,
+)
+
+var scrutineerScoresheetServer = scrutineer.NewScrutineerScoresheetServer(middleware.AuthenticationStrategy, scrutineerService)
+
+const apiScrutineerRoundEndpointV1_0 = "/api/v1.0/scrutineer/round"
+
+var openRoundController = util.DasController{
+	Name:         "OpenRoundController",
+	Description:  "Open a round for marking",
+	Method:       http.MethodPost,
+	Endpoint:     apiScrutineerRoundEndpointV1_0 + "/open",
+	Handler:      scrutineerScoresheetServer.OpenRoundHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.ScoreCompetitionPolicy,
+}
+
+var enterMarksController = util.DasController{
+	Name:         "EnterMarksController",
+	Description:  "Enter or correct the marks of an adjudicator in a dance",
+	Method:       http.MethodPut,
+	Endpoint:     apiScrutineerRoundEndpointV1_0 + "/marks",
+	Handler:      scrutineerScoresheetServer.EnterMarksHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.ScoreCompetitionPolicy,
+}
+
+var getMarksController = util.DasController{
+	Name:         "GetMarksController",
+	Description:  "Get all the marks of a round",
+	Method:       http.MethodGet,
+	Endpoint:     apiScrutineerRoundEndpointV1_0 + "/marks",
+	Handler:      scrutineerScoresheetServer.GetMarksHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.ScoreCompetitionPolicy,
+}
+
+var lockRoundController = util.DasController{
+	Name:         "LockRoundController",
+	Description:  "Lock the marks of a round",
+	Method:       http.MethodPost,
+	Endpoint:     apiScrutineerRoundEndpointV1_0 + "/lock",
+	Handler:      scrutineerScoresheetServer.LockRoundHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.ScoreCompetitionPolicy,
+}
+
+var computeRoundResultController = util.DasController{
+	Name:         "ComputeRoundResultController",
+	Description:  "Compute the recalls or placements of a locked round",
+	Method:       http.MethodPost,
+	Endpoint:     apiScrutineerRoundEndpointV1_0 + "/result",
+	Handler:      scrutineerScoresheetServer.ComputeRoundResultHandler,
+	AllowedRoles: []int{businesslogic.AccountTypeScrutineer},
+	Policy:       &businesslogic.ScoreCompetitionPolicy,
+}
+
+// ScrutineerScoresheetControllerGroup contains the controllers that scrutineers use to manage the marks of rounds
+var ScrutineerScoresheetControllerGroup = util.DasControllerGroup{
+	Controllers: []util.DasController{
+		openRoundController,
+		enterMarksController,
+		getMarksController,
+		lockRoundController,
+		computeRoundResultController,
+	},
+}
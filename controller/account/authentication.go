@@ -0,0 +1,105 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"gopkg.in/validator.v2"
+	"log"
+	"net/http"
+)
+
+// LocalAuthenticationServer is a virtual server that handles sign up, login, and sessions of accounts when DAS is the
+// identity provider
+type LocalAuthenticationServer struct {
+	strategy *local.LocalAuthenticationStrategy
+	roleRepo businesslogic.IAccountRoleRepository
+}
+
+func NewLocalAuthenticationServer(strategy *local.LocalAuthenticationStrategy, roleRepo businesslogic.IAccountRoleRepository) LocalAuthenticationServer {
+	return LocalAuthenticationServer{
+		strategy: strategy,
+		roleRepo: roleRepo,
+	}
+}
+
+// SignUpHandler handles the request:
+//	POST /api/v1.0/auth/signup
+// The account is created with the password and the default athlete role, and is signed in.
+func (server LocalAuthenticationServer) SignUpHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.SignUpDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := validator.Validate(dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	account := dto.ToAccountModel()
+	if err := account.MeetMinimalRequirement(); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err := server.strategy.SignUp(&account, dto.Password); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	defaultRole := businesslogic.NewAccountRole(account, businesslogic.AccountTypeAthlete)
+	if err := server.roleRepo.CreateAccountRole(&defaultRole); err != nil {
+		log.Printf("[error] creating user default role: %v", err)
+		util.RespondJsonResult(w, http.StatusInternalServerError, "Error in creating user's role", nil)
+		return
+	}
+	pair, err := server.strategy.Login(dto.Email, dto.Password)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "User is registered in DAS successfully", viewmodel.TokenPairToViewModel(pair))
+}
+
+// LoginHandler handles the request:
+//	POST /api/v1.0/auth/login
+func (server LocalAuthenticationServer) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.LoginDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	pair, err := server.strategy.Login(dto.Email, dto.Password)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "authorized", viewmodel.TokenPairToViewModel(pair))
+}
+
+// RefreshHandler handles the request:
+//	POST /api/v1.0/auth/refresh
+// The refresh token can only be used once, and the new refresh token must be used next time.
+func (server LocalAuthenticationServer) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.RefreshTokenDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	pair, err := server.strategy.Refresh(dto.RefreshToken)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "authorized", viewmodel.TokenPairToViewModel(pair))
+}
+
+// LogoutHandler handles the request:
+//	POST /api/v1.0/auth/logout
+// The session of the access token in the header is revoked.
+func (server LocalAuthenticationServer) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if err := server.strategy.Logout(r); err != nil {
+		util.RespondJsonResult(w, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "logged out", nil)
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// PaymentServer is a virtual server that handles requests of users who pay for orders and registrations
+type PaymentServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.PaymentService
+}
+
+func NewPaymentServer(authentication auth.IAuthenticationStrategy, service businesslogic.PaymentService) PaymentServer {
+	return PaymentServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// StartCheckoutHandler handles the request:
+//	POST /api/v1.0/account/payment/checkout
+func (server PaymentServer) StartCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.StartCheckoutDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	payment, session, err := server.service.StartCheckout(currentUser, dto.Purpose, dto.Reference, dto.SuccessURL, dto.CancelURL)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "checkout is created", viewmodel.CheckoutViewModel{
+		PaymentID: payment.ID,
+		SessionID: session.ID,
+		URL:       session.URL,
+	})
+}
+
+// SearchPaymentHandler handles the request:
+//	GET /api/v1.0/account/payment?competitionId=1
+func (server PaymentServer) SearchPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	criteria := new(businesslogic.SearchPaymentCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	payments, err := server.service.SearchOwnPayments(currentUser, *criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	output := make([]viewmodel.PaymentViewModel, 0)
+	for _, each := range payments {
+		output = append(output, viewmodel.PaymentDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
@@ -19,8 +19,8 @@ type UserPreferenceServer struct {
 // GetUserPreferenceHandler handles the request
 //	GET /api/v1.0/account/preference
 func (server UserPreferenceServer) GetUserPreferenceHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, userErr := server.GetCurrentUser(r)
-	if userErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
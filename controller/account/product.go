@@ -0,0 +1,65 @@
+package account
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// ProductOrderServer is a virtual server that handles requests of users who order competition products
+type ProductOrderServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionProductService
+}
+
+func NewProductOrderServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionProductService) ProductOrderServer {
+	return ProductOrderServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// PlaceOrderHandler handles the request:
+//	POST /api/v1.0/account/product/order
+func (server ProductOrderServer) PlaceOrderHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.PlaceProductOrderDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	order, err := server.service.PlaceOrder(currentUser, dto.ProductID, dto.Quantity)
+	if err == businesslogic.ProductSoldOutError {
+		util.RespondJsonResult(w, http.StatusConflict, err.Error(), nil)
+		return
+	} else if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "order is placed", viewmodel.ProductOrderDataModelToViewModel(order))
+}
+
+// SearchOrderHandler handles the request:
+//	GET /api/v1.0/account/product/order?competitionId=1
+func (server ProductOrderServer) SearchOrderHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	criteria := new(businesslogic.SearchProductOrderCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	orders, err := server.service.SearchOwnOrders(currentUser, *criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	output := make([]viewmodel.ProductOrderViewModel, 0)
+	for _, each := range orders {
+		output = append(output, viewmodel.ProductOrderDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
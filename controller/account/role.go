@@ -38,8 +38,8 @@ func NewRoleApplicationServer(authStrat auth.IAuthenticationStrategy, service bu
 //		"data": null
 //	}
 func (server RoleApplicationServer) CreateRoleApplicationHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, authErr := server.auth.GetCurrentUser(r)
-	if authErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
@@ -98,8 +98,8 @@ func (server RoleApplicationServer) GetAllApplicationStatus(w http.ResponseWrite
 // SearchRoleApplicationHandler handles the request:
 //	GET /api/v1.0/account/role/application
 func (server RoleApplicationServer) SearchRoleApplicationHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, userErr := server.auth.GetCurrentUser(r)
-	if userErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
@@ -140,8 +140,8 @@ func (server RoleApplicationServer) SearchRoleApplicationHandler(w http.Response
 //	GET /api/v1/admin/role/application
 // This will return all role applications to the admin user. This handler is for admin uses only and should enforce role check
 func (server RoleApplicationServer) AdminGetRoleApplicationHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, userErr := server.auth.GetCurrentUser(r)
-	if userErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
@@ -185,8 +185,8 @@ func (server RoleApplicationServer) AdminGetRoleApplicationHandler(w http.Respon
 // ProvisionRoleApplicationHandler handles the request:
 //	PUT /api/v1.o/account/role/provision
 func (server RoleApplicationServer) ProvisionRoleApplicationHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, userErr := server.auth.GetCurrentUser(r)
-	if userErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
@@ -232,8 +232,8 @@ type RoleServer struct {
 
 // GetAccountRoles get the roles of current user
 func (server RoleServer) GetAccountRolesHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, userErr := server.Auth.GetCurrentUser(r)
-	if userErr != nil {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		util.RespondJsonResult(w, http.StatusUnauthorized, "Invalid token", nil)
 		return
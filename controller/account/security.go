@@ -1,14 +1,101 @@
 package account
 
 import (
+	"github.com/DancesportSoftware/das/auth/local"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"gopkg.in/validator.v2"
+	"log"
 	"net/http"
 )
 
+// AccountSecurityServer is a virtual server that handles requests of users who manage the passwords of their accounts
 type AccountSecurityServer struct {
+	strategy *local.LocalAuthenticationStrategy
+	service  businesslogic.AccountSecurityService
+}
+
+func NewAccountSecurityServer(strategy *local.LocalAuthenticationStrategy, service businesslogic.AccountSecurityService) AccountSecurityServer {
+	return AccountSecurityServer{
+		strategy: strategy,
+		service:  service,
+	}
 }
 
 // UpdatePasswordHandler handles the request
 //	PUT /api/v1.0/account/password
+// Other sessions of the current user are ended, and the session of the request stays signed in.
 func (server AccountSecurityServer) UpdatePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.ChangePasswordDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := validator.Validate(dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.strategy.ChangePassword(r, dto.CurrentPassword, dto.NewPassword); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "password is updated", nil)
+}
+
+// ForgotPasswordHandler handles the request
+//	POST /api/v1.0/account/password/forgot
+// The response is the same whether or not the email belongs to an account.
+func (server AccountSecurityServer) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.ForgotPasswordDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := validator.Validate(dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.service.RequestPasswordReset(dto.Email); err != nil {
+		log.Printf("[error] requesting password reset: %v", err)
+	}
+	util.RespondJsonResult(w, http.StatusOK, "if the email belongs to an account, a link to reset the password is sent to it", nil)
+}
+
+// ResetPasswordHandler handles the request
+//	POST /api/v1.0/account/password/reset
+func (server AccountSecurityServer) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.ResetPasswordDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := validator.Validate(dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.service.ResetPassword(dto.Token, dto.NewPassword); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "password is reset", nil)
+}
 
+// UnlockAccountHandler handles the request
+//	POST /api/v1.0/account/unlock
+func (server AccountSecurityServer) UnlockAccountHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.UnlockAccountDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := validator.Validate(dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.service.UnlockAccount(dto.Token); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "account is unlocked", nil)
 }
@@ -0,0 +1,64 @@
+package adjudicator
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// AdjudicatorMarkingServer is a virtual server that handles requests of adjudicators to mark rounds electronically
+type AdjudicatorMarkingServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.AdjudicatorService
+}
+
+func NewAdjudicatorMarkingServer(authentication auth.IAuthenticationStrategy, service businesslogic.AdjudicatorService) AdjudicatorMarkingServer {
+	return AdjudicatorMarkingServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// GetHeatListHandler handles the request:
+//	GET /api/v1.0/adjudicator/round/heatlist
+// Only rounds that are open for marking are returned.
+func (server AdjudicatorMarkingServer) GetHeatListHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	heatLists, err := server.service.GetCurrentHeatLists(account)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+
+	output := make([]viewmodel.AdjudicatorHeatListDTO, 0)
+	for _, each := range heatLists {
+		output = append(output, viewmodel.AdjudicatorHeatListToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// SubmitMarksHandler handles the request:
+//	PUT /api/v1.0/adjudicator/round/marks
+// Marks of each dance can only be submitted once. Submitted marks can only be corrected by the scrutineer.
+func (server AdjudicatorMarkingServer) SubmitMarksHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.JudgeMarksDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	err := server.service.SubmitMarks(account, dto.ToJudgeMarksSubmission())
+	switch err {
+	case nil:
+		util.RespondJsonResult(w, http.StatusOK, "marks are submitted", nil)
+	case businesslogic.NotActiveCompetitionOfficialError, businesslogic.NotAssignedAdjudicatorError:
+		util.RespondJsonResult(w, http.StatusUnauthorized, err.Error(), nil)
+	case businesslogic.ScoresheetLockedError, businesslogic.MarksAlreadySubmittedError:
+		util.RespondJsonResult(w, http.StatusConflict, err.Error(), nil)
+	default:
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+	}
+}
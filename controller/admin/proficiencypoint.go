@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// ProficiencyPointOverrideServer handles requests of administrators who review the proficiency points of athletes and
+// override point-outs
+type ProficiencyPointOverrideServer struct {
+	auth        auth.IAuthenticationStrategy
+	accountRepo businesslogic.IAccountRepository
+	service     businesslogic.ProficiencyPointService
+}
+
+func NewProficiencyPointOverrideServer(authentication auth.IAuthenticationStrategy, accountRepo businesslogic.IAccountRepository, service businesslogic.ProficiencyPointService) ProficiencyPointOverrideServer {
+	return ProficiencyPointOverrideServer{
+		auth:        authentication,
+		accountRepo: accountRepo,
+		service:     service,
+	}
+}
+
+// GetAthleteProficiencyPointsHandler handles the request:
+//	GET /api/v1.0/admin/proficiency/point?athlete=uid
+func (server ProficiencyPointOverrideServer) GetAthleteProficiencyPointsHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.SearchAthleteProficiencyPointDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	athlete := businesslogic.GetAccountByUUID(dto.AthleteID, server.accountRepo)
+	if athlete.ID == 0 {
+		util.RespondJsonResult(w, http.StatusNotFound, "athlete does not exist", nil)
+		return
+	}
+
+	points, err := server.service.GetAthletePoints(athlete.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	summaries, err := server.service.GetPointSummary(athlete.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.AthleteProficiencyPointDataModelToViewModel(points, summaries))
+}
+
+// GrantOverrideHandler handles the request:
+//	POST /api/v1.0/admin/proficiency/override
+func (server ProficiencyPointOverrideServer) GrantOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.GrantProficiencyPointOverrideDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	athlete := businesslogic.GetAccountByUUID(dto.AthleteID, server.accountRepo)
+	if athlete.ID == 0 {
+		util.RespondJsonResult(w, http.StatusNotFound, "athlete does not exist", nil)
+		return
+	}
+
+	override := businesslogic.ProficiencyPointOverride{
+		AthleteID:     athlete.ID,
+		StyleID:       dto.StyleID,
+		ProficiencyID: dto.ProficiencyID,
+		Reason:        dto.Reason,
+	}
+	if err := server.service.GrantOverride(currentUser, &override); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "override is granted", viewmodel.ProficiencyPointOverrideDataModelToViewModel(override))
+}
+
+// RevokeOverrideHandler handles the request:
+//	DELETE /api/v1.0/admin/proficiency/override
+func (server ProficiencyPointOverrideServer) RevokeOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.RevokeProficiencyPointOverrideDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.service.RevokeOverride(currentUser, dto.OverrideID, dto.Note); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "override is revoked", nil)
+}
+
+// GetOverrideHistoryHandler handles the request:
+//	GET /api/v1.0/admin/proficiency/override/history?athlete=uid
+func (server ProficiencyPointOverrideServer) GetOverrideHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.SearchAthleteProficiencyPointDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	athlete := businesslogic.GetAccountByUUID(dto.AthleteID, server.accountRepo)
+	if athlete.ID == 0 {
+		util.RespondJsonResult(w, http.StatusNotFound, "athlete does not exist", nil)
+		return
+	}
+
+	history, err := server.service.SearchOverrideHistory(currentUser, businesslogic.SearchProficiencyPointOverrideHistoryCriteria{AthleteID: athlete.ID})
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.ProficiencyPointOverrideHistoryViewModel, 0)
+	for _, each := range history {
+		output = append(output, viewmodel.ProficiencyPointOverrideHistoryDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
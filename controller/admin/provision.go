@@ -28,7 +28,7 @@ func NewOrganizerProvisionServer(strategy auth.IAuthenticationStrategy, accountR
 //	PUT /api/admin/organizer/organizer
 // which allocates organizer competition slot for hosting competitions
 func (server OrganizerProvisionServer) UpdateOrganizerProvisionHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 
 	updateDTO := new(viewmodel.UpdateProvision)
 	parseErr := util.ParseRequestBodyData(r, updateDTO)
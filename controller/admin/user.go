@@ -23,7 +23,7 @@ func NewAdminUserManagementServer(auth auth.IAuthenticationStrategy, accountRepo
 
 // GET /api/v1/admin/user
 func (server AdminUserManagementServer) SearchUserHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	if !currentUser.HasRole(businesslogic.AccountTypeAdministrator) {
 		util.RespondJsonResult(w, http.StatusBadRequest, "Not authorized to search user accounts", nil)
 		return
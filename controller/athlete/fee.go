@@ -0,0 +1,65 @@
+package athlete
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// AthleteFeeServer handles requests of athletes who check the registration fees of competitions
+type AthleteFeeServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.RegistrationFeeService
+}
+
+func NewAthleteFeeServer(authentication auth.IAuthenticationStrategy, service businesslogic.RegistrationFeeService) AthleteFeeServer {
+	return AthleteFeeServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// GetFeeScheduleHandler handles the request:
+//	GET /api/v1.0/competition/fee?competition=1
+func (server AthleteFeeServer) GetFeeScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	criteria := new(businesslogic.SearchCompetitionFeeScheduleCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	schedule, err := server.service.GetFeeSchedule(criteria.CompetitionID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.CompetitionFeeScheduleDataModelToViewModel(schedule))
+}
+
+// GetInvoiceHandler handles the request:
+//	GET /api/v1.0/athlete/competition/invoice?competition=1
+//	GET /api/v1.0/athlete/competition/invoice?competition=1&partnership=3
+// which returns the invoice of the current user, or the invoice of a partnership of the current user
+func (server AthleteFeeServer) GetInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.SearchInvoiceDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	var invoice businesslogic.Invoice
+	var err error
+	if dto.PartnershipID > 0 {
+		invoice, err = server.service.GetOwnPartnershipInvoice(currentUser, dto.CompetitionID, dto.PartnershipID)
+	} else {
+		invoice, err = server.service.GetAthleteInvoice(dto.CompetitionID, currentUser.ID)
+	}
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.InvoiceDataModelToViewModel(invoice))
+}
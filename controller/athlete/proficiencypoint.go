@@ -0,0 +1,40 @@
+package athlete
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// AthleteProficiencyPointServer handles requests of athletes who check their collegiate proficiency points
+type AthleteProficiencyPointServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.ProficiencyPointService
+}
+
+func NewAthleteProficiencyPointServer(authentication auth.IAuthenticationStrategy, service businesslogic.ProficiencyPointService) AthleteProficiencyPointServer {
+	return AthleteProficiencyPointServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// GetProficiencyPointsHandler handles the request:
+//	GET /api/v1.0/athlete/proficiency/point
+// which returns the points of the current user and the levels that the user has pointed out of
+func (server AthleteProficiencyPointServer) GetProficiencyPointsHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	points, err := server.service.GetAthletePoints(currentUser.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	summaries, err := server.service.GetPointSummary(currentUser.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.AthleteProficiencyPointDataModelToViewModel(points, summaries))
+}
@@ -81,7 +81,7 @@ func (server CompetitionRegistrationServer) prepareRegistrationForm(dto viewmode
 // This DasController is for athlete use only. Organizer will have to use a different DasController
 func (server CompetitionRegistrationServer) CreateAthleteRegistrationHandler(w http.ResponseWriter, r *http.Request) {
 	// validate identity first
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 
 	registrationDTO := new(viewmodel.AthleteCompetitionRegistrationForm)
 	if parseErr := util.ParseRequestBodyData(r, registrationDTO); parseErr != nil {
@@ -98,6 +98,10 @@ func (server CompetitionRegistrationServer) CreateAthleteRegistrationHandler(w h
 	validationErr := server.Service.CreateAndUpdateRegistration(account, form)
 
 	// if registration is not valid, return error
+	if eligibilityErr, ok := validationErr.(businesslogic.EventEligibilityError); ok {
+		util.RespondJsonResult(w, http.StatusBadRequest, "partnership is not eligible for some events", viewmodel.EligibilityErrorToViewModel(eligibilityErr))
+		return
+	}
 	if validationErr != nil {
 		util.RespondJsonResult(w, http.StatusBadRequest, validationErr.Error(), nil)
 		return
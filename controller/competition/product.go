@@ -0,0 +1,56 @@
+package competition
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// PublicProductServer is a virtual server that handles public requests of browsing the products of competitions
+type PublicProductServer struct {
+	service businesslogic.CompetitionProductService
+}
+
+func NewPublicProductServer(service businesslogic.CompetitionProductService) PublicProductServer {
+	return PublicProductServer{
+		service: service,
+	}
+}
+
+// SearchProductHandler handles the request:
+//	GET /api/v1.0/competition/product?competitionId=1&category=2
+// Only the products that are available for order are returned.
+func (server PublicProductServer) SearchProductHandler(w http.ResponseWriter, r *http.Request) {
+	criteria := new(businesslogic.SearchCompetitionProductCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	products, err := server.service.SearchProducts(*criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	output := make([]viewmodel.CompetitionProductViewModel, 0)
+	for _, each := range products {
+		output = append(output, viewmodel.CompetitionProductDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// GetProductCategoryHandler handles the request:
+//	GET /api/v1.0/competition/product/category
+func (server PublicProductServer) GetProductCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	categories, err := server.service.GetProductCategories()
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	output := make([]viewmodel.ProductCategoryViewModel, 0)
+	for _, each := range categories {
+		output = append(output, viewmodel.ProductCategoryDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
@@ -38,7 +38,7 @@ func (server OrganizerCompetitionServer) OrganizerCreateCompetitionHandler(w htt
 		return
 	}
 
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 	competition := createDTO.ToCompetitionDataModel(account)
 
 	err := businesslogic.CreateCompetition(competition, server.ICompetitionRepository, server.IOrganizerProvisionRepository, server.IOrganizerProvisionHistoryRepository)
@@ -56,7 +56,7 @@ func (server OrganizerCompetitionServer) OrganizerSearchCompetitionHandler(w htt
 	if parseErr := util.ParseRequestData(r, searchDTO); parseErr != nil {
 		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, parseErr.Error())
 	} else {
-		account, _ := server.GetCurrentUser(r)
+		account, _ := auth.CurrentUser(r.Context())
 		if account.ID == 0 ||
 			(!account.HasRole(businesslogic.AccountTypeOrganizer) &&
 				!account.HasRole(businesslogic.AccountTypeAdministrator)) {
@@ -88,7 +88,7 @@ func (server OrganizerCompetitionServer) OrganizerSearchCompetitionHandler(w htt
 
 // PUT /api/organizer/competition
 func (server OrganizerCompetitionServer) OrganizerUpdateCompetitionHandler(w http.ResponseWriter, r *http.Request) {
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 	updateDTO := new(businesslogic.OrganizerUpdateCompetition)
 
 	if parseErr := util.ParseRequestBodyData(r, updateDTO); parseErr != nil {
@@ -96,66 +96,56 @@ func (server OrganizerCompetitionServer) OrganizerUpdateCompetitionHandler(w htt
 		return
 	}
 
-	competitions, _ := server.SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: updateDTO.CompetitionID})
-	if len(competitions) != 1 {
-		util.RespondJsonResult(w, http.StatusNotFound, "competition does not exist", nil)
-		return
+	// the policy of the controller allows only the organizers of the competition to update it
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	if updateDTO.Name != "" {
+		competition.Name = updateDTO.Name
 	}
+	if updateDTO.Website != "" {
+		competition.Website = updateDTO.Website
+	}
+	if updateDTO.FederationID != 0 {
+		competition.FederationID = updateDTO.FederationID
+	}
+	if updateDTO.CountryID != 0 {
+		competition.Country.ID = updateDTO.CountryID
+	}
+	if updateDTO.StateID != 0 {
+		competition.State.ID = updateDTO.StateID
+	}
+	if updateDTO.CityID != 0 {
+		competition.City.ID = updateDTO.CityID
 
-	// TODO: refactor this logic into businesslogic in the future.
-	if competitions[0].CreateUserID == account.ID {
-		if updateDTO.Name != "" {
-			competitions[0].Name = updateDTO.Name
-		}
-		if updateDTO.Website != "" {
-			competitions[0].Website = updateDTO.Website
-		}
-		if updateDTO.FederationID != 0 {
-			competitions[0].FederationID = updateDTO.FederationID
-		}
-		if updateDTO.CountryID != 0 {
-			competitions[0].Country.ID = updateDTO.CountryID
-		}
-		if updateDTO.StateID != 0 {
-			competitions[0].State.ID = updateDTO.StateID
-		}
-		if updateDTO.CityID != 0 {
-			competitions[0].City.ID = updateDTO.CityID
-
-		}
-		if updateDTO.Address != "" {
-			competitions[0].Street = updateDTO.Address
-		}
-		if updateDTO.Status != 0 {
-			statusErr := competitions[0].UpdateStatus(updateDTO.Status) // TODO; error prone
-			if statusErr != nil {
-				util.RespondJsonResult(w, http.StatusBadRequest, "invalid competition status change", nil)
-				return
-			}
-		}
-		if updateDTO.ContactEmail != "" {
-			competitions[0].ContactEmail = updateDTO.ContactEmail
-		}
-		if updateDTO.ContactPhone != "" {
-			competitions[0].ContactPhone = updateDTO.ContactPhone
-		}
-		if updateDTO.ContactName != "" {
-			competitions[0].ContactName = updateDTO.ContactPhone
-		}
-		if !updateDTO.StartDate.Equal(time.Time{}) {
-			competitions[0].StartDateTime = updateDTO.StartDate
-		}
-		if !updateDTO.EndDate.Equal(time.Time{}) {
-			competitions[0].EndDateTime = updateDTO.EndDate
+	}
+	if updateDTO.Address != "" {
+		competition.Street = updateDTO.Address
+	}
+	if updateDTO.Status != 0 {
+		statusErr := competition.UpdateStatus(updateDTO.Status) // TODO; error prone
+		if statusErr != nil {
+			util.RespondJsonResult(w, http.StatusBadRequest, "invalid competition status change", nil)
+			return
 		}
-		competitions[0].DateTimeUpdated = time.Now()
-		competitions[0].UpdateUserID = account.ID
-	} else {
-		util.RespondJsonResult(w, http.StatusUnauthorized, "not authorized to make changes to this competition", nil)
-		return
 	}
+	if updateDTO.ContactEmail != "" {
+		competition.ContactEmail = updateDTO.ContactEmail
+	}
+	if updateDTO.ContactPhone != "" {
+		competition.ContactPhone = updateDTO.ContactPhone
+	}
+	if updateDTO.ContactName != "" {
+		competition.ContactName = updateDTO.ContactPhone
+	}
+	if !updateDTO.StartDate.Equal(time.Time{}) {
+		competition.StartDateTime = updateDTO.StartDate
+	}
+	if !updateDTO.EndDate.Equal(time.Time{}) {
+		competition.EndDateTime = updateDTO.EndDate
+	}
+	competition.DateTimeUpdated = time.Now()
+	competition.UpdateUserID = account.ID
 
-	if updateErr := server.UpdateCompetition(competitions[0]); updateErr != nil {
+	if updateErr := server.UpdateCompetition(competition); updateErr != nil {
 		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, updateErr.Error())
 		return
 	}
@@ -21,8 +21,8 @@ type OrganizerCompetitionOfficialSearchServer struct {
 //
 // - Authorization: Organizer only
 func (server OrganizerCompetitionOfficialSearchServer) SearchEligibleOfficialHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, err := server.IAuthenticationStrategy.GetCurrentUser(r)
-	if err != nil || !currentUser.HasRole(businesslogic.AccountTypeOrganizer) {
+	currentUser, ok := auth.CurrentUser(r.Context())
+	if !ok || !currentUser.HasRole(businesslogic.AccountTypeOrganizer) {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "Not authorized", nil)
 		return
 	}
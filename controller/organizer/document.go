@@ -0,0 +1,70 @@
+package organizer
+
+import (
+	"fmt"
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"log"
+	"net/http"
+)
+
+// OrganizerDocumentServer is a virtual server that handles requests of organizers who print documents of their
+// competitions
+type OrganizerDocumentServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionDocumentService
+}
+
+func NewOrganizerDocumentServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionDocumentService) OrganizerDocumentServer {
+	return OrganizerDocumentServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+func (server OrganizerDocumentServer) respondDocument(w http.ResponseWriter, r *http.Request, name string,
+	render func(competition businesslogic.Competition, dto viewmodel.SearchCompetitionDocumentDTO) ([]byte, error)) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.SearchCompetitionDocumentDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	document, err := render(competition, *dto)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", server.service.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"competition-%d-%s.pdf\"", competition.ID, name))
+	if _, err := w.Write(document); err != nil {
+		log.Printf("[error] writing %s of competition %d: %v", name, competition.ID, err)
+	}
+}
+
+// BackNumbersHandler handles the request:
+//	GET /api/v1.0/organizer/competition/document/backnumber?competition=1
+func (server OrganizerDocumentServer) BackNumbersHandler(w http.ResponseWriter, r *http.Request) {
+	server.respondDocument(w, r, "back-numbers", func(competition businesslogic.Competition, dto viewmodel.SearchCompetitionDocumentDTO) ([]byte, error) {
+		return server.service.BackNumbers(competition)
+	})
+}
+
+// HeatSheetHandler handles the request:
+//	GET /api/v1.0/organizer/competition/document/heatsheet?competition=1&event=5
+func (server OrganizerDocumentServer) HeatSheetHandler(w http.ResponseWriter, r *http.Request) {
+	server.respondDocument(w, r, "heat-sheets", func(competition businesslogic.Competition, dto viewmodel.SearchCompetitionDocumentDTO) ([]byte, error) {
+		return server.service.HeatSheets(competition, dto.EventID)
+	})
+}
+
+// SchoolEntrySummaryHandler handles the request:
+//	GET /api/v1.0/organizer/competition/document/school?competition=1&school=2
+func (server OrganizerDocumentServer) SchoolEntrySummaryHandler(w http.ResponseWriter, r *http.Request) {
+	server.respondDocument(w, r, "school-entries", func(competition businesslogic.Competition, dto viewmodel.SearchCompetitionDocumentDTO) ([]byte, error) {
+		return server.service.SchoolEntrySummaries(competition, dto.SchoolID)
+	})
+}
@@ -21,7 +21,7 @@ type OrganizerEventServer struct {
 // CreateEventHandler handles the request:
 //	POST /api/v1.0/organizer/event
 func (server OrganizerEventServer) CreateEventHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.Authentication.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	createDTO := new(viewmodel.CreateEventForm)
 
 	if parseErr := util.ParseRequestBodyData(r, createDTO); parseErr != nil {
@@ -29,8 +29,9 @@ func (server OrganizerEventServer) CreateEventHandler(w http.ResponseWriter, r *
 		return
 	}
 
+	competition, _ := util.AuthorizedCompetition(r.Context())
 	event := createDTO.ToDomainModel(currentUser)
-	if err := server.Service.CreateEvent(event); err != nil {
+	if err := server.Service.CreateEvent(competition, event); err != nil {
 		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
@@ -46,7 +47,7 @@ func (server OrganizerEventServer) UpdateEventHandler(w http.ResponseWriter, r *
 // DeleteEventHandler handles the request:
 //	DELETE /api/v1.0/organizer/event
 func (server OrganizerEventServer) DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.Authentication.GetCurrentUser(r)
+	competition, _ := util.AuthorizedCompetition(r.Context())
 	deleteDTO := new(viewmodel.DeleteEventForm)
 
 	if parseErr := util.ParseRequestBodyData(r, deleteDTO); parseErr != nil {
@@ -56,7 +57,10 @@ func (server OrganizerEventServer) DeleteEventHandler(w http.ResponseWriter, r *
 		util.RespondJsonResult(w, http.StatusBadRequest, validErr.Error(), nil)
 		return
 	} else {
-		events, searchErr := server.Service.SearchEvents(businesslogic.SearchEventCriteria{EventID: deleteDTO.ID})
+		events, searchErr := server.Service.SearchEvents(businesslogic.SearchEventCriteria{
+			CompetitionID: competition.ID,
+			EventID:       deleteDTO.ID,
+		})
 		if searchErr != nil {
 			util.RespondJsonResult(w, http.StatusInternalServerError, searchErr.Error(), nil)
 			return
@@ -65,7 +69,7 @@ func (server OrganizerEventServer) DeleteEventHandler(w http.ResponseWriter, r *
 			util.RespondJsonResult(w, http.StatusNotFound, "event with this ID does not exist ", nil)
 			return
 		}
-		deletionErr := server.Service.DeleteEvent(events[0], currentUser)
+		deletionErr := server.Service.DeleteEvent(competition, events[0])
 		if deletionErr != nil {
 			util.RespondJsonResult(w, http.StatusInternalServerError, deletionErr.Error(), nil)
 			return
@@ -78,7 +82,7 @@ func (server OrganizerEventServer) DeleteEventHandler(w http.ResponseWriter, r *
 // SearchEventHandler handles the request:
 //	GET /api/v1.0/organizer/event
 func (server OrganizerEventServer) SearchEventHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.Authentication.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	searchCriteriaDTO := new(viewmodel.OrganizerSearchEventCriteria)
 
 	if parseErr := util.ParseRequestData(r, searchCriteriaDTO); parseErr != nil {
@@ -86,7 +90,14 @@ func (server OrganizerEventServer) SearchEventHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	searchCriteriaDTO.OrganizerID = currentUser.ID
+	// the policy is only checked when the request specifies a competition, otherwise all events that the organizer
+	// created are searched
+	if competition, ok := util.AuthorizedCompetition(r.Context()); ok {
+		searchCriteriaDTO.CompetitionID = competition.ID
+		searchCriteriaDTO.OrganizerID = 0
+	} else {
+		searchCriteriaDTO.OrganizerID = currentUser.ID
+	}
 	criteria := searchCriteriaDTO.ToBusinessModel()
 
 	events, searchErr := server.Service.SearchEvents(criteria)
@@ -109,7 +120,7 @@ func (server OrganizerEventServer) SearchEventHandler(w http.ResponseWriter, r *
 // SearchCompetitionEventTemplateHandler handles the request:
 //	GET /api/v1/organizer/event/template
 func (server OrganizerEventServer) SearchCompetitionEventTemplateHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.Authentication.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	searchCriteriaDTO := new(viewmodel.SearchCompetitionEventTemplateForm)
 
 	if parseErr := util.ParseRequestData(r, searchCriteriaDTO); parseErr != nil {
@@ -0,0 +1,68 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// OrganizerFeeServer is a virtual server that handles requests of organizers who charge registration fees at their
+// competitions
+type OrganizerFeeServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.RegistrationFeeService
+}
+
+func NewOrganizerFeeServer(authentication auth.IAuthenticationStrategy, service businesslogic.RegistrationFeeService) OrganizerFeeServer {
+	return OrganizerFeeServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// SaveFeeScheduleHandler handles the request:
+//	PUT /api/v1.0/organizer/competition/fee
+func (server OrganizerFeeServer) SaveFeeScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionFeeScheduleDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	schedule := dto.ToCompetitionFeeSchedule()
+	schedule.CompetitionID = competition.ID
+	if err := server.service.SaveFeeSchedule(currentUser, &schedule); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "fee schedule is saved", viewmodel.CompetitionFeeScheduleDataModelToViewModel(schedule))
+}
+
+// GetInvoiceHandler handles the request:
+//	GET /api/v1.0/organizer/competition/invoice?competition=1&athlete=2
+//	GET /api/v1.0/organizer/competition/invoice?competition=1&partnership=3
+func (server OrganizerFeeServer) GetInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.SearchInvoiceDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	var invoice businesslogic.Invoice
+	var err error
+	if dto.PartnershipID > 0 {
+		invoice, err = server.service.GetPartnershipInvoice(competition.ID, dto.PartnershipID)
+	} else {
+		invoice, err = server.service.GetAthleteInvoice(competition.ID, dto.AthleteID)
+	}
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.InvoiceDataModelToViewModel(invoice))
+}
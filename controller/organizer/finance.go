@@ -0,0 +1,57 @@
+package organizer
+
+import (
+	"fmt"
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"log"
+	"net/http"
+)
+
+// OrganizerFinanceServer is a virtual server that handles requests of organizers who check the finance of their
+// competitions
+type OrganizerFinanceServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionFinanceService
+}
+
+func NewOrganizerFinanceServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionFinanceService) OrganizerFinanceServer {
+	return OrganizerFinanceServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// GetFinanceReportHandler handles the request:
+//	GET /api/v1.0/organizer/competition/finance?competition=1
+//	GET /api/v1.0/organizer/competition/finance?competition=1&format=csv
+func (server OrganizerFinanceServer) GetFinanceReportHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.SearchFinanceReportDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if dto.Format != "" && dto.Format != "json" && dto.Format != "csv" {
+		util.RespondJsonResult(w, http.StatusBadRequest, "report can only be exported as json or csv", nil)
+		return
+	}
+
+	report, err := server.service.GetFinanceReport(competition.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	view := viewmodel.FinanceReportDataModelToViewModel(report)
+	if dto.Format != "csv" {
+		util.RespondJsonResult(w, http.StatusOK, "success", view)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"competition-%d-finance.csv\"", competition.ID))
+	if err := view.WriteCSV(w); err != nil {
+		log.Printf("[error] writing finance report of competition %d: %v", competition.ID, err)
+	}
+}
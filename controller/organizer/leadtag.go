@@ -2,13 +2,11 @@ package organizer
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/DancesportSoftware/das/auth"
 	"github.com/DancesportSoftware/das/businesslogic"
 	"github.com/DancesportSoftware/das/controller/util"
 	"github.com/DancesportSoftware/das/viewmodel"
 	"net/http"
-	"strconv"
 )
 
 // OrganizerLeadTagServer is a virtual server that handles request of searching and assigning lead tag
@@ -28,35 +26,157 @@ func NewOrganizerLeadTagServer(authenticaion auth.IAuthenticationStrategy, compR
 
 // GetAllLeadEntries returns all the leads at the specified competition
 func (server OrganizerLeadTagServer) GetAllLeadEntries(w http.ResponseWriter, r *http.Request) {
-	formErr := r.ParseForm()
-	if formErr != nil {
-		util.RespondJsonResult(w, http.StatusBadRequest, "bad form data", formErr)
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	entries, err := server.partnershipCompEntryService.GetAllLeadEntries(competition)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
 
-	compId, convErr := strconv.Atoi(r.FormValue("competitionId"))
-	if convErr != nil {
-		util.RespondJsonResult(w, http.StatusBadRequest, "invalid competition ID", nil)
+	output := make([]viewmodel.AthleteCompetitionEntryViewModel, 0)
+	for _, each := range entries {
+		output = append(output, viewmodel.AthleteCompetitionEntryToViewModel(each))
+	}
+
+	jsonResp, _ := json.Marshal(output)
+	w.Write(jsonResp)
+}
+
+// OrganizerLeadTagAssignmentServer is a virtual server that handles requests of organizers who number the tags of
+// leads at their competitions
+type OrganizerLeadTagAssignmentServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.LeadTagService
+}
+
+func NewOrganizerLeadTagAssignmentServer(authentication auth.IAuthenticationStrategy, service businesslogic.LeadTagService) OrganizerLeadTagAssignmentServer {
+	return OrganizerLeadTagAssignmentServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// GetLeadTagSettingsHandler handles the request:
+//	GET /api/v1.0/organizer/competition/leadtag/setting?competition=1
+func (server OrganizerLeadTagAssignmentServer) GetLeadTagSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	settings, err := server.service.GetSettings(competition.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.CompetitionLeadTagSettingsDataModelToViewModel(settings))
+}
+
+// SaveLeadTagSettingsHandler handles the request:
+//	PUT /api/v1.0/organizer/competition/leadtag/setting
+func (server OrganizerLeadTagAssignmentServer) SaveLeadTagSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionLeadTagSettingsViewModel)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
 		return
 	}
 
-	competitions, searchCompErr := server.compRepo.SearchCompetition(businesslogic.SearchCompetitionCriteria{ID: compId})
-	if searchCompErr != nil || len(competitions) != 1 {
-		util.RespondJsonResult(w, http.StatusBadRequest, fmt.Sprintf("cannot find competition with ID = %v", compId), nil)
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	settings := dto.ToCompetitionLeadTagSettings()
+	settings.CompetitionID = competition.ID
+	if err := server.service.SaveSettings(currentUser, &settings); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
+	util.RespondJsonResult(w, http.StatusOK, "lead tag settings are saved", viewmodel.CompetitionLeadTagSettingsDataModelToViewModel(settings))
+}
 
-	entries, err := server.partnershipCompEntryService.GetAllLeadEntries(competitions[0])
+// SearchLeadTagRangeHandler handles the request:
+//	GET /api/v1.0/organizer/competition/leadtag/range?competition=1
+func (server OrganizerLeadTagAssignmentServer) SearchLeadTagRangeHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	ranges, err := server.service.SearchRanges(competition.ID)
 	if err != nil {
-		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
+	output := make([]viewmodel.CompetitionLeadTagRangeViewModel, 0)
+	for _, each := range ranges {
+		output = append(output, viewmodel.CompetitionLeadTagRangeDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
 
-	output := make([]viewmodel.AthleteCompetitionEntryViewModel, 0)
-	for _, each := range entries {
-		output = append(output, viewmodel.AthleteCompetitionEntryToViewModel(each))
+// CreateLeadTagRangeHandler handles the request:
+//	POST /api/v1.0/organizer/competition/leadtag/range
+func (server OrganizerLeadTagAssignmentServer) CreateLeadTagRangeHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CreateLeadTagRangeDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
 	}
 
-	jsonResp, _ := json.Marshal(output)
-	w.Write(jsonResp)
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	tagRange := dto.ToCompetitionLeadTagRange()
+	tagRange.CompetitionID = competition.ID
+	if err := server.service.CreateRange(currentUser, &tagRange); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "lead tag range is created", viewmodel.CompetitionLeadTagRangeDataModelToViewModel(tagRange))
+}
+
+// DeleteLeadTagRangeHandler handles the request:
+//	DELETE /api/v1.0/organizer/competition/leadtag/range
+func (server OrganizerLeadTagAssignmentServer) DeleteLeadTagRangeHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.DeleteLeadTagRangeDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.DeleteRange(competition.ID, dto.RangeID); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "lead tag range is deleted", nil)
+}
+
+// SearchLeadTagHandler handles the request:
+//	GET /api/v1.0/organizer/competition/leadtag?competition=1
+func (server OrganizerLeadTagAssignmentServer) SearchLeadTagHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	tags, err := server.service.SearchLeadTags(competition.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.CompetitionLeadTagViewModel, 0)
+	for _, each := range tags {
+		output = append(output, viewmodel.CompetitionLeadTagDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// OverrideLeadTagHandler handles the request:
+//	PUT /api/v1.0/organizer/competition/leadtag
+// which assigns the tag to the lead regardless of the numbering of the competition
+func (server OrganizerLeadTagAssignmentServer) OverrideLeadTagHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.OverrideLeadTagDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	tag, err := server.service.OverrideLeadTag(currentUser, competition.ID, dto.LeadID, dto.Tag)
+	if err == businesslogic.LeadTagTakenError {
+		util.RespondJsonResult(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "lead tag is assigned", viewmodel.CompetitionLeadTagDataModelToViewModel(tag))
 }
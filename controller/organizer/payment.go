@@ -0,0 +1,135 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"io/ioutil"
+	"log"
+	"net/http"
+)
+
+// maxWebhookPayload is the largest webhook event that is accepted
+const maxWebhookPayload = 64 * 1024
+
+// OrganizerPaymentServer is a virtual server that handles requests of organizers who receive payments, and the webhook
+// events that their payment gateways send
+type OrganizerPaymentServer struct {
+	auth            auth.IAuthenticationStrategy
+	accountRepo     businesslogic.IAccountRepository
+	service         businesslogic.PaymentService
+	signatureHeader string
+}
+
+// NewOrganizerPaymentServer creates a server that reads the signature of webhook events from signatureHeader
+func NewOrganizerPaymentServer(authentication auth.IAuthenticationStrategy, accountRepo businesslogic.IAccountRepository, service businesslogic.PaymentService, signatureHeader string) OrganizerPaymentServer {
+	return OrganizerPaymentServer{
+		auth:            authentication,
+		accountRepo:     accountRepo,
+		service:         service,
+		signatureHeader: signatureHeader,
+	}
+}
+
+// GetStripeSettingsHandler handles the request:
+//	GET /api/v1.0/organizer/payment/stripe
+func (server OrganizerPaymentServer) GetStripeSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	settings, err := server.service.GetStripeSettings(currentUser)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.StripeSettingsDataModelToViewModel(settings))
+}
+
+// SaveStripeSettingsHandler handles the request:
+//	PUT /api/v1.0/organizer/payment/stripe
+func (server OrganizerPaymentServer) SaveStripeSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.StripeSettingsDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	if err := server.service.SaveStripeSettings(currentUser, dto.ToOrganizerStripeSettings()); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "payment settings are saved", nil)
+}
+
+// SearchPaymentHandler handles the request:
+//	GET /api/v1.0/organizer/payment?competitionId=1
+func (server OrganizerPaymentServer) SearchPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	criteria := new(businesslogic.SearchPaymentCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	criteria.CompetitionID = competition.ID
+	payments, err := server.service.SearchCompetitionPayments(*criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.PaymentViewModel, 0)
+	for _, each := range payments {
+		output = append(output, viewmodel.PaymentDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// RefundPaymentHandler handles the request:
+//	POST /api/v1.0/organizer/payment/refund
+func (server OrganizerPaymentServer) RefundPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.RefundPaymentDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	payment, err := server.service.RefundPayment(currentUser, competition.ID, dto.PaymentID, dto.Amount)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "payment is refunded", viewmodel.PaymentDataModelToViewModel(payment))
+}
+
+// WebhookHandler handles the request that the payment gateway of an organizer sends:
+//	POST /api/v1.0/organizer/payment/webhook?organizer=uid
+// The raw body is verified against the signature header, so it must not be parsed before it is verified.
+func (server OrganizerPaymentServer) WebhookHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.PaymentWebhookDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+	organizer := businesslogic.GetAccountByUUID(dto.OrganizerID, server.accountRepo)
+	if organizer.ID == 0 {
+		util.RespondJsonResult(w, http.StatusNotFound, "organizer does not exist", nil)
+		return
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayload))
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	err = server.service.HandleWebhook(organizer.ID, payload, r.Header.Get(server.signatureHeader))
+	if err == businesslogic.PaymentSignatureError {
+		util.RespondJsonResult(w, http.StatusUnauthorized, err.Error(), nil)
+		return
+	} else if err != nil {
+		log.Printf("[error] handling payment webhook of organizer %v: %v", organizer.ID, err)
+		util.RespondJsonResult(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", nil)
+}
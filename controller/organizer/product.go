@@ -0,0 +1,126 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// OrganizerProductServer is a virtual server that handles requests of organizers who sell products at their
+// competitions
+type OrganizerProductServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionProductService
+}
+
+func NewOrganizerProductServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionProductService) OrganizerProductServer {
+	return OrganizerProductServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// SearchProductHandler handles the request:
+//	GET /api/v1.0/organizer/competition/product?competitionId=1
+func (server OrganizerProductServer) SearchProductHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	criteria := new(businesslogic.SearchCompetitionProductCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	criteria.CompetitionID = competition.ID
+	products, err := server.service.SearchCompetitionProducts(*criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.CompetitionProductViewModel, 0)
+	for _, each := range products {
+		output = append(output, viewmodel.CompetitionProductDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// CreateProductHandler handles the request:
+//	POST /api/v1.0/organizer/competition/product
+func (server OrganizerProductServer) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionProductDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	product := dto.ToCompetitionProduct()
+	product.CompetitionID = competition.ID
+	if err := server.service.CreateProduct(currentUser, &product); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "product is created", viewmodel.CompetitionProductDataModelToViewModel(product))
+}
+
+// UpdateProductHandler handles the request:
+//	PUT /api/v1.0/organizer/competition/product
+func (server OrganizerProductServer) UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionProductDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	product := dto.ToCompetitionProduct()
+	product.CompetitionID = competition.ID
+	if err := server.service.UpdateProduct(currentUser, product); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "product is updated", nil)
+}
+
+// DeleteProductHandler handles the request:
+//	DELETE /api/v1.0/organizer/competition/product
+func (server OrganizerProductServer) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.DeleteCompetitionProductDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.DeleteProduct(competition.ID, dto.ProductID); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "product is deleted", nil)
+}
+
+// SearchOrderHandler handles the request:
+//	GET /api/v1.0/organizer/competition/product/order?competitionId=1&product=2
+func (server OrganizerProductServer) SearchOrderHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	criteria := new(businesslogic.SearchProductOrderCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	criteria.CompetitionID = competition.ID
+	orders, err := server.service.SearchCompetitionOrders(*criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.ProductOrderViewModel, 0)
+	for _, each := range orders {
+		output = append(output, viewmodel.ProductOrderDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
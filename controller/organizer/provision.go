@@ -19,7 +19,7 @@ type OrganizerProvisionServer struct {
 // GET /api/organizer/organizer/summary
 func (server OrganizerProvisionServer) GetOrganizerProvisionSummaryHandler(w http.ResponseWriter, r *http.Request) {
 
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 	if !account.HasRole(businesslogic.AccountTypeOrganizer) || account.ID == 0 {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "Access denied", nil)
 		return
@@ -51,7 +51,7 @@ type OrganizerProvisionHistoryServer struct {
 // GET /api/organizer/organizer/history
 func (server OrganizerProvisionHistoryServer) GetOrganizerProvisionHistoryHandler(w http.ResponseWriter, r *http.Request) {
 
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 	if !account.HasRole(businesslogic.AccountTypeOrganizer) && !account.HasRole(businesslogic.AccountTypeAdministrator) {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "Access denied", nil)
 		return
@@ -0,0 +1,103 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// OrganizerRoundServer is a virtual server that handles requests of creating rounds, advancing couples between rounds
+// and drawing the heats of rounds
+type OrganizerRoundServer struct {
+	auth        auth.IAuthenticationStrategy
+	service     businesslogic.RoundGenerationService
+	heatService businesslogic.HeatDrawService
+}
+
+func NewOrganizerRoundServer(authentication auth.IAuthenticationStrategy, service businesslogic.RoundGenerationService, heatService businesslogic.HeatDrawService) OrganizerRoundServer {
+	return OrganizerRoundServer{
+		auth:        authentication,
+		service:     service,
+		heatService: heatService,
+	}
+}
+
+// StartEventHandler handles the request:
+//	POST /api/v1.0/organizer/event/start
+func (server OrganizerRoundServer) StartEventHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.StartEventDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	rounds, err := server.service.StartEvent(currentUser, competition.ID, dto.EventID, dto.ToRoundGenerationSettings())
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.RoundViewModel, 0)
+	for _, each := range rounds {
+		output = append(output, viewmodel.RoundDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "event is started", output)
+}
+
+// AdvanceRecalledCouplesHandler handles the request:
+//	POST /api/v1.0/organizer/round/advance
+func (server OrganizerRoundServer) AdvanceRecalledCouplesHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.RoundDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	next, err := server.service.AdvanceRecalledCouples(currentUser, competition.ID, dto.RoundID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "recalled couples are advanced", viewmodel.RoundDataModelToViewModel(next))
+}
+
+// DrawHeatsHandler handles the request:
+//	POST /api/v1.0/organizer/round/heat
+func (server OrganizerRoundServer) DrawHeatsHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.DrawHeatsDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	draw, heats, err := server.heatService.DrawHeats(currentUser, competition.ID, dto.RoundID, dto.FloorCapacity, dto.Seed)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "heats are drawn", viewmodel.HeatDrawDataModelToViewModel(draw, heats))
+}
+
+// GetHeatsHandler handles the request:
+//	GET /api/v1.0/organizer/round/heat?round=1
+func (server OrganizerRoundServer) GetHeatsHandler(w http.ResponseWriter, r *http.Request) {
+	dto := new(viewmodel.RoundDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	draw, heats, err := server.heatService.GetHeats(dto.RoundID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.HeatDrawDataModelToViewModel(draw, heats))
+}
@@ -0,0 +1,103 @@
+package organizer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// OrganizerScheduleServer is a virtual server that handles requests of building the timetable of competitions
+type OrganizerScheduleServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionScheduleService
+}
+
+func NewOrganizerScheduleServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionScheduleService) OrganizerScheduleServer {
+	return OrganizerScheduleServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// BuildScheduleHandler handles the request:
+//	POST /api/v1.0/organizer/competition/schedule
+func (server OrganizerScheduleServer) BuildScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.BuildScheduleDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	schedule, err := server.service.BuildSchedule(currentUser, competition.ID, dto.ToCompetitionScheduleSettings())
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "schedule is built", viewmodel.CompetitionScheduleDataModelToViewModel(schedule))
+}
+
+// PublishScheduleHandler handles the request:
+//	POST /api/v1.0/organizer/competition/schedule/publish
+func (server OrganizerScheduleServer) PublishScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.PublishScheduleDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.PublishSchedule(currentUser, competition.ID); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "schedule is published", nil)
+}
+
+// GetScheduleHandler handles the request:
+//	GET /api/v1.0/organizer/competition/schedule?competitionId=1
+func (server OrganizerScheduleServer) GetScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.ScheduleSearchDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	schedule, err := server.service.GetSchedule(competition.ID)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusInternalServerError, util.HTTP500ErrorRetrievingData, err.Error())
+		return
+	}
+	output := make([]viewmodel.RoundScheduleViewModel, 0)
+	for _, each := range schedule {
+		output = append(output, viewmodel.RoundScheduleDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// GetAthleteConflictsHandler handles the request:
+//	GET /api/v1.0/organizer/competition/schedule/conflict?competitionId=1&minimumRest=10
+func (server OrganizerScheduleServer) GetAthleteConflictsHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.AthleteConflictSearchDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	conflicts, err := server.service.AnalyzeAthleteConflicts(competition.ID, dto.GetMinimumRest())
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.AthleteConflictViewModel, 0)
+	for _, each := range conflicts {
+		output = append(output, viewmodel.AthleteConflictDataModelToViewModel(each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
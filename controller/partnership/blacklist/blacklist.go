@@ -22,7 +22,7 @@ type PartnershipRequestBlacklistServer struct {
 
 // GET /api/partnership/blacklist
 func (server PartnershipRequestBlacklistServer) GetBlacklistedAccountHandler(w http.ResponseWriter, r *http.Request) {
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 
 	blacklist, err := account.GetBlacklistedAccounts(server.IAccountRepository, server.IPartnershipRequestBlacklistRepository)
 
@@ -21,7 +21,7 @@ type PartnershipServer struct {
 // SearchPartnershipHandler handles the request
 //	GET /api/partnership
 func (server PartnershipServer) SearchPartnershipHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	if currentUser.ID == 0 || !currentUser.HasRole(businesslogic.AccountTypeAthlete) {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "not authorized", nil)
 		return
@@ -50,7 +50,7 @@ type updatePartnership struct {
 
 // PUT /api/v1.0/athlete/partnership
 func (server PartnershipServer) UpdatePartnershipHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 	if currentUser.ID == 0 {
 		util.RespondJsonResult(w, http.StatusUnauthorized, "not authorized", nil)
 		return
@@ -31,7 +31,7 @@ func (server PartnershipRequestServer) CreatePartnershipRequestHandler(w http.Re
 		return
 	}
 
-	sender, _ := server.GetCurrentUser(r)
+	sender, _ := auth.CurrentUser(r.Context())
 	searchResults, err := server.SearchAccount(businesslogic.SearchAccountCriteria{Email: dto.RecipientEmail})
 	if len(searchResults) == 0 {
 		util.RespondJsonResult(w, http.StatusNotFound, util.Http404NoDataFound, nil)
@@ -83,7 +83,7 @@ func (server PartnershipRequestServer) CreatePartnershipRequestHandler(w http.Re
 // GET /api/partnership/request
 // Get a list of received partnership requests
 func (server PartnershipRequestServer) SearchPartnershipRequestHandler(w http.ResponseWriter, r *http.Request) {
-	account, _ := server.GetCurrentUser(r)
+	account, _ := auth.CurrentUser(r.Context())
 	criteria := new(businesslogic.SearchPartnershipRequestCriteria)
 	if parseErr := util.ParseRequestData(r, criteria); parseErr != nil {
 		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, parseErr.Error())
@@ -130,7 +130,7 @@ func (server PartnershipRequestServer) SearchPartnershipRequestHandler(w http.Re
 // UpdatePartnershipRequestHandler handles the request
 //	PUT /api/partnership/request
 func (server PartnershipRequestServer) UpdatePartnershipRequestHandler(w http.ResponseWriter, r *http.Request) {
-	currentUser, _ := server.GetCurrentUser(r)
+	currentUser, _ := auth.CurrentUser(r.Context())
 
 	respondDTO := new(viewmodel.PartnershipRequestResponse)
 	if parseErr := util.ParseRequestBodyData(r, respondDTO); parseErr != nil {
@@ -0,0 +1,150 @@
+package tba
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// CompetitionTBAServer serves requests of athletes who look for partners at competitions
+type CompetitionTBAServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.CompetitionTBAService
+}
+
+func NewCompetitionTBAServer(authentication auth.IAuthenticationStrategy, service businesslogic.CompetitionTBAService) CompetitionTBAServer {
+	return CompetitionTBAServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// SearchTBAEntryHandler handles the request:
+//	GET /api/v1.0/athlete/partnership/tba?competition=1&role=Follow&style=Latin
+func (server CompetitionTBAServer) SearchTBAEntryHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	criteria := new(businesslogic.SearchCompetitionTBAEntryCriteria)
+	if err := util.ParseRequestData(r, criteria); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	entries, err := server.service.SearchEntries(currentUser, *criteria)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.CompetitionTBAEntryViewModel, 0)
+	for _, each := range entries {
+		output = append(output, viewmodel.CompetitionTBAEntryDataModelToViewModel(currentUser, each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
+
+// CreateTBAEntryHandler handles the request:
+//	POST /api/v1.0/athlete/partnership/tba
+func (server CompetitionTBAServer) CreateTBAEntryHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionTBAEntryDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	entry := dto.ToCompetitionTBAEntry()
+	if err := server.service.PostEntry(currentUser, &entry); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "TBA entry is posted", viewmodel.CompetitionTBAEntryDataModelToViewModel(currentUser, entry))
+}
+
+// UpdateTBAEntryHandler handles the request:
+//	PUT /api/v1.0/athlete/partnership/tba
+func (server CompetitionTBAServer) UpdateTBAEntryHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.CompetitionTBAEntryDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.UpdateEntry(currentUser, dto.ToCompetitionTBAEntry()); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "TBA entry is updated", nil)
+}
+
+// DeleteTBAEntryHandler handles the request:
+//	DELETE /api/v1.0/athlete/partnership/tba
+func (server CompetitionTBAServer) DeleteTBAEntryHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.DeleteCompetitionTBAEntryDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.DeleteEntry(currentUser, dto.EntryID); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "TBA entry is deleted", nil)
+}
+
+// CreateTBAPartnershipRequestHandler handles the request:
+//	POST /api/v1.0/athlete/partnership/tba/request
+// which sends a partnership request to the athlete who posted the TBA entry
+func (server CompetitionTBAServer) CreateTBAPartnershipRequestHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	dto := new(viewmodel.TBAPartnershipRequestDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if _, err := server.service.SendPartnershipRequest(currentUser, dto.EntryID, dto.Message); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, "error in submitting partnership request", err.Error())
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", nil)
+}
+
+// PartnerSuggestionServer serves requests of athletes who look for suggestions of partners at competitions
+type PartnerSuggestionServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.PartnerSuggestionService
+}
+
+func NewPartnerSuggestionServer(authentication auth.IAuthenticationStrategy, service businesslogic.PartnerSuggestionService) PartnerSuggestionServer {
+	return PartnerSuggestionServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+// SuggestPartnerHandler handles the request:
+//	GET /api/v1.0/athlete/partnership/tba/suggestion?competition=1&sameSex=true&minHeightDifference=5&maxHeightDifference=15
+// which ranks the TBA entries of the competition by how well they match the TBA entry of the current user
+func (server PartnerSuggestionServer) SuggestPartnerHandler(w http.ResponseWriter, r *http.Request) {
+	currentUser, _ := auth.CurrentUser(r.Context())
+	settings := new(businesslogic.PartnerSuggestionSettings)
+	if err := util.ParseRequestData(r, settings); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	suggestions, err := server.service.SuggestPartners(currentUser, *settings)
+	if err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	output := make([]viewmodel.PartnerSuggestionViewModel, 0)
+	for _, each := range suggestions {
+		output = append(output, viewmodel.PartnerSuggestionDataModelToViewModel(currentUser, each))
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", output)
+}
@@ -0,0 +1,142 @@
+package scrutineer
+
+import (
+	"github.com/DancesportSoftware/das/auth"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/DancesportSoftware/das/viewmodel"
+	"net/http"
+)
+
+// ScrutineerScoresheetServer is a virtual server that handles requests of scrutineers to manage the marks of rounds
+type ScrutineerScoresheetServer struct {
+	auth    auth.IAuthenticationStrategy
+	service businesslogic.ScrutineerService
+}
+
+func NewScrutineerScoresheetServer(authentication auth.IAuthenticationStrategy, service businesslogic.ScrutineerService) ScrutineerScoresheetServer {
+	return ScrutineerScoresheetServer{
+		auth:    authentication,
+		service: service,
+	}
+}
+
+func respondScoresheetError(w http.ResponseWriter, err error) {
+	switch err {
+	case businesslogic.ScoresheetLockedError:
+		util.RespondJsonResult(w, http.StatusConflict, err.Error(), nil)
+	default:
+		util.RespondJsonResult(w, http.StatusBadRequest, err.Error(), nil)
+	}
+}
+
+// OpenRoundHandler handles the request:
+//	POST /api/v1.0/scrutineer/round/open
+func (server ScrutineerScoresheetServer) OpenRoundHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.OpenRoundDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	scoresheet, err := server.service.OpenRound(account, competition.ID, dto.RoundID, dto.Preliminary, dto.RecallSize)
+	if err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "round is opened for marking", viewmodel.ScoresheetDataModelToViewModel(scoresheet, nil))
+}
+
+// EnterMarksHandler handles the request:
+//	PUT /api/v1.0/scrutineer/round/marks
+// Marks that the adjudicator previously gave in the dance are replaced by the submitted marks.
+func (server ScrutineerScoresheetServer) EnterMarksHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.JudgeMarksDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	if err := server.service.EnterMarks(account, competition.ID, dto.ToJudgeMarksSubmission()); err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "marks are saved", nil)
+}
+
+// GetMarksHandler handles the request:
+//	GET /api/v1.0/scrutineer/round/marks?round=1
+func (server ScrutineerScoresheetServer) GetMarksHandler(w http.ResponseWriter, r *http.Request) {
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.RoundDTO)
+	if err := util.ParseRequestData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	scoresheet, placements, err := server.service.GetMarks(competition.ID, dto.RoundID)
+	if err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "success", viewmodel.ScoresheetDataModelToViewModel(scoresheet, placements))
+}
+
+// LockRoundHandler handles the request:
+//	POST /api/v1.0/scrutineer/round/lock
+func (server ScrutineerScoresheetServer) LockRoundHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.RoundDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	scoresheet, err := server.service.LockRound(account, competition.ID, dto.RoundID)
+	if err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "round is locked", viewmodel.ScoresheetDataModelToViewModel(scoresheet, nil))
+}
+
+// ComputeRoundResultHandler handles the request:
+//	POST /api/v1.0/scrutineer/round/result
+// Recalls are computed for preliminary rounds, and placements are computed for final rounds.
+func (server ScrutineerScoresheetServer) ComputeRoundResultHandler(w http.ResponseWriter, r *http.Request) {
+	account, _ := auth.CurrentUser(r.Context())
+	competition, _ := util.AuthorizedCompetition(r.Context())
+	dto := new(viewmodel.ComputeRoundResultDTO)
+	if err := util.ParseRequestBodyData(r, dto); err != nil {
+		util.RespondJsonResult(w, http.StatusBadRequest, util.HTTP400InvalidRequestData, err.Error())
+		return
+	}
+
+	scoresheet, err := server.service.GetScoresheet(competition.ID, dto.RoundID)
+	if err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+
+	if scoresheet.PreliminaryRoundIndicator {
+		tabulation, err := server.service.ComputePreliminaryRoundResult(account, competition.ID, dto.RoundID, dto.RecallTied)
+		if err != nil {
+			respondScoresheetError(w, err)
+			return
+		}
+		util.RespondJsonResult(w, http.StatusOK, "recalls are computed", viewmodel.RecallTabulationToViewModel(dto.RoundID, tabulation, dto.RecallTied))
+		return
+	}
+
+	tabulation, err := server.service.ComputeFinalRoundResult(account, competition.ID, dto.RoundID)
+	if err != nil {
+		respondScoresheetError(w, err)
+		return
+	}
+	util.RespondJsonResult(w, http.StatusOK, "placements are computed", viewmodel.RoundTabulationToViewModel(dto.RoundID, tabulation))
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"context"
+	"github.com/DancesportSoftware/das/businesslogic"
+)
+
+type contextKey int
+
+const competitionKey contextKey = iota
+
+// WithCompetition returns a copy of the context that carries the competition that the request is authorized for
+func WithCompetition(ctx context.Context, competition businesslogic.Competition) context.Context {
+	return context.WithValue(ctx, competitionKey, competition)
+}
+
+// AuthorizedCompetition returns the competition that the policy of the controller authorized the request for. The
+// second value is false if the controller does not specify a policy.
+func AuthorizedCompetition(ctx context.Context) (businesslogic.Competition, bool) {
+	competition, ok := ctx.Value(competitionKey).(businesslogic.Competition)
+	return competition, ok
+}
@@ -1,7 +1,14 @@
 package util
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 )
 
 // DasController specifies the description, allowed method, endpoint, handler functions,
@@ -9,15 +16,76 @@ import (
 // source of data that it depends on. Instead, create a separate sever for each particular
 // controller, specify the data source inside the server struct and inject data source into
 // controller's HandlerFunc implementation.
+//
+// Controllers that act on a competition may also specify a Policy. The policy is checked for the competition that
+// CompetitionID finds in the request with CompetitionIDKey, after the roles of the user are checked, and handlers take
+// the competition that is authorized from AuthorizedCompetition. If CompetitionOptional is set, requests that do not
+// identify a competition are passed to the handler without a competition.
 type DasController struct {
-	Name         string
-	Description  string
-	Method       string
-	Endpoint     string
-	Handler      http.HandlerFunc
-	AllowedRoles []int
+	Name                string
+	Description         string
+	Method              string
+	Endpoint            string
+	Handler             http.HandlerFunc
+	AllowedRoles        []int
+	Policy              *businesslogic.CompetitionPolicy
+	CompetitionIDKey    string
+	CompetitionOptional bool
 }
 
 type DasControllerGroup struct {
 	Controllers []DasController
 }
+
+// DefaultCompetitionIDKey is the query parameter, or the top-level field of the JSON body, that identifies the
+// competition of a request to a controller that does not specify its CompetitionIDKey
+const DefaultCompetitionIDKey = "competition"
+
+// CompetitionIDMissingError is returned when the request does not identify a competition with the key
+var CompetitionIDMissingError = errors.New("competition is not specified")
+
+// CompetitionID finds the ID of the competition of the request with the key. Requests with a body (POST, PUT and
+// DELETE) identify the competition in the JSON body that the handler reads, and cannot identify another competition in
+// the query parameter. Other requests identify the competition in the query parameter. The body is restored so that
+// handlers can still read it.
+func CompetitionID(r *http.Request, key string) (int, error) {
+	if key == "" {
+		key = DefaultCompetitionIDKey
+	}
+	query := r.URL.Query().Get(key)
+	if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		if query == "" {
+			return 0, CompetitionIDMissingError
+		}
+		id, err := strconv.Atoi(query)
+		if err != nil {
+			return 0, errors.New(fmt.Sprintf("%v is invalid", key))
+		}
+		return id, nil
+	}
+	if r.Body == nil {
+		return 0, CompetitionIDMissingError
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return 0, errors.New(fmt.Sprintf("%v is required", key))
+	}
+	value, found := fields[key]
+	if !found {
+		return 0, CompetitionIDMissingError
+	}
+	id, ok := value.(float64)
+	if !ok || id != float64(int(id)) {
+		return 0, errors.New(fmt.Sprintf("%v is invalid", key))
+	}
+	if query != "" && query != strconv.Itoa(int(id)) {
+		return 0, errors.New(fmt.Sprintf("%v of the query does not match %v of the body", key, key))
+	}
+	return int(id), nil
+}
@@ -0,0 +1,69 @@
+package util_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/controller/util"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCompetitionID(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1.0/organizer/competition/finance?competition=3", nil)
+	id, err := util.CompetitionID(r, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, id)
+
+	r, _ = http.NewRequest(http.MethodGet, "/api/v1.0/organizer/competition/finance", nil)
+	_, err = util.CompetitionID(r, "")
+	assert.Equal(t, util.CompetitionIDMissingError, err)
+
+	r, _ = http.NewRequest(http.MethodGet, "/api/v1.0/organizer/competition/schedule?competitionId=5&competition=3", nil)
+	id, err = util.CompetitionID(r, "competitionId")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, id, "controllers should find the competition with their own key")
+
+	body := `{"competition": 4, "name": "Ohio Star Ball"}`
+	r, _ = http.NewRequest(http.MethodPut, "/api/v1.0/organizer/competition/fee", strings.NewReader(body))
+	id, err = util.CompetitionID(r, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, id)
+	restored, _ := ioutil.ReadAll(r.Body)
+	assert.Equal(t, body, string(restored), "handlers should still read the body")
+
+	r, _ = http.NewRequest(http.MethodPut, "/api/v1.0/organizer/competition/fee?competition=4", strings.NewReader(body))
+	id, err = util.CompetitionID(r, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, id)
+
+	r, _ = http.NewRequest(http.MethodPut, "/api/v1.0/organizer/competition/fee?competition=3", strings.NewReader(body))
+	_, err = util.CompetitionID(r, "")
+	assert.NotNil(t, err, "requests should not be authorized for a competition other than the one in the body")
+
+	r, _ = http.NewRequest(http.MethodPost, "/api/v1.0/organizer/competition/product?competition=3", strings.NewReader(`{"title": "Program"}`))
+	_, err = util.CompetitionID(r, "")
+	assert.NotNil(t, err, "the competition of requests with a body should be in the body")
+
+	r, _ = http.NewRequest(http.MethodPut, "/api/v1.0/organizer/competition/fee", strings.NewReader(`{"competition": "4"}`))
+	_, err = util.CompetitionID(r, "")
+	assert.NotNil(t, err)
+	assert.NotEqual(t, util.CompetitionIDMissingError, err, "invalid competitions should not be treated as missing")
+
+	r, _ = http.NewRequest(http.MethodPut, "/api/v1.0/organizer/competition", strings.NewReader(`{"competitionId": 4, "name": "Ohio Star Ball"}`))
+	id, err = util.CompetitionID(r, "competitionId")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, id)
+}
+
+func TestAuthorizedCompetition(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1.0/organizer/competition/finance?competition=3", nil)
+	_, ok := util.AuthorizedCompetition(r.Context())
+	assert.False(t, ok)
+
+	r = r.WithContext(util.WithCompetition(r.Context(), businesslogic.Competition{ID: 3}))
+	competition, ok := util.AuthorizedCompetition(r.Context())
+	assert.True(t, ok)
+	assert.Equal(t, 3, competition.ID)
+}
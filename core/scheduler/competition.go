@@ -0,0 +1,334 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Session is a period of time when the floors of the competition are available for dancing
+type Session struct {
+	Start  time.Time
+	End    time.Time
+	Floors int
+}
+
+// BreakRule requires a floor to take a break once it has run for MaxContinuous without one. A floor that stays idle
+// for at least Duration between two rounds has had its break. A zero MaxContinuous disables breaks.
+type BreakRule struct {
+	MaxContinuous time.Duration
+	Duration      time.Duration
+}
+
+// ScheduleEvent is an event to be scheduled. Couples and Athletes are everyone entered in the event, and are used to
+// find the conflicts of the schedule.
+type ScheduleEvent struct {
+	EventID      int
+	Dances       int
+	RoundEntries []int // estimated couples of each round, from the first round to the final
+	Couples      []int
+	Athletes     []int
+}
+
+// ScheduledRound is a round in the timetable of the competition
+type ScheduledRound struct {
+	EventID int
+	Round   int // 1-based, the final is the last round of the event
+	Final   bool
+	Entries int
+	Session int // 0-based index of the session
+	Floor   int // 1-based
+	Start   time.Time
+	End     time.Time
+}
+
+func (round ScheduledRound) overlaps(other ScheduledRound) bool {
+	return round.Start.Before(other.End) && other.Start.Before(round.End)
+}
+
+// ScheduleConflict is a pair of rounds of different events that overlap in time, and have couples or athletes who are
+// entered in both events
+type ScheduleConflict struct {
+	First    ScheduledRound
+	Second   ScheduledRound
+	Couples  []int
+	Athletes []int
+}
+
+// CompetitionScheduler builds the timetable of all the events of a competition
+type CompetitionScheduler struct {
+	floorCapacity int
+	danceDuration time.Duration
+	roundInterval time.Duration
+	breakRule     BreakRule
+	sessions      []Session
+}
+
+// NewCompetitionScheduler creates a scheduler that runs rounds in the sessions. Consecutive rounds of the same event
+// are at least roundInterval apart, which leaves time to tabulate the recalls.
+func NewCompetitionScheduler(floorCapacity int, danceDuration, roundInterval time.Duration, breakRule BreakRule, sessions []Session) (CompetitionScheduler, error) {
+	if floorCapacity < 1 {
+		return CompetitionScheduler{}, errors.New("floor capacity must be larger than 0")
+	}
+	if danceDuration < time.Second {
+		return CompetitionScheduler{}, errors.New("dances must be at least 1 second")
+	}
+	if roundInterval < 0 || breakRule.MaxContinuous < 0 || breakRule.Duration < 0 {
+		return CompetitionScheduler{}, errors.New("intervals and breaks must not be negative")
+	}
+	if len(sessions) == 0 {
+		return CompetitionScheduler{}, errors.New("at least one session is required")
+	}
+	sorted := make([]Session, len(sessions))
+	copy(sorted, sessions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	for i, each := range sorted {
+		if !each.End.After(each.Start) {
+			return CompetitionScheduler{}, errors.New("sessions must end after they start")
+		}
+		if each.Floors < 1 {
+			return CompetitionScheduler{}, errors.New("sessions must have at least 1 floor")
+		}
+		if i > 0 && each.Start.Before(sorted[i-1].End) {
+			return CompetitionScheduler{}, errors.New("sessions must not overlap")
+		}
+	}
+	return CompetitionScheduler{
+		floorCapacity: floorCapacity,
+		danceDuration: danceDuration,
+		roundInterval: roundInterval,
+		breakRule:     breakRule,
+		sessions:      sorted,
+	}, nil
+}
+
+// RoundDuration estimates how long it takes to dance all the heats of a round
+func (scheduler CompetitionScheduler) RoundDuration(entries, dances int) time.Duration {
+	heats := (entries + scheduler.floorCapacity - 1) / scheduler.floorCapacity
+	if heats < 1 {
+		heats = 1
+	}
+	return time.Duration(heats*dances) * scheduler.danceDuration
+}
+
+type floorState struct {
+	available       time.Time
+	continuousSince time.Time
+}
+
+type roundSlot struct {
+	session int
+	floor   int
+	start   time.Time
+	reset   bool // whether the floor starts a new continuous period with this round
+}
+
+// Schedule orders the rounds of the events into a timetable. Rounds that are further from their final are danced
+// first, so all first rounds are danced before the semi-finals, and all finals are danced at the end. Rounds at the
+// same stage are danced in the order of the events. Each round takes the earliest slot on any floor, and rounds never
+// go back to an earlier session than the previous round.
+func (scheduler CompetitionScheduler) Schedule(events []ScheduleEvent) ([]ScheduledRound, error) {
+	type pending struct {
+		event     int
+		round     int
+		remaining int
+	}
+	queue := make([]pending, 0)
+	for i, event := range events {
+		if event.Dances < 1 {
+			return nil, errors.New(fmt.Sprintf("event %v must have at least 1 dance", event.EventID))
+		}
+		for r := range event.RoundEntries {
+			queue = append(queue, pending{event: i, round: r, remaining: len(event.RoundEntries) - 1 - r})
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].remaining > queue[j].remaining })
+
+	floors := make([][]floorState, len(scheduler.sessions))
+	for i, session := range scheduler.sessions {
+		floors[i] = make([]floorState, session.Floors)
+		for j := range floors[i] {
+			floors[i][j] = floorState{available: session.Start, continuousSince: session.Start}
+		}
+	}
+
+	previous := make(map[int]time.Time) // key: index of event, value: end of its latest scheduled round
+	timetable := make([]ScheduledRound, 0)
+	currentSession := 0
+	for _, each := range queue {
+		event := events[each.event]
+		duration := scheduler.RoundDuration(event.RoundEntries[each.round], event.Dances)
+		earliest := time.Time{}
+		if end, ok := previous[each.event]; ok {
+			earliest = end.Add(scheduler.roundInterval)
+		}
+
+		var best *roundSlot
+		for s := currentSession; s < len(scheduler.sessions) && best == nil; s++ {
+			for f := range floors[s] {
+				slot, ok := scheduler.fit(scheduler.sessions[s], floors[s][f], earliest, duration)
+				if !ok {
+					continue
+				}
+				if best == nil || slot.start.Before(best.start) {
+					slot.session, slot.floor = s, f
+					best = &slot
+				}
+			}
+		}
+		if best == nil {
+			return timetable, errors.New(fmt.Sprintf("round %v of event %v does not fit in any session", each.round+1, event.EventID))
+		}
+
+		state := &floors[best.session][best.floor]
+		if best.reset {
+			state.continuousSince = best.start
+		}
+		state.available = best.start.Add(duration)
+		currentSession = best.session
+		previous[each.event] = state.available
+		timetable = append(timetable, ScheduledRound{
+			EventID: event.EventID,
+			Round:   each.round + 1,
+			Final:   each.remaining == 0,
+			Entries: event.RoundEntries[each.round],
+			Session: best.session,
+			Floor:   best.floor + 1,
+			Start:   best.start,
+			End:     state.available,
+		})
+	}
+	return timetable, nil
+}
+
+// fit finds the earliest start of a round on the floor, taking a break first if the floor would otherwise run longer
+// than the break rule allows
+func (scheduler CompetitionScheduler) fit(session Session, floor floorState, earliest time.Time, duration time.Duration) (roundSlot, bool) {
+	slot := roundSlot{start: floor.available}
+	if earliest.After(slot.start) {
+		slot.start = earliest
+	}
+	rule := scheduler.breakRule
+	if slot.start.Sub(floor.available) >= rule.Duration {
+		slot.reset = true
+	} else if rule.MaxContinuous > 0 && slot.start.Add(duration).Sub(floor.continuousSince) > rule.MaxContinuous {
+		slot.start = floor.available.Add(rule.Duration)
+		slot.reset = true
+	}
+	if slot.start.Add(duration).After(session.End) {
+		return slot, false
+	}
+	return slot, true
+}
+
+// FindConflicts finds the rounds of different events that overlap in time and share couples or athletes. Couples and
+// athletes are considered to dance in every round of the events they are entered in, since recalls are not known when
+// the schedule is built.
+func FindConflicts(events []ScheduleEvent, timetable []ScheduledRound) []ScheduleConflict {
+	entrants := make(map[int]ScheduleEvent)
+	for _, each := range events {
+		entrants[each.EventID] = each
+	}
+	conflicts := make([]ScheduleConflict, 0)
+	for i := 0; i < len(timetable); i++ {
+		for j := i + 1; j < len(timetable); j++ {
+			first, second := timetable[i], timetable[j]
+			if first.EventID == second.EventID || !first.overlaps(second) {
+				continue
+			}
+			couples := intersect(entrants[first.EventID].Couples, entrants[second.EventID].Couples)
+			athletes := intersect(entrants[first.EventID].Athletes, entrants[second.EventID].Athletes)
+			if len(couples) > 0 || len(athletes) > 0 {
+				conflicts = append(conflicts, ScheduleConflict{
+					First:    first,
+					Second:   second,
+					Couples:  couples,
+					Athletes: athletes,
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// intersect returns the sorted IDs that are in both lists
+func intersect(a, b []int) []int {
+	set := make(map[int]bool)
+	for _, each := range a {
+		set[each] = true
+	}
+	shared := make([]int, 0)
+	for _, each := range b {
+		if set[each] {
+			shared = append(shared, each)
+			delete(set, each)
+		}
+	}
+	sort.Ints(shared)
+	return shared
+}
+
+// AthleteConflict is a pair of rounds of different events that an athlete is entered in, and either overlap in time
+// or leave the athlete less rest than required between them. Rest is negative when the rounds overlap.
+type AthleteConflict struct {
+	AthleteID int
+	First     ScheduledRound
+	Second    ScheduledRound
+	Clash     bool
+	Rest      time.Duration
+}
+
+// FindAthleteConflicts checks the rounds of each athlete in the order they are danced, and reports the rounds that
+// overlap and the rounds that start less than minimumRest after an earlier round of the athlete ends. Athletes are
+// considered to dance in every round of the events they are entered in. Conflicts are ordered by athlete, then by the
+// start of the rounds.
+func FindAthleteConflicts(events []ScheduleEvent, timetable []ScheduledRound, minimumRest time.Duration) []AthleteConflict {
+	rounds := make(map[int][]ScheduledRound) // key: athlete
+	for _, event := range events {
+		athletes := make(map[int]bool)
+		for _, athlete := range event.Athletes {
+			athletes[athlete] = true
+		}
+		for _, round := range timetable {
+			if round.EventID != event.EventID {
+				continue
+			}
+			for athlete := range athletes {
+				rounds[athlete] = append(rounds[athlete], round)
+			}
+		}
+	}
+
+	athletes := make([]int, 0)
+	for each := range rounds {
+		athletes = append(athletes, each)
+	}
+	sort.Ints(athletes)
+
+	conflicts := make([]AthleteConflict, 0)
+	for _, athlete := range athletes {
+		danced := rounds[athlete]
+		sort.SliceStable(danced, func(i, j int) bool { return danced[i].Start.Before(danced[j].Start) })
+		for i := 0; i < len(danced); i++ {
+			for j := i + 1; j < len(danced); j++ {
+				first, second := danced[i], danced[j]
+				if first.EventID == second.EventID {
+					continue
+				}
+				rest := second.Start.Sub(first.End)
+				if rest >= minimumRest {
+					continue
+				}
+				conflicts = append(conflicts, AthleteConflict{
+					AthleteID: athlete,
+					First:     first,
+					Second:    second,
+					Clash:     first.overlaps(second),
+					Rest:      rest,
+				})
+			}
+		}
+	}
+	return conflicts
+}
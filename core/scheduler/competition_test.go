@@ -0,0 +1,141 @@
+package scheduler_test
+
+import (
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var sessionStart = time.Date(2018, time.November, 3, 8, 0, 0, 0, time.UTC)
+
+func newSession(hours int, floors int) scheduler.Session {
+	return scheduler.Session{
+		Start:  sessionStart,
+		End:    sessionStart.Add(time.Duration(hours) * time.Hour),
+		Floors: floors,
+	}
+}
+
+func TestNewCompetitionScheduler(t *testing.T) {
+	_, err := scheduler.NewCompetitionScheduler(0, 90*time.Second, 0, scheduler.BreakRule{}, []scheduler.Session{newSession(8, 1)})
+	assert.NotNil(t, err, "floor capacity must be positive")
+
+	_, err = scheduler.NewCompetitionScheduler(12, 90*time.Second, 0, scheduler.BreakRule{}, nil)
+	assert.NotNil(t, err, "should not schedule without any session")
+
+	_, err = scheduler.NewCompetitionScheduler(12, 90*time.Second, 0, scheduler.BreakRule{}, []scheduler.Session{newSession(8, 1), newSession(4, 1)})
+	assert.NotNil(t, err, "sessions should not overlap")
+}
+
+func TestCompetitionScheduler_Schedule(t *testing.T) {
+	competition, err := scheduler.NewCompetitionScheduler(12, 90*time.Second, 15*time.Minute, scheduler.BreakRule{}, []scheduler.Session{newSession(8, 1)})
+	assert.Nil(t, err)
+
+	timetable, err := competition.Schedule([]scheduler.ScheduleEvent{
+		{EventID: 1, Dances: 2, RoundEntries: []int{24, 12, 6}},
+		{EventID: 2, Dances: 1, RoundEntries: []int{6}},
+		{EventID: 3, Dances: 3, RoundEntries: []int{10, 6}},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, timetable, 6)
+
+	order := make([][]int, 0)
+	for _, each := range timetable {
+		order = append(order, []int{each.EventID, each.Round})
+	}
+	assert.Equal(t, [][]int{{1, 1}, {1, 2}, {3, 1}, {1, 3}, {2, 1}, {3, 2}}, order, "finals should be danced at the end")
+
+	assert.Equal(t, sessionStart, timetable[0].Start)
+	assert.Equal(t, 6*time.Minute, timetable[0].End.Sub(timetable[0].Start), "24 couples should dance 2 heats of 2 dances")
+	assert.Equal(t, timetable[0].End.Add(15*time.Minute), timetable[1].Start, "rounds of the same event should leave time for recalls")
+	assert.Equal(t, timetable[1].End, timetable[2].Start, "rounds of other events do not wait for the recalls")
+	assert.True(t, timetable[3].Final)
+}
+
+func TestCompetitionScheduler_Schedule_Breaks(t *testing.T) {
+	rule := scheduler.BreakRule{MaxContinuous: 10 * time.Minute, Duration: 5 * time.Minute}
+	competition, err := scheduler.NewCompetitionScheduler(6, 60*time.Second, 0, rule, []scheduler.Session{newSession(8, 1)})
+	assert.Nil(t, err)
+
+	timetable, err := competition.Schedule([]scheduler.ScheduleEvent{
+		{EventID: 1, Dances: 4, RoundEntries: []int{6}},
+		{EventID: 2, Dances: 4, RoundEntries: []int{6}},
+		{EventID: 3, Dances: 4, RoundEntries: []int{6}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, timetable[0].End, timetable[1].Start)
+	assert.Equal(t, timetable[1].End.Add(5*time.Minute), timetable[2].Start, "the floor should take a break after 10 minutes")
+}
+
+func TestCompetitionScheduler_Schedule_Sessions(t *testing.T) {
+	morning := newSession(1, 1)
+	afternoon := scheduler.Session{Start: sessionStart.Add(4 * time.Hour), End: sessionStart.Add(8 * time.Hour), Floors: 1}
+	competition, err := scheduler.NewCompetitionScheduler(6, 10*time.Minute, 0, scheduler.BreakRule{}, []scheduler.Session{afternoon, morning})
+	assert.Nil(t, err)
+
+	timetable, err := competition.Schedule([]scheduler.ScheduleEvent{
+		{EventID: 1, Dances: 5, RoundEntries: []int{6}},
+		{EventID: 2, Dances: 5, RoundEntries: []int{6}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, timetable[0].Session)
+	assert.Equal(t, 1, timetable[1].Session, "rounds that do not fit should be moved to the next session")
+	assert.Equal(t, afternoon.Start, timetable[1].Start)
+
+	_, err = competition.Schedule([]scheduler.ScheduleEvent{{EventID: 1, Dances: 30, RoundEntries: []int{6}}})
+	assert.NotNil(t, err, "rounds longer than any session cannot be scheduled")
+}
+
+func TestFindConflicts(t *testing.T) {
+	competition, err := scheduler.NewCompetitionScheduler(6, 60*time.Second, 0, scheduler.BreakRule{}, []scheduler.Session{newSession(8, 2)})
+	assert.Nil(t, err)
+
+	events := []scheduler.ScheduleEvent{
+		{EventID: 1, Dances: 3, RoundEntries: []int{6}, Couples: []int{11, 12}, Athletes: []int{1, 2, 3, 4}},
+		{EventID: 2, Dances: 3, RoundEntries: []int{6}, Couples: []int{13}, Athletes: []int{1, 5}},
+		{EventID: 3, Dances: 3, RoundEntries: []int{6}, Couples: []int{11}, Athletes: []int{1, 2}},
+	}
+	timetable, err := competition.Schedule(events)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, timetable[0].Floor)
+	assert.Equal(t, 2, timetable[1].Floor, "rounds should use the other floor when it is free")
+	assert.Equal(t, timetable[0].Start, timetable[1].Start)
+
+	conflicts := scheduler.FindConflicts(events, timetable)
+	assert.Len(t, conflicts, 1, "only event 1 and 2 are danced at the same time")
+	assert.Empty(t, conflicts[0].Couples)
+	assert.Equal(t, []int{1}, conflicts[0].Athletes, "athlete 1 dances in both events with different partners")
+}
+
+func TestFindAthleteConflicts(t *testing.T) {
+	events := []scheduler.ScheduleEvent{
+		{EventID: 1, Athletes: []int{1, 2}},
+		{EventID: 2, Athletes: []int{1, 3}},
+		{EventID: 3, Athletes: []int{1, 2}},
+		{EventID: 4, Athletes: []int{4}},
+	}
+	at := func(minutes int) time.Time { return sessionStart.Add(time.Duration(minutes) * time.Minute) }
+	timetable := []scheduler.ScheduledRound{
+		{EventID: 1, Round: 1, Start: at(0), End: at(10)},
+		{EventID: 2, Round: 1, Floor: 2, Start: at(5), End: at(15)},
+		{EventID: 3, Round: 1, Start: at(20), End: at(30)},
+		{EventID: 1, Round: 2, Start: at(30), End: at(40)},
+		{EventID: 4, Round: 1, Start: at(40), End: at(50)},
+	}
+
+	conflicts := scheduler.FindAthleteConflicts(events, timetable, 10*time.Minute)
+	assert.Len(t, conflicts, 4)
+
+	assert.Equal(t, 1, conflicts[0].AthleteID)
+	assert.True(t, conflicts[0].Clash, "athlete 1 dances event 1 and 2 at the same time")
+	assert.Equal(t, -5*time.Minute, conflicts[0].Rest)
+
+	assert.Equal(t, []int{2, 3}, []int{conflicts[1].First.EventID, conflicts[1].Second.EventID})
+	assert.Equal(t, 5*time.Minute, conflicts[1].Rest, "athlete 1 rests only 5 minutes before event 3")
+	assert.False(t, conflicts[1].Clash)
+
+	assert.Equal(t, 0*time.Minute, conflicts[2].Rest, "athlete 1 dances event 1 right after event 3")
+	assert.Equal(t, 2, conflicts[3].AthleteID)
+	assert.Len(t, scheduler.FindAthleteConflicts(events, timetable, 0), 1, "only overlapping rounds conflict without a minimum rest")
+}
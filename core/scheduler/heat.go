@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// HeatCouple is a couple to be drawn into heats. Couples that share any affiliation, such as the same studio or the
+// same school, are kept in different heats where possible.
+type HeatCouple struct {
+	CoupleID     int
+	Affiliations []string
+}
+
+func (couple HeatCouple) sharesAffiliation(other HeatCouple) bool {
+	for _, mine := range couple.Affiliations {
+		for _, theirs := range other.Affiliations {
+			if mine == theirs {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HeatDraw splits the couples of a round into heats that fit on the floor. The draw only depends on the seed and the
+// couples, so a draw can be reproduced with the same seed when it is appealed.
+type HeatDraw struct {
+	seed          int64
+	floorCapacity int
+}
+
+func NewHeatDraw(seed int64, floorCapacity int) (HeatDraw, error) {
+	if floorCapacity < 1 {
+		return HeatDraw{}, errors.New("floor capacity must be larger than 0")
+	}
+	return HeatDraw{seed: seed, floorCapacity: floorCapacity}, nil
+}
+
+// CountHeats returns the number of heats that are needed for the couples to dance within the floor capacity
+func (draw HeatDraw) CountHeats(couples int) int {
+	return (couples + draw.floorCapacity - 1) / draw.floorCapacity
+}
+
+// SplitHeats draws the couples into heats for each dance. The result is indexed by dance, then by heat, and couples
+// in each heat are sorted by ID. Couples are reshuffled for every dance, and heats are balanced so that their sizes
+// differ by at most one couple.
+func (draw HeatDraw) SplitHeats(couples []HeatCouple, dances int) ([][][]int, error) {
+	if dances < 1 {
+		return nil, errors.New("dances must be at least 1")
+	}
+	sorted := make([]HeatCouple, len(couples))
+	copy(sorted, couples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].CoupleID < sorted[j].CoupleID })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].CoupleID == sorted[i-1].CoupleID {
+			return nil, errors.New("couples must not be drawn more than once")
+		}
+	}
+
+	rng := rand.New(rand.NewSource(draw.seed))
+	result := make([][][]int, 0)
+	for i := 0; i < dances; i++ {
+		result = append(result, draw.drawDance(sorted, rng))
+	}
+	return result, nil
+}
+
+// drawDance assigns the couples to heats. Couples with the most affiliated competitors are assigned first, and each
+// couple goes to the heat with the fewest affiliated couples, then to the heat with the most space left.
+func (draw HeatDraw) drawDance(couples []HeatCouple, rng *rand.Rand) [][]int {
+	heatCount := draw.CountHeats(len(couples))
+	if heatCount == 0 {
+		return [][]int{}
+	}
+
+	shuffled := make([]HeatCouple, 0)
+	for _, i := range rng.Perm(len(couples)) {
+		shuffled = append(shuffled, couples[i])
+	}
+	conflicts := make(map[int]int)
+	for _, couple := range shuffled {
+		for _, other := range shuffled {
+			if couple.CoupleID != other.CoupleID && couple.sharesAffiliation(other) {
+				conflicts[couple.CoupleID]++
+			}
+		}
+	}
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return conflicts[shuffled[i].CoupleID] > conflicts[shuffled[j].CoupleID]
+	})
+
+	// heats are balanced: the first (couples % heats) heats take one more couple than the others
+	capacity := make([]int, heatCount)
+	for i := range capacity {
+		capacity[i] = len(couples) / heatCount
+		if i < len(couples)%heatCount {
+			capacity[i]++
+		}
+	}
+	heatOrder := rng.Perm(heatCount)
+
+	heats := make([][]HeatCouple, heatCount)
+	for _, couple := range shuffled {
+		best, bestConflicts, bestSpace := -1, 0, 0
+		for _, h := range heatOrder {
+			space := capacity[h] - len(heats[h])
+			if space == 0 {
+				continue
+			}
+			count := 0
+			for _, other := range heats[h] {
+				if couple.sharesAffiliation(other) {
+					count++
+				}
+			}
+			if best < 0 || count < bestConflicts || (count == bestConflicts && space > bestSpace) {
+				best, bestConflicts, bestSpace = h, count, space
+			}
+		}
+		heats[best] = append(heats[best], couple)
+	}
+
+	result := make([][]int, 0)
+	for _, heat := range heats {
+		ids := make([]int, 0)
+		for _, couple := range heat {
+			ids = append(ids, couple.CoupleID)
+		}
+		sort.Ints(ids)
+		result = append(result, ids)
+	}
+	return result
+}
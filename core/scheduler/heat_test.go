@@ -0,0 +1,85 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newHeatCouples(count int, studios int) []scheduler.HeatCouple {
+	couples := make([]scheduler.HeatCouple, 0)
+	for i := 0; i < count; i++ {
+		couples = append(couples, scheduler.HeatCouple{
+			CoupleID:     101 + i,
+			Affiliations: []string{fmt.Sprintf("studio-%v", i%studios)},
+		})
+	}
+	return couples
+}
+
+func TestHeatDraw_SplitHeats(t *testing.T) {
+	draw, err := scheduler.NewHeatDraw(2019, 12)
+	assert.Nil(t, err)
+
+	heats, err := draw.SplitHeats(newHeatCouples(30, 30), 3)
+	assert.Nil(t, err)
+	assert.Len(t, heats, 3, "each dance should have its own heats")
+	for _, dance := range heats {
+		assert.Len(t, dance, 3, "30 couples on a floor of 12 should dance in 3 heats")
+		total := 0
+		for _, heat := range dance {
+			assert.Len(t, heat, 10, "heats should be balanced")
+			total += len(heat)
+		}
+		assert.Equal(t, 30, total, "every couple should dance in exactly one heat")
+	}
+	assert.NotEqual(t, heats[0], heats[1], "couples should be reshuffled for each dance")
+}
+
+func TestHeatDraw_SplitHeats_Reproducible(t *testing.T) {
+	couples := newHeatCouples(20, 4)
+	first, _ := scheduler.NewHeatDraw(42, 8)
+	second, _ := scheduler.NewHeatDraw(42, 8)
+	other, _ := scheduler.NewHeatDraw(43, 8)
+
+	a, _ := first.SplitHeats(couples, 2)
+	reversed := make([]scheduler.HeatCouple, 0)
+	for i := len(couples) - 1; i >= 0; i-- {
+		reversed = append(reversed, couples[i])
+	}
+	b, _ := second.SplitHeats(reversed, 2)
+	c, _ := other.SplitHeats(couples, 2)
+
+	assert.Equal(t, a, b, "draws with the same seed should be the same regardless of the order of couples")
+	assert.NotEqual(t, a, c, "draws with different seeds should be different")
+}
+
+func TestHeatDraw_SplitHeats_SeparateAffiliations(t *testing.T) {
+	// 3 studios with 3 couples each, drawn into 3 heats: every heat can have one couple from each studio
+	draw, _ := scheduler.NewHeatDraw(7, 3)
+	heats, err := draw.SplitHeats(newHeatCouples(9, 3), 4)
+	assert.Nil(t, err)
+	for _, dance := range heats {
+		for _, heat := range dance {
+			studios := make(map[int]bool)
+			for _, couple := range heat {
+				studios[(couple-101)%3] = true
+			}
+			assert.Len(t, studios, 3, "couples from the same studio should be in different heats")
+		}
+	}
+}
+
+func TestHeatDraw_InvalidInput(t *testing.T) {
+	_, err := scheduler.NewHeatDraw(1, 0)
+	assert.NotNil(t, err)
+
+	draw, _ := scheduler.NewHeatDraw(1, 6)
+	_, err = draw.SplitHeats(newHeatCouples(3, 1), 0)
+	assert.NotNil(t, err)
+
+	couples := append(newHeatCouples(3, 1), scheduler.HeatCouple{CoupleID: 101})
+	_, err = draw.SplitHeats(couples, 1)
+	assert.NotNil(t, err, "a couple should not be drawn twice")
+}
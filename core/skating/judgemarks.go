@@ -14,14 +14,27 @@ var INVALID_PLACEMENT_ERROR = func(coupleID, placement int) error {
 	return errors.New(fmt.Sprintf("couple %v of has invalid placement: %v", coupleID, placement))
 }
 
+var DUPLICATE_COUPLE_ERROR = func(coupleID int) error {
+	return errors.New(fmt.Sprintf("couple %v is already placed", coupleID))
+}
+
 var PLACEMENT_OUTOFRANGE_ERROR = func(coupleID, placement int) error {
 	return errors.New(fmt.Sprintf("placement of couple %v is out of range: %v", coupleID, placement))
 }
 
+var INVALID_RECALL_COUNT_ERROR = func(expected, actual int) error {
+	return errors.New(fmt.Sprintf("%v couples must be recalled, but %v are recalled", expected, actual))
+}
+
+var INCOMPLETE_PLACEMENT_ERROR = func(expected, actual int) error {
+	return errors.New(fmt.Sprintf("all %v couples must be placed, but %v are placed", expected, actual))
+}
+
 type JudgeMarks struct {
-	callbacks  map[int]bool // key: coupleID, value: recall
-	placements map[int]int  // key: placement, value: coupleID
-	roundSize  int
+	adjudicatorID int
+	callbacks     map[int]bool // key: coupleID, value: recall
+	placements    map[int]int  // key: placement, value: coupleID
+	roundSize     int
 }
 
 func NewJudgeMarks(roundSize int) JudgeMarks {
@@ -32,6 +45,17 @@ func NewJudgeMarks(roundSize int) JudgeMarks {
 	}
 }
 
+// SetAdjudicator labels the marks with the adjudicator who gave them. The label is only used to identify the
+// adjudicator in tabulations and does not affect any calculation.
+func (marks *JudgeMarks) SetAdjudicator(adjudicatorID int) {
+	marks.adjudicatorID = adjudicatorID
+}
+
+// GetAdjudicator returns the adjudicator who gave the marks, or 0 if the marks are not labelled
+func (marks JudgeMarks) GetAdjudicator() int {
+	return marks.adjudicatorID
+}
+
 func (marks *JudgeMarks) AddCallback(coupleID int, callback bool) {
 	marks.callbacks[coupleID] = callback
 }
@@ -50,6 +74,9 @@ func (marks *JudgeMarks) AddPlacement(coupleID, placement int) error {
 	if placement > marks.roundSize {
 		return PLACEMENT_OUTOFRANGE_ERROR(coupleID, placement)
 	}
+	if marks.GetPlacement(coupleID) != 0 {
+		return DUPLICATE_COUPLE_ERROR(coupleID)
+	}
 	marks.placements[placement] = coupleID
 	return nil
 }
@@ -68,6 +95,31 @@ func (marks JudgeMarks) GetCallbacks() []int {
 	return callbacks
 }
 
+// ValidateRecall checks that the adjudicator recalls exactly the requested number of couples. When fewer couples than
+// requested are dancing, all couples must be recalled.
+//
+// This method ensures compliance with Rule 1.
+func (marks JudgeMarks) ValidateRecall(recallSize int) error {
+	expected := recallSize
+	if marks.roundSize < expected {
+		expected = marks.roundSize
+	}
+	if actual := len(marks.GetCallbacks()); actual != expected {
+		return INVALID_RECALL_COUNT_ERROR(expected, actual)
+	}
+	return nil
+}
+
+// ValidatePlacements checks that every couple of the round is placed by the adjudicator.
+//
+// This method ensures compliance with Rule 3.
+func (marks JudgeMarks) ValidatePlacements() error {
+	if actual := len(marks.placements); actual != marks.roundSize {
+		return INCOMPLETE_PLACEMENT_ERROR(marks.roundSize, actual)
+	}
+	return nil
+}
+
 func (marks JudgeMarks) GetPlacements() [][]int {
 	placements := make([][]int, 0)
 	for place, couple := range marks.placements {
@@ -84,3 +136,23 @@ func (marks JudgeMarks) GetCouples() []int {
 	sort.Ints(couples)
 	return couples
 }
+
+// GetPlacement returns the placement that the adjudicator gave to the couple, or 0 if the couple is not placed
+func (marks JudgeMarks) GetPlacement(coupleID int) int {
+	for place, couple := range marks.placements {
+		if couple == coupleID {
+			return place
+		}
+	}
+	return 0
+}
+
+// GetPlacedCouples returns all the couples that are placed by the adjudicator in a sorted order
+func (marks JudgeMarks) GetPlacedCouples() []int {
+	couples := make([]int, 0)
+	for _, couple := range marks.placements {
+		couples = append(couples, couple)
+	}
+	sort.Ints(couples)
+	return couples
+}
@@ -73,3 +73,29 @@ func TestJudgeMarks_InvalidPlacements(t *testing.T) {
 	err = marks.AddPlacement(102, 15)
 	assert.Equal(t, skating.PLACEMENT_OUTOFRANGE_ERROR(102, 15), err)
 }
+
+func TestJudgeMarks_ValidateRecall(t *testing.T) {
+	marks := skating.NewJudgeMarks(4)
+	marks.AddCallback(101, true)
+	marks.AddCallback(102, true)
+	marks.AddCallback(103, false)
+	marks.AddCallback(104, false)
+
+	assert.Nil(t, marks.ValidateRecall(2))
+	assert.NotNil(t, marks.ValidateRecall(3), "adjudicator should recall the requested number of couples")
+	assert.NotNil(t, marks.ValidateRecall(1), "adjudicator should not recall more couples than requested")
+
+	marks.AddCallback(103, true)
+	marks.AddCallback(104, true)
+	assert.Nil(t, marks.ValidateRecall(6), "all couples should be recalled when fewer couples than requested are dancing")
+}
+
+func TestJudgeMarks_ValidatePlacements(t *testing.T) {
+	marks := skating.NewJudgeMarks(3)
+	assert.Nil(t, marks.AddPlacement(101, 1))
+	assert.Nil(t, marks.AddPlacement(102, 2))
+	assert.NotNil(t, marks.ValidatePlacements(), "every couple in the final should be placed")
+
+	assert.Nil(t, marks.AddPlacement(103, 3))
+	assert.Nil(t, marks.ValidatePlacements())
+}
@@ -0,0 +1,126 @@
+package skating
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var INVALID_RECALL_SIZE_ERROR = func(size int) error {
+	return errors.New(fmt.Sprintf("recall size must be positive: %v", size))
+}
+
+// CoupleRecall is the total number of callbacks that a couple receives from all adjudicators in all dances of a
+// preliminary round
+type CoupleRecall struct {
+	CoupleID int
+	Marks    int
+	Rank     int // couples with the same marks have the same rank
+}
+
+// RecallTabulation is the result of a preliminary round. When couples at the cutoff have the same marks, they cannot
+// all be recalled within the requested size, and the chairman of adjudicators must decide whether to recall more or
+// fewer couples than requested.
+type RecallTabulation struct {
+	RecallSize int
+	Couples    []CoupleRecall // ordered by marks, then by couple
+	Recalled   []int          // couples that are recalled regardless of the decision of the chairman
+	Tied       []int          // couples that have the same marks at the cutoff
+}
+
+// HasCutoffTie returns true if the requested number of couples cannot be recalled without separating tied couples
+func (tabulation RecallTabulation) HasCutoffTie() bool {
+	return len(tabulation.Tied) > 0
+}
+
+// GetFewerRecallSize returns the number of recalled couples if tied couples at the cutoff are not recalled
+func (tabulation RecallTabulation) GetFewerRecallSize() int {
+	return len(tabulation.Recalled)
+}
+
+// GetMoreRecallSize returns the number of recalled couples if tied couples at the cutoff are all recalled
+func (tabulation RecallTabulation) GetMoreRecallSize() int {
+	return len(tabulation.Recalled) + len(tabulation.Tied)
+}
+
+// GetRecalledCouples returns the couples that are recalled to the next round. Tied couples at the cutoff are recalled
+// only when includeTied is true.
+func (tabulation RecallTabulation) GetRecalledCouples(includeTied bool) []int {
+	couples := make([]int, 0)
+	couples = append(couples, tabulation.Recalled...)
+	if includeTied {
+		couples = append(couples, tabulation.Tied...)
+	}
+	sort.Ints(couples)
+	return couples
+}
+
+// CalculateRecall totals the callbacks of each couple from all adjudicators in all dances of a preliminary round, and
+// selects the couples to recall for the requested recall size.
+func (sheet RoundScoreSheet) CalculateRecall(algorithm string, recallSize int) (RecallTabulation, error) {
+	if algorithm != ALGORITHM_SKATING {
+		return RecallTabulation{}, UNSUPPORTED_ALGORITHM_ERROR(algorithm)
+	}
+	if recallSize < 1 {
+		return RecallTabulation{}, INVALID_RECALL_SIZE_ERROR(recallSize)
+	}
+	if len(sheet.dances) == 0 {
+		return RecallTabulation{}, NO_DANCE_SHEET_ERROR
+	}
+
+	totals := make(map[int]int)
+	for _, dance := range sheet.dances {
+		for _, marks := range dance.GetJudgeMarks() {
+			for _, couple := range marks.GetCouples() {
+				totals[couple] += 0
+			}
+			for _, couple := range marks.GetCallbacks() {
+				totals[couple]++
+			}
+		}
+	}
+	if len(totals) == 0 {
+		return RecallTabulation{}, NO_COUPLE_ERROR
+	}
+
+	tabulation := RecallTabulation{
+		RecallSize: recallSize,
+		Couples:    make([]CoupleRecall, 0),
+		Recalled:   make([]int, 0),
+		Tied:       make([]int, 0),
+	}
+	for couple, total := range totals {
+		tabulation.Couples = append(tabulation.Couples, CoupleRecall{CoupleID: couple, Marks: total})
+	}
+	sort.Slice(tabulation.Couples, func(i, j int) bool {
+		if tabulation.Couples[i].Marks != tabulation.Couples[j].Marks {
+			return tabulation.Couples[i].Marks > tabulation.Couples[j].Marks
+		}
+		return tabulation.Couples[i].CoupleID < tabulation.Couples[j].CoupleID
+	})
+	for i := range tabulation.Couples {
+		if i > 0 && tabulation.Couples[i].Marks == tabulation.Couples[i-1].Marks {
+			tabulation.Couples[i].Rank = tabulation.Couples[i-1].Rank
+		} else {
+			tabulation.Couples[i].Rank = i + 1
+		}
+	}
+
+	if recallSize >= len(tabulation.Couples) {
+		for _, each := range tabulation.Couples {
+			tabulation.Recalled = append(tabulation.Recalled, each.CoupleID)
+		}
+		return tabulation, nil
+	}
+
+	cutoff := tabulation.Couples[recallSize-1].Marks
+	tied := tabulation.Couples[recallSize].Marks == cutoff
+	for _, each := range tabulation.Couples {
+		if each.Marks > cutoff || (!tied && each.Marks == cutoff && len(tabulation.Recalled) < recallSize) {
+			tabulation.Recalled = append(tabulation.Recalled, each.CoupleID)
+		} else if tied && each.Marks == cutoff {
+			tabulation.Tied = append(tabulation.Tied, each.CoupleID)
+		}
+	}
+	return tabulation, nil
+}
@@ -0,0 +1,69 @@
+package skating_test
+
+import (
+	"github.com/DancesportSoftware/das/core/skating"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newPreliminaryScoreSheet creates a score sheet of a preliminary round. Each row of callbacks is the couples
+// recalled by one adjudicator.
+func newPreliminaryScoreSheet(couples []int, callbacks [][]int) skating.DanceScoreSheet {
+	sheet := skating.NewDanceScoreSheet()
+	for _, row := range callbacks {
+		marks := skating.NewJudgeMarks(len(couples))
+		for _, couple := range couples {
+			marks.AddCallback(couple, false)
+		}
+		for _, couple := range row {
+			marks.AddCallback(couple, true)
+		}
+		sheet.AddJudgeMarks(marks)
+	}
+	return sheet
+}
+
+func TestRoundScoreSheet_CalculateRecall(t *testing.T) {
+	couples := []int{101, 102, 103, 104, 105}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newPreliminaryScoreSheet(couples, [][]int{{101, 102, 103}, {101, 102, 104}, {101, 103, 102}}))
+	roundSheet.AddDanceSheet(newPreliminaryScoreSheet(couples, [][]int{{101, 102, 105}, {101, 103, 104}, {102, 103, 101}}))
+
+	tabulation, err := roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, 3)
+	assert.Nil(t, err)
+	assert.False(t, tabulation.HasCutoffTie())
+	assert.Equal(t, []int{101, 102, 103}, tabulation.GetRecalledCouples(false))
+	assert.Equal(t, skating.CoupleRecall{CoupleID: 101, Marks: 6, Rank: 1}, tabulation.Couples[0])
+	assert.Equal(t, skating.CoupleRecall{CoupleID: 105, Marks: 1, Rank: 5}, tabulation.Couples[4])
+}
+
+func TestRoundScoreSheet_CalculateRecall_CutoffTie(t *testing.T) {
+	couples := []int{101, 102, 103, 104, 105}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newPreliminaryScoreSheet(couples, [][]int{{101, 102, 103}, {101, 102, 104}, {101, 105, 102}}))
+
+	tabulation, err := roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, 3)
+	assert.Nil(t, err)
+	assert.True(t, tabulation.HasCutoffTie(), "couples 103, 104, and 105 are tied for the 3rd spot")
+	assert.Equal(t, []int{101, 102}, tabulation.Recalled)
+	assert.Equal(t, []int{103, 104, 105}, tabulation.Tied)
+	assert.Equal(t, 2, tabulation.GetFewerRecallSize())
+	assert.Equal(t, 5, tabulation.GetMoreRecallSize())
+	assert.Equal(t, []int{101, 102, 103, 104, 105}, tabulation.GetRecalledCouples(true))
+	assert.Equal(t, 3, tabulation.Couples[2].Rank)
+	assert.Equal(t, 3, tabulation.Couples[4].Rank)
+}
+
+func TestRoundScoreSheet_CalculateRecall_InvalidSize(t *testing.T) {
+	roundSheet := skating.NewRoundScoreSheet()
+	_, err := roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, 0)
+	assert.Equal(t, skating.INVALID_RECALL_SIZE_ERROR(0), err)
+
+	_, err = roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, 6)
+	assert.Equal(t, skating.NO_DANCE_SHEET_ERROR, err)
+
+	roundSheet.AddDanceSheet(newPreliminaryScoreSheet([]int{101, 102}, [][]int{{101}}))
+	tabulation, err := roundSheet.CalculateRecall(skating.ALGORITHM_SKATING, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{101, 102}, tabulation.GetRecalledCouples(false), "all couples should be recalled if there are not enough couples")
+}
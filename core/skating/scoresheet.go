@@ -1,5 +1,11 @@
 package skating
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
 const (
 	ALGORITHM_SKATING = "skating"
 )
@@ -9,28 +15,531 @@ const (
 	FINAL_ROUND       = "final"
 )
 
+var UNSUPPORTED_ALGORITHM_ERROR = func(algorithm string) error {
+	return errors.New(fmt.Sprintf("algorithm %v is not supported", algorithm))
+}
+
+var UNSUPPORTED_ROUND_TYPE_ERROR = func(roundType string) error {
+	return errors.New(fmt.Sprintf("placements of %v round cannot be calculated", roundType))
+}
+
+var NO_JUDGE_MARKS_ERROR = errors.New("no adjudicator has submitted marks")
+
+var NO_COUPLE_ERROR = errors.New("no couple is placed")
+
+var INCONSISTENT_COUPLES_ERROR = func(adjudicator string) error {
+	return errors.New(fmt.Sprintf("adjudicator %v did not place the same couples as other adjudicators", adjudicator))
+}
+
+// DanceScoreSheet collects the marks of all adjudicators in one dance of a round
 type DanceScoreSheet struct {
+	marks []JudgeMarks
 }
 
 func NewDanceScoreSheet() DanceScoreSheet {
-	return DanceScoreSheet{}
+	return DanceScoreSheet{
+		marks: make([]JudgeMarks, 0),
+	}
+}
+
+// AddJudgeMarks adds the marks of an adjudicator to the score sheet. The order in which marks are added is the order
+// of adjudicators in the tabulation.
+func (sheet *DanceScoreSheet) AddJudgeMarks(marks JudgeMarks) {
+	sheet.marks = append(sheet.marks, marks)
 }
 
-func (sheet *DanceScoreSheet) AddJudgeMarks(marks JudgeMarks) {}
+// GetJudgeMarks returns the marks of all adjudicators of this dance
+func (sheet DanceScoreSheet) GetJudgeMarks() []JudgeMarks {
+	return sheet.marks
+}
 
+// CalculateDancePlacements calculates the placements of couples in this dance and returns a list of
+// {placement, coupleID} that is ordered by placement, then by couple.
 func (sheet DanceScoreSheet) CalculateDancePlacements(algorithm, roundType string) ([][]int, error) {
-	return [][]int{{1, 295}, {2, 356}, {3, 281}}, nil
+	tabulation, err := sheet.CalculateDanceTabulation(algorithm, roundType)
+	if err != nil {
+		return make([][]int, 0), err
+	}
+	return tabulation.GetPlacements(), nil
+}
+
+// CalculateDanceTabulation calculates the placements of couples in this dance with Rules 5 to 8 of the Skating System,
+// and returns the detailed tabulation of each couple.
+//
+// This method ensures compliance with Rule 5, 6, 7, and 8.
+func (sheet DanceScoreSheet) CalculateDanceTabulation(algorithm, roundType string) (DanceTabulation, error) {
+	if algorithm != ALGORITHM_SKATING {
+		return DanceTabulation{}, UNSUPPORTED_ALGORITHM_ERROR(algorithm)
+	}
+	if roundType != FINAL_ROUND {
+		return DanceTabulation{}, UNSUPPORTED_ROUND_TYPE_ERROR(roundType)
+	}
+	if len(sheet.marks) == 0 {
+		return DanceTabulation{}, NO_JUDGE_MARKS_ERROR
+	}
+
+	couples := sheet.marks[0].GetPlacedCouples()
+	if len(couples) == 0 {
+		return DanceTabulation{}, NO_COUPLE_ERROR
+	}
+	for i, each := range sheet.marks {
+		if !sameCouples(couples, each.GetPlacedCouples()) {
+			return DanceTabulation{}, INCONSISTENT_COUPLES_ERROR(adjudicatorLabel(i, each.GetAdjudicator()))
+		}
+		for _, couple := range couples {
+			if placement := each.GetPlacement(couple); placement > len(couples) {
+				return DanceTabulation{}, PLACEMENT_OUTOFRANGE_ERROR(couple, placement)
+			}
+		}
+	}
+
+	calculator := newSkatingCalculator(couples, sheet.marks)
+	calculator.award(couples, 1, 1)
+	return calculator.tabulation(), nil
+}
+
+// skatingCalculator allocates the places of a single dance in a final round
+type skatingCalculator struct {
+	adjudicators []int
+	majority     int
+	columns      int
+	rows         map[int]*CoupleTabulation
+}
+
+func newSkatingCalculator(couples []int, marks []JudgeMarks) skatingCalculator {
+	calculator := skatingCalculator{
+		adjudicators: make([]int, 0),
+		majority:     len(marks)/2 + 1,
+		columns:      len(couples),
+		rows:         make(map[int]*CoupleTabulation),
+	}
+	for _, each := range marks {
+		calculator.adjudicators = append(calculator.adjudicators, each.GetAdjudicator())
+	}
+	for _, couple := range couples {
+		row := CoupleTabulation{
+			CoupleID:   couple,
+			Marks:      make([]int, len(marks)),
+			Majorities: make([]int, len(couples)),
+			Sums:       make([]int, len(couples)),
+		}
+		for i, each := range marks {
+			row.Marks[i] = each.GetPlacement(couple)
+		}
+		for column := 1; column <= len(couples); column++ {
+			for _, mark := range row.Marks {
+				if mark <= column {
+					row.Majorities[column-1]++
+					row.Sums[column-1] += mark
+				}
+			}
+		}
+		calculator.rows[couple] = &row
+	}
+	return calculator
+}
+
+// award allocates places to candidates, starting from the specified place and reviewing the specified column
+func (calculator skatingCalculator) award(candidates []int, place, column int) {
+	remaining := candidates
+	for len(remaining) > 0 && column <= calculator.columns {
+		majorities := make([]int, 0)
+		others := make([]int, 0)
+		for _, couple := range remaining {
+			if calculator.rows[couple].Majorities[column-1] >= calculator.majority {
+				majorities = append(majorities, couple)
+			} else {
+				others = append(others, couple)
+			}
+		}
+		if len(majorities) == 0 {
+			// Rule 8: no couple has a majority for the place under review, include the next place
+			column++
+			continue
+		}
+
+		groups := calculator.group(majorities, column)
+		for i, group := range groups {
+			if len(group) > 1 {
+				calculator.breakTie(group, place, column+1)
+			} else {
+				rule := RULE_5
+				if len(majorities) > 1 {
+					rule = RULE_6
+					if (i > 0 && calculator.sameMajority(groups[i-1][0], group[0], column)) ||
+						(i < len(groups)-1 && calculator.sameMajority(groups[i+1][0], group[0], column)) {
+						rule = RULE_7
+					}
+				}
+				calculator.place(group[0], place, rule, column)
+			}
+			place += len(group)
+		}
+
+		remaining = others
+		column++
+		if column < place {
+			column = place
+		}
+	}
+}
+
+// breakTie separates couples that have the same majority and sum by reviewing the following places one by one. Couples
+// that cannot be separated after reviewing all places share the places.
+func (calculator skatingCalculator) breakTie(tied []int, place, column int) {
+	if column > calculator.columns {
+		value := float64(place) + float64(len(tied)-1)/2.0
+		for _, couple := range tied {
+			row := calculator.rows[couple]
+			row.Placement = place
+			row.PlacementValue = value
+			row.Rule = RULE_8
+			row.Column = calculator.columns
+			row.Tied = true
+		}
+		return
+	}
+	for _, group := range calculator.group(tied, column) {
+		if len(group) > 1 {
+			calculator.breakTie(group, place, column+1)
+		} else {
+			calculator.place(group[0], place, RULE_8, column)
+		}
+		place += len(group)
+	}
+}
+
+// group orders couples by greater majority and then by lower sum of the specified column. Couples that have both the
+// same majority and the same sum are in the same group.
+func (calculator skatingCalculator) group(couples []int, column int) [][]int {
+	sorted := make([]int, len(couples))
+	copy(sorted, couples)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		rowI := calculator.rows[sorted[i]]
+		rowJ := calculator.rows[sorted[j]]
+		if rowI.Majorities[column-1] != rowJ.Majorities[column-1] {
+			return rowI.Majorities[column-1] > rowJ.Majorities[column-1]
+		}
+		if rowI.Sums[column-1] != rowJ.Sums[column-1] {
+			return rowI.Sums[column-1] < rowJ.Sums[column-1]
+		}
+		return rowI.CoupleID < rowJ.CoupleID
+	})
+
+	groups := make([][]int, 0)
+	for _, couple := range sorted {
+		last := len(groups) - 1
+		if last >= 0 && calculator.sameMajority(groups[last][0], couple, column) &&
+			calculator.rows[groups[last][0]].Sums[column-1] == calculator.rows[couple].Sums[column-1] {
+			groups[last] = append(groups[last], couple)
+		} else {
+			groups = append(groups, []int{couple})
+		}
+	}
+	return groups
+}
+
+func (calculator skatingCalculator) sameMajority(coupleA, coupleB, column int) bool {
+	return calculator.rows[coupleA].Majorities[column-1] == calculator.rows[coupleB].Majorities[column-1]
+}
+
+func (calculator skatingCalculator) place(couple, place, rule, column int) {
+	row := calculator.rows[couple]
+	row.Placement = place
+	row.PlacementValue = float64(place)
+	row.Rule = rule
+	row.Column = column
 }
 
+func (calculator skatingCalculator) tabulation() DanceTabulation {
+	tabulation := DanceTabulation{
+		Adjudicators: calculator.adjudicators,
+		Majority:     calculator.majority,
+		Couples:      make([]CoupleTabulation, 0),
+	}
+	for _, row := range calculator.rows {
+		tabulation.Couples = append(tabulation.Couples, *row)
+	}
+	sort.Slice(tabulation.Couples, func(i, j int) bool {
+		if tabulation.Couples[i].Placement != tabulation.Couples[j].Placement {
+			return tabulation.Couples[i].Placement < tabulation.Couples[j].Placement
+		}
+		return tabulation.Couples[i].CoupleID < tabulation.Couples[j].CoupleID
+	})
+	return tabulation
+}
+
+func sameCouples(expected, actual []int) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var NO_DANCE_SHEET_ERROR = errors.New("no dance is added to the round")
+
+var INCONSISTENT_DANCE_COUPLES_ERROR = func(dance int) error {
+	return errors.New(fmt.Sprintf("dance %v does not have the same couples as other dances", dance))
+}
+
+// RoundScoreSheet collects the score sheets of all dances in a final round
 type RoundScoreSheet struct {
+	dances []DanceScoreSheet
 }
 
 func NewRoundScoreSheet() RoundScoreSheet {
-	return RoundScoreSheet{}
+	return RoundScoreSheet{
+		dances: make([]DanceScoreSheet, 0),
+	}
 }
 
-func (sheet *RoundScoreSheet) AddDanceSheet(danceSheet DanceScoreSheet) {}
+// AddDanceSheet adds the score sheet of a dance to the round. The order in which sheets are added is the order of
+// dances in the summary.
+func (sheet *RoundScoreSheet) AddDanceSheet(danceSheet DanceScoreSheet) {
+	sheet.dances = append(sheet.dances, danceSheet)
+}
 
+// GetDanceSheets returns the score sheets of all dances of this round
+func (sheet RoundScoreSheet) GetDanceSheets() []DanceScoreSheet {
+	return sheet.dances
+}
+
+// CalculateRoundPlacements calculates the overall placements of couples in a final round and returns a list of
+// {placement, coupleID} that is ordered by placement, then by couple.
 func (sheet RoundScoreSheet) CalculateRoundPlacements(algorithm string) ([][]int, error) {
-	return make([][]int, 0), nil
+	tabulation, err := sheet.CalculateRoundTabulation(algorithm)
+	if err != nil {
+		return make([][]int, 0), err
+	}
+	return tabulation.GetPlacements(), nil
+}
+
+// CalculateRoundTabulation calculates the result of each dance, combines them into the final summary and returns
+// the summary with the rule that decided each place.
+//
+// This method ensures compliance with Rule 9, 10, and 11.
+func (sheet RoundScoreSheet) CalculateRoundTabulation(algorithm string) (RoundTabulation, error) {
+	if algorithm != ALGORITHM_SKATING {
+		return RoundTabulation{}, UNSUPPORTED_ALGORITHM_ERROR(algorithm)
+	}
+	if len(sheet.dances) == 0 {
+		return RoundTabulation{}, NO_DANCE_SHEET_ERROR
+	}
+
+	dances := make([]DanceTabulation, 0)
+	for i, each := range sheet.dances {
+		tabulation, err := each.CalculateDanceTabulation(algorithm, FINAL_ROUND)
+		if err != nil {
+			return RoundTabulation{}, err
+		}
+		if i > 0 && !sameCouples(dances[0].getCouples(), tabulation.getCouples()) {
+			return RoundTabulation{}, INCONSISTENT_DANCE_COUPLES_ERROR(i + 1)
+		}
+		dances = append(dances, tabulation)
+	}
+
+	calculator := newSummaryCalculator(dances)
+	calculator.award()
+	return calculator.tabulation(), nil
+}
+
+// summaryCalculator allocates the final places of a multi-dance final round from the results of each dance
+type summaryCalculator struct {
+	dances  []DanceTabulation
+	columns int
+	rows    map[int]*CoupleSummary
+	marks   map[int][]int // key: coupleID, value: marks of all adjudicators in all dances, used by Rule 11
+}
+
+func newSummaryCalculator(dances []DanceTabulation) summaryCalculator {
+	couples := dances[0].getCouples()
+	calculator := summaryCalculator{
+		dances:  dances,
+		columns: len(couples),
+		rows:    make(map[int]*CoupleSummary),
+		marks:   make(map[int][]int),
+	}
+	for _, couple := range couples {
+		row := CoupleSummary{
+			CoupleID: couple,
+			Places:   make([]float64, len(dances)),
+		}
+		for i, dance := range dances {
+			result, _ := dance.GetCouple(couple)
+			row.Places[i] = result.PlacementValue
+			row.Total += result.PlacementValue
+			calculator.marks[couple] = append(calculator.marks[couple], result.Marks...)
+		}
+		calculator.rows[couple] = &row
+	}
+	return calculator
+}
+
+// award allocates places by the total of places (Rule 9), and resolves couples with the same total with Rule 10
+func (calculator summaryCalculator) award() {
+	couples := make([]int, 0)
+	for couple := range calculator.rows {
+		couples = append(couples, couple)
+	}
+	sort.Slice(couples, func(i, j int) bool {
+		rowI := calculator.rows[couples[i]]
+		rowJ := calculator.rows[couples[j]]
+		if rowI.Total != rowJ.Total {
+			return rowI.Total < rowJ.Total
+		}
+		return rowI.CoupleID < rowJ.CoupleID
+	})
+
+	place := 1
+	for i := 0; i < len(couples); {
+		j := i + 1
+		for j < len(couples) && calculator.rows[couples[j]].Total == calculator.rows[couples[i]].Total {
+			j++
+		}
+		if j-i == 1 {
+			calculator.place([]int{couples[i]}, place, RULE_9)
+		} else {
+			calculator.breakTie(couples[i:j], place)
+		}
+		place += j - i
+		i = j
+	}
+}
+
+// breakTie allocates the places of couples with the same total one place at a time. The place under review is
+// awarded to the couple that won that place or higher in the most dances, then to the lower sum of those places
+// (Rule 10). Couples that are still equal are re-skated as one dance (Rule 11). The remaining couples are reviewed
+// for the next place.
+func (calculator summaryCalculator) breakTie(tied []int, place int) {
+	remaining := tied
+	rule := RULE_10
+	for len(remaining) > 0 {
+		if len(remaining) == 1 {
+			// the last couple is separated from the others by the rule that decided the previous place
+			calculator.place(remaining, place, rule)
+			return
+		}
+
+		winners := remaining
+		for column := place; column <= calculator.columns; column++ {
+			best := make([]int, 0)
+			bestCount, bestSum := 0, 0.0
+			for _, couple := range remaining {
+				count, sum := calculator.rows[couple].countPlaces(float64(column))
+				if count == 0 {
+					continue
+				}
+				if len(best) == 0 || count > bestCount || (count == bestCount && sum < bestSum) {
+					best = []int{couple}
+					bestCount, bestSum = count, sum
+				} else if count == bestCount && sum == bestSum {
+					best = append(best, couple)
+				}
+			}
+			if len(best) > 0 {
+				winners = best
+				break
+			}
+		}
+
+		if len(winners) == 1 {
+			rule = RULE_10
+			calculator.place(winners, place, rule)
+		} else {
+			rule = RULE_11
+			winners = calculator.reskate(winners, place)
+		}
+		place += len(winners)
+		remaining = exclude(remaining, winners)
+	}
+}
+
+// reskate treats the marks of all adjudicators in all dances as the marks of a single dance and awards the place
+// under review with Rules 5 to 8 (Rule 11). It returns the couples that are awarded the place, which are all the
+// couples if they cannot be separated.
+func (calculator summaryCalculator) reskate(tied []int, place int) []int {
+	majority := len(calculator.marks[tied[0]])/2 + 1
+	candidates := tied
+	for column := place; column <= calculator.columns; column++ {
+		best := make([]int, 0)
+		bestCount, bestSum := 0, 0
+		for _, couple := range candidates {
+			count, sum := 0, 0
+			for _, mark := range calculator.marks[couple] {
+				if mark <= column {
+					count++
+					sum += mark
+				}
+			}
+			if count < majority {
+				continue
+			}
+			if len(best) == 0 || count > bestCount || (count == bestCount && sum < bestSum) {
+				best = []int{couple}
+				bestCount, bestSum = count, sum
+			} else if count == bestCount && sum == bestSum {
+				best = append(best, couple)
+			}
+		}
+		if len(best) == 1 {
+			calculator.place(best, place, RULE_11)
+			return best
+		}
+		if len(best) > 1 {
+			candidates = best
+		}
+	}
+	calculator.place(candidates, place, RULE_11)
+	return candidates
+}
+
+// place awards the place to the couples. Couples that are awarded the place together share the average of their places.
+func (calculator summaryCalculator) place(couples []int, place, rule int) {
+	value := float64(place) + float64(len(couples)-1)/2.0
+	for _, couple := range couples {
+		row := calculator.rows[couple]
+		row.Placement = place
+		row.PlacementValue = value
+		row.Rule = rule
+		row.Tied = len(couples) > 1
+	}
+}
+
+func (calculator summaryCalculator) tabulation() RoundTabulation {
+	tabulation := RoundTabulation{
+		Dances:  calculator.dances,
+		Couples: make([]CoupleSummary, 0),
+	}
+	for _, row := range calculator.rows {
+		tabulation.Couples = append(tabulation.Couples, *row)
+	}
+	sort.Slice(tabulation.Couples, func(i, j int) bool {
+		if tabulation.Couples[i].Placement != tabulation.Couples[j].Placement {
+			return tabulation.Couples[i].Placement < tabulation.Couples[j].Placement
+		}
+		return tabulation.Couples[i].CoupleID < tabulation.Couples[j].CoupleID
+	})
+	return tabulation
+}
+
+func exclude(couples, excluded []int) []int {
+	result := make([]int, 0)
+	for _, couple := range couples {
+		found := false
+		for _, each := range excluded {
+			if couple == each {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, couple)
+		}
+	}
+	return result
 }
@@ -54,7 +54,7 @@ func TestDanceScoreSheet_CalculateDancePlacements(t *testing.T) {
 
 	marks4 := skating.NewJudgeMarks(3)
 	marks4.AddPlacement(281, 3)
-	marks4.AddPlacement(256, 2)
+	marks4.AddPlacement(356, 2)
 	marks4.AddPlacement(295, 1)
 
 	marks5 := skating.NewJudgeMarks(3)
@@ -65,45 +65,225 @@ func TestDanceScoreSheet_CalculateDancePlacements(t *testing.T) {
 	sheet.AddJudgeMarks(marks1)
 	sheet.AddJudgeMarks(marks2)
 	sheet.AddJudgeMarks(marks3)
+	sheet.AddJudgeMarks(marks4)
+	sheet.AddJudgeMarks(marks5)
 
 	ranks, err := sheet.CalculateDancePlacements(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
 	assert.Equal(t, [][]int{{1, 295}, {2, 356}, {3, 281}}, ranks)
 	assert.Nil(t, err)
 }
 
-/*
-func TestRoundScoreSheet_NewRoundScoreSheet (t *testing.T) {
+func TestRoundScoreSheet_CalculateRoundPlacements(t *testing.T) {
 	roundSheet := skating.NewRoundScoreSheet()
+	couples := []int{281, 356, 295}
 
-	danceSheetA := skating.NewDanceScoreSheet()
-	danceSheetB := skating.NewDanceScoreSheet()
-	danceSheetC := skating.NewDanceScoreSheet()
-
-
-	judgeMarksA1 := skating.NewJudgeMarks()
-	judgeMarksA2 := skating.NewJudgeMarks()
-	judgeMarksA3 := skating.NewJudgeMarks()
-	judgeMarksB1 := skating.NewJudgeMarks()
-	judgeMarksB2 := skating.NewJudgeMarks()
-	judgeMarksB3 := skating.NewJudgeMarks()
-	judgeMarksC1 := skating.NewJudgeMarks()
-	judgeMarksC2 := skating.NewJudgeMarks()
-	judgeMarksC3 := skating.NewJudgeMarks()
-
-	danceSheetA.AddJudgeMarks(judgeMarksA1)
-	danceSheetA.AddJudgeMarks(judgeMarksA2)
-	danceSheetA.AddJudgeMarks(judgeMarksA3)
-	danceSheetB.AddJudgeMarks(judgeMarksB1)
-	danceSheetB.AddJudgeMarks(judgeMarksB2)
-	danceSheetB.AddJudgeMarks(judgeMarksB3)
-	danceSheetC.AddJudgeMarks(judgeMarksC1)
-	danceSheetC.AddJudgeMarks(judgeMarksC2)
-	danceSheetC.AddJudgeMarks(judgeMarksC3)
-
-	roundSheet.AddDanceSheet(danceSheetA)
-	roundSheet.AddDanceSheet(danceSheetB)
-	roundSheet.AddDanceSheet(danceSheetC)
+	// cha cha, rumba, and samba
+	roundSheet.AddDanceSheet(newFinalScoreSheet(couples, [][]int{{1, 2, 3}, {2, 1, 3}, {2, 1, 3}, {2, 1, 3}, {1, 3, 2}}))
+	roundSheet.AddDanceSheet(newFinalScoreSheet(couples, [][]int{{2, 3, 1}, {3, 1, 2}, {2, 1, 3}, {3, 2, 1}, {2, 3, 1}}))
+	roundSheet.AddDanceSheet(newFinalScoreSheet(couples, [][]int{{1, 3, 2}, {3, 2, 1}, {1, 2, 3}, {3, 1, 2}, {1, 3, 2}}))
 
 	ranks, err := roundSheet.CalculateRoundPlacements(skating.ALGORITHM_SKATING)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 281}, {2, 356}, {3, 295}}, ranks)
+
+	tabulation, _ := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+	assert.Equal(t, 3, len(tabulation.Dances))
+	for _, each := range tabulation.Couples {
+		assert.Equal(t, 6.0, each.Total, "all couples should have the same total")
+		assert.Equal(t, skating.RULE_11, each.Rule, "couples that cannot be separated by Rule 10 should be re-skated")
+	}
+}
+
+// newFinalScoreSheet creates a score sheet of a final round. Each row of placements is the marks of one adjudicator
+// and each column is the placement of the couple at the same index
+func newFinalScoreSheet(couples []int, placements [][]int) skating.DanceScoreSheet {
+	sheet := skating.NewDanceScoreSheet()
+	for i, row := range placements {
+		marks := skating.NewJudgeMarks(len(couples))
+		marks.SetAdjudicator(10 + i)
+		for j, couple := range couples {
+			marks.AddPlacement(couple, row[j])
+		}
+		sheet.AddJudgeMarks(marks)
+	}
+	return sheet
+}
+
+func TestDanceScoreSheet_CalculateDanceTabulation_Majority(t *testing.T) {
+	// use cha cha
+	sheet := newFinalScoreSheet([]int{281, 356, 295}, [][]int{
+		{1, 2, 3},
+		{2, 1, 3},
+		{2, 1, 3},
+		{2, 1, 3},
+		{1, 3, 2},
+	})
+
+	tabulation, err := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 356}, {2, 281}, {3, 295}}, tabulation.GetPlacements())
+	assert.Equal(t, 3, tabulation.Majority, "majority of 5 adjudicators should be 3")
+
+	couple, found := tabulation.GetCouple(281)
+	assert.True(t, found)
+	assert.Equal(t, []int{1, 2, 2, 2, 1}, couple.Marks)
+	assert.Equal(t, []int{2, 5, 5}, couple.Majorities)
+	assert.Equal(t, skating.RULE_5, couple.Rule, "a single couple with majority should be placed by Rule 5")
+	assert.Equal(t, 2, couple.Column)
+}
+
+func TestDanceScoreSheet_CalculateDanceTabulation_GreaterMajority(t *testing.T) {
+	// use samba
+	sheet := newFinalScoreSheet([]int{281, 356, 295}, [][]int{
+		{1, 3, 2},
+		{3, 2, 1},
+		{1, 2, 3},
+		{3, 1, 2},
+		{1, 3, 2},
+	})
+
+	tabulation, err := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 281}, {2, 295}, {3, 356}}, tabulation.GetPlacements())
+
+	couple, _ := tabulation.GetCouple(295)
+	assert.Equal(t, skating.RULE_6, couple.Rule, "greater majority should be placed by Rule 6")
+}
+
+func TestDanceScoreSheet_CalculateDanceTabulation_LowerSum(t *testing.T) {
+	// use rumba
+	sheet := newFinalScoreSheet([]int{281, 356, 295}, [][]int{
+		{2, 3, 1},
+		{3, 1, 2},
+		{2, 1, 3},
+		{3, 2, 1},
+		{2, 3, 1},
+	})
+
+	tabulation, err := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 295}, {2, 356}, {3, 281}}, tabulation.GetPlacements())
+
+	couple, _ := tabulation.GetCouple(356)
+	assert.Equal(t, skating.RULE_7, couple.Rule, "equal majority with lower sum should be placed by Rule 7")
+	assert.Equal(t, 4, couple.Sums[1])
+}
+
+func TestDanceScoreSheet_CalculateDanceTabulation_FollowingPlace(t *testing.T) {
+	sheet := newFinalScoreSheet([]int{101, 102, 103, 104}, [][]int{
+		{3, 4, 1, 2},
+		{2, 1, 4, 3},
+		{1, 2, 4, 3},
+	})
+
+	tabulation, err := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {2, 102}, {3, 104}, {4, 103}}, tabulation.GetPlacements())
+
+	couple, _ := tabulation.GetCouple(101)
+	assert.Equal(t, skating.RULE_8, couple.Rule, "equal majority and sum should be separated in the following place")
+	assert.Equal(t, 3, couple.Column)
+}
+
+func TestDanceScoreSheet_CalculateDanceTabulation_Tie(t *testing.T) {
+	sheet := newFinalScoreSheet([]int{101, 102, 103}, [][]int{
+		{1, 2, 3},
+		{2, 3, 1},
+		{3, 1, 2},
+	})
+
+	tabulation, err := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {1, 102}, {1, 103}}, tabulation.GetPlacements(), "tied couples should be ordered by couple")
+	for _, each := range tabulation.Couples {
+		assert.True(t, each.Tied)
+		assert.Equal(t, 2.0, each.PlacementValue, "couples tied for 1st to 3rd place should share 2nd place")
+	}
+}
+
+func TestDanceScoreSheet_CalculateDancePlacements_InvalidMarks(t *testing.T) {
+	sheet := skating.NewDanceScoreSheet()
+	_, err := sheet.CalculateDancePlacements(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Equal(t, skating.NO_JUDGE_MARKS_ERROR, err)
+
+	sheet = newFinalScoreSheet([]int{101, 102}, [][]int{{1, 2}})
+	marks := skating.NewJudgeMarks(2)
+	marks.SetAdjudicator(11)
+	marks.AddPlacement(101, 1)
+	marks.AddPlacement(103, 2)
+	sheet.AddJudgeMarks(marks)
+	_, err = sheet.CalculateDancePlacements(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+	assert.Equal(t, skating.INCONSISTENT_COUPLES_ERROR("11"), err)
+
+	_, err = sheet.CalculateDancePlacements("unknown", skating.FINAL_ROUND)
+	assert.Equal(t, skating.UNSUPPORTED_ALGORITHM_ERROR("unknown"), err)
+}
+
+// newSingleAdjudicatorSheet creates a dance score sheet where one adjudicator decides the placement of each couple
+func newSingleAdjudicatorSheet(couples []int, placements []int) skating.DanceScoreSheet {
+	return newFinalScoreSheet(couples, [][]int{placements})
+}
+
+func TestRoundScoreSheet_CalculateRoundTabulation_TotalOfPlaces(t *testing.T) {
+	couples := []int{101, 102, 103}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2, 3}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2, 3}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2, 3}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 3, 2}))
+
+	tabulation, err := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {2, 102}, {3, 103}}, tabulation.GetPlacements())
+
+	couple, found := tabulation.GetCouple(102)
+	assert.True(t, found)
+	assert.Equal(t, []float64{2, 2, 2, 3}, couple.Places)
+	assert.Equal(t, 9.0, couple.Total)
+	assert.Equal(t, skating.RULE_9, couple.Rule)
+}
+
+func TestRoundScoreSheet_CalculateRoundTabulation_MostPlaces(t *testing.T) {
+	couples := []int{101, 102, 103, 104}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2, 3, 4}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2, 3, 4}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{4, 3, 1, 2}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{4, 3, 1, 2}))
+
+	tabulation, err := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 103}, {2, 101}, {3, 102}, {4, 104}}, tabulation.GetPlacements())
+
+	rules := make([]int, 0)
+	for _, each := range tabulation.Couples {
+		rules = append(rules, each.Rule)
+	}
+	assert.Equal(t, []int{skating.RULE_9, skating.RULE_10, skating.RULE_10, skating.RULE_9}, rules)
+}
+
+func TestRoundScoreSheet_CalculateRoundTabulation_Tie(t *testing.T) {
+	couples := []int{101, 102}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{2, 1}))
+
+	tabulation, err := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 101}, {1, 102}}, tabulation.GetPlacements())
+	for _, each := range tabulation.Couples {
+		assert.True(t, each.Tied)
+		assert.Equal(t, 1.5, each.PlacementValue)
+	}
+}
+
+func TestRoundScoreSheet_CalculateRoundPlacements_InvalidSheets(t *testing.T) {
+	roundSheet := skating.NewRoundScoreSheet()
+	_, err := roundSheet.CalculateRoundPlacements(skating.ALGORITHM_SKATING)
+	assert.Equal(t, skating.NO_DANCE_SHEET_ERROR, err)
+
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet([]int{101, 102}, []int{1, 2}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet([]int{101, 103}, []int{1, 2}))
+	_, err = roundSheet.CalculateRoundPlacements(skating.ALGORITHM_SKATING)
+	assert.Equal(t, skating.INCONSISTENT_DANCE_COUPLES_ERROR(2), err)
 }
-*/
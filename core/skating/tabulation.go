@@ -0,0 +1,204 @@
+package skating
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Rules of the Skating System that can decide a placement
+const (
+	RULE_5 = 5 // a couple has the majority for the place under review
+	RULE_6 = 6 // more than one couple has a majority, the greater majority wins
+	RULE_7 = 7 // equal majorities, the lower sum of marks wins
+	RULE_8 = 8 // equal majorities and sums, the tied couples are compared in the following place
+)
+
+// Rules of the Skating System that can decide a place in the final summary of a multi-dance final
+const (
+	RULE_9  = 9  // the couple with the lowest total of places wins
+	RULE_10 = 10 // equal totals, the couple that won the place or higher in the most dances wins
+	RULE_11 = 11 // still equal, the marks of all dances are skated as one dance
+)
+
+// CoupleTabulation is the row of a couple in the tabulation of a dance. Majorities and Sums are cumulative: the i-th
+// element counts (or sums) the marks of 1st to (i+1)-th place.
+type CoupleTabulation struct {
+	CoupleID   int
+	Marks      []int // placements given by each adjudicator, in the same order as DanceTabulation.Adjudicators
+	Majorities []int
+	Sums       []int
+	Placement  int // couples that cannot be separated all receive the highest of their shared places
+	// PlacementValue is the value of the placement when dances are combined: couples that cannot be separated share
+	// the average of their places, e.g. two couples tied for 2nd and 3rd place are both valued at 2.5
+	PlacementValue float64
+	Rule           int  // the rule that decided the placement
+	Column         int  // the column (1-based) that decided the placement
+	Tied           bool // true if the couple shares the placement with other couples
+}
+
+// DanceTabulation is the detailed result of a dance, which allows scrutineers to audit how each placement is reached
+type DanceTabulation struct {
+	Adjudicators []int
+	Majority     int
+	Couples      []CoupleTabulation // ordered by placement, then by couple
+}
+
+// GetPlacements returns the placement of each couple as a pair of {placement, coupleID}. Couples that share a placement
+// receive the highest place of the shared places, e.g. two couples sharing 2nd and 3rd place are both 2nd.
+func (tabulation DanceTabulation) GetPlacements() [][]int {
+	placements := make([][]int, 0)
+	for _, each := range tabulation.Couples {
+		placements = append(placements, []int{each.Placement, each.CoupleID})
+	}
+	return placements
+}
+
+// GetCouple returns the tabulation of the specified couple
+func (tabulation DanceTabulation) GetCouple(coupleID int) (CoupleTabulation, bool) {
+	for _, each := range tabulation.Couples {
+		if each.CoupleID == coupleID {
+			return each, true
+		}
+	}
+	return CoupleTabulation{}, false
+}
+
+func (tabulation DanceTabulation) getCouples() []int {
+	couples := make([]int, 0)
+	for _, each := range tabulation.Couples {
+		couples = append(couples, each.CoupleID)
+	}
+	sort.Ints(couples)
+	return couples
+}
+
+// String prints the tabulation in the layout of a scrutineering sheet: marks of each adjudicator, the cumulative
+// majority of each column (with sums when sums are used), the placement and the rule that decided the placement.
+func (tabulation DanceTabulation) String() string {
+	buffer := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprint(writer, "Couple\t")
+	for i, each := range tabulation.Adjudicators {
+		fmt.Fprintf(writer, "%v\t", adjudicatorLabel(i, each))
+	}
+	for i := range tabulation.Couples {
+		fmt.Fprintf(writer, "%v\t", columnLabel(i+1))
+	}
+	fmt.Fprint(writer, "Place\tRule\t\n")
+
+	for _, row := range tabulation.Couples {
+		fmt.Fprintf(writer, "%v\t", row.CoupleID)
+		for _, mark := range row.Marks {
+			fmt.Fprintf(writer, "%v\t", mark)
+		}
+		for i := range row.Majorities {
+			column := i + 1
+			if column > row.Column {
+				fmt.Fprint(writer, "\t")
+			} else if column == row.Column && (row.Rule == RULE_7 || row.Rule == RULE_8) {
+				fmt.Fprintf(writer, "%v(%v)\t", row.Majorities[i], row.Sums[i])
+			} else if row.Majorities[i] == 0 {
+				fmt.Fprint(writer, "-\t")
+			} else {
+				fmt.Fprintf(writer, "%v\t", row.Majorities[i])
+			}
+		}
+		fmt.Fprintf(writer, "%v\tR%v\t\n", formatPlacement(row.PlacementValue), row.Rule)
+	}
+	writer.Flush()
+	return buffer.String()
+}
+
+func adjudicatorLabel(index, adjudicatorID int) string {
+	if adjudicatorID > 0 {
+		return strconv.Itoa(adjudicatorID)
+	}
+	return fmt.Sprintf("#%v", index+1)
+}
+
+func columnLabel(column int) string {
+	if column == 1 {
+		return "1"
+	}
+	return fmt.Sprintf("1-%v", column)
+}
+
+func formatPlacement(placement float64) string {
+	return strconv.FormatFloat(placement, 'f', -1, 64)
+}
+
+// CoupleSummary is the row of a couple in the final summary of a multi-dance final
+type CoupleSummary struct {
+	CoupleID       int
+	Places         []float64 // placement value of each dance, in the same order as RoundTabulation.Dances
+	Total          float64
+	Placement      int
+	PlacementValue float64
+	Rule           int  // the rule that decided the placement
+	Tied           bool // true if the couple shares the placement with other couples
+}
+
+// countPlaces returns the number of dances in which the couple is placed at or above the place, and the sum of
+// those places
+func (summary CoupleSummary) countPlaces(place float64) (int, float64) {
+	count, sum := 0, 0.0
+	for _, each := range summary.Places {
+		if each <= place {
+			count++
+			sum += each
+		}
+	}
+	return count, sum
+}
+
+// RoundTabulation is the detailed result of a multi-dance final, including the tabulation of each dance
+type RoundTabulation struct {
+	Dances  []DanceTabulation
+	Couples []CoupleSummary // ordered by placement, then by couple
+}
+
+// GetPlacements returns the final placement of each couple as a pair of {placement, coupleID}
+func (tabulation RoundTabulation) GetPlacements() [][]int {
+	placements := make([][]int, 0)
+	for _, each := range tabulation.Couples {
+		placements = append(placements, []int{each.Placement, each.CoupleID})
+	}
+	return placements
+}
+
+// GetCouple returns the summary of the specified couple
+func (tabulation RoundTabulation) GetCouple(coupleID int) (CoupleSummary, bool) {
+	for _, each := range tabulation.Couples {
+		if each.CoupleID == coupleID {
+			return each, true
+		}
+	}
+	return CoupleSummary{}, false
+}
+
+// String prints the final summary: the place of each couple in each dance, the total, the final placement and the rule
+// that decided the placement.
+func (tabulation RoundTabulation) String() string {
+	buffer := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprint(writer, "Couple\t")
+	for i := range tabulation.Dances {
+		fmt.Fprintf(writer, "D%v\t", i+1)
+	}
+	fmt.Fprint(writer, "Total\tPlace\tRule\t\n")
+
+	for _, row := range tabulation.Couples {
+		fmt.Fprintf(writer, "%v\t", row.CoupleID)
+		for _, place := range row.Places {
+			fmt.Fprintf(writer, "%v\t", formatPlacement(place))
+		}
+		fmt.Fprintf(writer, "%v\t%v\tR%v\t\n", formatPlacement(row.Total), formatPlacement(row.PlacementValue), row.Rule)
+	}
+	writer.Flush()
+	return buffer.String()
+}
@@ -0,0 +1,38 @@
+package skating_test
+
+import (
+	"github.com/DancesportSoftware/das/core/skating"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDanceTabulation_String(t *testing.T) {
+	sheet := newFinalScoreSheet([]int{281, 356, 295}, [][]int{
+		{2, 3, 1},
+		{3, 1, 2},
+		{2, 1, 3},
+		{3, 2, 1},
+		{2, 3, 1},
+	})
+	tabulation, _ := sheet.CalculateDanceTabulation(skating.ALGORITHM_SKATING, skating.FINAL_ROUND)
+
+	lines := strings.Split(strings.TrimRight(tabulation.String(), "\n"), "\n")
+	assert.Equal(t, 4, len(lines), "should print a header and one line per couple")
+	assert.Equal(t, []string{"Couple", "10", "11", "12", "13", "14", "1", "1-2", "1-3", "Place", "Rule"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"295", "1", "2", "3", "1", "1", "3", "1", "R5"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"356", "3", "1", "1", "2", "3", "2", "3(4)", "2", "R7"}, strings.Fields(lines[2]))
+}
+
+func TestRoundTabulation_String(t *testing.T) {
+	couples := []int{101, 102}
+	roundSheet := skating.NewRoundScoreSheet()
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2}))
+	roundSheet.AddDanceSheet(newSingleAdjudicatorSheet(couples, []int{1, 2}))
+	tabulation, _ := roundSheet.CalculateRoundTabulation(skating.ALGORITHM_SKATING)
+
+	lines := strings.Split(strings.TrimRight(tabulation.String(), "\n"), "\n")
+	assert.Equal(t, []string{"Couple", "D1", "D2", "Total", "Place", "Rule"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"101", "1", "1", "2", "1", "R9"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"102", "2", "2", "4", "2", "R9"}, strings.Fields(lines[2]))
+}
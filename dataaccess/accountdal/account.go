@@ -257,15 +257,29 @@ func (repo PostgresAccountRepository) UpdateAccount(account businesslogic.Accoun
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	stmt := repo.SQLBuilder.Update(DasUserAccountTable)
-	if account.ID > 0 {
+	if account.ID < 1 {
+		return errors.New("account ID was not specified")
 	}
-	var err error
+	stmt := repo.SQLBuilder.Update("").
+		Table(DasUserAccountTable).
+		Set(DAS_USER_ACCOUNT_COL_USER_STATUS_ID, account.AccountStatusID).
+		Set(DAS_USER_ACCOUNT_COL_USER_GENDER_ID, account.UserGenderID).
+		Set(DAS_USER_ACCOUNT_COL_LAST_NAME, account.LastName).
+		Set(DAS_USER_ACCOUNT_COL_MIDDLE_NAMES, account.MiddleNames).
+		Set(DAS_USER_ACCOUNT_COL_FIRST_NAME, account.FirstName).
+		Set(DAS_USER_ACCOUNT_COL_DATE_OF_BIRTH, account.DateOfBirth).
+		Set(DAS_USER_ACCOUNT_COL_EMAIL, account.Email).
+		Set(DAS_USER_ACCOUNT_COL_PHONE, account.Phone).
+		Set(DAS_USER_ACCOUNT_COL_DATETIME_UPDATED, time.Now()).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: account.ID})
 	tx, txErr := repo.Database.Begin()
 	if txErr != nil {
 		return txErr
 	}
-	_, err = stmt.RunWith(repo.Database).Exec()
-	err = tx.Commit()
-	return err
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating account with ID = %v: %v", account.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,458 @@
+package accountdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+const (
+	dasAccountSecurityTable             = "DAS.ACCOUNT_SECURITY"
+	dasAccountSessionTable              = "DAS.ACCOUNT_SESSION"
+	columnAccountPasswordHash           = "PASSWORD_HASH"
+	columnAccountDateTimePasswordChange = "DATETIME_PASSWORD_CHANGED"
+	columnAccountFailedLoginAttempts    = "FAILED_LOGIN_ATTEMPTS"
+	columnAccountSessionTokenID         = "TOKEN_ID"
+	columnAccountSessionDateTimeExpires = "DATETIME_EXPIRES"
+	columnAccountSessionDateTimeRevoked = "DATETIME_REVOKED"
+	dasAccountSecurityTokenTable        = "DAS.ACCOUNT_SECURITY_TOKEN"
+	columnAccountTokenPurpose           = "PURPOSE"
+	columnAccountTokenHash              = "TOKEN_HASH"
+	columnAccountTokenDateTimeExpires   = "DATETIME_EXPIRES"
+	columnAccountTokenDateTimeUsed      = "DATETIME_USED"
+)
+
+// PostgresAccountSecurityRepository implements IAccountSecurityRepository with a Postgres database
+type PostgresAccountSecurityRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateAccountSecurity creates AccountSecurity in a Postgres database
+func (repo PostgresAccountSecurityRepository) CreateAccountSecurity(security *businesslogic.AccountSecurity) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasAccountSecurityTable).
+		Columns(
+			common.ColumnAccountID,
+			columnAccountPasswordHash,
+			columnAccountDateTimePasswordChange,
+			columnAccountFailedLoginAttempts,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			security.AccountID,
+			security.PasswordHash,
+			security.DateTimePasswordChanged,
+			security.FailedLoginAttempts,
+			security.CreateUserID,
+			security.DateTimeCreated,
+			security.UpdateUserID,
+			security.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&security.ID); scanErr != nil {
+		log.Printf("[error] creating AccountSecurity of account %v: %v", security.AccountID, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchAccountSecurity searches AccountSecurity in a Postgres database
+func (repo PostgresAccountSecurityRepository) SearchAccountSecurity(criteria businesslogic.SearchAccountSecurityCriteria) ([]businesslogic.AccountSecurity, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.ColumnAccountID,
+		columnAccountPasswordHash,
+		columnAccountDateTimePasswordChange,
+		columnAccountFailedLoginAttempts,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasAccountSecurityTable)
+	if criteria.AccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnAccountID: criteria.AccountID})
+	}
+
+	results := make([]businesslogic.AccountSecurity, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching AccountSecurity with criteria %#v: %v", criteria, err)
+		return results, err
+	}
+	for rows.Next() {
+		each := businesslogic.AccountSecurity{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.AccountID,
+			&each.PasswordHash,
+			&each.DateTimePasswordChanged,
+			&each.FailedLoginAttempts,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning AccountSecurity with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return results, scanErr
+		}
+		results = append(results, each)
+	}
+	return results, rows.Close()
+}
+
+// UpdateAccountSecurity updates AccountSecurity in a Postgres database
+func (repo PostgresAccountSecurityRepository) UpdateAccountSecurity(security businesslogic.AccountSecurity) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if security.ID < 1 {
+		return errors.New("ID of AccountSecurity must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasAccountSecurityTable).
+		Set(columnAccountPasswordHash, security.PasswordHash).
+		Set(columnAccountDateTimePasswordChange, security.DateTimePasswordChanged).
+		Set(columnAccountFailedLoginAttempts, security.FailedLoginAttempts).
+		Set(common.ColumnUpdateUserID, security.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, security.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: security.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating AccountSecurity with ID = %v: %v", security.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddFailedLogin counts a failed login of the account in a Postgres database, and returns the failed logins in a row
+func (repo PostgresAccountSecurityRepository) AddFailedLogin(accountID int) (int, error) {
+	if repo.Database == nil {
+		return 0, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasAccountSecurityTable).
+		Set(columnAccountFailedLoginAttempts, squirrel.Expr(columnAccountFailedLoginAttempts+" + 1")).
+		Set(common.ColumnDateTimeUpdated, time.Now()).
+		Where(squirrel.Eq{common.ColumnAccountID: accountID}).
+		Suffix("RETURNING " + columnAccountFailedLoginAttempts)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return 0, txErr
+	}
+	failedLogins := 0
+	if scanErr := tx.QueryRow(clause, args...).Scan(&failedLogins); scanErr != nil {
+		log.Printf("[error] counting failed login of account %v: %v", accountID, scanErr)
+		tx.Rollback()
+		return 0, scanErr
+	}
+	return failedLogins, tx.Commit()
+}
+
+// PostgresAccountSessionRepository implements IAccountSessionRepository with a Postgres database
+type PostgresAccountSessionRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateAccountSession creates AccountSession in a Postgres database
+func (repo PostgresAccountSessionRepository) CreateAccountSession(session *businesslogic.AccountSession) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasAccountSessionTable).
+		Columns(
+			common.ColumnAccountID,
+			columnAccountSessionTokenID,
+			columnAccountSessionDateTimeExpires,
+			columnAccountSessionDateTimeRevoked,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			session.AccountID,
+			session.TokenID,
+			session.DateTimeExpires,
+			session.DateTimeRevoked,
+			session.CreateUserID,
+			session.DateTimeCreated,
+			session.UpdateUserID,
+			session.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&session.ID); scanErr != nil {
+		log.Printf("[error] creating AccountSession of account %v: %v", session.AccountID, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchAccountSession searches AccountSession in a Postgres database
+func (repo PostgresAccountSessionRepository) SearchAccountSession(criteria businesslogic.SearchAccountSessionCriteria) ([]businesslogic.AccountSession, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.ColumnAccountID,
+		columnAccountSessionTokenID,
+		columnAccountSessionDateTimeExpires,
+		columnAccountSessionDateTimeRevoked,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasAccountSessionTable)
+	if criteria.AccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnAccountID: criteria.AccountID})
+	}
+	if criteria.TokenID != "" {
+		stmt = stmt.Where(squirrel.Eq{columnAccountSessionTokenID: criteria.TokenID})
+	}
+
+	sessions := make([]businesslogic.AccountSession, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching AccountSession with criteria %#v: %v", criteria, err)
+		return sessions, err
+	}
+	for rows.Next() {
+		each := businesslogic.AccountSession{}
+		revoked := sql.NullTime{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.AccountID,
+			&each.TokenID,
+			&each.DateTimeExpires,
+			&revoked,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning AccountSession with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return sessions, scanErr
+		}
+		if revoked.Valid {
+			each.DateTimeRevoked = &revoked.Time
+		}
+		sessions = append(sessions, each)
+	}
+	return sessions, rows.Close()
+}
+
+// UpdateAccountSession updates AccountSession in a Postgres database
+func (repo PostgresAccountSessionRepository) UpdateAccountSession(session businesslogic.AccountSession) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if session.ID < 1 {
+		return errors.New("ID of AccountSession must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasAccountSessionTable).
+		Set(columnAccountSessionDateTimeExpires, session.DateTimeExpires).
+		Set(columnAccountSessionDateTimeRevoked, session.DateTimeRevoked).
+		Set(common.ColumnUpdateUserID, session.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, session.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: session.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating AccountSession with ID = %v: %v", session.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PostgresAccountSecurityTokenRepository implements IAccountSecurityTokenRepository with a Postgres database
+type PostgresAccountSecurityTokenRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateAccountSecurityToken creates AccountSecurityToken in a Postgres database
+func (repo PostgresAccountSecurityTokenRepository) CreateAccountSecurityToken(token *businesslogic.AccountSecurityToken) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasAccountSecurityTokenTable).
+		Columns(
+			common.ColumnAccountID,
+			columnAccountTokenPurpose,
+			columnAccountTokenHash,
+			columnAccountTokenDateTimeExpires,
+			columnAccountTokenDateTimeUsed,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			token.AccountID,
+			token.Purpose,
+			token.TokenHash,
+			token.DateTimeExpires,
+			token.DateTimeUsed,
+			token.CreateUserID,
+			token.DateTimeCreated,
+			token.UpdateUserID,
+			token.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&token.ID); scanErr != nil {
+		log.Printf("[error] creating AccountSecurityToken of account %v: %v", token.AccountID, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchAccountSecurityToken searches AccountSecurityToken in a Postgres database
+func (repo PostgresAccountSecurityTokenRepository) SearchAccountSecurityToken(criteria businesslogic.SearchAccountSecurityTokenCriteria) ([]businesslogic.AccountSecurityToken, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.ColumnAccountID,
+		columnAccountTokenPurpose,
+		columnAccountTokenHash,
+		columnAccountTokenDateTimeExpires,
+		columnAccountTokenDateTimeUsed,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasAccountSecurityTokenTable)
+	if criteria.AccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnAccountID: criteria.AccountID})
+	}
+	if criteria.Purpose != "" {
+		stmt = stmt.Where(squirrel.Eq{columnAccountTokenPurpose: criteria.Purpose})
+	}
+	if criteria.TokenHash != "" {
+		stmt = stmt.Where(squirrel.Eq{columnAccountTokenHash: criteria.TokenHash})
+	}
+
+	tokens := make([]businesslogic.AccountSecurityToken, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching AccountSecurityToken: %v", err)
+		return tokens, err
+	}
+	for rows.Next() {
+		each := businesslogic.AccountSecurityToken{}
+		used := sql.NullTime{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.AccountID,
+			&each.Purpose,
+			&each.TokenHash,
+			&each.DateTimeExpires,
+			&used,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning AccountSecurityToken: %v", scanErr)
+			rows.Close()
+			return tokens, scanErr
+		}
+		if used.Valid {
+			each.DateTimeUsed = &used.Time
+		}
+		tokens = append(tokens, each)
+	}
+	return tokens, rows.Close()
+}
+
+// UseAccountSecurityToken marks AccountSecurityToken as used in a Postgres database if it has not been used
+func (repo PostgresAccountSecurityTokenRepository) UseAccountSecurityToken(token businesslogic.AccountSecurityToken) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if token.ID < 1 {
+		return errors.New("ID of AccountSecurityToken must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasAccountSecurityTokenTable).
+		Set(columnAccountTokenDateTimeUsed, token.DateTimeUsed).
+		Set(common.ColumnUpdateUserID, token.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, token.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: token.ID}).
+		Where(squirrel.Eq{columnAccountTokenDateTimeUsed: nil})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	result, err := stmt.RunWith(tx).Exec()
+	if err != nil {
+		log.Printf("[error] using AccountSecurityToken with ID = %v: %v", token.ID, err)
+		tx.Rollback()
+		return err
+	}
+	if used, err := result.RowsAffected(); err != nil || used != 1 {
+		tx.Rollback()
+		if err != nil {
+			return err
+		}
+		return businesslogic.InvalidSecurityTokenError
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,264 @@
+package collegiatedal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	collegiatePointOverrideTable        = "COLLEGIATE.PROFICIENCY_POINT_OVERRIDE"
+	collegiatePointOverrideHistoryTable = "COLLEGIATE.PROFICIENCY_POINT_OVERRIDE_HISTORY"
+	columnReason                        = "REASON"
+	columnActiveIndicator               = "ACTIVE_IND"
+	columnOverrideID                    = "OVERRIDE_ID"
+	columnAction                        = "ACTION"
+)
+
+// PostgresProficiencyPointOverrideRepository implements IProficiencyPointOverrideRepository with a Postgres database
+type PostgresProficiencyPointOverrideRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateProficiencyPointOverride creates a ProficiencyPointOverride in a Postgres database
+func (repo PostgresProficiencyPointOverrideRepository) CreateProficiencyPointOverride(override *businesslogic.ProficiencyPointOverride) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(collegiatePointOverrideTable).
+		Columns(
+			common.COL_ATHLETE_ID,
+			common.COL_STYLE_ID,
+			common.COL_PROFICIENCY_ID,
+			columnReason,
+			columnActiveIndicator,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			override.AthleteID,
+			override.StyleID,
+			override.ProficiencyID,
+			override.Reason,
+			override.Active,
+			override.CreateUserID,
+			override.DateTimeCreated,
+			override.UpdateUserID,
+			override.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&override.ID); scanErr != nil {
+		log.Printf("[error] creating ProficiencyPointOverride %#v: %v", override, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchProficiencyPointOverride searches ProficiencyPointOverride in a Postgres database
+func (repo PostgresProficiencyPointOverrideRepository) SearchProficiencyPointOverride(criteria businesslogic.SearchProficiencyPointOverrideCriteria) ([]businesslogic.ProficiencyPointOverride, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_ATHLETE_ID,
+		common.COL_STYLE_ID,
+		common.COL_PROFICIENCY_ID,
+		columnReason,
+		columnActiveIndicator,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(collegiatePointOverrideTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.AthleteID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_ATHLETE_ID: criteria.AthleteID})
+	}
+	if criteria.StyleID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_STYLE_ID: criteria.StyleID})
+	}
+	if criteria.ProficiencyID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_PROFICIENCY_ID: criteria.ProficiencyID})
+	}
+	if criteria.ActiveOnly {
+		stmt = stmt.Where(squirrel.Eq{columnActiveIndicator: true})
+	}
+
+	overrides := make([]businesslogic.ProficiencyPointOverride, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching ProficiencyPointOverride with criteria %#v: %v", criteria, err)
+		return overrides, err
+	}
+	for rows.Next() {
+		each := businesslogic.ProficiencyPointOverride{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.AthleteID,
+			&each.StyleID,
+			&each.ProficiencyID,
+			&each.Reason,
+			&each.Active,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning ProficiencyPointOverride with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return overrides, scanErr
+		}
+		overrides = append(overrides, each)
+	}
+	return overrides, rows.Close()
+}
+
+// UpdateProficiencyPointOverride updates the reason and status of a ProficiencyPointOverride in a Postgres database
+func (repo PostgresProficiencyPointOverrideRepository) UpdateProficiencyPointOverride(override businesslogic.ProficiencyPointOverride) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if override.ID < 1 {
+		return errors.New("ID of ProficiencyPointOverride must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(collegiatePointOverrideTable).
+		Set(columnReason, override.Reason).
+		Set(columnActiveIndicator, override.Active).
+		Set(common.ColumnUpdateUserID, override.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, override.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: override.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating ProficiencyPointOverride with ID = %v: %v", override.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PostgresProficiencyPointOverrideHistoryRepository implements IProficiencyPointOverrideHistoryRepository with a
+// Postgres database
+type PostgresProficiencyPointOverrideHistoryRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateProficiencyPointOverrideHistory creates an entry in the history of overrides in a Postgres database
+func (repo PostgresProficiencyPointOverrideHistoryRepository) CreateProficiencyPointOverrideHistory(entry *businesslogic.ProficiencyPointOverrideHistoryEntry) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(collegiatePointOverrideHistoryTable).
+		Columns(
+			columnOverrideID,
+			common.COL_ATHLETE_ID,
+			columnAction,
+			common.COL_NOTE,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			entry.OverrideID,
+			entry.AthleteID,
+			entry.Action,
+			entry.Note,
+			entry.CreateUserID,
+			entry.DateTimeCreated,
+			entry.UpdateUserID,
+			entry.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&entry.ID); scanErr != nil {
+		log.Printf("[error] creating ProficiencyPointOverrideHistoryEntry %#v: %v", entry, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchProficiencyPointOverrideHistory searches the history of overrides in a Postgres database. Entries are ordered
+// by the time they were created.
+func (repo PostgresProficiencyPointOverrideHistoryRepository) SearchProficiencyPointOverrideHistory(criteria businesslogic.SearchProficiencyPointOverrideHistoryCriteria) ([]businesslogic.ProficiencyPointOverrideHistoryEntry, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnOverrideID,
+		common.COL_ATHLETE_ID,
+		columnAction,
+		common.COL_NOTE,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(collegiatePointOverrideHistoryTable).
+		OrderBy(common.ColumnDateTimeCreated, common.ColumnPrimaryKey)
+	if criteria.OverrideID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnOverrideID: criteria.OverrideID})
+	}
+	if criteria.AthleteID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_ATHLETE_ID: criteria.AthleteID})
+	}
+
+	history := make([]businesslogic.ProficiencyPointOverrideHistoryEntry, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching ProficiencyPointOverrideHistoryEntry with criteria %#v: %v", criteria, err)
+		return history, err
+	}
+	for rows.Next() {
+		each := businesslogic.ProficiencyPointOverrideHistoryEntry{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.OverrideID,
+			&each.AthleteID,
+			&each.Action,
+			&each.Note,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning ProficiencyPointOverrideHistoryEntry with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return history, scanErr
+		}
+		history = append(history, each)
+	}
+	return history, rows.Close()
+}
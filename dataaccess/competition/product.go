@@ -0,0 +1,467 @@
+package competition
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasCompetitionProductCategoryTable = "DAS.COMPETITION_PRODUCT_CATEGORY"
+	dasCompetitionProductTable         = "DAS.COMPETITION_PRODUCT"
+	dasCompetitionProductOrderTable    = "DAS.COMPETITION_PRODUCT_ORDER"
+	columnProductCategoryID            = "PRODUCT_CATEGORY_ID"
+	columnProductTitle                 = "PRODUCT_TITLE"
+	columnProductStatusID              = "PRODUCT_STATUS_ID"
+	columnProductDetail                = "PRODUCT_DETAIL"
+	columnProductCost                  = "PRODUCT_COST"
+	columnMaximumAmount                = "MAXIMUM_AMOUNT"
+	columnAvailableAmount              = "AVAILABLE_AMOUNT"
+	columnEffectiveStart               = "EFFECTIVE_START"
+	columnEffectiveEnd                 = "EFFECTIVE_END"
+	columnProductID                    = "PRODUCT_ID"
+	columnUserAccountID                = "USER_ACCOUNT_ID"
+	columnQuantity                     = "QUANTITY"
+	columnTotalCost                    = "TOTAL_COST"
+	columnOrderStatusID                = "ORDER_STATUS_ID"
+)
+
+// PostgresProductCategoryRepository implements IProductCategoryRepository with a Postgres database
+type PostgresProductCategoryRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// GetProductCategories returns all the product categories in a Postgres database
+func (repo PostgresProductCategoryRepository) GetProductCategories() ([]businesslogic.ProductCategory, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_NAME,
+		common.COL_DESCRIPTION,
+		common.ColumnDateTimeCreated,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionProductCategoryTable).
+		OrderBy(common.ColumnPrimaryKey)
+	categories := make([]businesslogic.ProductCategory, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		return categories, err
+	}
+	for rows.Next() {
+		each := businesslogic.ProductCategory{}
+		description := sql.NullString{}
+		if scanErr := rows.Scan(&each.ID, &each.Name, &description, &each.DateTimeCreated, &each.DateTimeUpdated); scanErr != nil {
+			rows.Close()
+			return categories, scanErr
+		}
+		each.Description = description.String
+		categories = append(categories, each)
+	}
+	return categories, rows.Close()
+}
+
+// PostgresCompetitionProductRepository implements ICompetitionProductRepository with a Postgres database. Products
+// with unlimited inventory are stored with NULL amounts.
+type PostgresCompetitionProductRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+func nullableAmount(amount int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(amount), Valid: amount > 0}
+}
+
+// CreateCompetitionProduct creates a CompetitionProduct in a Postgres database
+func (repo PostgresCompetitionProductRepository) CreateCompetitionProduct(product *businesslogic.CompetitionProduct) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionProductTable).
+		Columns(
+			common.COL_COMPETITION_ID,
+			columnProductCategoryID,
+			columnProductTitle,
+			columnProductStatusID,
+			columnProductDetail,
+			columnProductCost,
+			columnMaximumAmount,
+			columnAvailableAmount,
+			columnEffectiveStart,
+			columnEffectiveEnd,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			product.CompetitionID,
+			product.CategoryID,
+			product.Title,
+			product.StatusID,
+			product.Detail,
+			product.Cost,
+			nullableAmount(product.MaximumAmount),
+			sql.NullInt64{Int64: int64(product.AvailableAmount), Valid: product.MaximumAmount > 0},
+			product.EffectiveStart,
+			product.EffectiveEnd,
+			product.CreateUserID,
+			product.DateTimeCreated,
+			product.UpdateUserID,
+			product.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&product.ID); scanErr != nil {
+		log.Printf("[error] creating CompetitionProduct %#v: %v", product, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// DeleteCompetitionProduct deletes a CompetitionProduct from a Postgres database
+func (repo PostgresCompetitionProductRepository) DeleteCompetitionProduct(product businesslogic.CompetitionProduct) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if product.ID < 1 {
+		return errors.New("ID of CompetitionProduct must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasCompetitionProductTable).Where(squirrel.Eq{common.ColumnPrimaryKey: product.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchCompetitionProduct searches CompetitionProduct in a Postgres database
+func (repo PostgresCompetitionProductRepository) SearchCompetitionProduct(criteria businesslogic.SearchCompetitionProductCriteria) ([]businesslogic.CompetitionProduct, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_COMPETITION_ID,
+		columnProductCategoryID,
+		columnProductTitle,
+		columnProductStatusID,
+		fmt.Sprintf("COALESCE(%s, '')", columnProductDetail),
+		columnProductCost,
+		fmt.Sprintf("COALESCE(%s, 0)", columnMaximumAmount),
+		fmt.Sprintf("COALESCE(%s, 0)", columnAvailableAmount),
+		columnEffectiveStart,
+		columnEffectiveEnd,
+		fmt.Sprintf("COALESCE(%s, 0)", common.ColumnCreateUserID),
+		common.ColumnDateTimeCreated,
+		fmt.Sprintf("COALESCE(%s, 0)", common.ColumnUpdateUserID),
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionProductTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+	if criteria.CategoryID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnProductCategoryID: criteria.CategoryID})
+	}
+	if criteria.StatusID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnProductStatusID: criteria.StatusID})
+	}
+
+	products := make([]businesslogic.CompetitionProduct, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching CompetitionProduct with criteria %#v: %v", criteria, err)
+		return products, err
+	}
+	for rows.Next() {
+		each := businesslogic.CompetitionProduct{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.CompetitionID,
+			&each.CategoryID,
+			&each.Title,
+			&each.StatusID,
+			&each.Detail,
+			&each.Cost,
+			&each.MaximumAmount,
+			&each.AvailableAmount,
+			&each.EffectiveStart,
+			&each.EffectiveEnd,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning CompetitionProduct with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return products, scanErr
+		}
+		products = append(products, each)
+	}
+	return products, rows.Close()
+}
+
+// UpdateCompetitionProduct updates a CompetitionProduct in a Postgres database. The available amount is adjusted by
+// the change of the maximum amount in the same statement, so that orders placed at the same time are not lost. The
+// check constraint of the table rejects a maximum amount that is less than the amount that has been sold.
+func (repo PostgresCompetitionProductRepository) UpdateCompetitionProduct(product businesslogic.CompetitionProduct) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if product.ID < 1 {
+		return errors.New("ID of CompetitionProduct must be specified")
+	}
+	maximum := nullableAmount(product.MaximumAmount)
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasCompetitionProductTable).
+		Set(columnProductCategoryID, product.CategoryID).
+		Set(columnProductTitle, product.Title).
+		Set(columnProductStatusID, product.StatusID).
+		Set(columnProductDetail, product.Detail).
+		Set(columnProductCost, product.Cost).
+		Set(columnAvailableAmount, squirrel.Expr(fmt.Sprintf(
+			"CASE WHEN CAST(? AS INTEGER) IS NULL THEN NULL WHEN %s IS NULL THEN CAST(? AS INTEGER) ELSE %s + CAST(? AS INTEGER) - %s END",
+			columnMaximumAmount, columnAvailableAmount, columnMaximumAmount), maximum, maximum, maximum)).
+		Set(columnMaximumAmount, maximum).
+		Set(columnEffectiveStart, product.EffectiveStart).
+		Set(columnEffectiveEnd, product.EffectiveEnd).
+		Set(common.ColumnUpdateUserID, product.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, product.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: product.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating CompetitionProduct with ID = %v: %v", product.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PostgresProductOrderRepository implements IProductOrderRepository with a Postgres database
+type PostgresProductOrderRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateProductOrder decrements the available amount of the product and creates the order in one transaction. The
+// decrement only succeeds if the product is active and has enough inventory, and the row lock that it takes makes
+// concurrent orders of the same product wait for each other, so products are never oversold.
+func (repo PostgresProductOrderRepository) CreateProductOrder(order *businesslogic.ProductOrder) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	reserve := repo.SQLBuilder.Update("").
+		Table(dasCompetitionProductTable).
+		Set(columnAvailableAmount, squirrel.Expr(fmt.Sprintf("%s - ?", columnAvailableAmount), order.Quantity)).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: order.ProductID}).
+		Where(squirrel.Eq{columnProductStatusID: businesslogic.ProductStatusActive}).
+		Where(squirrel.Or{
+			squirrel.Eq{columnAvailableAmount: nil},
+			squirrel.GtOrEq{columnAvailableAmount: order.Quantity},
+		})
+	insert := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionProductOrderTable).
+		Columns(
+			common.COL_COMPETITION_ID,
+			columnProductID,
+			columnUserAccountID,
+			columnQuantity,
+			columnTotalCost,
+			columnOrderStatusID,
+			common.ColumnDateTimeCreated,
+			common.ColumnDateTimeUpdated).
+		Values(
+			order.CompetitionID,
+			order.ProductID,
+			order.UserAccountID,
+			order.Quantity,
+			order.TotalCost,
+			order.StatusID,
+			order.DateTimeCreated,
+			order.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	result, err := reserve.RunWith(tx).Exec()
+	if err != nil {
+		log.Printf("[error] reserving %v of CompetitionProduct %v: %v", order.Quantity, order.ProductID, err)
+		tx.Rollback()
+		return err
+	}
+	if reserved, err := result.RowsAffected(); err != nil || reserved != 1 {
+		tx.Rollback()
+		if err != nil {
+			return err
+		}
+		return businesslogic.ProductSoldOutError
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&order.ID); scanErr != nil {
+		log.Printf("[error] creating ProductOrder %#v: %v", order, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchProductOrder searches ProductOrder in a Postgres database
+func (repo PostgresProductOrderRepository) SearchProductOrder(criteria businesslogic.SearchProductOrderCriteria) ([]businesslogic.ProductOrder, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_COMPETITION_ID,
+		columnProductID,
+		columnUserAccountID,
+		columnQuantity,
+		columnTotalCost,
+		columnOrderStatusID,
+		common.ColumnDateTimeCreated,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionProductOrderTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+	if criteria.ProductID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnProductID: criteria.ProductID})
+	}
+	if criteria.UserAccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnUserAccountID: criteria.UserAccountID})
+	}
+
+	orders := make([]businesslogic.ProductOrder, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching ProductOrder with criteria %#v: %v", criteria, err)
+		return orders, err
+	}
+	for rows.Next() {
+		each := businesslogic.ProductOrder{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.CompetitionID,
+			&each.ProductID,
+			&each.UserAccountID,
+			&each.Quantity,
+			&each.TotalCost,
+			&each.StatusID,
+			&each.DateTimeCreated,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning ProductOrder with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return orders, scanErr
+		}
+		orders = append(orders, each)
+	}
+	return orders, rows.Close()
+}
+
+// UpdateProductOrder updates the status of a ProductOrder in a Postgres database
+func (repo PostgresProductOrderRepository) UpdateProductOrder(order businesslogic.ProductOrder) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if order.ID < 1 {
+		return errors.New("ID of ProductOrder must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasCompetitionProductOrderTable).
+		Set(columnOrderStatusID, order.StatusID).
+		Set(common.ColumnDateTimeUpdated, order.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: order.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating ProductOrder with ID = %v: %v", order.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// CancelProductOrder changes the status of an order that is still pending or paid, and returns its quantity to the
+// available amount of the product in the same transaction. Orders that have been cancelled or refunded are not
+// changed and return an error, so that their products are only released once.
+func (repo PostgresProductOrderRepository) CancelProductOrder(order businesslogic.ProductOrder) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if order.ID < 1 {
+		return errors.New("ID of ProductOrder must be specified")
+	}
+	cancel := repo.SQLBuilder.Update("").
+		Table(dasCompetitionProductOrderTable).
+		Set(columnOrderStatusID, order.StatusID).
+		Set(common.ColumnDateTimeUpdated, order.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: order.ID}).
+		Where(squirrel.Eq{columnOrderStatusID: []int{businesslogic.ProductOrderStatusPending, businesslogic.ProductOrderStatusPaid}})
+	release := repo.SQLBuilder.Update("").
+		Table(dasCompetitionProductTable).
+		Set(columnAvailableAmount, squirrel.Expr(fmt.Sprintf("%s + ?", columnAvailableAmount), order.Quantity)).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: order.ProductID})
+
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	result, err := cancel.RunWith(tx).Exec()
+	if err != nil {
+		log.Printf("[error] cancelling ProductOrder with ID = %v: %v", order.ID, err)
+		tx.Rollback()
+		return err
+	}
+	if cancelled, err := result.RowsAffected(); err != nil || cancelled != 1 {
+		tx.Rollback()
+		if err != nil {
+			return err
+		}
+		return errors.New(fmt.Sprintf("ProductOrder %d is not pending or paid", order.ID))
+	}
+	if _, err := release.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] releasing %v of CompetitionProduct %v: %v", order.Quantity, order.ProductID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
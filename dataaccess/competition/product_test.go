@@ -0,0 +1,36 @@
+package competition_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/competition"
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"testing"
+)
+
+var productOrderRepository = competition.PostgresProductOrderRepository{
+	Database:   nil,
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+func TestPostgresProductOrderRepository_CancelProductOrder(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	order := businesslogic.ProductOrder{ID: 11, ProductID: 5, Quantity: 2, StatusID: businesslogic.ProductOrderStatusCancelled}
+	repo := productOrderRepository
+	repo.Database = db
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE DAS.COMPETITION_PRODUCT_ORDER SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE DAS.COMPETITION_PRODUCT SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.Nil(t, repo.CancelProductOrder(order))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE DAS.COMPETITION_PRODUCT_ORDER SET`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.NotNil(t, repo.CancelProductOrder(order), "should not cancel an order that is no longer pending or paid")
+	assert.Nil(t, mock.ExpectationsWereMet(), "should not release the products of an order that is not cancelled")
+}
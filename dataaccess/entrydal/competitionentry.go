@@ -25,6 +25,7 @@ const (
 	dasAthleteCompetitionEntryColumnLeadIndicator = "DAS.COMPETITION_ENTRY_ATHLETE.LEAD_INDICATOR"
 	dasAthleteCompetitionEntryColumnLeadTag       = "DAS.COMPETITION_ENTRY_ATHLETE.LEAD_TAG"
 	dasAthleteCompetitionEntryColumnOrganizerNote = "DAS.COMPETITION_ENTRY_ATHLETE.ORGANIZER_NOTE"
+	dasAthleteCompetitionEntryColumnPaymentInd    = "DAS.COMPETITION_ENTRY_ATHLETE.PAYMENT_IND"
 )
 
 // PostgresAthleteCompetitionEntryRepository is a Postgres-based Athlete Competition Entry Repository
@@ -91,7 +92,7 @@ func (repo PostgresAthleteCompetitionEntryRepository) SearchEntry(criteria busin
 	if repo.Database == nil {
 		return entries, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	clause := repo.SQLBuilder.Select(fmt.Sprintf("%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s",
+	clause := repo.SQLBuilder.Select(fmt.Sprintf("%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s",
 		common.ColumnPrimaryKey,
 		common.COL_COMPETITION_ID,
 		common.COL_ATHLETE_ID,
@@ -100,6 +101,7 @@ func (repo PostgresAthleteCompetitionEntryRepository) SearchEntry(criteria busin
 		dasAthleteCompetitionEntryColumnOrganizerNote,
 		dasCompetitionEntryColCheckinInd,
 		dasCompetitionEntryColCheckinDateTime,
+		dasAthleteCompetitionEntryColumnPaymentInd,
 		common.ColumnCreateUserID,
 		common.ColumnDateTimeCreated,
 		common.ColumnUpdateUserID,
@@ -143,6 +145,7 @@ func (repo PostgresAthleteCompetitionEntryRepository) SearchEntry(criteria busin
 			&each.OrganizerNote,
 			&each.CheckedIn,
 			&each.DateTimeCheckedIn,
+			&each.PaymentReceivedIndicator,
 			&each.CreateUserID,
 			&each.DateTimeCreated,
 			&each.UpdateUserID,
@@ -192,17 +195,23 @@ func (repo PostgresAthleteCompetitionEntryRepository) UpdateEntry(entry business
 	stmt := repo.SQLBuilder.Update("").Table(dasAthleteCompetitionEntryTable).
 		Set(dasCompetitionEntryColCheckinInd, entry.CheckedIn).
 		Set(dasCompetitionEntryColCheckinDateTime, entry.DateTimeCheckedIn).
-		Set(dasAthleteCompetitionEntryColumnLeadIndicator, entry.IsLead).
-		Set(dasAthleteCompetitionEntryColumnLeadTag, entry.LeadTag).
-		Set(dasAthleteCompetitionEntryColumnOrganizerNote, entry.OrganizerNote)
-	var err error
-	if tx, txErr := repo.Database.Begin(); txErr != nil {
+		Set("LEAD_INDICATOR", entry.IsLead).
+		Set("LEAD_TAG", entry.LeadTag).
+		Set("ORGANIZER_NOTE", entry.OrganizerNote).
+		Set("PAYMENT_IND", entry.PaymentReceivedIndicator).
+		Set(common.ColumnUpdateUserID, entry.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, entry.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
 		return txErr
-	} else {
-		_, err = stmt.RunWith(repo.Database).Exec()
-		err = tx.Commit()
 	}
-	return err
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating competition entry with ID = %v: %v", entry.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 func (repo PostgresAthleteCompetitionEntryRepository) NextAvailableLeadTag(competition businesslogic.Competition) (int, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/DancesportSoftware/das/dataaccess/common"
 	"github.com/DancesportSoftware/das/dataaccess/util"
 	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"log"
 )
 
@@ -15,8 +16,31 @@ const (
 	dasCompetitionLeadTagTable                = "DAS.COMPETITION_LEAD_TAG"
 	dasCompetitionLeadTagTableColumnLeadID    = "DAS.COMPETITION_LEAD_TAG.LEAD_ID"
 	dasCompetitionLeadTagTableColumnTagNumber = "DAS.COMPETITION_LEAD_TAG.TAG_NUMBER"
+
+	// pqUniqueViolation is the Postgres error code of unique_violation
+	pqUniqueViolation = "23505"
+	// pqExclusionViolation is the Postgres error code of exclusion_violation
+	pqExclusionViolation = "23P01"
 )
 
+// leadTagError returns businesslogic.LeadTagTakenError if the tag violates the unique constraint of tags at a
+// competition, so that the caller can pick another tag
+func leadTagError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+		return businesslogic.LeadTagTakenError
+	}
+	return err
+}
+
+// leadTagRangeError returns businesslogic.LeadTagRangeOverlapError if the range overlaps with another range of the
+// competition, so that the caller can pick another range
+func leadTagRangeError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqExclusionViolation {
+		return businesslogic.LeadTagRangeOverlapError
+	}
+	return err
+}
+
 type PostgresCompetitionLeadTagRepository struct {
 	Database   *sql.DB
 	SQLBuilder squirrel.StatementBuilderType
@@ -46,19 +70,18 @@ func (repo PostgresCompetitionLeadTagRepository) CreateCompetitionLeadTag(tag *b
 			tag.UpdateUserID,
 			tag.DateTimeUpdated).Suffix(dalutil.SQLSuffixReturningID)
 	clause, args, err := stmt.ToSql()
-	if tx, txErr := repo.Database.Begin(); txErr != nil {
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
 		return txErr
-	} else {
-		row := repo.Database.QueryRow(clause, args...)
-		scanErr := row.Scan(&tag.ID)
-		if scanErr != nil {
-			return scanErr
-		}
-		if commitErr := tx.Commit(); commitErr != nil {
-			return commitErr
-		}
 	}
-	return err
+	if scanErr := tx.QueryRow(clause, args...).Scan(&tag.ID); scanErr != nil {
+		tx.Rollback()
+		return leadTagError(scanErr)
+	}
+	return tx.Commit()
 }
 
 // DeleteCompetitionLeadTag deletes the tag from a Postgres database by the ID of the tag
@@ -73,9 +96,10 @@ func (repo PostgresCompetitionLeadTagRepository) DeleteCompetitionLeadTag(tag bu
 		if tx, txErr := repo.Database.Begin(); txErr != nil {
 			return txErr
 		} else {
-			_, err = stmt.RunWith(repo.Database).Exec()
+			_, err = stmt.RunWith(tx).Exec()
 			if err != nil {
 				log.Printf("[error] go error while deleting Competition Lead Tag with ID = %v: %v", tag.ID, err)
+				tx.Rollback()
 				return err
 			}
 			return tx.Commit()
@@ -107,7 +131,7 @@ func (repo PostgresCompetitionLeadTagRepository) SearchCompetitionLeadTag(criter
 		clause = clause.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
 	}
 	if criteria.CompetitionID > 0 {
-		clause = clause.Where(squirrel.Eq{dasCompetitionLeadTagTableColumnTagNumber: criteria.CompetitionID})
+		clause = clause.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
 	}
 	if criteria.LeadID > 0 {
 		clause = clause.Where(squirrel.Eq{dasCompetitionLeadTagTableColumnLeadID: criteria.LeadID})
@@ -136,11 +160,13 @@ func (repo PostgresCompetitionLeadTagRepository) SearchCompetitionLeadTag(criter
 			&each.UpdateUserID,
 			&each.DateTimeUpdated)
 		if scanErr != nil {
-			return collection, err
+			rows.Close()
+			return collection, scanErr
 		}
 		tags = append(tags, each)
 	}
 	err = rows.Close()
+	collection.SetTags(tags)
 
 	return collection, err
 }
@@ -155,19 +181,19 @@ func (repo PostgresCompetitionLeadTagRepository) UpdateCompetitionLeadTag(tag bu
 		stmt = stmt.Set(common.COL_COMPETITION_ID, tag.CompetitionID).
 			Set(dasCompetitionLeadTagTableColumnLeadID, tag.LeadID).
 			Set(dasCompetitionLeadTagTableColumnTagNumber, tag.Tag).
-			Set(common.ColumnUpdateUserID, tag.DateTimeCreated).
-			Set(common.ColumnDateTimeUpdated, tag.DateTimeUpdated)
+			Set(common.ColumnUpdateUserID, tag.UpdateUserID).
+			Set(common.ColumnDateTimeUpdated, tag.DateTimeUpdated).
+			Where(squirrel.Eq{common.ColumnPrimaryKey: tag.ID})
 	} else {
 		return errors.New("ID of CompetitionLeadTag must be specified")
 	}
-	var err error
-	if tx, txErr := repo.Database.Begin(); txErr != nil {
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
 		return txErr
-	} else {
-		_, err = stmt.RunWith(repo.Database).Exec()
-		if commitErr := tx.Commit(); commitErr != nil {
-			return commitErr
-		}
 	}
-	return err
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return leadTagError(err)
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,287 @@
+package entrydal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasCompetitionLeadTagSettingTable = "DAS.COMPETITION_LEAD_TAG_SETTING"
+	dasCompetitionLeadTagRangeTable   = "DAS.COMPETITION_LEAD_TAG_RANGE"
+	columnLeadTagNumbering            = "NUMBERING"
+	columnLeadTagFirstTag             = "FIRST_TAG"
+	columnLeadTagLastTag              = "LAST_TAG"
+	columnLeadTagBlockSize            = "BLOCK_SIZE"
+	columnLeadTagSchoolID             = "SCHOOL_ID"
+	columnLeadTagStudioID             = "STUDIO_ID"
+)
+
+// PostgresCompetitionLeadTagSettingsRepository implements ICompetitionLeadTagSettingsRepository with a Postgres database
+type PostgresCompetitionLeadTagSettingsRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateCompetitionLeadTagSettings creates CompetitionLeadTagSettings in a Postgres database
+func (repo PostgresCompetitionLeadTagSettingsRepository) CreateCompetitionLeadTagSettings(settings *businesslogic.CompetitionLeadTagSettings) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionLeadTagSettingTable).
+		Columns(
+			common.COL_COMPETITION_ID,
+			columnLeadTagNumbering,
+			columnLeadTagFirstTag,
+			columnLeadTagBlockSize,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			settings.CompetitionID,
+			settings.Numbering,
+			settings.FirstTag,
+			settings.BlockSize,
+			settings.CreateUserID,
+			settings.DateTimeCreated,
+			settings.UpdateUserID,
+			settings.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&settings.ID); scanErr != nil {
+		log.Printf("[error] creating CompetitionLeadTagSettings %#v: %v", settings, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchCompetitionLeadTagSettings searches CompetitionLeadTagSettings in a Postgres database
+func (repo PostgresCompetitionLeadTagSettingsRepository) SearchCompetitionLeadTagSettings(criteria businesslogic.SearchCompetitionLeadTagSettingsCriteria) ([]businesslogic.CompetitionLeadTagSettings, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_COMPETITION_ID,
+		columnLeadTagNumbering,
+		columnLeadTagFirstTag,
+		columnLeadTagBlockSize,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionLeadTagSettingTable)
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+
+	settings := make([]businesslogic.CompetitionLeadTagSettings, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching CompetitionLeadTagSettings with criteria %#v: %v", criteria, err)
+		return settings, err
+	}
+	for rows.Next() {
+		each := businesslogic.CompetitionLeadTagSettings{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.CompetitionID,
+			&each.Numbering,
+			&each.FirstTag,
+			&each.BlockSize,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning CompetitionLeadTagSettings with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return settings, scanErr
+		}
+		settings = append(settings, each)
+	}
+	return settings, rows.Close()
+}
+
+// UpdateCompetitionLeadTagSettings updates CompetitionLeadTagSettings in a Postgres database
+func (repo PostgresCompetitionLeadTagSettingsRepository) UpdateCompetitionLeadTagSettings(settings businesslogic.CompetitionLeadTagSettings) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if settings.ID < 1 {
+		return errors.New("ID of CompetitionLeadTagSettings must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasCompetitionLeadTagSettingTable).
+		Set(columnLeadTagNumbering, settings.Numbering).
+		Set(columnLeadTagFirstTag, settings.FirstTag).
+		Set(columnLeadTagBlockSize, settings.BlockSize).
+		Set(common.ColumnUpdateUserID, settings.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, settings.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: settings.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating CompetitionLeadTagSettings with ID = %v: %v", settings.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PostgresCompetitionLeadTagRangeRepository implements ICompetitionLeadTagRangeRepository with a Postgres database
+type PostgresCompetitionLeadTagRangeRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateCompetitionLeadTagRange creates a CompetitionLeadTagRange in a Postgres database
+func (repo PostgresCompetitionLeadTagRangeRepository) CreateCompetitionLeadTagRange(tagRange *businesslogic.CompetitionLeadTagRange) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionLeadTagRangeTable).
+		Columns(
+			common.COL_COMPETITION_ID,
+			columnLeadTagFirstTag,
+			columnLeadTagLastTag,
+			columnLeadTagSchoolID,
+			columnLeadTagStudioID,
+			common.COL_DESCRIPTION,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			tagRange.CompetitionID,
+			tagRange.FirstTag,
+			tagRange.LastTag,
+			tagRange.SchoolID,
+			tagRange.StudioID,
+			tagRange.Description,
+			tagRange.CreateUserID,
+			tagRange.DateTimeCreated,
+			tagRange.UpdateUserID,
+			tagRange.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&tagRange.ID); scanErr != nil {
+		log.Printf("[error] creating CompetitionLeadTagRange %#v: %v", tagRange, scanErr)
+		tx.Rollback()
+		return leadTagRangeError(scanErr)
+	}
+	return tx.Commit()
+}
+
+// DeleteCompetitionLeadTagRange deletes a CompetitionLeadTagRange from a Postgres database
+func (repo PostgresCompetitionLeadTagRangeRepository) DeleteCompetitionLeadTagRange(tagRange businesslogic.CompetitionLeadTagRange) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if tagRange.ID < 1 {
+		return errors.New("ID of CompetitionLeadTagRange must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasCompetitionLeadTagRangeTable).Where(squirrel.Eq{common.ColumnPrimaryKey: tagRange.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchCompetitionLeadTagRange searches CompetitionLeadTagRange in a Postgres database
+func (repo PostgresCompetitionLeadTagRangeRepository) SearchCompetitionLeadTagRange(criteria businesslogic.SearchCompetitionLeadTagRangeCriteria) ([]businesslogic.CompetitionLeadTagRange, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_COMPETITION_ID,
+		columnLeadTagFirstTag,
+		columnLeadTagLastTag,
+		columnLeadTagSchoolID,
+		columnLeadTagStudioID,
+		fmt.Sprintf("COALESCE(%s, '')", common.COL_DESCRIPTION),
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionLeadTagRangeTable).
+		OrderBy(columnLeadTagFirstTag)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+
+	ranges := make([]businesslogic.CompetitionLeadTagRange, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching CompetitionLeadTagRange with criteria %#v: %v", criteria, err)
+		return ranges, err
+	}
+	for rows.Next() {
+		each := businesslogic.CompetitionLeadTagRange{}
+		schoolID := sql.NullInt64{}
+		studioID := sql.NullInt64{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.CompetitionID,
+			&each.FirstTag,
+			&each.LastTag,
+			&schoolID,
+			&studioID,
+			&each.Description,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning CompetitionLeadTagRange with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return ranges, scanErr
+		}
+		if schoolID.Valid {
+			id := int(schoolID.Int64)
+			each.SchoolID = &id
+		}
+		if studioID.Valid {
+			id := int(studioID.Int64)
+			each.StudioID = &id
+		}
+		ranges = append(ranges, each)
+	}
+	return ranges, rows.Close()
+}
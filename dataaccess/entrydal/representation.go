@@ -2,7 +2,20 @@ package entrydal
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
 	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasPartnershipCompetitionRepresentationTable = "DAS.COMPETITION_REPRESENTATION_PARTNERSHIP"
+	columnCompetitionEntryID                     = "COMPETITION_ENTRY_ID"
+	columnStudioID                               = "STUDIO_ID"
+	columnSchoolID                               = "SCHOOL_ID"
 )
 
 // PostgresPartnershipCompetitionRepresentationRepository implements IPartnershipCompetitionRepresentationRepository with a Postgres database
@@ -12,11 +25,117 @@ type PostgresPartnershipCompetitionRepresentationRepository struct {
 }
 
 // CreateCompetitionRepresentation creates a PartnershipCompetitionRepresentation in a Postgres database
-func (repo PostgresPartnershipCompetitionRepresentationRepository) CreateCompetitionRepresentation() {
-
+func (repo PostgresPartnershipCompetitionRepresentationRepository) CreateCompetitionRepresentation(representation *businesslogic.PartnershipCompetitionRepresentation) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasPartnershipCompetitionRepresentationTable).
+		Columns(
+			columnCompetitionEntryID,
+			common.COL_COUNTRY_ID,
+			common.COL_STATE_ID,
+			columnStudioID,
+			columnSchoolID,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			representation.PartnershipCompetitionEntryID,
+			representation.CountryID,
+			representation.StateID,
+			representation.StudioID,
+			representation.SchoolID,
+			representation.CreateUserID,
+			representation.DateTimeCreated,
+			representation.UpdateUserID,
+			representation.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&representation.ID); scanErr != nil {
+		log.Printf("[error] creating PartnershipCompetitionRepresentation %#v: %v", representation, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
 }
 
 // SearchCompetitionRepresentation searches PartnershipCompetitionRepresentation in a Postgres database
-func (repo PostgresPartnershipCompetitionRepresentationRepository) SearchCompetitionRepresentation() {
+func (repo PostgresPartnershipCompetitionRepresentationRepository) SearchCompetitionRepresentation(criteria businesslogic.SearchPartnershipCompetitionRepresentationCriteria) ([]businesslogic.PartnershipCompetitionRepresentation, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	column := func(name string) string {
+		return fmt.Sprintf("%s.%s", dasPartnershipCompetitionRepresentationTable, name)
+	}
+	stmt := repo.SQLBuilder.Select(
+		column(common.ColumnPrimaryKey),
+		column(columnCompetitionEntryID),
+		column(common.COL_COUNTRY_ID),
+		column(common.COL_STATE_ID),
+		column(columnStudioID),
+		column(columnSchoolID),
+		column(common.ColumnCreateUserID),
+		column(common.ColumnDateTimeCreated),
+		column(common.ColumnUpdateUserID),
+		column(common.ColumnDateTimeUpdated)).
+		From(dasPartnershipCompetitionRepresentationTable).
+		OrderBy(column(common.ColumnPrimaryKey))
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", dasPartnershipCompetitionEntryTable,
+			dasPartnershipCompetitionEntryTable, common.ColumnPrimaryKey, column(columnCompetitionEntryID))).
+			Where(squirrel.Eq{fmt.Sprintf("%s.%s", dasPartnershipCompetitionEntryTable, common.COL_COMPETITION_ID): criteria.CompetitionID})
+	}
+	if criteria.PartnershipCompetitionEntryID > 0 {
+		stmt = stmt.Where(squirrel.Eq{column(columnCompetitionEntryID): criteria.PartnershipCompetitionEntryID})
+	}
+
+	representations := make([]businesslogic.PartnershipCompetitionRepresentation, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching PartnershipCompetitionRepresentation with criteria %#v: %v", criteria, err)
+		return representations, err
+	}
+	for rows.Next() {
+		each := businesslogic.PartnershipCompetitionRepresentation{}
+		var country, state, studio, school sql.NullInt64
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.PartnershipCompetitionEntryID,
+			&country,
+			&state,
+			&studio,
+			&school,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			rows.Close()
+			return representations, scanErr
+		}
+		each.CountryID = nullableID(country)
+		each.StateID = nullableID(state)
+		each.StudioID = nullableID(studio)
+		each.SchoolID = nullableID(school)
+		representations = append(representations, each)
+	}
+	return representations, rows.Close()
+}
 
+func nullableID(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	id := int(value.Int64)
+	return &id
 }
@@ -3,10 +3,19 @@ package entrydal
 import (
 	"database/sql"
 	"errors"
-
+	"fmt"
 	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
 	"github.com/DancesportSoftware/das/dataaccess/util"
 	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasPartnershipRoundEntryTable = "DAS.ROUND_ENTRY_PARTNERSHIP"
+	dasAdjudicatorRoundEntryTable = "DAS.ROUND_ENTRY_ADJUDICATOR"
+	dasRoundTable                 = "DAS.ROUND"
+	columnRoundID                 = "ROUND_ID"
 )
 
 // PostgresPartnershipRoundEntryRepository implements the IPartnershipRoundEntryRepository with a Postgres database
@@ -20,7 +29,24 @@ func (repo PostgresPartnershipRoundEntryRepository) CreatePartnershipRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasPartnershipRoundEntryTable).
+		Columns(
+			columnRoundID,
+			common.COL_PARTNERSHIP_ID,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			entry.RoundEntry.RoundID,
+			entry.PartnershipID,
+			entry.RoundEntry.CreateUserID,
+			entry.RoundEntry.DateTimeCreated,
+			entry.RoundEntry.UpdateUserID,
+			entry.RoundEntry.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	return createRoundEntry(repo.Database, stmt, &entry.ID)
 }
 
 // DeletePartnershipRoundEntry deletes a PartnershipRoundEntry from a Postgres database
@@ -28,7 +54,12 @@ func (repo PostgresPartnershipRoundEntryRepository) DeletePartnershipRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	if entry.ID < 1 {
+		return errors.New("ID of PartnershipRoundEntry must be specified")
+	}
+	return deleteRoundEntry(repo.Database, repo.SQLBuilder.Delete("").
+		From(dasPartnershipRoundEntryTable).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID}))
 }
 
 // SearchPartnershipRoundEntry searches PartnershipRoundEntry in a Postgres database
@@ -36,7 +67,58 @@ func (repo PostgresPartnershipRoundEntryRepository) SearchPartnershipRoundEntry(
 	if repo.Database == nil {
 		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return nil, errors.New("not implemented")
+	column := func(name string) string {
+		return fmt.Sprintf("%s.%s", dasPartnershipRoundEntryTable, name)
+	}
+	stmt := repo.SQLBuilder.Select(
+		column(common.ColumnPrimaryKey),
+		column(columnRoundID),
+		column(common.COL_PARTNERSHIP_ID),
+		column(common.ColumnCreateUserID),
+		column(common.ColumnDateTimeCreated),
+		column(common.ColumnUpdateUserID),
+		column(common.ColumnDateTimeUpdated)).
+		From(dasPartnershipRoundEntryTable).
+		OrderBy(column(common.ColumnPrimaryKey))
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{column(common.ColumnPrimaryKey): criteria.ID})
+	}
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{column(columnRoundID): criteria.RoundID})
+	}
+	if criteria.PartnershipID > 0 {
+		stmt = stmt.Where(squirrel.Eq{column(common.COL_PARTNERSHIP_ID): criteria.PartnershipID})
+	}
+	if criteria.EventID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", dasRoundTable, dasRoundTable, common.ColumnPrimaryKey, column(columnRoundID))).
+			Where(squirrel.Eq{fmt.Sprintf("%s.%s", dasRoundTable, common.COL_EVENT_ID): criteria.EventID})
+	}
+
+	entries := make([]businesslogic.PartnershipRoundEntry, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching PartnershipRoundEntry with criteria %#v: %v", criteria, err)
+		return entries, err
+	}
+	for rows.Next() {
+		each := businesslogic.PartnershipRoundEntry{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundEntry.RoundID,
+			&each.PartnershipID,
+			&each.RoundEntry.CreateUserID,
+			&each.RoundEntry.DateTimeCreated,
+			&each.RoundEntry.UpdateUserID,
+			&each.RoundEntry.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning PartnershipRoundEntry with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return entries, scanErr
+		}
+		entries = append(entries, each)
+	}
+	return entries, rows.Close()
 }
 
 // UpdatePartnershipRoundEntry updates a PartnershipRoundEntry in a Postgres database
@@ -44,7 +126,16 @@ func (repo PostgresPartnershipRoundEntryRepository) UpdatePartnershipRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	if entry.ID < 1 {
+		return errors.New("ID of PartnershipRoundEntry must be specified")
+	}
+	return updateRoundEntry(repo.Database, repo.SQLBuilder.Update("").
+		Table(dasPartnershipRoundEntryTable).
+		Set(columnRoundID, entry.RoundEntry.RoundID).
+		Set(common.COL_PARTNERSHIP_ID, entry.PartnershipID).
+		Set(common.ColumnUpdateUserID, entry.RoundEntry.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, entry.RoundEntry.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID}))
 }
 
 // PostgresAdjudicatorRoundEntryRepository implements IAdjudicatorRoundEntryRepository with a Postgres database
@@ -58,7 +149,24 @@ func (repo PostgresAdjudicatorRoundEntryRepository) CreateAdjudicatorRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasAdjudicatorRoundEntryTable).
+		Columns(
+			columnRoundID,
+			common.COL_ADJUDICATOR_ID,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			entry.RoundEntry.RoundID,
+			entry.AdjudicatorEntryID,
+			entry.RoundEntry.CreateUserID,
+			entry.RoundEntry.DateTimeCreated,
+			entry.RoundEntry.UpdateUserID,
+			entry.RoundEntry.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	return createRoundEntry(repo.Database, stmt, &entry.ID)
 }
 
 // DeleteAdjudicatorRoundEntry deletes an AdjudicatorRoundEntry from a Postgres database
@@ -66,7 +174,12 @@ func (repo PostgresAdjudicatorRoundEntryRepository) DeleteAdjudicatorRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	if entry.ID < 1 {
+		return errors.New("ID of AdjudicatorRoundEntry must be specified")
+	}
+	return deleteRoundEntry(repo.Database, repo.SQLBuilder.Delete("").
+		From(dasAdjudicatorRoundEntryTable).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID}))
 }
 
 // SearchAdjudicatorRoundEntry searches AdjudicatorRoundEntry from a Postgres database
@@ -74,7 +187,51 @@ func (repo PostgresAdjudicatorRoundEntryRepository) SearchAdjudicatorRoundEntry(
 	if repo.Database == nil {
 		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return nil, errors.New("not implemented")
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnRoundID,
+		common.COL_ADJUDICATOR_ID,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasAdjudicatorRoundEntryTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnRoundID: criteria.RoundID})
+	}
+	if criteria.AdjudicatorID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_ADJUDICATOR_ID: criteria.AdjudicatorID})
+	}
+
+	entries := make([]businesslogic.AdjudicatorRoundEntry, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching AdjudicatorRoundEntry with criteria %#v: %v", criteria, err)
+		return entries, err
+	}
+	for rows.Next() {
+		each := businesslogic.AdjudicatorRoundEntry{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundEntry.RoundID,
+			&each.AdjudicatorEntryID,
+			&each.RoundEntry.CreateUserID,
+			&each.RoundEntry.DateTimeCreated,
+			&each.RoundEntry.UpdateUserID,
+			&each.RoundEntry.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning AdjudicatorRoundEntry with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return entries, scanErr
+		}
+		entries = append(entries, each)
+	}
+	return entries, rows.Close()
 }
 
 // UpdateAdjudicatorRoundEntry updates an AdjudicatorRoundEntry from a Postgres database
@@ -82,5 +239,57 @@ func (repo PostgresAdjudicatorRoundEntryRepository) UpdateAdjudicatorRoundEntry(
 	if repo.Database == nil {
 		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
 	}
-	return errors.New("not implemented")
+	if entry.ID < 1 {
+		return errors.New("ID of AdjudicatorRoundEntry must be specified")
+	}
+	return updateRoundEntry(repo.Database, repo.SQLBuilder.Update("").
+		Table(dasAdjudicatorRoundEntryTable).
+		Set(columnRoundID, entry.RoundEntry.RoundID).
+		Set(common.COL_ADJUDICATOR_ID, entry.AdjudicatorEntryID).
+		Set(common.ColumnUpdateUserID, entry.RoundEntry.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, entry.RoundEntry.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID}))
+}
+
+func createRoundEntry(db *sql.DB, stmt squirrel.InsertBuilder, id *int) error {
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(id); scanErr != nil {
+		log.Printf("[error] creating round entry: %v", scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+func deleteRoundEntry(db *sql.DB, stmt squirrel.DeleteBuilder) error {
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting round entry: %v", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateRoundEntry(db *sql.DB, stmt squirrel.UpdateBuilder) error {
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating round entry: %v", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,217 @@
+package entrydal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasCompetitionEntryTBATable = "DAS.COMPETITION_ENTRY_TBA"
+	columnTBARole               = "ROLE"
+	columnTBAProficiency        = "PROFICIENCY"
+	columnTBAStyle              = "STYLE"
+	columnTBAHeight             = "HEIGHT"
+	columnTBASchool             = "SCHOOL"
+	columnTBAContactEmail       = "CONTACT_EMAIL"
+	columnTBAContactPhone       = "CONTACT_PHONE"
+	columnTBAMiscInfo           = "MISC_INFO"
+)
+
+// PostgresCompetitionTBAEntryRepository implements ICompetitionTBAEntryRepository with a Postgres database
+type PostgresCompetitionTBAEntryRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateCompetitionTBAEntry creates a CompetitionTBAEntry in a Postgres database
+func (repo PostgresCompetitionTBAEntryRepository) CreateCompetitionTBAEntry(entry *businesslogic.CompetitionTBAEntry) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionEntryTBATable).
+		Columns(
+			common.ColumnAccountID,
+			common.COL_COMPETITION_ID,
+			columnTBARole,
+			columnTBAProficiency,
+			columnTBAStyle,
+			columnTBAHeight,
+			columnTBASchool,
+			columnTBAContactEmail,
+			columnTBAContactPhone,
+			columnTBAMiscInfo,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			entry.AccountID,
+			entry.CompetitionID,
+			entry.Role,
+			entry.Proficiency,
+			entry.Style,
+			entry.Height,
+			entry.School,
+			entry.ContactEmail,
+			entry.ContactPhone,
+			entry.MiscInfo,
+			entry.CreateUserID,
+			entry.DateTimeCreated,
+			entry.UpdateUserID,
+			entry.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&entry.ID); scanErr != nil {
+		log.Printf("[error] creating CompetitionTBAEntry %#v: %v", entry, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// DeleteCompetitionTBAEntry deletes a CompetitionTBAEntry from a Postgres database
+func (repo PostgresCompetitionTBAEntryRepository) DeleteCompetitionTBAEntry(entry businesslogic.CompetitionTBAEntry) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if entry.ID < 1 {
+		return errors.New("ID of CompetitionTBAEntry must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasCompetitionEntryTBATable).Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchCompetitionTBAEntry searches CompetitionTBAEntry in a Postgres database
+func (repo PostgresCompetitionTBAEntryRepository) SearchCompetitionTBAEntry(criteria businesslogic.SearchCompetitionTBAEntryCriteria) ([]businesslogic.CompetitionTBAEntry, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.ColumnAccountID,
+		common.COL_COMPETITION_ID,
+		columnTBARole,
+		columnTBAProficiency,
+		columnTBAStyle,
+		columnTBAHeight,
+		columnTBASchool,
+		fmt.Sprintf("COALESCE(%s, '')", columnTBAContactEmail),
+		fmt.Sprintf("COALESCE(%s, '')", columnTBAContactPhone),
+		fmt.Sprintf("COALESCE(%s, '')", columnTBAMiscInfo),
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionEntryTBATable).
+		OrderBy(common.ColumnDateTimeCreated)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.AccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnAccountID: criteria.AccountID})
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+	if criteria.Role != "" {
+		stmt = stmt.Where(squirrel.Eq{columnTBARole: criteria.Role})
+	}
+	if criteria.Proficiency != "" {
+		stmt = stmt.Where(squirrel.Eq{columnTBAProficiency: criteria.Proficiency})
+	}
+	if criteria.Style != "" {
+		stmt = stmt.Where(squirrel.Eq{columnTBAStyle: criteria.Style})
+	}
+	if criteria.School != "" {
+		stmt = stmt.Where(fmt.Sprintf("%s ILIKE ?", columnTBASchool), "%"+criteria.School+"%")
+	}
+
+	entries := make([]businesslogic.CompetitionTBAEntry, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching CompetitionTBAEntry with criteria %#v: %v", criteria, err)
+		return entries, err
+	}
+	for rows.Next() {
+		each := businesslogic.CompetitionTBAEntry{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.AccountID,
+			&each.CompetitionID,
+			&each.Role,
+			&each.Proficiency,
+			&each.Style,
+			&each.Height,
+			&each.School,
+			&each.ContactEmail,
+			&each.ContactPhone,
+			&each.MiscInfo,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning CompetitionTBAEntry with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return entries, scanErr
+		}
+		entries = append(entries, each)
+	}
+	return entries, rows.Close()
+}
+
+// UpdateCompetitionTBAEntry updates a CompetitionTBAEntry in a Postgres database
+func (repo PostgresCompetitionTBAEntryRepository) UpdateCompetitionTBAEntry(entry businesslogic.CompetitionTBAEntry) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if entry.ID < 1 {
+		return errors.New("ID of CompetitionTBAEntry must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasCompetitionEntryTBATable).
+		Set(columnTBARole, entry.Role).
+		Set(columnTBAProficiency, entry.Proficiency).
+		Set(columnTBAStyle, entry.Style).
+		Set(columnTBAHeight, entry.Height).
+		Set(columnTBASchool, entry.School).
+		Set(columnTBAContactEmail, entry.ContactEmail).
+		Set(columnTBAContactPhone, entry.ContactPhone).
+		Set(columnTBAMiscInfo, entry.MiscInfo).
+		Set(common.ColumnUpdateUserID, entry.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, entry.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: entry.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating CompetitionTBAEntry with ID = %v: %v", entry.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -68,6 +68,9 @@ func (repo PostgresEventRepository) SearchEvent(criteria businesslogic.SearchEve
 	if criteria.StatusID > 0 {
 		stmt = stmt.Where(squirrel.Eq{dasEventColumnEventStatusID: criteria.StatusID})
 	}
+	if criteria.OrganizerID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnCreateUserID: criteria.OrganizerID})
+	}
 	rows, err := stmt.RunWith(repo.Database).Query()
 	events := make([]businesslogic.Event, 0)
 	if err != nil {
@@ -178,14 +181,18 @@ func (repo PostgresEventRepository) UpdateEvent(event businesslogic.Event) error
 	}
 	stmt := repo.SQLBuilder.Update("").Table(DAS_EVENT_TABLE).
 		Set(dasEventColumnEventStatusID, event.StatusID).
-		Where(squirrel.Eq{common.COL_COMPETITION_ID: event.CompetitionID})
+		Set(common.ColumnUpdateUserID, event.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, event.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: event.ID})
 	tx, txErr := repo.Database.Begin()
 	if txErr != nil {
 		return txErr
 	}
-	_, err := stmt.RunWith(repo.Database).Exec()
-	err = tx.Commit()
-	return err
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 // DeleteEvent deletes an Event from a Postgres database
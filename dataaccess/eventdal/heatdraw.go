@@ -0,0 +1,244 @@
+package eventdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasRoundHeatDrawTable               = "DAS.ROUND_HEAT_DRAW"
+	dasRoundHeatDrawColumnRoundID       = "ROUND_ID"
+	dasRoundHeatDrawColumnSeed          = "SEED"
+	dasRoundHeatDrawColumnFloorCapacity = "FLOOR_CAPACITY"
+
+	dasRoundHeatAssignmentTable                         = "DAS.ROUND_HEAT_ASSIGNMENT"
+	dasRoundHeatAssignmentColumnRoundHeatDrawID         = "ROUND_HEAT_DRAW_ID"
+	dasRoundHeatAssignmentColumnEventDanceID            = "EVENT_DANCE_ID"
+	dasRoundHeatAssignmentColumnHeatNumber              = "HEAT_NUMBER"
+	dasRoundHeatAssignmentColumnPartnershipRoundEntryID = "ROUND_ENTRY_PARTNERSHIP_ID"
+)
+
+// PostgresRoundHeatDrawRepository implements IRoundHeatDrawRepository with a Postgres database
+type PostgresRoundHeatDrawRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateRoundHeatDraw creates a RoundHeatDraw and its heats in a Postgres database
+func (repo PostgresRoundHeatDrawRepository) CreateRoundHeatDraw(draw *businesslogic.RoundHeatDraw) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasRoundHeatDrawTable).
+		Columns(
+			dasRoundHeatDrawColumnRoundID,
+			dasRoundHeatDrawColumnSeed,
+			dasRoundHeatDrawColumnFloorCapacity,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			draw.RoundID,
+			draw.Seed,
+			draw.FloorCapacity,
+			draw.CreateUserID,
+			draw.DateTimeCreated,
+			draw.UpdateUserID,
+			draw.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&draw.ID); scanErr != nil {
+		log.Printf("[error] creating RoundHeatDraw %#v: %v", draw, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	if err := repo.insertHeats(tx, *draw); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertHeats creates a row for each couple in each heat of the draw
+func (repo PostgresRoundHeatDrawRepository) insertHeats(tx *sql.Tx, draw businesslogic.RoundHeatDraw) error {
+	for _, heat := range draw.Heats {
+		for _, couple := range heat.Couples {
+			stmt := repo.SQLBuilder.Insert("").
+				Into(dasRoundHeatAssignmentTable).
+				Columns(
+					dasRoundHeatAssignmentColumnRoundHeatDrawID,
+					dasRoundHeatAssignmentColumnEventDanceID,
+					dasRoundHeatAssignmentColumnHeatNumber,
+					dasRoundHeatAssignmentColumnPartnershipRoundEntryID).
+				Values(draw.ID, heat.EventDanceID, heat.Number, couple)
+			if _, err := stmt.RunWith(tx).Exec(); err != nil {
+				log.Printf("[error] creating heat %d of RoundHeatDraw %d: %v", heat.Number, draw.ID, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRoundHeatDraw deletes a RoundHeatDraw from a Postgres database by the ID of the draw
+func (repo PostgresRoundHeatDrawRepository) DeleteRoundHeatDraw(draw businesslogic.RoundHeatDraw) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if draw.ID < 1 {
+		return errors.New("ID of RoundHeatDraw must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasRoundHeatDrawTable).Where(squirrel.Eq{common.ColumnPrimaryKey: draw.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchRoundHeatDraw searches RoundHeatDraw in a Postgres database
+func (repo PostgresRoundHeatDrawRepository) SearchRoundHeatDraw(criteria businesslogic.SearchRoundHeatDrawCriteria) ([]businesslogic.RoundHeatDraw, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		dasRoundHeatDrawColumnRoundID,
+		dasRoundHeatDrawColumnSeed,
+		dasRoundHeatDrawColumnFloorCapacity,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasRoundHeatDrawTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{dasRoundHeatDrawColumnRoundID: criteria.RoundID})
+	}
+
+	draws := make([]businesslogic.RoundHeatDraw, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		return draws, err
+	}
+	for rows.Next() {
+		each := businesslogic.RoundHeatDraw{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundID,
+			&each.Seed,
+			&each.FloorCapacity,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			rows.Close()
+			return draws, scanErr
+		}
+		draws = append(draws, each)
+	}
+	if err := rows.Close(); err != nil {
+		return draws, err
+	}
+	for i := range draws {
+		heats, err := repo.searchHeats(draws[i])
+		if err != nil {
+			return draws, err
+		}
+		draws[i].Heats = heats
+	}
+	return draws, nil
+}
+
+// searchHeats returns the heats of the draw in the order in which they were created
+func (repo PostgresRoundHeatDrawRepository) searchHeats(draw businesslogic.RoundHeatDraw) ([]businesslogic.Heat, error) {
+	stmt := repo.SQLBuilder.Select(
+		dasRoundHeatAssignmentColumnEventDanceID,
+		dasRoundHeatAssignmentColumnHeatNumber,
+		dasRoundHeatAssignmentColumnPartnershipRoundEntryID).
+		From(dasRoundHeatAssignmentTable).
+		Where(squirrel.Eq{dasRoundHeatAssignmentColumnRoundHeatDrawID: draw.ID}).
+		OrderBy(common.ColumnPrimaryKey)
+
+	heats := make([]businesslogic.Heat, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		return heats, err
+	}
+	for rows.Next() {
+		var danceID, number, couple int
+		if scanErr := rows.Scan(&danceID, &number, &couple); scanErr != nil {
+			rows.Close()
+			return heats, scanErr
+		}
+		if last := len(heats) - 1; last >= 0 && heats[last].EventDanceID == danceID && heats[last].Number == number {
+			heats[last].Couples = append(heats[last].Couples, couple)
+			continue
+		}
+		heats = append(heats, businesslogic.Heat{
+			RoundID:      draw.RoundID,
+			EventDanceID: danceID,
+			Number:       number,
+			Couples:      []int{couple},
+		})
+	}
+	return heats, rows.Close()
+}
+
+// UpdateRoundHeatDraw replaces the seed, floor capacity and heats of a RoundHeatDraw in a Postgres database
+func (repo PostgresRoundHeatDrawRepository) UpdateRoundHeatDraw(draw businesslogic.RoundHeatDraw) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if draw.ID < 1 {
+		return errors.New("ID of RoundHeatDraw must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasRoundHeatDrawTable).
+		Set(dasRoundHeatDrawColumnSeed, draw.Seed).
+		Set(dasRoundHeatDrawColumnFloorCapacity, draw.FloorCapacity).
+		Set(common.ColumnUpdateUserID, draw.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, draw.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: draw.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating RoundHeatDraw with ID = %v: %v", draw.ID, err)
+		tx.Rollback()
+		return err
+	}
+	deleteHeats := repo.SQLBuilder.Delete("").
+		From(dasRoundHeatAssignmentTable).
+		Where(squirrel.Eq{dasRoundHeatAssignmentColumnRoundHeatDrawID: draw.ID})
+	if _, err := deleteHeats.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := repo.insertHeats(tx, draw); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,171 @@
+package eventdal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	DAS_ROUND_TABLE          = "DAS.ROUND"
+	dasRoundColumnRoundOrder = "ROUND_ORDER"
+)
+
+// PostgresRoundRepository implements IRoundRepository with a Postgres database. The order of a round is stored as
+// the rank of the round within its event.
+type PostgresRoundRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateRound creates a Round in a Postgres database
+func (repo PostgresRoundRepository) CreateRound(round *businesslogic.Round) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(DAS_ROUND_TABLE).
+		Columns(
+			common.COL_EVENT_ID,
+			dasRoundColumnRoundOrder,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			round.EventID,
+			round.Order.Rank,
+			round.CreateUserID,
+			round.DateTimeCreated,
+			round.UpdateUserID,
+			round.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&round.ID); scanErr != nil {
+		log.Printf("[error] creating Round %#v: %v", round, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// DeleteRound deletes a Round from a Postgres database by the ID of the Round
+func (repo PostgresRoundRepository) DeleteRound(round businesslogic.Round) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if round.ID < 1 {
+		return errors.New("ID of Round must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(DAS_ROUND_TABLE).Where(squirrel.Eq{common.ColumnPrimaryKey: round.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting Round with ID = %v: %v", round.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchRound searches Rounds in a Postgres database. Rounds are ordered by event, then by the order of the round.
+func (repo PostgresRoundRepository) SearchRound(criteria businesslogic.SearchRoundCriteria) ([]businesslogic.Round, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		roundColumn(common.ColumnPrimaryKey),
+		roundColumn(common.COL_EVENT_ID),
+		roundColumn(dasRoundColumnRoundOrder),
+		roundColumn(common.ColumnCreateUserID),
+		roundColumn(common.ColumnDateTimeCreated),
+		roundColumn(common.ColumnUpdateUserID),
+		roundColumn(common.ColumnDateTimeUpdated)).
+		From(DAS_ROUND_TABLE).
+		OrderBy(roundColumn(common.COL_EVENT_ID), roundColumn(dasRoundColumnRoundOrder))
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", DAS_EVENT_TABLE,
+			DAS_EVENT_TABLE, common.ColumnPrimaryKey, roundColumn(common.COL_EVENT_ID))).
+			Where(squirrel.Eq{fmt.Sprintf("%s.%s", DAS_EVENT_TABLE, common.COL_COMPETITION_ID): criteria.CompetitionID})
+	}
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{roundColumn(common.ColumnPrimaryKey): criteria.ID})
+	}
+	if criteria.EventID > 0 {
+		stmt = stmt.Where(squirrel.Eq{roundColumn(common.COL_EVENT_ID): criteria.EventID})
+	}
+	if criteria.RoundOrderID > 0 {
+		stmt = stmt.Where(squirrel.Eq{roundColumn(dasRoundColumnRoundOrder): criteria.RoundOrderID})
+	}
+
+	rounds := make([]businesslogic.Round, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching Round with criteria %#v: %v", criteria, err)
+		return rounds, err
+	}
+	for rows.Next() {
+		each := businesslogic.Round{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.EventID,
+			&each.Order.Rank,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning Round with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return rounds, scanErr
+		}
+		each.Order.ID = each.Order.Rank
+		rounds = append(rounds, each)
+	}
+	return rounds, rows.Close()
+}
+
+// UpdateRound updates the order of a Round in a Postgres database
+func (repo PostgresRoundRepository) UpdateRound(round businesslogic.Round) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if round.ID < 1 {
+		return errors.New("ID of Round must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(DAS_ROUND_TABLE).
+		Set(dasRoundColumnRoundOrder, round.Order.Rank).
+		Set(common.ColumnUpdateUserID, round.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, round.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: round.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating Round with ID = %v: %v", round.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func roundColumn(column string) string {
+	return fmt.Sprintf("%s.%s", DAS_ROUND_TABLE, column)
+}
@@ -0,0 +1,276 @@
+package eventdal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+const (
+	dasEventRoundScheduleTable                    = "DAS.EVENT_ROUND_SCHEDULE"
+	dasEventRoundScheduleColumnRoundOrder         = "ROUND_ORDER"
+	dasEventRoundScheduleColumnPreliminaryRound   = "PRELIMINARY_ROUND_IND"
+	dasEventRoundScheduleColumnFloor              = "FLOOR"
+	dasEventRoundScheduleColumnEstimatedStartTime = "ESTIMATED_STARTTIME"
+	dasEventRoundScheduleColumnEstimatedEndTime   = "ESTIMATED_ENDTIME"
+	dasEventRoundScheduleColumnActualStartTime    = "ACTUAL_STARTTIME"
+	dasEventRoundScheduleColumnPublished          = "PUBLISHED_IND"
+)
+
+// PostgresEventRoundScheduleRepository implements IEventRoundScheduleRepository with a Postgres database
+type PostgresEventRoundScheduleRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateEventRoundSchedule creates an EventRoundSchedule in a Postgres database
+func (repo PostgresEventRoundScheduleRepository) CreateEventRoundSchedule(schedule *businesslogic.EventRoundSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if err := repo.insertEventRoundSchedule(tx, schedule); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo PostgresEventRoundScheduleRepository) insertEventRoundSchedule(tx *sql.Tx, schedule *businesslogic.EventRoundSchedule) error {
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasEventRoundScheduleTable).
+		Columns(
+			common.COL_EVENT_ID,
+			dasEventRoundScheduleColumnRoundOrder,
+			dasEventRoundScheduleColumnPreliminaryRound,
+			dasEventRoundScheduleColumnFloor,
+			dasEventRoundScheduleColumnEstimatedStartTime,
+			dasEventRoundScheduleColumnEstimatedEndTime,
+			dasEventRoundScheduleColumnActualStartTime,
+			dasEventRoundScheduleColumnPublished,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			schedule.EventID,
+			schedule.RoundOrder,
+			schedule.PreliminaryRoundIndicator,
+			schedule.Floor,
+			schedule.EstimatedStartTime,
+			schedule.EstimatedEndTime,
+			schedule.ActualStartTime,
+			schedule.Published,
+			schedule.CreateUserID,
+			schedule.DateTimeCreated,
+			schedule.UpdateUserID,
+			schedule.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&schedule.ID); scanErr != nil {
+		log.Printf("[error] creating EventRoundSchedule %#v: %v", schedule, scanErr)
+		return scanErr
+	}
+	return nil
+}
+
+// DeleteEventRoundSchedule deletes an EventRoundSchedule from a Postgres database by the ID of the schedule
+func (repo PostgresEventRoundScheduleRepository) DeleteEventRoundSchedule(schedule businesslogic.EventRoundSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if schedule.ID < 1 {
+		return errors.New("ID of EventRoundSchedule must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasEventRoundScheduleTable).Where(squirrel.Eq{common.ColumnPrimaryKey: schedule.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchEventRoundSchedule searches EventRoundSchedule in a Postgres database. Schedules are ordered by event, then by
+// the order of the round.
+func (repo PostgresEventRoundScheduleRepository) SearchEventRoundSchedule(criteria businesslogic.SearchEventRoundScheduleCriteria) ([]businesslogic.EventRoundSchedule, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		roundScheduleColumn(common.ColumnPrimaryKey),
+		roundScheduleColumn(common.COL_EVENT_ID),
+		roundScheduleColumn(dasEventRoundScheduleColumnRoundOrder),
+		roundScheduleColumn(dasEventRoundScheduleColumnPreliminaryRound),
+		roundScheduleColumn(dasEventRoundScheduleColumnFloor),
+		roundScheduleColumn(dasEventRoundScheduleColumnEstimatedStartTime),
+		roundScheduleColumn(dasEventRoundScheduleColumnEstimatedEndTime),
+		roundScheduleColumn(dasEventRoundScheduleColumnActualStartTime),
+		roundScheduleColumn(dasEventRoundScheduleColumnPublished),
+		roundScheduleColumn(common.ColumnCreateUserID),
+		roundScheduleColumn(common.ColumnDateTimeCreated),
+		roundScheduleColumn(common.ColumnUpdateUserID),
+		roundScheduleColumn(common.ColumnDateTimeUpdated)).
+		From(dasEventRoundScheduleTable).
+		OrderBy(roundScheduleColumn(common.COL_EVENT_ID), roundScheduleColumn(dasEventRoundScheduleColumnRoundOrder))
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", DAS_EVENT_TABLE,
+			DAS_EVENT_TABLE, common.ColumnPrimaryKey, roundScheduleColumn(common.COL_EVENT_ID))).
+			Where(squirrel.Eq{fmt.Sprintf("%s.%s", DAS_EVENT_TABLE, common.COL_COMPETITION_ID): criteria.CompetitionID})
+	}
+	if criteria.EventID > 0 {
+		stmt = stmt.Where(squirrel.Eq{roundScheduleColumn(common.COL_EVENT_ID): criteria.EventID})
+	}
+
+	schedules := make([]businesslogic.EventRoundSchedule, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching EventRoundSchedule with criteria %#v: %v", criteria, err)
+		return schedules, err
+	}
+	for rows.Next() {
+		each := businesslogic.EventRoundSchedule{}
+		var endTime *time.Time // schedules that were created before end times were estimated do not have one
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.EventID,
+			&each.RoundOrder,
+			&each.PreliminaryRoundIndicator,
+			&each.Floor,
+			&each.EstimatedStartTime,
+			&endTime,
+			&each.ActualStartTime,
+			&each.Published,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning EventRoundSchedule with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return schedules, scanErr
+		}
+		if endTime != nil {
+			each.EstimatedEndTime = *endTime
+		}
+		schedules = append(schedules, each)
+	}
+	return schedules, rows.Close()
+}
+
+// UpdateEventRoundSchedule updates the times and floor of an EventRoundSchedule in a Postgres database
+func (repo PostgresEventRoundScheduleRepository) UpdateEventRoundSchedule(schedule businesslogic.EventRoundSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if schedule.ID < 1 {
+		return errors.New("ID of EventRoundSchedule must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasEventRoundScheduleTable).
+		Set(dasEventRoundScheduleColumnFloor, schedule.Floor).
+		Set(dasEventRoundScheduleColumnEstimatedStartTime, schedule.EstimatedStartTime).
+		Set(dasEventRoundScheduleColumnEstimatedEndTime, schedule.EstimatedEndTime).
+		Set(dasEventRoundScheduleColumnActualStartTime, schedule.ActualStartTime).
+		Set(common.ColumnUpdateUserID, schedule.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, schedule.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: schedule.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating EventRoundSchedule with ID = %v: %v", schedule.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceEventRoundSchedule deletes the timetable of all the events of the competition and creates the schedules in a
+// single transaction in a Postgres database
+func (repo PostgresEventRoundScheduleRepository) ReplaceEventRoundSchedule(competitionID int, schedules []businesslogic.EventRoundSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	events, err := competitionEvents(competitionID)
+	if err != nil {
+		return err
+	}
+	stmt := repo.SQLBuilder.Delete("").From(dasEventRoundScheduleTable).Where(events)
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting EventRoundSchedule of competition %v: %v", competitionID, err)
+		tx.Rollback()
+		return err
+	}
+	for i := range schedules {
+		if err := repo.insertEventRoundSchedule(tx, &schedules[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// PublishEventRoundSchedule publishes all the rounds in the timetable of the competition in a Postgres database
+func (repo PostgresEventRoundScheduleRepository) PublishEventRoundSchedule(competitionID, updateUserID int) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	events, err := competitionEvents(competitionID)
+	if err != nil {
+		return err
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasEventRoundScheduleTable).
+		Set(dasEventRoundScheduleColumnPublished, true).
+		Set(common.ColumnUpdateUserID, updateUserID).
+		Set(common.ColumnDateTimeUpdated, time.Now()).
+		Where(events)
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] publishing EventRoundSchedule of competition %v: %v", competitionID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// competitionEvents is the condition that selects the schedules of all the events of the competition
+func competitionEvents(competitionID int) (squirrel.Sqlizer, error) {
+	events, args, err := squirrel.Select(common.ColumnPrimaryKey).
+		From(DAS_EVENT_TABLE).
+		Where(squirrel.Eq{common.COL_COMPETITION_ID: competitionID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return squirrel.Expr(fmt.Sprintf("%s IN (%s)", common.COL_EVENT_ID, events), args...), nil
+}
+
+func roundScheduleColumn(column string) string {
+	return fmt.Sprintf("%s.%s", dasEventRoundScheduleTable, column)
+}
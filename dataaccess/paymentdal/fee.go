@@ -0,0 +1,166 @@
+package paymentdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasCompetitionFeeScheduleTable  = "DAS.COMPETITION_FEE_SCHEDULE"
+	columnBaseFee                   = "BASE_FEE"
+	columnPerEventFee               = "PER_EVENT_FEE"
+	columnEarlyRegistrationDeadline = "EARLY_REGISTRATION_DEADLINE"
+	columnEarlyDiscountRate         = "EARLY_DISCOUNT_RATE"
+	columnLateRegistrationStart     = "LATE_REGISTRATION_START"
+	columnLateSurchargeRate         = "LATE_SURCHARGE_RATE"
+	columnStudentDiscountRate       = "STUDENT_DISCOUNT_RATE"
+)
+
+// PostgresCompetitionFeeScheduleRepository implements ICompetitionFeeScheduleRepository with a Postgres database
+type PostgresCompetitionFeeScheduleRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateCompetitionFeeSchedule creates a CompetitionFeeSchedule in a Postgres database
+func (repo PostgresCompetitionFeeScheduleRepository) CreateCompetitionFeeSchedule(schedule *businesslogic.CompetitionFeeSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasCompetitionFeeScheduleTable).
+		Columns(
+			common.COL_COMPETITION_ID,
+			columnBaseFee,
+			columnPerEventFee,
+			columnEarlyRegistrationDeadline,
+			columnEarlyDiscountRate,
+			columnLateRegistrationStart,
+			columnLateSurchargeRate,
+			columnStudentDiscountRate,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			schedule.CompetitionID,
+			schedule.BaseFee,
+			schedule.PerEventFee,
+			schedule.EarlyRegistrationDeadline,
+			schedule.EarlyDiscountRate,
+			schedule.LateRegistrationStart,
+			schedule.LateSurchargeRate,
+			schedule.StudentDiscountRate,
+			schedule.CreateUserID,
+			schedule.DateTimeCreated,
+			schedule.UpdateUserID,
+			schedule.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&schedule.ID); scanErr != nil {
+		log.Printf("[error] creating CompetitionFeeSchedule of competition %v: %v", schedule.CompetitionID, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchCompetitionFeeSchedule searches CompetitionFeeSchedule in a Postgres database
+func (repo PostgresCompetitionFeeScheduleRepository) SearchCompetitionFeeSchedule(criteria businesslogic.SearchCompetitionFeeScheduleCriteria) ([]businesslogic.CompetitionFeeSchedule, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		common.COL_COMPETITION_ID,
+		columnBaseFee,
+		columnPerEventFee,
+		columnEarlyRegistrationDeadline,
+		columnEarlyDiscountRate,
+		columnLateRegistrationStart,
+		columnLateSurchargeRate,
+		columnStudentDiscountRate,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasCompetitionFeeScheduleTable)
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+
+	output := make([]businesslogic.CompetitionFeeSchedule, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching CompetitionFeeSchedule of competition %v: %v", criteria.CompetitionID, err)
+		return output, err
+	}
+	for rows.Next() {
+		each := businesslogic.CompetitionFeeSchedule{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.CompetitionID,
+			&each.BaseFee,
+			&each.PerEventFee,
+			&each.EarlyRegistrationDeadline,
+			&each.EarlyDiscountRate,
+			&each.LateRegistrationStart,
+			&each.LateSurchargeRate,
+			&each.StudentDiscountRate,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			rows.Close()
+			return output, scanErr
+		}
+		output = append(output, each)
+	}
+	return output, rows.Close()
+}
+
+// UpdateCompetitionFeeSchedule updates a CompetitionFeeSchedule in a Postgres database
+func (repo PostgresCompetitionFeeScheduleRepository) UpdateCompetitionFeeSchedule(schedule businesslogic.CompetitionFeeSchedule) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if schedule.ID < 1 {
+		return errors.New("ID of CompetitionFeeSchedule must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasCompetitionFeeScheduleTable).
+		Set(columnBaseFee, schedule.BaseFee).
+		Set(columnPerEventFee, schedule.PerEventFee).
+		Set(columnEarlyRegistrationDeadline, schedule.EarlyRegistrationDeadline).
+		Set(columnEarlyDiscountRate, schedule.EarlyDiscountRate).
+		Set(columnLateRegistrationStart, schedule.LateRegistrationStart).
+		Set(columnLateSurchargeRate, schedule.LateSurchargeRate).
+		Set(columnStudentDiscountRate, schedule.StudentDiscountRate).
+		Set(common.ColumnUpdateUserID, schedule.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, schedule.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: schedule.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating CompetitionFeeSchedule of competition %v: %v", schedule.CompetitionID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,244 @@
+package paymentdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+const (
+	dasPaymentTable        = "DAS.PAYMENT"
+	columnOrganizerID      = "ORGANIZER_ID"
+	columnAccountID        = "ACCOUNT_ID"
+	columnPurpose          = "PURPOSE"
+	columnReferenceID      = "REFERENCE_ID"
+	columnAmount           = "AMOUNT"
+	columnRefundedAmount   = "REFUNDED_AMOUNT"
+	columnCurrency         = "CURRENCY"
+	columnPaymentStatusID  = "PAYMENT_STATUS_ID"
+	columnGatewaySessionID = "GATEWAY_SESSION_ID"
+	columnGatewayPaymentID = "GATEWAY_PAYMENT_ID"
+)
+
+// PostgresPaymentRepository implements IPaymentRepository with a Postgres database
+type PostgresPaymentRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// CreatePayment creates a Payment in a Postgres database
+func (repo PostgresPaymentRepository) CreatePayment(payment *businesslogic.Payment) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasPaymentTable).
+		Columns(
+			columnOrganizerID,
+			common.COL_COMPETITION_ID,
+			columnAccountID,
+			columnPurpose,
+			columnReferenceID,
+			columnAmount,
+			columnRefundedAmount,
+			columnCurrency,
+			columnPaymentStatusID,
+			columnGatewaySessionID,
+			columnGatewayPaymentID,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			payment.OrganizerID,
+			payment.CompetitionID,
+			payment.AccountID,
+			payment.Purpose,
+			payment.ReferenceID,
+			payment.Amount,
+			payment.RefundedAmount,
+			payment.Currency,
+			payment.StatusID,
+			nullableString(payment.GatewaySessionID),
+			nullableString(payment.GatewayPaymentID),
+			payment.CreateUserID,
+			payment.DateTimeCreated,
+			payment.UpdateUserID,
+			payment.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&payment.ID); scanErr != nil {
+		log.Printf("[error] creating Payment %#v: %v", payment, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchPayment searches Payment in a Postgres database
+func (repo PostgresPaymentRepository) SearchPayment(criteria businesslogic.SearchPaymentCriteria) ([]businesslogic.Payment, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnOrganizerID,
+		common.COL_COMPETITION_ID,
+		columnAccountID,
+		columnPurpose,
+		columnReferenceID,
+		columnAmount,
+		columnRefundedAmount,
+		columnCurrency,
+		columnPaymentStatusID,
+		columnGatewaySessionID,
+		columnGatewayPaymentID,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasPaymentTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.COL_COMPETITION_ID: criteria.CompetitionID})
+	}
+	if criteria.AccountID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnAccountID: criteria.AccountID})
+	}
+	if criteria.Purpose != "" {
+		stmt = stmt.Where(squirrel.Eq{columnPurpose: criteria.Purpose})
+	}
+	if criteria.ReferenceID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnReferenceID: criteria.ReferenceID})
+	}
+	if criteria.StatusID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnPaymentStatusID: criteria.StatusID})
+	}
+	if criteria.GatewaySessionID != "" {
+		stmt = stmt.Where(squirrel.Eq{columnGatewaySessionID: criteria.GatewaySessionID})
+	}
+
+	payments := make([]businesslogic.Payment, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching Payment with criteria %#v: %v", criteria, err)
+		return payments, err
+	}
+	for rows.Next() {
+		each := businesslogic.Payment{}
+		sessionID := sql.NullString{}
+		paymentID := sql.NullString{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.OrganizerID,
+			&each.CompetitionID,
+			&each.AccountID,
+			&each.Purpose,
+			&each.ReferenceID,
+			&each.Amount,
+			&each.RefundedAmount,
+			&each.Currency,
+			&each.StatusID,
+			&sessionID,
+			&paymentID,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning Payment with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return payments, scanErr
+		}
+		each.GatewaySessionID = sessionID.String
+		each.GatewayPaymentID = paymentID.String
+		payments = append(payments, each)
+	}
+	return payments, rows.Close()
+}
+
+// UpdatePayment updates the status, refunds and gateway identifiers of a Payment in a Postgres database
+func (repo PostgresPaymentRepository) UpdatePayment(payment businesslogic.Payment) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if payment.ID < 1 {
+		return errors.New("ID of Payment must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasPaymentTable).
+		Set(columnRefundedAmount, payment.RefundedAmount).
+		Set(columnPaymentStatusID, payment.StatusID).
+		Set(columnGatewaySessionID, nullableString(payment.GatewaySessionID)).
+		Set(columnGatewayPaymentID, nullableString(payment.GatewayPaymentID)).
+		Set(common.ColumnUpdateUserID, payment.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, payment.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: payment.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating Payment with ID = %v: %v", payment.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddPaymentRefund adds a refund to a Payment in a Postgres database in a single statement, so that concurrent
+// refunds cannot overwrite each other. The Payment is marked as refunded once it is fully refunded.
+func (repo PostgresPaymentRepository) AddPaymentRefund(paymentID int, refundedAmount, amount float64, updateUserID int) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasPaymentTable).
+		Set(columnRefundedAmount, squirrel.Expr(columnRefundedAmount+" + ?", amount)).
+		Set(columnPaymentStatusID, squirrel.Expr(
+			"CASE WHEN "+columnAmount+" - ("+columnRefundedAmount+" + ?) < 0.005 THEN ? ELSE "+columnPaymentStatusID+" END",
+			amount, businesslogic.PaymentStatusRefunded)).
+		Set(common.ColumnUpdateUserID, updateUserID).
+		Set(common.ColumnDateTimeUpdated, time.Now()).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: paymentID}).
+		Where(squirrel.Eq{columnPaymentStatusID: businesslogic.PaymentStatusSucceeded}).
+		Where(squirrel.Expr("ABS("+columnRefundedAmount+" - ?) < 0.005", refundedAmount))
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	result, err := stmt.RunWith(tx).Exec()
+	if err != nil {
+		log.Printf("[error] refunding %v of Payment with ID = %v: %v", amount, paymentID, err)
+		tx.Rollback()
+		return err
+	}
+	if refunded, err := result.RowsAffected(); err != nil || refunded != 1 {
+		tx.Rollback()
+		if err != nil {
+			return err
+		}
+		return businesslogic.PaymentRefundConflictError
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,147 @@
+package paymentdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasOrganizerStripeSettingsTable = "DAS.ORGANIZER_STRIPE_SETTINGS"
+	columnPublicKey                 = "PUBLIC_KEY"
+	columnRestrictedKey             = "RESTRICTED_KEY"
+	columnWebhookSecret             = "WEBHOOK_SECRET"
+)
+
+// PostgresOrganizerStripeSettingsRepository implements IOrganizerStripeSettingsRepository with a Postgres database
+type PostgresOrganizerStripeSettingsRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateOrganizerStripeSettings creates OrganizerStripeSettings in a Postgres database
+func (repo PostgresOrganizerStripeSettingsRepository) CreateOrganizerStripeSettings(settings *businesslogic.OrganizerStripeSettings) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasOrganizerStripeSettingsTable).
+		Columns(
+			columnOrganizerID,
+			columnPublicKey,
+			columnRestrictedKey,
+			columnWebhookSecret,
+			columnCurrency,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			settings.OrganizerID,
+			settings.StripePublicKey,
+			settings.StripeRestrictedKey,
+			settings.StripeWebhookSecret,
+			settings.Currency,
+			settings.CreateUserID,
+			settings.DateTimeCreated,
+			settings.UpdateUserID,
+			settings.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&settings.ID); scanErr != nil {
+		log.Printf("[error] creating OrganizerStripeSettings of organizer %v: %v", settings.OrganizerID, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// SearchOrganizerStripeSettings searches OrganizerStripeSettings in a Postgres database
+func (repo PostgresOrganizerStripeSettingsRepository) SearchOrganizerStripeSettings(criteria businesslogic.SearchOrganizerStripeSettingsCriteria) ([]businesslogic.OrganizerStripeSettings, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnOrganizerID,
+		columnPublicKey,
+		columnRestrictedKey,
+		columnWebhookSecret,
+		columnCurrency,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasOrganizerStripeSettingsTable)
+	if criteria.OrganizerID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnOrganizerID: criteria.OrganizerID})
+	}
+
+	output := make([]businesslogic.OrganizerStripeSettings, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching OrganizerStripeSettings of organizer %v: %v", criteria.OrganizerID, err)
+		return output, err
+	}
+	for rows.Next() {
+		each := businesslogic.OrganizerStripeSettings{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.OrganizerID,
+			&each.StripePublicKey,
+			&each.StripeRestrictedKey,
+			&each.StripeWebhookSecret,
+			&each.Currency,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			rows.Close()
+			return output, scanErr
+		}
+		output = append(output, each)
+	}
+	return output, rows.Close()
+}
+
+// UpdateOrganizerStripeSettings updates OrganizerStripeSettings in a Postgres database
+func (repo PostgresOrganizerStripeSettingsRepository) UpdateOrganizerStripeSettings(settings businesslogic.OrganizerStripeSettings) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if settings.ID < 1 {
+		return errors.New("ID of OrganizerStripeSettings must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasOrganizerStripeSettingsTable).
+		Set(columnPublicKey, settings.StripePublicKey).
+		Set(columnRestrictedKey, settings.StripeRestrictedKey).
+		Set(columnWebhookSecret, settings.StripeWebhookSecret).
+		Set(columnCurrency, settings.Currency).
+		Set(common.ColumnUpdateUserID, settings.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, settings.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: settings.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating OrganizerStripeSettings of organizer %v: %v", settings.OrganizerID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
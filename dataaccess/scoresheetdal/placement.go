@@ -0,0 +1,220 @@
+package scoresheetdal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasScoresheetPlacementTable         = "DAS.SCORESHEET_PLACEMENT"
+	columnRoundID                       = "ROUND_ID"
+	columnEventDanceID                  = "EVENT_DANCE_ID"
+	columnAdjudicatorRoundEntryID       = "ROUND_ENTRY_ADJUDICATOR_ID"
+	columnPartnershipRoundEntryID       = "ROUND_ENTRY_PARTNERSHIP_ID"
+	columnPreliminaryRoundIndicator     = "PRELIMINARY_ROUND_IND"
+	columnCallbackIndicator             = "CALLBACK_IND"
+	dasRoundTable                       = "DAS.ROUND"
+	dasEventTable                       = "DAS.EVENT"
+	dasPartnershipRoundEntryTable       = "DAS.ROUND_ENTRY_PARTNERSHIP"
+	dasPartnershipRoundEntryPartnership = "DAS.ROUND_ENTRY_PARTNERSHIP.PARTNERSHIP_ID"
+)
+
+// PostgresPlacementRepository implements IPlacementRepository with a Postgres database
+type PostgresPlacementRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreatePlacement creates a Placement in a Postgres database
+func (repo PostgresPlacementRepository) CreatePlacement(placement *businesslogic.Placement) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasScoresheetPlacementTable).
+		Columns(
+			columnRoundID,
+			columnEventDanceID,
+			columnAdjudicatorRoundEntryID,
+			columnPartnershipRoundEntryID,
+			columnPreliminaryRoundIndicator,
+			columnCallbackIndicator,
+			common.COL_PLACEMENT,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			placement.RoundID,
+			placement.EventDanceID,
+			placement.AdjudicatorRoundEntryID,
+			placement.PartnershipRoundEntryID,
+			placement.PreliminaryRoundIndicator,
+			placement.Callback,
+			placement.Placement,
+			placement.CreateUserID,
+			placement.DateTimeCreated,
+			placement.UpdateUserID,
+			placement.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&placement.ID); scanErr != nil {
+		log.Printf("[error] creating Placement %#v: %v", placement, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// DeletePlacement deletes a Placement from a Postgres database by the ID of the Placement
+func (repo PostgresPlacementRepository) DeletePlacement(placement businesslogic.Placement) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if placement.ID < 1 {
+		return errors.New("ID of Placement must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").
+		From(dasScoresheetPlacementTable).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: placement.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting Placement with ID = %v: %v", placement.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchPlacement searches Placements in a Postgres database
+func (repo PostgresPlacementRepository) SearchPlacement(criteria businesslogic.SearchPlacementCriteria) ([]businesslogic.Placement, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		placementColumn(common.ColumnPrimaryKey),
+		placementColumn(columnRoundID),
+		placementColumn(columnEventDanceID),
+		placementColumn(columnAdjudicatorRoundEntryID),
+		placementColumn(columnPartnershipRoundEntryID),
+		placementColumn(columnPreliminaryRoundIndicator),
+		placementColumn(columnCallbackIndicator),
+		placementColumn(common.COL_PLACEMENT),
+		placementColumn(common.ColumnCreateUserID),
+		placementColumn(common.ColumnDateTimeCreated),
+		placementColumn(common.ColumnUpdateUserID),
+		placementColumn(common.ColumnDateTimeUpdated)).
+		From(dasScoresheetPlacementTable).
+		OrderBy(placementColumn(common.ColumnPrimaryKey))
+
+	if criteria.CompetitionID > 0 || criteria.EventID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", dasRoundTable,
+			dasRoundTable, common.ColumnPrimaryKey, placementColumn(columnRoundID)))
+	}
+	if criteria.CompetitionID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s.%s", dasEventTable,
+			dasEventTable, common.ColumnPrimaryKey, dasRoundTable, common.COL_EVENT_ID)).
+			Where(squirrel.Eq{fmt.Sprintf("%s.%s", dasEventTable, common.COL_COMPETITION_ID): criteria.CompetitionID})
+	}
+	if criteria.EventID > 0 {
+		stmt = stmt.Where(squirrel.Eq{fmt.Sprintf("%s.%s", dasRoundTable, common.COL_EVENT_ID): criteria.EventID})
+	}
+	if criteria.PartnershipID > 0 {
+		stmt = stmt.Join(fmt.Sprintf("%s ON %s.%s = %s", dasPartnershipRoundEntryTable,
+			dasPartnershipRoundEntryTable, common.ColumnPrimaryKey, placementColumn(columnPartnershipRoundEntryID))).
+			Where(squirrel.Eq{dasPartnershipRoundEntryPartnership: criteria.PartnershipID})
+	}
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{placementColumn(columnRoundID): criteria.RoundID})
+	}
+	if criteria.EventDanceID > 0 {
+		stmt = stmt.Where(squirrel.Eq{placementColumn(columnEventDanceID): criteria.EventDanceID})
+	}
+	if criteria.AdjudicatorRoundEntryID > 0 {
+		stmt = stmt.Where(squirrel.Eq{placementColumn(columnAdjudicatorRoundEntryID): criteria.AdjudicatorRoundEntryID})
+	}
+	if criteria.PartnershipRoundEntryID > 0 {
+		stmt = stmt.Where(squirrel.Eq{placementColumn(columnPartnershipRoundEntryID): criteria.PartnershipRoundEntryID})
+	}
+
+	placements := make([]businesslogic.Placement, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching Placement with criteria %#v: %v", criteria, err)
+		return placements, err
+	}
+	for rows.Next() {
+		each := businesslogic.Placement{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundID,
+			&each.EventDanceID,
+			&each.AdjudicatorRoundEntryID,
+			&each.PartnershipRoundEntryID,
+			&each.PreliminaryRoundIndicator,
+			&each.Callback,
+			&each.Placement,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning Placement with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return placements, scanErr
+		}
+		placements = append(placements, each)
+	}
+	return placements, rows.Close()
+}
+
+// UpdatePlacement updates the callback and placement of a Placement in a Postgres database
+func (repo PostgresPlacementRepository) UpdatePlacement(placement businesslogic.Placement) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if placement.ID < 1 {
+		return errors.New("ID of Placement must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasScoresheetPlacementTable).
+		Set(columnCallbackIndicator, placement.Callback).
+		Set(common.COL_PLACEMENT, placement.Placement).
+		Set(common.ColumnUpdateUserID, placement.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, placement.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: placement.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating Placement with ID = %v: %v", placement.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// placementColumn qualifies the column with the placement table, since searching may join other tables that have
+// columns of the same name
+func placementColumn(column string) string {
+	return fmt.Sprintf("%s.%s", dasScoresheetPlacementTable, column)
+}
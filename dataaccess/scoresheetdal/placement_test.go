@@ -0,0 +1,78 @@
+package scoresheetdal_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/scoresheetdal"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"testing"
+	"time"
+)
+
+var placementRepository = scoresheetdal.PostgresPlacementRepository{
+	Database:   nil,
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+func TestPostgresPlacementRepository_CreatePlacement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	placement := businesslogic.Placement{
+		RoundID:                 7,
+		EventDanceID:            3,
+		AdjudicatorRoundEntryID: 21,
+		PartnershipRoundEntryID: 101,
+		Placement:               1,
+	}
+	repo := placementRepository
+	err := repo.CreatePlacement(&placement)
+	assert.NotNil(t, err, dalutil.ErrorNilDatabase)
+
+	repo.Database = db
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO DAS.SCORESHEET_PLACEMENT`).WillReturnRows(sqlmock.NewRows([]string{"ID"}).AddRow(11))
+	mock.ExpectCommit()
+
+	err = repo.CreatePlacement(&placement)
+	assert.Nil(t, err)
+	assert.Equal(t, 11, placement.ID)
+}
+
+func TestPostgresPlacementRepository_SearchPlacement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := placementRepository
+	repo.Database = db
+	rows := sqlmock.NewRows([]string{
+		"ID", "ROUND_ID", "EVENT_DANCE_ID", "ROUND_ENTRY_ADJUDICATOR_ID", "ROUND_ENTRY_PARTNERSHIP_ID",
+		"PRELIMINARY_ROUND_IND", "CALLBACK_IND", "PLACEMENT",
+		"CREATE_USER_ID", "DATETIME_CREATED", "UPDATE_USER_ID", "DATETIME_UPDATED",
+	}).AddRow(11, 7, 3, 21, 101, false, false, 1, 1, time.Now(), 1, time.Now())
+	mock.ExpectQuery(`SELECT (.+) FROM DAS.SCORESHEET_PLACEMENT JOIN DAS.ROUND (.+) WHERE DAS.ROUND.EVENT_ID = (.+)`).
+		WillReturnRows(rows)
+
+	placements, err := repo.SearchPlacement(businesslogic.SearchPlacementCriteria{EventID: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(placements))
+	assert.Equal(t, 101, placements[0].PartnershipRoundEntryID)
+}
+
+func TestPostgresPlacementRepository_UpdatePlacement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := placementRepository
+	repo.Database = db
+	err := repo.UpdatePlacement(businesslogic.Placement{Placement: 2})
+	assert.NotNil(t, err, "should not update Placement without ID")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE DAS.SCORESHEET_PLACEMENT SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = repo.UpdatePlacement(businesslogic.Placement{ID: 11, Placement: 2})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,232 @@
+package scoresheetdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasScoresheetResultTable = "DAS.SCORESHEET_RESULT"
+	columnRecallMarks        = "RECALL_MARKS"
+	columnRecallIndicator    = "RECALL_IND"
+	columnPlacementValue     = "PLACEMENT_VALUE"
+	columnRule               = "RULE"
+)
+
+// PostgresRoundResultRepository implements IRoundResultRepository with a Postgres database
+type PostgresRoundResultRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateRoundResult creates a RoundResult in a Postgres database. The overall result of a round, which has no
+// EventDanceID, is stored with a NULL dance.
+func (repo PostgresRoundResultRepository) CreateRoundResult(result *businesslogic.RoundResult) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if err := repo.insertRoundResult(tx, result); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceRoundResults deletes the stored results of the round and creates the results in one transaction, so that a
+// round never has partial results
+func (repo PostgresRoundResultRepository) ReplaceRoundResults(roundID int, results []businesslogic.RoundResult) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	stmt := repo.SQLBuilder.Delete("").
+		From(dasScoresheetResultTable).
+		Where(squirrel.Eq{columnRoundID: roundID})
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting RoundResult of round %v: %v", roundID, err)
+		tx.Rollback()
+		return err
+	}
+	for i := range results {
+		if err := repo.insertRoundResult(tx, &results[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (repo PostgresRoundResultRepository) insertRoundResult(tx *sql.Tx, result *businesslogic.RoundResult) error {
+	eventDanceID := sql.NullInt64{Int64: int64(result.EventDanceID), Valid: result.EventDanceID > 0}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasScoresheetResultTable).
+		Columns(
+			columnRoundID,
+			columnEventDanceID,
+			columnPartnershipRoundEntryID,
+			columnPreliminaryRoundIndicator,
+			columnRecallMarks,
+			columnRecallIndicator,
+			common.COL_PLACEMENT,
+			columnPlacementValue,
+			columnRule,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			result.RoundID,
+			eventDanceID,
+			result.PartnershipRoundEntryID,
+			result.PreliminaryRoundIndicator,
+			result.RecallMarks,
+			result.Recalled,
+			result.Placement,
+			result.PlacementValue,
+			result.Rule,
+			result.CreateUserID,
+			result.DateTimeCreated,
+			result.UpdateUserID,
+			result.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&result.ID); scanErr != nil {
+		log.Printf("[error] creating RoundResult %#v: %v", result, scanErr)
+		return scanErr
+	}
+	return nil
+}
+
+// DeleteRoundResult deletes a RoundResult from a Postgres database by the ID of the RoundResult
+func (repo PostgresRoundResultRepository) DeleteRoundResult(result businesslogic.RoundResult) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if result.ID < 1 {
+		return errors.New("ID of RoundResult must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").
+		From(dasScoresheetResultTable).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: result.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting RoundResult with ID = %v: %v", result.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchRoundResult searches RoundResults in a Postgres database
+func (repo PostgresRoundResultRepository) SearchRoundResult(criteria businesslogic.SearchRoundResultCriteria) ([]businesslogic.RoundResult, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnRoundID,
+		columnEventDanceID,
+		columnPartnershipRoundEntryID,
+		columnPreliminaryRoundIndicator,
+		columnRecallMarks,
+		columnRecallIndicator,
+		common.COL_PLACEMENT,
+		columnPlacementValue,
+		columnRule,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasScoresheetResultTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnRoundID: criteria.RoundID})
+	}
+	if criteria.PartnershipRoundEntryID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnPartnershipRoundEntryID: criteria.PartnershipRoundEntryID})
+	}
+
+	results := make([]businesslogic.RoundResult, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching RoundResult with criteria %#v: %v", criteria, err)
+		return results, err
+	}
+	for rows.Next() {
+		each := businesslogic.RoundResult{}
+		eventDanceID := sql.NullInt64{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundID,
+			&eventDanceID,
+			&each.PartnershipRoundEntryID,
+			&each.PreliminaryRoundIndicator,
+			&each.RecallMarks,
+			&each.Recalled,
+			&each.Placement,
+			&each.PlacementValue,
+			&each.Rule,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning RoundResult with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return results, scanErr
+		}
+		each.EventDanceID = int(eventDanceID.Int64)
+		results = append(results, each)
+	}
+	return results, rows.Close()
+}
+
+// UpdateRoundResult updates the recall and placement of a RoundResult in a Postgres database
+func (repo PostgresRoundResultRepository) UpdateRoundResult(result businesslogic.RoundResult) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if result.ID < 1 {
+		return errors.New("ID of RoundResult must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasScoresheetResultTable).
+		Set(columnRecallMarks, result.RecallMarks).
+		Set(columnRecallIndicator, result.Recalled).
+		Set(common.COL_PLACEMENT, result.Placement).
+		Set(columnPlacementValue, result.PlacementValue).
+		Set(columnRule, result.Rule).
+		Set(common.ColumnUpdateUserID, result.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, result.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: result.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating RoundResult with ID = %v: %v", result.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
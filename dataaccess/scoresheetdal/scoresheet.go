@@ -0,0 +1,179 @@
+package scoresheetdal
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/common"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"log"
+)
+
+const (
+	dasScoresheetTable   = "DAS.SCORESHEET"
+	columnRecallSize     = "RECALL_SIZE"
+	columnStatus         = "STATUS"
+	columnDateTimeLocked = "DATETIME_LOCKED"
+)
+
+// PostgresScoresheetRepository implements IScoresheetRepository with a Postgres database
+type PostgresScoresheetRepository struct {
+	Database   *sql.DB
+	SQLBuilder squirrel.StatementBuilderType
+}
+
+// CreateScoresheet creates a Scoresheet in a Postgres database
+func (repo PostgresScoresheetRepository) CreateScoresheet(scoresheet *businesslogic.Scoresheet) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Insert("").
+		Into(dasScoresheetTable).
+		Columns(
+			columnRoundID,
+			columnPreliminaryRoundIndicator,
+			columnRecallSize,
+			columnStatus,
+			common.COL_SCRUTINEER_ID,
+			columnDateTimeLocked,
+			common.ColumnCreateUserID,
+			common.ColumnDateTimeCreated,
+			common.ColumnUpdateUserID,
+			common.ColumnDateTimeUpdated).
+		Values(
+			scoresheet.RoundID,
+			scoresheet.PreliminaryRoundIndicator,
+			scoresheet.RecallSize,
+			scoresheet.Status,
+			scoresheet.ScrutineerID,
+			scoresheet.DateTimeLocked,
+			scoresheet.CreateUserID,
+			scoresheet.DateTimeCreated,
+			scoresheet.UpdateUserID,
+			scoresheet.DateTimeUpdated).
+		Suffix(dalutil.SQLSuffixReturningID)
+
+	clause, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if scanErr := tx.QueryRow(clause, args...).Scan(&scoresheet.ID); scanErr != nil {
+		log.Printf("[error] creating Scoresheet %#v: %v", scoresheet, scanErr)
+		tx.Rollback()
+		return scanErr
+	}
+	return tx.Commit()
+}
+
+// DeleteScoresheet deletes a Scoresheet from a Postgres database by the ID of the Scoresheet
+func (repo PostgresScoresheetRepository) DeleteScoresheet(scoresheet businesslogic.Scoresheet) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if scoresheet.ID < 1 {
+		return errors.New("ID of Scoresheet must be specified")
+	}
+	stmt := repo.SQLBuilder.Delete("").
+		From(dasScoresheetTable).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: scoresheet.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] deleting Scoresheet with ID = %v: %v", scoresheet.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchScoresheet searches Scoresheets in a Postgres database
+func (repo PostgresScoresheetRepository) SearchScoresheet(criteria businesslogic.SearchScoresheetCriteria) ([]businesslogic.Scoresheet, error) {
+	if repo.Database == nil {
+		return nil, errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	stmt := repo.SQLBuilder.Select(
+		common.ColumnPrimaryKey,
+		columnRoundID,
+		columnPreliminaryRoundIndicator,
+		columnRecallSize,
+		columnStatus,
+		common.COL_SCRUTINEER_ID,
+		columnDateTimeLocked,
+		common.ColumnCreateUserID,
+		common.ColumnDateTimeCreated,
+		common.ColumnUpdateUserID,
+		common.ColumnDateTimeUpdated).
+		From(dasScoresheetTable).
+		OrderBy(common.ColumnPrimaryKey)
+	if criteria.ID > 0 {
+		stmt = stmt.Where(squirrel.Eq{common.ColumnPrimaryKey: criteria.ID})
+	}
+	if criteria.RoundID > 0 {
+		stmt = stmt.Where(squirrel.Eq{columnRoundID: criteria.RoundID})
+	}
+
+	scoresheets := make([]businesslogic.Scoresheet, 0)
+	rows, err := stmt.RunWith(repo.Database).Query()
+	if err != nil {
+		log.Printf("[error] searching Scoresheet with criteria %#v: %v", criteria, err)
+		return scoresheets, err
+	}
+	for rows.Next() {
+		each := businesslogic.Scoresheet{}
+		scanErr := rows.Scan(
+			&each.ID,
+			&each.RoundID,
+			&each.PreliminaryRoundIndicator,
+			&each.RecallSize,
+			&each.Status,
+			&each.ScrutineerID,
+			&each.DateTimeLocked,
+			&each.CreateUserID,
+			&each.DateTimeCreated,
+			&each.UpdateUserID,
+			&each.DateTimeUpdated,
+		)
+		if scanErr != nil {
+			log.Printf("[error] scanning Scoresheet with criteria %#v: %v", criteria, scanErr)
+			rows.Close()
+			return scoresheets, scanErr
+		}
+		scoresheets = append(scoresheets, each)
+	}
+	return scoresheets, rows.Close()
+}
+
+// UpdateScoresheet updates the recall size and status of a Scoresheet in a Postgres database
+func (repo PostgresScoresheetRepository) UpdateScoresheet(scoresheet businesslogic.Scoresheet) error {
+	if repo.Database == nil {
+		return errors.New(dalutil.DataSourceNotSpecifiedError(repo))
+	}
+	if scoresheet.ID < 1 {
+		return errors.New("ID of Scoresheet must be specified")
+	}
+	stmt := repo.SQLBuilder.Update("").
+		Table(dasScoresheetTable).
+		Set(columnRecallSize, scoresheet.RecallSize).
+		Set(columnStatus, scoresheet.Status).
+		Set(columnDateTimeLocked, scoresheet.DateTimeLocked).
+		Set(common.ColumnUpdateUserID, scoresheet.UpdateUserID).
+		Set(common.ColumnDateTimeUpdated, scoresheet.DateTimeUpdated).
+		Where(squirrel.Eq{common.ColumnPrimaryKey: scoresheet.ID})
+	tx, txErr := repo.Database.Begin()
+	if txErr != nil {
+		return txErr
+	}
+	if _, err := stmt.RunWith(tx).Exec(); err != nil {
+		log.Printf("[error] updating Scoresheet with ID = %v: %v", scoresheet.ID, err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,55 @@
+package scoresheetdal_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/dataaccess/scoresheetdal"
+	"github.com/DancesportSoftware/das/dataaccess/util"
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"testing"
+	"time"
+)
+
+var scoresheetRepository = scoresheetdal.PostgresScoresheetRepository{
+	Database:   nil,
+	SQLBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+}
+
+func TestPostgresScoresheetRepository_CreateScoresheet(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	scoresheet := businesslogic.Scoresheet{RoundID: 7, Status: businesslogic.SCORESHEET_STATUS_OPEN}
+	repo := scoresheetRepository
+	err := repo.CreateScoresheet(&scoresheet)
+	assert.NotNil(t, err, dalutil.ErrorNilDatabase)
+
+	repo.Database = db
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO DAS.SCORESHEET`).WillReturnRows(sqlmock.NewRows([]string{"ID"}).AddRow(4))
+	mock.ExpectCommit()
+
+	err = repo.CreateScoresheet(&scoresheet)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, scoresheet.ID)
+}
+
+func TestPostgresScoresheetRepository_SearchScoresheet(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := scoresheetRepository
+	repo.Database = db
+	rows := sqlmock.NewRows([]string{
+		"ID", "ROUND_ID", "PRELIMINARY_ROUND_IND", "RECALL_SIZE", "STATUS", "SCRUTINEER_ID", "DATETIME_LOCKED",
+		"CREATE_USER_ID", "DATETIME_CREATED", "UPDATE_USER_ID", "DATETIME_UPDATED",
+	}).AddRow(4, 7, true, 12, businesslogic.SCORESHEET_STATUS_LOCKED, 11, time.Now(), 11, time.Now(), 11, time.Now())
+	mock.ExpectQuery(`SELECT .+ FROM DAS.SCORESHEET WHERE ROUND_ID = \$1`).WithArgs(7).WillReturnRows(rows)
+
+	scoresheets, err := repo.SearchScoresheet(businesslogic.SearchScoresheetCriteria{RoundID: 7})
+	assert.Nil(t, err)
+	assert.Len(t, scoresheets, 1)
+	assert.False(t, scoresheets[0].IsOpen())
+	assert.NotNil(t, scoresheets[0].DateTimeLocked)
+}
@@ -0,0 +1,233 @@
+// Package pdf implements businesslogic.IDocumentRenderer with PDF documents. Documents are written directly with the
+// standard Helvetica fonts of PDF readers, so that nothing needs to be installed or called to print them.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of PDF documents
+const ContentType = "application/pdf"
+
+// Sizes of pages in points
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+	margin       = 54.0
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// Renderer renders competition documents on US Letter pages. Back numbers are printed in landscape, one on each page.
+type Renderer struct{}
+
+func NewRenderer() Renderer {
+	return Renderer{}
+}
+
+// ContentType returns the media type of PDF documents
+func (renderer Renderer) ContentType() string {
+	return ContentType
+}
+
+// RenderBackNumbers prints the number and the name of each lead on a page
+func (renderer Renderer) RenderBackNumbers(numbers []businesslogic.BackNumber) ([]byte, error) {
+	doc := newDocument(LetterHeight, LetterWidth)
+	for _, each := range numbers {
+		doc.newPage()
+		doc.centeredText(doc.height-margin-24, 24, fontRegular, each.CompetitionName)
+		doc.centeredText(doc.height/2-100, 300, fontBold, strconv.Itoa(each.Tag))
+		doc.centeredText(margin, 28, fontRegular, each.LeadName)
+	}
+	return doc.bytes(), nil
+}
+
+// RenderHeatSheets prints each event from a new page, with the couples of each heat ordered by their numbers
+func (renderer Renderer) RenderHeatSheets(sheets []businesslogic.HeatSheet) ([]byte, error) {
+	doc := newDocument(LetterWidth, LetterHeight)
+	for _, sheet := range sheets {
+		doc.newPage()
+		doc.writeLine(0, 12, fontRegular, sheet.CompetitionName)
+		doc.writeLine(0, 18, fontBold, sheet.EventName)
+		doc.space(6)
+		for _, heat := range sheet.Heats {
+			title := "Entries"
+			if heat.Round > 0 {
+				title = fmt.Sprintf("Round %v - %v - Heat %v", heat.Round, heat.Dance, heat.Number)
+			}
+			doc.keepLines(2)
+			doc.space(6)
+			doc.writeLine(0, 13, fontBold, title)
+			if len(heat.Couples) == 0 {
+				doc.writeLine(18, 11, fontRegular, "No couples")
+			}
+			for _, couple := range heat.Couples {
+				doc.writeLine(18, 11, fontRegular, coupleLine(couple))
+			}
+		}
+	}
+	return doc.bytes(), nil
+}
+
+// RenderSchoolEntrySummaries prints each school from a new page, with the events that each of its couples entered
+func (renderer Renderer) RenderSchoolEntrySummaries(summaries []businesslogic.SchoolEntrySummary) ([]byte, error) {
+	doc := newDocument(LetterWidth, LetterHeight)
+	for _, summary := range summaries {
+		doc.newPage()
+		doc.writeLine(0, 12, fontRegular, summary.CompetitionName)
+		doc.writeLine(0, 18, fontBold, summary.SchoolName)
+		doc.writeLine(0, 11, fontRegular, fmt.Sprintf("%v couples", len(summary.Entries)))
+		for _, entry := range summary.Entries {
+			doc.keepLines(2)
+			doc.space(6)
+			doc.writeLine(0, 12, fontBold, coupleLine(entry.Couple))
+			for _, event := range entry.Events {
+				doc.writeLine(18, 11, fontRegular, event)
+			}
+		}
+	}
+	return doc.bytes(), nil
+}
+
+func coupleLine(couple businesslogic.DocumentCouple) string {
+	number := "-"
+	if couple.Tag > 0 {
+		number = strconv.Itoa(couple.Tag)
+	}
+	return fmt.Sprintf("%-6v %v & %v", number, couple.LeadName, couple.FollowName)
+}
+
+// document lays out text on pages from the top. Positions are in points from the bottom left corner of the page.
+type document struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+	y      float64
+}
+
+func newDocument(width, height float64) *document {
+	return &document{width: width, height: height}
+}
+
+func (doc *document) newPage() {
+	doc.pages = append(doc.pages, new(bytes.Buffer))
+	doc.y = doc.height - margin
+}
+
+func (doc *document) text(x, y, size float64, font, text string) {
+	if len(doc.pages) == 0 {
+		doc.newPage()
+	}
+	fmt.Fprintf(doc.pages[len(doc.pages)-1], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(text))
+}
+
+func (doc *document) centeredText(y, size float64, font, text string) {
+	doc.text((doc.width-textWidth(text, size, font))/2, y, size, font, text)
+}
+
+// writeLine writes the text below the previous line, and continues on a new page if the page is full
+func (doc *document) writeLine(indent, size float64, font, text string) {
+	if len(doc.pages) == 0 {
+		doc.newPage()
+	}
+	lineHeight := size * 1.4
+	if doc.y-lineHeight < margin {
+		doc.newPage()
+	}
+	doc.y -= lineHeight
+	doc.text(margin+indent, doc.y, size, font, text)
+}
+
+func (doc *document) space(points float64) {
+	doc.y -= points
+}
+
+// keepLines starts a new page unless the page has room for the lines, so that titles are not separated from what
+// they describe
+func (doc *document) keepLines(lines int) {
+	if doc.y-float64(lines)*20 < margin {
+		doc.newPage()
+	}
+}
+
+// bytes writes the document with a catalog, a page tree, the two fonts, and a page and a content stream for each page
+func (doc *document) bytes() []byte {
+	if len(doc.pages) == 0 {
+		doc.newPage()
+	}
+	out := new(bytes.Buffer)
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, 0)
+	for i := range doc.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range doc.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			doc.width, doc.height, fontRegular, fontBold, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, each := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", each)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// escape encodes the text in WinAnsiEncoding as a PDF string. Characters that the encoding does not have are printed
+// as question marks.
+func escape(text string) string {
+	out := new(bytes.Buffer)
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r >= 32 && r < 127:
+			out.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(out, "\\%03o", r)
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+// textWidth estimates the width of the text in points. Digits of Helvetica are exactly 556 units wide, and other
+// characters are estimated by the average width of the font.
+func textWidth(text string, size float64, font string) float64 {
+	units := 0.0
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == ' ':
+			units += 278
+		case font == fontBold:
+			units += 590
+		default:
+			units += 540
+		}
+	}
+	return units * size / 1000
+}
@@ -0,0 +1,98 @@
+package pdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/document/pdf"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// assertValidPDF checks that the cross-reference table points at each object of the document
+func assertValidPDF(t *testing.T, document []byte) {
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(document)
+	if !assert.NotNil(t, startxref) {
+		return
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	assert.True(t, bytes.HasPrefix(document[xref:], []byte("xref\n")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document[xref:], -1)
+	for i, each := range offsets {
+		offset, _ := strconv.Atoi(string(each[1]))
+		assert.True(t, bytes.HasPrefix(document[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+	for _, each := range regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindAllSubmatchIndex(document, -1) {
+		length, _ := strconv.Atoi(string(document[each[2]:each[3]]))
+		assert.True(t, bytes.HasPrefix(document[each[1]+length:], []byte("endstream")), "length of stream")
+	}
+}
+
+func pageCount(document []byte) int {
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(document)
+	if count == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(count[1]))
+	return n
+}
+
+func TestRenderer_RenderBackNumbers(t *testing.T) {
+	document, err := pdf.NewRenderer().RenderBackNumbers([]businesslogic.BackNumber{
+		{CompetitionName: "Ohio Star Ball", Tag: 101, LeadName: "John Smith"},
+		{CompetitionName: "Ohio Star Ball", Tag: 102, LeadName: "José (Pepe) García"},
+	})
+	assert.Nil(t, err)
+	assertValidPDF(t, document)
+	assert.Equal(t, 2, pageCount(document), "each back number should be printed on a page")
+	assert.Contains(t, string(document), "/MediaBox [0 0 792 612]", "back numbers should be printed in landscape")
+	assert.Contains(t, string(document), "(101) Tj")
+	assert.Contains(t, string(document), `(Jos\351 \(Pepe\) Garc\355a) Tj`, "names should be encoded and escaped")
+}
+
+func TestRenderer_RenderHeatSheets(t *testing.T) {
+	couples := make([]businesslogic.DocumentCouple, 0)
+	for i := 1; i <= 60; i++ {
+		couples = append(couples, businesslogic.DocumentCouple{Tag: 100 + i, LeadName: fmt.Sprintf("Lead %d", i), FollowName: fmt.Sprintf("Follow %d", i)})
+	}
+	document, err := pdf.NewRenderer().RenderHeatSheets([]businesslogic.HeatSheet{
+		{CompetitionName: "Ohio Star Ball", EventName: "Amateur Adult Gold Latin", Heats: []businesslogic.HeatSheetHeat{
+			{Round: 1, Dance: "Cha Cha", Number: 1, Couples: couples},
+		}},
+		{CompetitionName: "Ohio Star Ball", EventName: "Amateur Adult Bronze Standard", Heats: []businesslogic.HeatSheetHeat{
+			{Couples: couples[:2]},
+		}},
+	})
+	assert.Nil(t, err)
+	assertValidPDF(t, document)
+	assert.Equal(t, 3, pageCount(document), "long heats should continue on the next page, and each event should start a new page")
+	assert.Contains(t, string(document), "(Round 1 - Cha Cha - Heat 1) Tj")
+	assert.Contains(t, string(document), "(Entries) Tj", "events without heats should list their entries")
+	assert.Contains(t, string(document), "(160    Lead 60 & Follow 60) Tj")
+}
+
+func TestRenderer_RenderSchoolEntrySummaries(t *testing.T) {
+	document, err := pdf.NewRenderer().RenderSchoolEntrySummaries([]businesslogic.SchoolEntrySummary{
+		{CompetitionName: "Ohio Star Ball", SchoolName: "Ohio State University", Entries: []businesslogic.SchoolEntry{
+			{Couple: businesslogic.DocumentCouple{LeadName: "John Smith", FollowName: "Jane Doe"}, Events: []string{"Amateur Adult Gold Latin"}},
+		}},
+	})
+	assert.Nil(t, err)
+	assertValidPDF(t, document)
+	assert.Equal(t, 1, pageCount(document))
+	assert.Contains(t, string(document), "(-      John Smith & Jane Doe) Tj", "couples without numbers should be marked")
+	assert.True(t, strings.Contains(string(document), "(Amateur Adult Gold Latin) Tj"))
+}
+
+func TestRenderer_Empty(t *testing.T) {
+	document, err := pdf.NewRenderer().RenderBackNumbers(nil)
+	assert.Nil(t, err)
+	assertValidPDF(t, document)
+	assert.Equal(t, 1, pageCount(document), "empty documents should still have a page")
+}
@@ -7,6 +7,20 @@ const (
 	VarFirebaseProjectId        = "FIREBASE_PROJECT_ID"
 	VarHMACSigningKey           = "HMAC_SIGNING_KEY"
 	VarHMACValidHours           = "HMAC_VALID_HOURS"
+	VarRSASigningKey            = "RSA_SIGNING_KEY"
+	VarAuthStrategy             = "AUTH_STRATEGY"
+	VarMailDirectory            = "MAIL_DIRECTORY"
+	VarWebAppURL                = "WEB_APP_URL"
+	VarOIDCIssuer               = "OIDC_ISSUER"
+	VarOIDCClientID             = "OIDC_CLIENT_ID"
+	VarEligibilityRules         = "ELIGIBILITY_RULES"
+)
+
+// Authentication strategies that can be selected with AUTH_STRATEGY
+const (
+	AuthStrategyFirebase = "firebase"
+	AuthStrategyLocal    = "local"
+	AuthStrategyOIDC     = "oidc"
 )
 
 const (
@@ -21,4 +35,11 @@ var (
 	FirebaseAuthCredential   string
 	HmacSigningKey           string
 	HmacValidHours           int
+	RSASigningKey            string
+	AuthStrategy             = AuthStrategyFirebase
+	MailDirectory            string
+	WebAppURL                string
+	OIDCIssuer               string
+	OIDCClientID             string
+	EligibilityRules         string // path to a JSON file of the eligibility rules of each federation and division
 )
@@ -3,6 +3,7 @@ package env
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -25,4 +26,53 @@ func init() {
 	} else {
 		log.Printf("[warning] %v is missing or undefined", VarFirebaseAuthCredential)
 	}
+	if val, ok := os.LookupEnv(VarAuthStrategy); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarAuthStrategy)
+		AuthStrategy = strings.ToLower(strings.TrimSpace(val))
+	} else {
+		log.Printf("[info] %v is undefined, using %v", VarAuthStrategy, AuthStrategyFirebase)
+	}
+	if val, ok := os.LookupEnv(VarHMACSigningKey); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarHMACSigningKey)
+		HmacSigningKey = strings.TrimSpace(val)
+	} else if AuthStrategy == AuthStrategyLocal {
+		log.Printf("[warning] %v is missing or undefined", VarHMACSigningKey)
+	}
+	if val, ok := os.LookupEnv(VarHMACValidHours); ok && len(strings.TrimSpace(val)) != 0 {
+		hours, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || hours < 1 {
+			log.Printf("[warning] %v is not a positive number of hours: %v", VarHMACValidHours, val)
+		} else {
+			log.Printf("[info] %v is defined", VarHMACValidHours)
+			HmacValidHours = hours
+		}
+	}
+	if val, ok := os.LookupEnv(VarRSASigningKey); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarRSASigningKey)
+		RSASigningKey = strings.TrimSpace(val)
+	}
+	if val, ok := os.LookupEnv(VarMailDirectory); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarMailDirectory)
+		MailDirectory = strings.TrimSpace(val)
+	}
+	if val, ok := os.LookupEnv(VarWebAppURL); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarWebAppURL)
+		WebAppURL = strings.TrimSpace(val)
+	}
+	if val, ok := os.LookupEnv(VarOIDCIssuer); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarOIDCIssuer)
+		OIDCIssuer = strings.TrimSpace(val)
+	} else if AuthStrategy == AuthStrategyOIDC {
+		log.Printf("[warning] %v is missing or undefined", VarOIDCIssuer)
+	}
+	if val, ok := os.LookupEnv(VarOIDCClientID); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarOIDCClientID)
+		OIDCClientID = strings.TrimSpace(val)
+	} else if AuthStrategy == AuthStrategyOIDC {
+		log.Printf("[warning] %v is missing or undefined", VarOIDCClientID)
+	}
+	if val, ok := os.LookupEnv(VarEligibilityRules); ok && len(strings.TrimSpace(val)) != 0 {
+		log.Printf("[info] %v is defined", VarEligibilityRules)
+		EligibilityRules = strings.TrimSpace(val)
+	}
 }
@@ -0,0 +1,57 @@
+// Package file implements businesslogic.IMailer for development. Messages are written to a directory as .eml files
+// that can be opened with any mail client, or to the log if no directory is specified, so that no mail server is needed.
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sender is the From address of messages
+const Sender = "DAS <no-reply@localhost>"
+
+// Mailer writes each message to a file in Directory, or to the log if Directory is empty. It is safe for concurrent
+// use.
+type Mailer struct {
+	Directory string
+	lock      *sync.Mutex
+	sequence  *int
+}
+
+func NewMailer(directory string) Mailer {
+	return Mailer{Directory: directory, lock: new(sync.Mutex), sequence: new(int)}
+}
+
+// SendMail writes the message
+func (mailer Mailer) SendMail(message businesslogic.MailMessage) error {
+	now := time.Now()
+	content := new(bytes.Buffer)
+	fmt.Fprintf(content, "From: %v\r\n", Sender)
+	fmt.Fprintf(content, "To: %v\r\n", message.To)
+	fmt.Fprintf(content, "Subject: %v\r\n", message.Subject)
+	fmt.Fprintf(content, "Date: %v\r\n", now.Format(time.RFC1123Z))
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.WriteString(message.Body)
+
+	if mailer.Directory == "" {
+		log.Printf("[info] mail to %v:\n%v", message.To, content.String())
+		return nil
+	}
+	mailer.lock.Lock()
+	*mailer.sequence++
+	name := fmt.Sprintf("%v-%04d.eml", now.Format("20060102T150405"), *mailer.sequence)
+	mailer.lock.Unlock()
+	path := filepath.Join(mailer.Directory, name)
+	if err := ioutil.WriteFile(path, content.Bytes(), 0600); err != nil {
+		log.Printf("[error] writing mail to %v: %v", path, err)
+		return err
+	}
+	log.Printf("[info] mail to %v is written to %v", message.To, path)
+	return nil
+}
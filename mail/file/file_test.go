@@ -0,0 +1,32 @@
+package file_test
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/mail/file"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMailer_SendMail(t *testing.T) {
+	directory, err := ioutil.TempDir("", "das-mail")
+	if !assert.Nil(t, err) {
+		return
+	}
+	mailer := file.NewMailer(directory)
+	message := businesslogic.MailMessage{To: "jsmith@example.com", Subject: "Reset your DAS password", Body: "Token: abc\r\n"}
+	assert.Nil(t, mailer.SendMail(message))
+	assert.Nil(t, mailer.SendMail(message))
+
+	files, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+	if assert.Len(t, files, 2, "each message should be written to its own file") {
+		content, _ := ioutil.ReadFile(files[0])
+		assert.True(t, strings.Contains(string(content), "To: jsmith@example.com\r\n"))
+		assert.True(t, strings.Contains(string(content), "Subject: Reset your DAS password\r\n"))
+		assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nToken: abc\r\n"))
+	}
+
+	assert.Nil(t, file.NewMailer("").SendMail(message), "messages should be logged without a directory")
+}
@@ -156,9 +156,9 @@ func (mr *MockICompetitionLeadTagRepositoryMockRecorder) DeleteCompetitionLeadTa
 }
 
 // SearchCompetitionLeadTag mocks base method
-func (m *MockICompetitionLeadTagRepository) SearchCompetitionLeadTag(criteria businesslogic.SearchCompetitionLeadTagCriteria) ([]businesslogic.CompetitionLeadTag, error) {
+func (m *MockICompetitionLeadTagRepository) SearchCompetitionLeadTag(criteria businesslogic.SearchCompetitionLeadTagCriteria) (businesslogic.CompetitionLeadTagCollection, error) {
 	ret := m.ctrl.Call(m, "SearchCompetitionLeadTag", criteria)
-	ret0, _ := ret[0].([]businesslogic.CompetitionLeadTag)
+	ret0, _ := ret[0].(businesslogic.CompetitionLeadTagCollection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -81,3 +81,63 @@ func (m *MockIOrganizerProvisionRepository) SearchOrganizerProvision(criteria bu
 func (mr *MockIOrganizerProvisionRepositoryMockRecorder) SearchOrganizerProvision(criteria interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchOrganizerProvision", reflect.TypeOf((*MockIOrganizerProvisionRepository)(nil).SearchOrganizerProvision), criteria)
 }
+
+// MockIOrganizerStripeSettingsRepository is a mock of IOrganizerStripeSettingsRepository interface
+type MockIOrganizerStripeSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOrganizerStripeSettingsRepositoryMockRecorder
+}
+
+// MockIOrganizerStripeSettingsRepositoryMockRecorder is the mock recorder for MockIOrganizerStripeSettingsRepository
+type MockIOrganizerStripeSettingsRepositoryMockRecorder struct {
+	mock *MockIOrganizerStripeSettingsRepository
+}
+
+// NewMockIOrganizerStripeSettingsRepository creates a new mock instance
+func NewMockIOrganizerStripeSettingsRepository(ctrl *gomock.Controller) *MockIOrganizerStripeSettingsRepository {
+	mock := &MockIOrganizerStripeSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockIOrganizerStripeSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIOrganizerStripeSettingsRepository) EXPECT() *MockIOrganizerStripeSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateOrganizerStripeSettings mocks base method
+func (m *MockIOrganizerStripeSettingsRepository) CreateOrganizerStripeSettings(settings *businesslogic.OrganizerStripeSettings) error {
+	ret := m.ctrl.Call(m, "CreateOrganizerStripeSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrganizerStripeSettings indicates an expected call of CreateOrganizerStripeSettings
+func (mr *MockIOrganizerStripeSettingsRepositoryMockRecorder) CreateOrganizerStripeSettings(settings interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganizerStripeSettings", reflect.TypeOf((*MockIOrganizerStripeSettingsRepository)(nil).CreateOrganizerStripeSettings), settings)
+}
+
+// SearchOrganizerStripeSettings mocks base method
+func (m *MockIOrganizerStripeSettingsRepository) SearchOrganizerStripeSettings(criteria businesslogic.SearchOrganizerStripeSettingsCriteria) ([]businesslogic.OrganizerStripeSettings, error) {
+	ret := m.ctrl.Call(m, "SearchOrganizerStripeSettings", criteria)
+	ret0, _ := ret[0].([]businesslogic.OrganizerStripeSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchOrganizerStripeSettings indicates an expected call of SearchOrganizerStripeSettings
+func (mr *MockIOrganizerStripeSettingsRepositoryMockRecorder) SearchOrganizerStripeSettings(criteria interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchOrganizerStripeSettings", reflect.TypeOf((*MockIOrganizerStripeSettingsRepository)(nil).SearchOrganizerStripeSettings), criteria)
+}
+
+// UpdateOrganizerStripeSettings mocks base method
+func (m *MockIOrganizerStripeSettingsRepository) UpdateOrganizerStripeSettings(settings businesslogic.OrganizerStripeSettings) error {
+	ret := m.ctrl.Call(m, "UpdateOrganizerStripeSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrganizerStripeSettings indicates an expected call of UpdateOrganizerStripeSettings
+func (mr *MockIOrganizerStripeSettingsRepositoryMockRecorder) UpdateOrganizerStripeSettings(settings interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizerStripeSettings", reflect.TypeOf((*MockIOrganizerStripeSettingsRepository)(nil).UpdateOrganizerStripeSettings), settings)
+}
@@ -0,0 +1,163 @@
+// Package fake implements businesslogic.IPaymentGateway in process, so that payments can be tested without a payment
+// provider. Checkout sessions are kept in memory, and their webhook events are signed with the webhook secret of the
+// organizer just like a real gateway would sign them.
+package fake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"sync"
+)
+
+// CheckoutSession is a checkout session that is created with the fake gateway
+type CheckoutSession struct {
+	ID       string
+	Settings businesslogic.OrganizerStripeSettings
+	Request  businesslogic.PaymentCheckoutRequest
+	Amount   float64
+}
+
+type webhookEvent struct {
+	Type      string  `json:"type"`
+	SessionID string  `json:"session"`
+	PaymentID string  `json:"payment"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+}
+
+// PaymentGateway is an in-process payment gateway. It is safe for concurrent use.
+type PaymentGateway struct {
+	lock     sync.Mutex
+	sessions map[string]CheckoutSession
+	refunds  []businesslogic.PaymentRefundRequest
+	sequence int
+	// FailCheckout makes CreateCheckoutSession fail when it is not nil
+	FailCheckout error
+}
+
+// NewPaymentGateway creates a fake PaymentGateway without any sessions
+func NewPaymentGateway() *PaymentGateway {
+	return &PaymentGateway{sessions: make(map[string]CheckoutSession)}
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateCheckoutSession creates a session in memory
+func (gateway *PaymentGateway) CreateCheckoutSession(settings businesslogic.OrganizerStripeSettings, request businesslogic.PaymentCheckoutRequest) (businesslogic.PaymentCheckoutSession, error) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+	if gateway.FailCheckout != nil {
+		return businesslogic.PaymentCheckoutSession{}, gateway.FailCheckout
+	}
+	gateway.sequence++
+	session := CheckoutSession{
+		ID:       fmt.Sprintf("cs_fake_%d", gateway.sequence),
+		Settings: settings,
+		Request:  request,
+		Amount:   businesslogic.PaymentCharge{Items: request.Items}.Total(),
+	}
+	gateway.sessions[session.ID] = session
+	return businesslogic.PaymentCheckoutSession{
+		ID:  session.ID,
+		URL: fmt.Sprintf("https://checkout.fake/%v", session.ID),
+	}, nil
+}
+
+// VerifyWebhook verifies that the payload is signed with the webhook secret of the organizer
+func (gateway *PaymentGateway) VerifyWebhook(settings businesslogic.OrganizerStripeSettings, payload []byte, signature string) (businesslogic.PaymentEvent, error) {
+	if !hmac.Equal([]byte(signature), []byte(sign(settings.StripeWebhookSecret, payload))) {
+		return businesslogic.PaymentEvent{}, businesslogic.PaymentSignatureError
+	}
+	event := webhookEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return businesslogic.PaymentEvent{}, err
+	}
+	return businesslogic.PaymentEvent{
+		Type:      event.Type,
+		SessionID: event.SessionID,
+		PaymentID: event.PaymentID,
+		Reference: event.Reference,
+		Amount:    event.Amount,
+	}, nil
+}
+
+// Refund records the refund. Like Stripe, a refund with the idempotency key of an earlier refund is not recorded again.
+func (gateway *PaymentGateway) Refund(settings businesslogic.OrganizerStripeSettings, request businesslogic.PaymentRefundRequest) (businesslogic.PaymentRefund, error) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+	if request.PaymentID == "" {
+		return businesslogic.PaymentRefund{}, errors.New("payment of the refund is not specified")
+	}
+	for i, each := range gateway.refunds {
+		if request.IdempotencyKey != "" && each.IdempotencyKey == request.IdempotencyKey {
+			return businesslogic.PaymentRefund{
+				ID:     fmt.Sprintf("re_fake_%d", i+1),
+				Amount: each.Amount,
+				Status: "succeeded",
+			}, nil
+		}
+	}
+	gateway.refunds = append(gateway.refunds, request)
+	return businesslogic.PaymentRefund{
+		ID:     fmt.Sprintf("re_fake_%d", len(gateway.refunds)),
+		Amount: request.Amount,
+		Status: "succeeded",
+	}, nil
+}
+
+// Session returns the checkout session with the ID
+func (gateway *PaymentGateway) Session(sessionID string) (CheckoutSession, bool) {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+	session, ok := gateway.sessions[sessionID]
+	return session, ok
+}
+
+// Refunds returns the refunds that have been made
+func (gateway *PaymentGateway) Refunds() []businesslogic.PaymentRefundRequest {
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+	return append([]businesslogic.PaymentRefundRequest{}, gateway.refunds...)
+}
+
+// event returns the payload and signature of a webhook event about the session
+func (gateway *PaymentGateway) event(sessionID, eventType string, amount float64) ([]byte, string, error) {
+	session, ok := gateway.Session(sessionID)
+	if !ok {
+		return nil, "", errors.New(fmt.Sprintf("cannot find checkout session %v", sessionID))
+	}
+	payload, err := json.Marshal(webhookEvent{
+		Type:      eventType,
+		SessionID: session.ID,
+		PaymentID: "pi_" + session.ID,
+		Reference: session.Request.Reference,
+		Amount:    amount,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, sign(session.Settings.StripeWebhookSecret, payload), nil
+}
+
+// Pay pays the full amount of the session, and returns the signed webhook event that reports the payment
+func (gateway *PaymentGateway) Pay(sessionID string) ([]byte, string, error) {
+	session, ok := gateway.Session(sessionID)
+	if !ok {
+		return nil, "", errors.New(fmt.Sprintf("cannot find checkout session %v", sessionID))
+	}
+	return gateway.event(sessionID, businesslogic.PaymentEventSucceeded, session.Amount)
+}
+
+// Fail fails the payment of the session, and returns the signed webhook event that reports the failure
+func (gateway *PaymentGateway) Fail(sessionID string) ([]byte, string, error) {
+	return gateway.event(sessionID, businesslogic.PaymentEventFailed, 0)
+}
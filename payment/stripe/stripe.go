@@ -0,0 +1,244 @@
+// Package stripe implements businesslogic.IPaymentGateway with the Stripe API. Every request is made with the keys of
+// the organizer who receives the payment, so that payments go directly to the Stripe account of the organizer.
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Stripe API
+	DefaultBaseURL = "https://api.stripe.com"
+	// SignatureHeader is the HTTP header that contains the signature of webhook events
+	SignatureHeader = "Stripe-Signature"
+	// IdempotencyKeyHeader is the HTTP header that makes Stripe perform requests with the same key only once
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// DefaultWebhookTolerance is how old a webhook event can be when it is verified
+	DefaultWebhookTolerance = 5 * time.Minute
+)
+
+// zeroDecimalCurrencies are the currencies that Stripe charges in their major unit
+var zeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true, "krw": true, "mga": true,
+	"pyg": true, "rwf": true, "ugx": true, "vnd": true, "vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+func toMinorUnit(amount float64, currency string) int64 {
+	if zeroDecimalCurrencies[strings.ToLower(currency)] {
+		return int64(math.Round(amount))
+	}
+	return int64(math.Round(amount * 100))
+}
+
+func toMajorUnit(amount int64, currency string) float64 {
+	if zeroDecimalCurrencies[strings.ToLower(currency)] {
+		return float64(amount)
+	}
+	return float64(amount) / 100
+}
+
+// PaymentGateway implements businesslogic.IPaymentGateway with the Stripe Checkout API
+type PaymentGateway struct {
+	Client           *http.Client
+	BaseURL          string
+	WebhookTolerance time.Duration
+	Now              func() time.Time
+}
+
+// NewPaymentGateway creates a PaymentGateway that sends requests to the Stripe API
+func NewPaymentGateway() PaymentGateway {
+	return PaymentGateway{
+		Client:           &http.Client{Timeout: 30 * time.Second},
+		BaseURL:          DefaultBaseURL,
+		WebhookTolerance: DefaultWebhookTolerance,
+		Now:              time.Now,
+	}
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// post sends a form to the Stripe API with the restricted key of the organizer, and decodes the JSON response. Stripe
+// returns the result of the first request for later requests with the same idempotency key, if the key is not empty.
+func (gateway PaymentGateway) post(settings businesslogic.OrganizerStripeSettings, path string, form url.Values, idempotencyKey string, output interface{}) error {
+	if settings.StripeRestrictedKey == "" {
+		return errors.New("stripe key of organizer is not specified")
+	}
+	request, err := http.NewRequest(http.MethodPost, gateway.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+settings.StripeRestrictedKey)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		request.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
+	response, err := gateway.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		stripeErr := stripeError{}
+		if decodeErr := json.NewDecoder(response.Body).Decode(&stripeErr); decodeErr != nil || stripeErr.Error.Message == "" {
+			return errors.New(fmt.Sprintf("stripe responded with status %v", response.StatusCode))
+		}
+		return errors.New(fmt.Sprintf("stripe: %v", stripeErr.Error.Message))
+	}
+	return json.NewDecoder(response.Body).Decode(output)
+}
+
+// CreateCheckoutSession creates a Stripe Checkout session in payment mode. The reference is sent as the client
+// reference ID of the session, and is returned in the events of the session.
+func (gateway PaymentGateway) CreateCheckoutSession(settings businesslogic.OrganizerStripeSettings, request businesslogic.PaymentCheckoutRequest) (businesslogic.PaymentCheckoutSession, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", request.SuccessURL)
+	form.Set("cancel_url", request.CancelURL)
+	form.Set("client_reference_id", request.Reference)
+	form.Set("metadata[reference]", request.Reference)
+	for i, each := range request.Items {
+		prefix := fmt.Sprintf("line_items[%d]", i)
+		form.Set(prefix+"[quantity]", strconv.Itoa(each.Quantity))
+		form.Set(prefix+"[price_data][currency]", request.Currency)
+		form.Set(prefix+"[price_data][unit_amount]", strconv.FormatInt(toMinorUnit(each.UnitAmount, request.Currency), 10))
+		form.Set(prefix+"[price_data][product_data][name]", each.Name)
+	}
+
+	session := struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}{}
+	if err := gateway.post(settings, "/v1/checkout/sessions", form, "", &session); err != nil {
+		return businesslogic.PaymentCheckoutSession{}, err
+	}
+	return businesslogic.PaymentCheckoutSession{ID: session.ID, URL: session.URL}, nil
+}
+
+// Refund refunds a payment intent. The whole payment is refunded if the amount is 0. The idempotency key of the request
+// is sent to Stripe so that a retried refund is only refunded once.
+func (gateway PaymentGateway) Refund(settings businesslogic.OrganizerStripeSettings, request businesslogic.PaymentRefundRequest) (businesslogic.PaymentRefund, error) {
+	if request.PaymentID == "" {
+		return businesslogic.PaymentRefund{}, errors.New("payment intent of the refund is not specified")
+	}
+	form := url.Values{}
+	form.Set("payment_intent", request.PaymentID)
+	if request.Amount > 0 {
+		form.Set("amount", strconv.FormatInt(toMinorUnit(request.Amount, request.Currency), 10))
+	}
+
+	refund := struct {
+		ID       string `json:"id"`
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+		Status   string `json:"status"`
+	}{}
+	if err := gateway.post(settings, "/v1/refunds", form, request.IdempotencyKey, &refund); err != nil {
+		return businesslogic.PaymentRefund{}, err
+	}
+	return businesslogic.PaymentRefund{
+		ID:     refund.ID,
+		Amount: toMajorUnit(refund.Amount, refund.Currency),
+		Status: refund.Status,
+	}, nil
+}
+
+// verifySignature checks the signature header of a webhook event, which has the form t=timestamp,v1=signature. The
+// signature is the HMAC-SHA256 of "timestamp.payload" with the webhook secret of the organizer.
+func (gateway PaymentGateway) verifySignature(secret string, payload []byte, header string) error {
+	timestamp := ""
+	signatures := make([]string, 0)
+	for _, each := range strings.Split(header, ",") {
+		pair := strings.SplitN(strings.TrimSpace(each), "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		switch pair[0] {
+		case "t":
+			timestamp = pair[1]
+		case "v1":
+			signatures = append(signatures, pair[1])
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return businesslogic.PaymentSignatureError
+	}
+	age := gateway.Now().Sub(time.Unix(seconds, 0))
+	if gateway.WebhookTolerance > 0 && (age > gateway.WebhookTolerance || age < -gateway.WebhookTolerance) {
+		return businesslogic.PaymentSignatureError
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, each := range signatures {
+		signature, err := hex.DecodeString(each)
+		if err == nil && hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return businesslogic.PaymentSignatureError
+}
+
+// VerifyWebhook verifies the signature of a webhook event with the webhook secret of the organizer, and converts the
+// events of Checkout sessions to payment events. Other events are returned with an empty type.
+func (gateway PaymentGateway) VerifyWebhook(settings businesslogic.OrganizerStripeSettings, payload []byte, signature string) (businesslogic.PaymentEvent, error) {
+	if err := gateway.verifySignature(settings.StripeWebhookSecret, payload, signature); err != nil {
+		return businesslogic.PaymentEvent{}, err
+	}
+	event := struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID                string `json:"id"`
+				ClientReferenceID string `json:"client_reference_id"`
+				PaymentIntent     string `json:"payment_intent"`
+				PaymentStatus     string `json:"payment_status"`
+				AmountTotal       int64  `json:"amount_total"`
+				Currency          string `json:"currency"`
+			} `json:"object"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return businesslogic.PaymentEvent{}, err
+	}
+
+	session := event.Data.Object
+	output := businesslogic.PaymentEvent{
+		SessionID: session.ID,
+		PaymentID: session.PaymentIntent,
+		Reference: session.ClientReferenceID,
+		Amount:    toMajorUnit(session.AmountTotal, session.Currency),
+	}
+	switch event.Type {
+	case "checkout.session.completed":
+		// payments with delayed methods, such as bank debits, are completed before they are paid
+		if session.PaymentStatus == "paid" {
+			output.Type = businesslogic.PaymentEventSucceeded
+		}
+	case "checkout.session.async_payment_succeeded":
+		output.Type = businesslogic.PaymentEventSucceeded
+	case "checkout.session.async_payment_failed", "checkout.session.expired":
+		output.Type = businesslogic.PaymentEventFailed
+	}
+	return output, nil
+}
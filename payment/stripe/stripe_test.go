@@ -0,0 +1,108 @@
+package stripe_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/payment/stripe"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var settings = businesslogic.OrganizerStripeSettings{
+	OrganizerID:         41,
+	StripeRestrictedKey: "rk_test_41",
+	StripeWebhookSecret: "whsec_41",
+	Currency:            "usd",
+}
+
+func newGateway(server *httptest.Server, now time.Time) stripe.PaymentGateway {
+	gateway := stripe.NewPaymentGateway()
+	if server != nil {
+		gateway.Client = server.Client()
+		gateway.BaseURL = server.URL
+	}
+	gateway.Now = func() time.Time { return now }
+	return gateway
+}
+
+func signature(secret string, timestamp int64, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.%s", timestamp, payload)))
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestPaymentGateway_CreateCheckoutSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/checkout/sessions", r.URL.Path)
+		assert.Equal(t, "Bearer rk_test_41", r.Header.Get("Authorization"), "should use the key of the organizer")
+		r.ParseForm()
+		assert.Equal(t, "payment", r.PostForm.Get("mode"))
+		assert.Equal(t, "9", r.PostForm.Get("client_reference_id"))
+		assert.Equal(t, "2500", r.PostForm.Get("line_items[0][price_data][unit_amount]"))
+		assert.Equal(t, "2", r.PostForm.Get("line_items[0][quantity]"))
+		w.Write([]byte(`{"id": "cs_test_1", "url": "https://checkout.stripe.com/c/pay/cs_test_1"}`))
+	}))
+	defer server.Close()
+
+	session, err := newGateway(server, time.Now()).CreateCheckoutSession(settings, businesslogic.PaymentCheckoutRequest{
+		Reference: "9",
+		Currency:  "usd",
+		Items:     []businesslogic.PaymentLineItem{{Name: "Rumba Workshop", UnitAmount: 25, Quantity: 2}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "cs_test_1", session.ID)
+	assert.Equal(t, "https://checkout.stripe.com/c/pay/cs_test_1", session.URL)
+}
+
+func TestPaymentGateway_Refund(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("payment_intent") == "pi_missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"type": "invalid_request_error", "message": "No such payment_intent"}}`))
+			return
+		}
+		assert.Equal(t, "1000", r.PostForm.Get("amount"))
+		assert.Equal(t, "payment-9-refund-0.00", r.Header.Get("Idempotency-Key"))
+		w.Write([]byte(`{"id": "re_1", "amount": 1000, "currency": "usd", "status": "succeeded"}`))
+	}))
+	defer server.Close()
+	gateway := newGateway(server, time.Now())
+
+	refund, err := gateway.Refund(settings, businesslogic.PaymentRefundRequest{PaymentID: "pi_1", Amount: 10, Currency: "usd", IdempotencyKey: "payment-9-refund-0.00"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, refund.Amount)
+
+	_, err = gateway.Refund(settings, businesslogic.PaymentRefundRequest{PaymentID: "pi_missing", Currency: "usd"})
+	assert.EqualError(t, err, "stripe: No such payment_intent")
+}
+
+func TestPaymentGateway_VerifyWebhook(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	gateway := newGateway(nil, now)
+	payload := `{"type": "checkout.session.completed", "data": {"object": {"id": "cs_test_1", "client_reference_id": "9", "payment_intent": "pi_1", "payment_status": "paid", "amount_total": 5000, "currency": "usd"}}}`
+
+	event, err := gateway.VerifyWebhook(settings, []byte(payload), signature("whsec_41", now.Unix(), payload))
+	assert.Nil(t, err)
+	assert.Equal(t, businesslogic.PaymentEventSucceeded, event.Type)
+	assert.Equal(t, "cs_test_1", event.SessionID)
+	assert.Equal(t, "pi_1", event.PaymentID)
+	assert.EqualValues(t, 50, event.Amount)
+
+	_, err = gateway.VerifyWebhook(settings, []byte(payload), signature("whsec_other", now.Unix(), payload))
+	assert.Equal(t, businesslogic.PaymentSignatureError, err, "should reject events signed by other accounts")
+
+	_, err = gateway.VerifyWebhook(settings, []byte(payload), signature("whsec_41", now.Add(-time.Hour).Unix(), payload))
+	assert.Equal(t, businesslogic.PaymentSignatureError, err, "should reject events that are replayed later")
+
+	expired := `{"type": "checkout.session.expired", "data": {"object": {"id": "cs_test_2"}}}`
+	event, err = gateway.VerifyWebhook(settings, []byte(expired), signature("whsec_41", now.Unix(), expired))
+	assert.Nil(t, err)
+	assert.Equal(t, businesslogic.PaymentEventFailed, event.Type)
+}
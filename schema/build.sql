@@ -67,8 +67,14 @@ CREATE SCHEMA IF NOT EXISTS USADANCE;
 \i 'tables/das/competition_product_category.sql'
 \i 'tables/das/competition_product_status.sql'
 \i 'tables/das/competition_product.sql'
+\i 'tables/das/competition_product_order_status.sql'
 \i 'tables/das/competition_product_order.sql'
 
+-- payment section
+\i 'tables/das/competition_fee_schedule.sql'
+\i 'tables/das/organizer_stripe_settings.sql'
+\i 'tables/das/payment.sql'
+
 -- event section
 \i 'tables/das/event_status.sql'
 \i 'tables/das/event_category.sql'
@@ -81,6 +87,7 @@ CREATE SCHEMA IF NOT EXISTS USADANCE;
 
 -- entry section
 \i 'tables/das/competition_entry.sql'
+\i 'tables/das/competition_lead_tag_setting.sql'
 \i 'tables/das/competition_entry_tba.sql'
 \i 'tables/das/competition_representation.sql'
 \i 'tables/das/event_entry.sql'
@@ -93,4 +100,7 @@ CREATE SCHEMA IF NOT EXISTS USADANCE;
 \i 'tables/das/scoresheet.sql'
 
 -- rank section
-\i 'tables/rating/rank_competitive_ballroom.sql'
\ No newline at end of file
+\i 'tables/rating/rank_competitive_ballroom.sql'
+
+-- collegiate section
+\i 'tables/collegiate/proficiency_point_override.sql'
\ No newline at end of file
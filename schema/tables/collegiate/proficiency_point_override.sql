@@ -0,0 +1,30 @@
+-- Overrides that allow athletes to enter levels of a style they have pointed out of. Overrides are revoked by clearing
+-- ACTIVE_IND, and are never deleted.
+CREATE TABLE IF NOT EXISTS COLLEGIATE.PROFICIENCY_POINT_OVERRIDE (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ATHLETE_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  STYLE_ID INTEGER NOT NULL REFERENCES DAS.STYLE (ID),
+  PROFICIENCY_ID INTEGER NOT NULL REFERENCES DAS.PROFICIENCY (ID),
+  REASON TEXT NOT NULL,
+  ACTIVE_IND BOOLEAN NOT NULL DEFAULT TRUE,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ON COLLEGIATE.PROFICIENCY_POINT_OVERRIDE (ATHLETE_ID);
+
+-- Audit trail of overrides: every grant and revocation is recorded with the administrator who made it
+CREATE TABLE IF NOT EXISTS COLLEGIATE.PROFICIENCY_POINT_OVERRIDE_HISTORY (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  OVERRIDE_ID INTEGER NOT NULL REFERENCES COLLEGIATE.PROFICIENCY_POINT_OVERRIDE (ID),
+  ATHLETE_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  ACTION VARCHAR(16) NOT NULL,
+  NOTE TEXT NOT NULL,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ON COLLEGIATE.PROFICIENCY_POINT_OVERRIDE_HISTORY (OVERRIDE_ID);
+CREATE INDEX ON COLLEGIATE.PROFICIENCY_POINT_OVERRIDE_HISTORY (ATHLETE_ID);
@@ -1,4 +1,47 @@
+-- Credentials of accounts that sign in with DAS rather than an external identity provider. PASSWORD_HASH contains the
+-- name and parameters of the key derivation function along with the salt and the derived key. FAILED_LOGIN_ATTEMPTS
+-- counts the failed logins since the last successful one, and the account is locked when it reaches the limit.
 CREATE TABLE IF NOT EXISTS DAS.ACCOUNT_SECURITY (
   ID SERIAL NOT NULL PRIMARY KEY,
-  ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID)
-);
\ No newline at end of file
+  ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID) UNIQUE,
+  PASSWORD_HASH TEXT NOT NULL,
+  DATETIME_PASSWORD_CHANGED TIMESTAMP NOT NULL DEFAULT NOW(),
+  FAILED_LOGIN_ATTEMPTS INTEGER NOT NULL DEFAULT 0,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Sessions of accounts that sign in with DAS. Tokens of a session carry its TOKEN_ID, and are only accepted while the
+-- session is neither expired nor revoked.
+CREATE TABLE IF NOT EXISTS DAS.ACCOUNT_SESSION (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  TOKEN_ID TEXT NOT NULL UNIQUE,
+  DATETIME_EXPIRES TIMESTAMP NOT NULL,
+  DATETIME_REVOKED TIMESTAMP,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON DAS.ACCOUNT_SESSION (ACCOUNT_ID);
+
+-- Single-use tokens that are sent to users by email to reset their password or unlock their account. Only the SHA-256
+-- hash of each token is stored.
+CREATE TABLE IF NOT EXISTS DAS.ACCOUNT_SECURITY_TOKEN (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  PURPOSE TEXT NOT NULL,
+  TOKEN_HASH TEXT NOT NULL UNIQUE,
+  DATETIME_EXPIRES TIMESTAMP NOT NULL,
+  DATETIME_USED TIMESTAMP,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON DAS.ACCOUNT_SECURITY_TOKEN (ACCOUNT_ID);
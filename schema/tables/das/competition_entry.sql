@@ -160,7 +160,8 @@ CREATE TABLE IF NOT EXISTS DAS.COMPETITION_LEAD_TAG (
   DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
   UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
   DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
-  UNIQUE (COMPETITION_ID, LEAD_ID, TAG_NUMBER)
+  UNIQUE (COMPETITION_ID, LEAD_ID),
+  UNIQUE (COMPETITION_ID, TAG_NUMBER) -- no two leads share a tag, even if they register at the same time
 );
 
 CREATE INDEX ON DAS.COMPETITION_LEAD_TAG (COMPETITION_ID);
@@ -0,0 +1,18 @@
+-- Registration fees of competitions. Every athlete pays BASE_FEE once and PER_EVENT_FEE for each event. Entries made
+-- before EARLY_REGISTRATION_DEADLINE are discounted, and entries made after LATE_REGISTRATION_START are surcharged.
+-- Rates are percentages.
+CREATE TABLE IF NOT EXISTS DAS.COMPETITION_FEE_SCHEDULE (
+  ID SERIAL NOT NULL PRIMARY KEY ,
+  COMPETITION_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION(ID) UNIQUE,
+  BASE_FEE REAL NOT NULL DEFAULT 0 CHECK (BASE_FEE >= 0),
+  PER_EVENT_FEE REAL NOT NULL DEFAULT 0 CHECK (PER_EVENT_FEE >= 0),
+  EARLY_REGISTRATION_DEADLINE TIMESTAMP,
+  EARLY_DISCOUNT_RATE REAL NOT NULL DEFAULT 0 CHECK (EARLY_DISCOUNT_RATE BETWEEN 0 AND 100),
+  LATE_REGISTRATION_START TIMESTAMP,
+  LATE_SURCHARGE_RATE REAL NOT NULL DEFAULT 0 CHECK (LATE_SURCHARGE_RATE BETWEEN 0 AND 100),
+  STUDENT_DISCOUNT_RATE REAL NOT NULL DEFAULT 0 CHECK (STUDENT_DISCOUNT_RATE BETWEEN 0 AND 100),
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,38 @@
+-- How lead tags of a competition are numbered. Tags are assigned sequentially from FIRST_TAG, or in blocks of
+-- BLOCK_SIZE tags for each school or studio if NUMBERING is BLOCK.
+CREATE TABLE IF NOT EXISTS DAS.COMPETITION_LEAD_TAG_SETTING (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  COMPETITION_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION(ID) UNIQUE,
+  NUMBERING TEXT NOT NULL DEFAULT 'SEQUENTIAL' CHECK (NUMBERING IN ('SEQUENTIAL', 'BLOCK')),
+  FIRST_TAG INTEGER NOT NULL DEFAULT 101 CHECK (FIRST_TAG > 0),
+  BLOCK_SIZE INTEGER NOT NULL DEFAULT 100 CHECK (BLOCK_SIZE > 0),
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Ranges of lead tags of a competition. A range of a school or studio is the block of tags of its leads, and a range
+-- without a school or studio is reserved: its tags are only assigned by the organizer. Ranges of a competition cannot
+-- overlap, which needs btree_gist to compare competitions in the exclusion constraint.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+CREATE TABLE IF NOT EXISTS DAS.COMPETITION_LEAD_TAG_RANGE (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  COMPETITION_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION(ID),
+  FIRST_TAG INTEGER NOT NULL CHECK (FIRST_TAG > 0),
+  LAST_TAG INTEGER NOT NULL,
+  SCHOOL_ID INTEGER REFERENCES DAS.SCHOOL(ID),
+  STUDIO_ID INTEGER REFERENCES DAS.STUDIO(ID),
+  DESCRIPTION TEXT,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (LAST_TAG >= FIRST_TAG),
+  CHECK (SCHOOL_ID IS NULL OR STUDIO_ID IS NULL),
+  UNIQUE (COMPETITION_ID, SCHOOL_ID),
+  UNIQUE (COMPETITION_ID, STUDIO_ID),
+  EXCLUDE USING GIST (COMPETITION_ID WITH =, INT4RANGE(FIRST_TAG, LAST_TAG, '[]') WITH &&)
+);
+
+CREATE INDEX ON DAS.COMPETITION_LEAD_TAG_RANGE (COMPETITION_ID);
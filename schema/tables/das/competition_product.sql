@@ -1,3 +1,6 @@
+-- Products that are sold by competitions. Products without MAXIMUM_AMOUNT have unlimited inventory, and their
+-- AVAILABLE_AMOUNT is NULL. AVAILABLE_AMOUNT is decremented by orders and can never be negative, which prevents
+-- overselling.
 CREATE TABLE IF NOT EXISTS DAS.COMPETITION_PRODUCT (
   ID SERIAL NOT NULL  PRIMARY KEY ,
   COMPETITION_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION(ID),
@@ -7,9 +10,13 @@ CREATE TABLE IF NOT EXISTS DAS.COMPETITION_PRODUCT (
   PRODUCT_DETAIL TEXT,
   PRODUCT_COST REAL NOT NULL CHECK (PRODUCT_COST >= 0),
   MAXIMUM_AMOUNT INTEGER CHECK (MAXIMUM_AMOUNT > 0),
-  AVAILABLE_AMOUNT INTEGER CHECK (AVAILABLE_AMOUNT > 0),
+  AVAILABLE_AMOUNT INTEGER CHECK (AVAILABLE_AMOUNT >= 0 AND AVAILABLE_AMOUNT <= MAXIMUM_AMOUNT),
   EFFECTIVE_START DATE NOT NULL DEFAULT NOW(),
   EFFECTIVE_END DATE NOT NULL DEFAULT NOW(),
+  CREATE_USER_ID INTEGER REFERENCES DAS.ACCOUNT (ID),
   DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
-  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
-);
\ No newline at end of file
+  UPDATE_USER_ID INTEGER REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (EFFECTIVE_END >= EFFECTIVE_START)
+);
+CREATE INDEX ON DAS.COMPETITION_PRODUCT (COMPETITION_ID);
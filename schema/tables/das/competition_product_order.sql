@@ -4,7 +4,10 @@ CREATE TABLE IF NOT EXISTS DAS.COMPETITION_PRODUCT_ORDER (
   PRODUCT_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION_PRODUCT(ID),
   USER_ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
   QUANTITY INTEGER NOT NULL CHECK (QUANTITY > 0),
-  TOTAL_COST REAL NOT NULL CHECK (TOTAL_COST > 0),
+  TOTAL_COST REAL NOT NULL CHECK (TOTAL_COST >= 0), -- free products can be ordered
+  ORDER_STATUS_ID INTEGER NOT NULL DEFAULT 1 REFERENCES DAS.COMPETITION_PRODUCT_ORDER_STATUS(ID),
   DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
   DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
-);
\ No newline at end of file
+);
+CREATE INDEX ON DAS.COMPETITION_PRODUCT_ORDER (PRODUCT_ID);
+CREATE INDEX ON DAS.COMPETITION_PRODUCT_ORDER (USER_ACCOUNT_ID);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS DAS.COMPETITION_PRODUCT_ORDER_STATUS (
+  ID SERIAL NOT NULL  PRIMARY KEY ,
+  ORDER_STATUS_NAME VARCHAR (16) NOT NULL UNIQUE,
+  ORDER_STATUS_DESC TEXT,
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO DAS.COMPETITION_PRODUCT_ORDER_STATUS(ORDER_STATUS_NAME, ORDER_STATUS_DESC) VALUES ('Pending', 'Order holds the products until its payment succeeds or fails');
+INSERT INTO DAS.COMPETITION_PRODUCT_ORDER_STATUS(ORDER_STATUS_NAME, ORDER_STATUS_DESC) VALUES ('Paid', 'Order is paid and confirmed');
+INSERT INTO DAS.COMPETITION_PRODUCT_ORDER_STATUS(ORDER_STATUS_NAME, ORDER_STATUS_DESC) VALUES ('Cancelled', 'Payment of the order failed, and the products are released');
+INSERT INTO DAS.COMPETITION_PRODUCT_ORDER_STATUS(ORDER_STATUS_NAME, ORDER_STATUS_DESC) VALUES ('Refunded', 'Payment of the order is refunded, and the products are released');
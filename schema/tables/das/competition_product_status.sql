@@ -4,4 +4,8 @@ CREATE TABLE IF NOT EXISTS DAS.COMPETITION_PRODUCT_STATUS (
   PRODUCT_STATUS_DESC TEXT,
   DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
   DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
-);
\ No newline at end of file
+);
+
+INSERT INTO DAS.COMPETITION_PRODUCT_STATUS(PRODUCT_STATUS_NAME, PRODUCT_STATUS_DESC) VALUES ('Draft', 'Product is being prepared by the organizer and cannot be ordered');
+INSERT INTO DAS.COMPETITION_PRODUCT_STATUS(PRODUCT_STATUS_NAME, PRODUCT_STATUS_DESC) VALUES ('Active', 'Product can be ordered during its effective window');
+INSERT INTO DAS.COMPETITION_PRODUCT_STATUS(PRODUCT_STATUS_NAME, PRODUCT_STATUS_DESC) VALUES ('Discontinued', 'Product can no longer be ordered, but existing orders are kept');
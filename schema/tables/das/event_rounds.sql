@@ -1,14 +1,21 @@
+-- Timetable of each event. The timetable is built before rounds are created, so each round is identified by its
+-- ROUND_ORDER in the event, and ROUND_ID refers to the round once it is created.
 CREATE TABLE IF NOT EXISTS DAS.EVENT_ROUND_SCHEDULE (
   ID SERIAL NOT NULL PRIMARY KEY,
   EVENT_ID INTEGER REFERENCES DAS.EVENT(ID),
-  ROUND_ID INTEGER NOT NULL,
+  ROUND_ID INTEGER REFERENCES DAS.ROUND(ID),
+  ROUND_ORDER INTEGER NOT NULL,
   PRELIMINARY_ROUND_IND BOOLEAN DEFAULT TRUE,
+  FLOOR INTEGER NOT NULL DEFAULT 1,
   ESTIMATED_STARTTIME TIMESTAMP DEFAULT NOW(),
+  ESTIMATED_ENDTIME TIMESTAMP,
   ACTUAL_STARTTIME TIMESTAMP DEFAULT NOW(),
+  PUBLISHED_IND BOOLEAN NOT NULL DEFAULT FALSE,
   CREATE_USER_ID INTEGER REFERENCES DAS.ACCOUNT(ID),
   DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
   UPDATE_USER_ID INTEGER REFERENCES DAS.ACCOUNT(ID),
   DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
-  UNIQUE (EVENT_ID, ROUND_ID)
+  UNIQUE (EVENT_ID, ROUND_ID),
+  UNIQUE (EVENT_ID, ROUND_ORDER)
 );
 CREATE INDEX ON DAS.EVENT_ROUND_SCHEDULE (EVENT_ID);
@@ -0,0 +1,14 @@
+-- Stripe keys of organizers. Payments of a competition are made to the Stripe account of its organizer, and webhook
+-- events of that account are verified with its own signing secret.
+CREATE TABLE IF NOT EXISTS DAS.ORGANIZER_STRIPE_SETTINGS (
+  ID SERIAL NOT NULL PRIMARY KEY ,
+  ORGANIZER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID) UNIQUE,
+  PUBLIC_KEY TEXT NOT NULL,
+  RESTRICTED_KEY TEXT NOT NULL,
+  WEBHOOK_SECRET TEXT NOT NULL,
+  CURRENCY VARCHAR(3) NOT NULL DEFAULT 'usd',
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS DAS.PAYMENT_STATUS (
+  ID SERIAL NOT NULL  PRIMARY KEY ,
+  PAYMENT_STATUS_NAME VARCHAR (16) NOT NULL UNIQUE,
+  PAYMENT_STATUS_DESC TEXT,
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO DAS.PAYMENT_STATUS(PAYMENT_STATUS_NAME, PAYMENT_STATUS_DESC) VALUES ('Pending', 'Checkout is created and the payment gateway has not reported the result');
+INSERT INTO DAS.PAYMENT_STATUS(PAYMENT_STATUS_NAME, PAYMENT_STATUS_DESC) VALUES ('Succeeded', 'Payment succeeded and what it pays for is confirmed');
+INSERT INTO DAS.PAYMENT_STATUS(PAYMENT_STATUS_NAME, PAYMENT_STATUS_DESC) VALUES ('Failed', 'Payment failed or the checkout expired');
+INSERT INTO DAS.PAYMENT_STATUS(PAYMENT_STATUS_NAME, PAYMENT_STATUS_DESC) VALUES ('Refunded', 'Payment is fully refunded');
+
+-- Payments made through the payment gateway. PURPOSE and REFERENCE_ID identify what is paid for, such as the ID of a
+-- product order.
+CREATE TABLE IF NOT EXISTS DAS.PAYMENT (
+  ID SERIAL NOT NULL PRIMARY KEY ,
+  ORGANIZER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  COMPETITION_ID INTEGER NOT NULL REFERENCES DAS.COMPETITION(ID),
+  ACCOUNT_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  PURPOSE VARCHAR(32) NOT NULL,
+  REFERENCE_ID INTEGER NOT NULL,
+  AMOUNT REAL NOT NULL CHECK (AMOUNT > 0),
+  REFUNDED_AMOUNT REAL NOT NULL DEFAULT 0 CHECK (REFUNDED_AMOUNT >= 0 AND REFUNDED_AMOUNT <= AMOUNT),
+  CURRENCY VARCHAR(3) NOT NULL,
+  PAYMENT_STATUS_ID INTEGER NOT NULL REFERENCES DAS.PAYMENT_STATUS(ID),
+  GATEWAY_SESSION_ID TEXT UNIQUE,
+  GATEWAY_PAYMENT_ID TEXT,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON DAS.PAYMENT (COMPETITION_ID);
+CREATE INDEX ON DAS.PAYMENT (ACCOUNT_ID);
+CREATE INDEX ON DAS.PAYMENT (PURPOSE, REFERENCE_ID);
@@ -9,3 +9,16 @@ CREATE TABLE IF NOT EXISTS DAS.ROUND (
     DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
     UNIQUE (EVENT_ID, ROUND_ORDER)
 );
+
+-- Heat draw of each round. The seed reproduces the draw from the same couples and affiliations
+CREATE TABLE IF NOT EXISTS DAS.ROUND_HEAT_DRAW (
+    ID SERIAL NOT NULL PRIMARY KEY,
+    ROUND_ID INTEGER NOT NULL REFERENCES DAS.ROUND (ID) ON DELETE CASCADE,
+    SEED BIGINT NOT NULL,
+    FLOOR_CAPACITY INTEGER NOT NULL,
+    CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+    DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+    UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+    DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (ROUND_ID)
+);
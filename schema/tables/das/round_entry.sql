@@ -30,4 +30,14 @@ CREATE TABLE IF NOT EXISTS DAS.ROUND_ENTRY_ADJUDICATOR (
 );
 
 CREATE INDEX ON DAS.ROUND_ENTRY_ADJUDICATOR (ROUND_ID);
-CREATE INDEX ON DAS.ROUND_ENTRY_ADJUDICATOR (ADJUDICATOR_ID);
\ No newline at end of file
+CREATE INDEX ON DAS.ROUND_ENTRY_ADJUDICATOR (ADJUDICATOR_ID);
+
+-- Heats of each draw, which stay as they were drawn when couples or their affiliations change after the draw
+CREATE TABLE IF NOT EXISTS DAS.ROUND_HEAT_ASSIGNMENT (
+    ID SERIAL NOT NULL PRIMARY KEY,
+    ROUND_HEAT_DRAW_ID INTEGER NOT NULL REFERENCES DAS.ROUND_HEAT_DRAW (ID) ON DELETE CASCADE,
+    EVENT_DANCE_ID INTEGER NOT NULL REFERENCES DAS.EVENT_DANCES (ID),
+    HEAT_NUMBER INTEGER NOT NULL,
+    ROUND_ENTRY_PARTNERSHIP_ID INTEGER NOT NULL REFERENCES DAS.ROUND_ENTRY_PARTNERSHIP (ID) ON DELETE CASCADE,
+    UNIQUE (ROUND_HEAT_DRAW_ID, EVENT_DANCE_ID, ROUND_ENTRY_PARTNERSHIP_ID)
+);
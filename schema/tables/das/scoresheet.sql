@@ -0,0 +1,67 @@
+-- Scoresheet of a round, which is opened by a scrutineer before marks are entered, and locked before results are
+-- calculated. STATUS: 1 = Open, 2 = Locked
+CREATE TABLE IF NOT EXISTS DAS.SCORESHEET (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ROUND_ID INTEGER NOT NULL REFERENCES DAS.ROUND (ID) ON DELETE CASCADE,
+  PRELIMINARY_ROUND_IND BOOLEAN NOT NULL DEFAULT TRUE,
+  RECALL_SIZE INTEGER NOT NULL DEFAULT 0, -- number of couples to recall from a preliminary round
+  STATUS INTEGER NOT NULL DEFAULT 1,
+  SCRUTINEER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT (ID),
+  DATETIME_LOCKED TIMESTAMP,
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (ROUND_ID),
+  CHECK (RECALL_SIZE >= 0),
+  CHECK (STATUS IN (1, 2))
+);
+
+-- Marks of adjudicators: each row is the mark that an adjudicator gives to a couple in a dance of a round. Marks of
+-- preliminary rounds are callbacks and marks of final rounds are placements.
+CREATE TABLE IF NOT EXISTS DAS.SCORESHEET_PLACEMENT (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ROUND_ID INTEGER NOT NULL REFERENCES DAS.ROUND (ID) ON DELETE CASCADE,
+  EVENT_DANCE_ID INTEGER NOT NULL REFERENCES DAS.EVENT_DANCES (ID),
+  ROUND_ENTRY_ADJUDICATOR_ID INTEGER NOT NULL REFERENCES DAS.ROUND_ENTRY_ADJUDICATOR (ID) ON DELETE CASCADE,
+  ROUND_ENTRY_PARTNERSHIP_ID INTEGER NOT NULL REFERENCES DAS.ROUND_ENTRY_PARTNERSHIP (ID) ON DELETE CASCADE,
+  PRELIMINARY_ROUND_IND BOOLEAN NOT NULL DEFAULT TRUE,
+  CALLBACK_IND BOOLEAN NOT NULL DEFAULT FALSE, -- only used in preliminary rounds
+  PLACEMENT INTEGER NOT NULL DEFAULT 0, -- only used in final rounds
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (EVENT_DANCE_ID, ROUND_ENTRY_ADJUDICATOR_ID, ROUND_ENTRY_PARTNERSHIP_ID),
+  CHECK (PLACEMENT >= 0)
+);
+
+CREATE INDEX ON DAS.SCORESHEET_PLACEMENT (ROUND_ID);
+CREATE INDEX ON DAS.SCORESHEET_PLACEMENT (ROUND_ENTRY_ADJUDICATOR_ID);
+CREATE INDEX ON DAS.SCORESHEET_PLACEMENT (ROUND_ENTRY_PARTNERSHIP_ID);
+
+-- Results of couples in a round, calculated from the marks of adjudicators. Results of each dance have an
+-- EVENT_DANCE_ID, and the overall result of the round does not.
+CREATE TABLE IF NOT EXISTS DAS.SCORESHEET_RESULT (
+  ID SERIAL NOT NULL PRIMARY KEY,
+  ROUND_ID INTEGER NOT NULL REFERENCES DAS.ROUND (ID) ON DELETE CASCADE,
+  EVENT_DANCE_ID INTEGER REFERENCES DAS.EVENT_DANCES (ID),
+  ROUND_ENTRY_PARTNERSHIP_ID INTEGER NOT NULL REFERENCES DAS.ROUND_ENTRY_PARTNERSHIP (ID) ON DELETE CASCADE,
+  PRELIMINARY_ROUND_IND BOOLEAN NOT NULL DEFAULT TRUE,
+  RECALL_MARKS INTEGER NOT NULL DEFAULT 0, -- total callbacks in a preliminary round
+  RECALL_IND BOOLEAN NOT NULL DEFAULT FALSE,
+  PLACEMENT INTEGER NOT NULL DEFAULT 0,
+  PLACEMENT_VALUE REAL NOT NULL DEFAULT 0, -- tied couples share the average of their places
+  RULE INTEGER NOT NULL DEFAULT 0, -- the rule of the Skating System that decided the placement
+  CREATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_CREATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UPDATE_USER_ID INTEGER NOT NULL REFERENCES DAS.ACCOUNT(ID),
+  DATETIME_UPDATED TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (ROUND_ID, EVENT_DANCE_ID, ROUND_ENTRY_PARTNERSHIP_ID)
+);
+
+CREATE INDEX ON DAS.SCORESHEET_RESULT (ROUND_ID);
+CREATE INDEX ON DAS.SCORESHEET_RESULT (ROUND_ENTRY_PARTNERSHIP_ID);
+
+-- the unique constraint of the table does not apply to overall results, whose EVENT_DANCE_ID is NULL
+CREATE UNIQUE INDEX ON DAS.SCORESHEET_RESULT (ROUND_ID, ROUND_ENTRY_PARTNERSHIP_ID) WHERE EVENT_DANCE_ID IS NULL;
@@ -0,0 +1,33 @@
+package viewmodel
+
+import "github.com/DancesportSoftware/das/businesslogic"
+
+// AdjudicatorHeatListDTO is the heat list of a round that an adjudicator marks
+type AdjudicatorHeatListDTO struct {
+	RoundID         int   `json:"round"`
+	EventID         int   `json:"event"`
+	Preliminary     bool  `json:"preliminary"`
+	RecallSize      int   `json:"recallSize"`
+	Dances          []int `json:"dances"`
+	Couples         []int `json:"couples"`
+	SubmittedDances []int `json:"submitted"`
+}
+
+func AdjudicatorHeatListToViewModel(heatList businesslogic.AdjudicatorHeatList) AdjudicatorHeatListDTO {
+	dto := AdjudicatorHeatListDTO{
+		RoundID:         heatList.Round.ID,
+		EventID:         heatList.Round.EventID,
+		Preliminary:     heatList.Scoresheet.PreliminaryRoundIndicator,
+		RecallSize:      heatList.Scoresheet.RecallSize,
+		Dances:          make([]int, 0),
+		Couples:         make([]int, 0),
+		SubmittedDances: heatList.SubmittedDances,
+	}
+	for _, each := range heatList.Dances {
+		dto.Dances = append(dto.Dances, each.ID)
+	}
+	for _, each := range heatList.Couples {
+		dto.Couples = append(dto.Couples, each.ID)
+	}
+	return dto
+}
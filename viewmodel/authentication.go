@@ -0,0 +1,62 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/auth/local"
+	"time"
+)
+
+// SignUpDTO is the JSON payload for request POST /api/v1.0/auth/signup
+type SignUpDTO struct {
+	CreateAccountDTO
+	Password string `json:"password" validate:"nonzero"`
+}
+
+// LoginDTO is the JSON payload for request POST /api/v1.0/auth/login
+type LoginDTO struct {
+	Email    string `json:"email" validate:"nonzero"`
+	Password string `json:"password" validate:"nonzero"`
+}
+
+// RefreshTokenDTO is the JSON payload for request POST /api/v1.0/auth/refresh
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refreshToken" validate:"nonzero"`
+}
+
+// TokenPairViewModel is returned when users log in or refresh their session
+type TokenPairViewModel struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	TokenType    string    `json:"tokenType"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+func TokenPairToViewModel(pair local.TokenPair) TokenPairViewModel {
+	return TokenPairViewModel{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    pair.ExpiresAt,
+	}
+}
+
+// ChangePasswordDTO is the JSON payload for request PUT /api/v1.0/account/password
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"currentPassword" validate:"nonzero"`
+	NewPassword     string `json:"newPassword" validate:"nonzero"`
+}
+
+// ForgotPasswordDTO is the JSON payload for request POST /api/v1.0/account/password/forgot
+type ForgotPasswordDTO struct {
+	Email string `json:"email" validate:"nonzero"`
+}
+
+// ResetPasswordDTO is the JSON payload for request POST /api/v1.0/account/password/reset
+type ResetPasswordDTO struct {
+	Token       string `json:"token" validate:"nonzero"`
+	NewPassword string `json:"newPassword" validate:"nonzero"`
+}
+
+// UnlockAccountDTO is the JSON payload for request POST /api/v1.0/account/unlock
+type UnlockAccountDTO struct {
+	Token string `json:"token" validate:"nonzero"`
+}
@@ -0,0 +1,10 @@
+package viewmodel
+
+// SearchCompetitionDocumentDTO specifies the printable document of a competition. Heat sheets are printed for the
+// event if it is specified, and entry summaries are printed for the school if it is specified. Otherwise, documents
+// cover the whole competition.
+type SearchCompetitionDocumentDTO struct {
+	CompetitionID int `schema:"competition,required"`
+	EventID       int `schema:"event"`
+	SchoolID      int `schema:"school"`
+}
@@ -6,7 +6,7 @@ import (
 )
 
 // OrganizerSearchEventCriteria defines the query string that Organizer can submit to search
-// events that the organizer created
+// events of a competition that the organizer manages, or all events that the organizer created
 type OrganizerSearchEventCriteria struct {
 	FederationID  int `schema:"federationId"`
 	CompetitionID int `schema:"competitionId"`
@@ -122,5 +122,6 @@ func (view *EventViewModel) PopulateViewModel(model businesslogic.Event) {
 }
 
 type DeleteEventForm struct {
-	ID int `json:"eventId";validate:"min=1"`
+	CompetitionID int `json:"competition" validate:"min=1"`
+	ID            int `json:"eventId";validate:"min=1"`
 }
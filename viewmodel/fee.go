@@ -0,0 +1,110 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"time"
+)
+
+// CompetitionFeeScheduleDTO is the payload that an organizer submits to set the registration fees of a competition
+type CompetitionFeeScheduleDTO struct {
+	CompetitionID             int        `json:"competition" validate:"min=1"`
+	BaseFee                   float64    `json:"baseFee"`
+	PerEventFee               float64    `json:"perEventFee"`
+	EarlyRegistrationDeadline *time.Time `json:"earlyRegistrationDeadline"`
+	EarlyDiscountRate         float64    `json:"earlyDiscountRate"`
+	LateRegistrationStart     *time.Time `json:"lateRegistrationStart"`
+	LateSurchargeRate         float64    `json:"lateSurchargeRate"`
+	StudentDiscountRate       float64    `json:"studentDiscountRate"`
+}
+
+func (dto CompetitionFeeScheduleDTO) ToCompetitionFeeSchedule() businesslogic.CompetitionFeeSchedule {
+	return businesslogic.CompetitionFeeSchedule{
+		CompetitionID:             dto.CompetitionID,
+		BaseFee:                   dto.BaseFee,
+		PerEventFee:               dto.PerEventFee,
+		EarlyRegistrationDeadline: dto.EarlyRegistrationDeadline,
+		EarlyDiscountRate:         dto.EarlyDiscountRate,
+		LateRegistrationStart:     dto.LateRegistrationStart,
+		LateSurchargeRate:         dto.LateSurchargeRate,
+		StudentDiscountRate:       dto.StudentDiscountRate,
+	}
+}
+
+// CompetitionFeeScheduleViewModel is the registration fees of a competition
+type CompetitionFeeScheduleViewModel struct {
+	CompetitionID             int        `json:"competition"`
+	BaseFee                   float64    `json:"baseFee"`
+	PerEventFee               float64    `json:"perEventFee"`
+	EarlyRegistrationDeadline *time.Time `json:"earlyRegistrationDeadline"`
+	EarlyDiscountRate         float64    `json:"earlyDiscountRate"`
+	LateRegistrationStart     *time.Time `json:"lateRegistrationStart"`
+	LateSurchargeRate         float64    `json:"lateSurchargeRate"`
+	StudentDiscountRate       float64    `json:"studentDiscountRate"`
+}
+
+func CompetitionFeeScheduleDataModelToViewModel(schedule businesslogic.CompetitionFeeSchedule) CompetitionFeeScheduleViewModel {
+	return CompetitionFeeScheduleViewModel{
+		CompetitionID:             schedule.CompetitionID,
+		BaseFee:                   schedule.BaseFee,
+		PerEventFee:               schedule.PerEventFee,
+		EarlyRegistrationDeadline: schedule.EarlyRegistrationDeadline,
+		EarlyDiscountRate:         schedule.EarlyDiscountRate,
+		LateRegistrationStart:     schedule.LateRegistrationStart,
+		LateSurchargeRate:         schedule.LateSurchargeRate,
+		StudentDiscountRate:       schedule.StudentDiscountRate,
+	}
+}
+
+// SearchInvoiceDTO specifies the invoice to view. The invoice of a partnership is returned if the partnership is
+// specified, otherwise the invoice of the athlete is returned.
+type SearchInvoiceDTO struct {
+	CompetitionID int `schema:"competition,required"`
+	AthleteID     int `schema:"athlete"`
+	PartnershipID int `schema:"partnership"`
+}
+
+// InvoiceItemViewModel is a fee in an invoice
+type InvoiceItemViewModel struct {
+	Description     string  `json:"description"`
+	AthleteID       int     `json:"athlete"`
+	EventID         int     `json:"event,omitempty"`
+	Fee             float64 `json:"fee"`
+	Adjustment      float64 `json:"adjustment"`
+	StudentDiscount float64 `json:"studentDiscount"`
+	Amount          float64 `json:"amount"`
+}
+
+// InvoiceViewModel is the itemized registration fee of an athlete or a partnership
+type InvoiceViewModel struct {
+	CompetitionID int                    `json:"competition"`
+	AthleteID     int                    `json:"athlete,omitempty"`
+	PartnershipID int                    `json:"partnership,omitempty"`
+	Items         []InvoiceItemViewModel `json:"items"`
+	Total         float64                `json:"total"`
+	AmountPaid    float64                `json:"paid"`
+	Balance       float64                `json:"balance"`
+}
+
+func InvoiceDataModelToViewModel(invoice businesslogic.Invoice) InvoiceViewModel {
+	view := InvoiceViewModel{
+		CompetitionID: invoice.CompetitionID,
+		AthleteID:     invoice.AthleteID,
+		PartnershipID: invoice.PartnershipID,
+		Items:         make([]InvoiceItemViewModel, 0),
+		Total:         invoice.Total,
+		AmountPaid:    invoice.AmountPaid,
+		Balance:       invoice.Balance,
+	}
+	for _, each := range invoice.Items {
+		view.Items = append(view.Items, InvoiceItemViewModel{
+			Description:     each.Description,
+			AthleteID:       each.AthleteID,
+			EventID:         each.EventID,
+			Fee:             each.Fee,
+			Adjustment:      each.Adjustment,
+			StudentDiscount: each.StudentDiscount,
+			Amount:          each.Amount,
+		})
+	}
+	return view
+}
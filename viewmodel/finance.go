@@ -0,0 +1,154 @@
+package viewmodel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/DancesportSoftware/das/businesslogic"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SearchFinanceReportDTO specifies the competition of the finance report, and whether it is exported as CSV or JSON
+type SearchFinanceReportDTO struct {
+	CompetitionID int    `schema:"competition,required"`
+	Format        string `schema:"format"`
+}
+
+// ProductCategoryRevenueViewModel is what the products of a category have made at a competition
+type ProductCategoryRevenueViewModel struct {
+	CategoryID   int     `json:"category"`
+	CategoryName string  `json:"name"`
+	Orders       int     `json:"orders"`
+	Quantity     int     `json:"quantity"`
+	Sales        float64 `json:"sales"`
+	Refunds      float64 `json:"refunds"`
+	Net          float64 `json:"net"`
+}
+
+// AthleteBalanceViewModel is the registration fee of an athlete and how much of it is paid
+type AthleteBalanceViewModel struct {
+	EntryID     int     `json:"entry"`
+	AthleteID   int     `json:"athlete"`
+	AthleteName string  `json:"name"`
+	Total       float64 `json:"total"`
+	AmountPaid  float64 `json:"paid"`
+	Balance     float64 `json:"balance"`
+}
+
+// FinanceReportViewModel is the finance report of a competition
+type FinanceReportViewModel struct {
+	CompetitionID        int                               `json:"competition"`
+	ProductRevenue       []ProductCategoryRevenueViewModel `json:"products"`
+	RegistrationInvoiced float64                           `json:"registrationInvoiced"`
+	RegistrationSales    float64                           `json:"registrationSales"`
+	RegistrationRefunds  float64                           `json:"registrationRefunds"`
+	OutstandingBalances  []AthleteBalanceViewModel         `json:"outstanding"`
+	UnpaidEntries        []AthleteBalanceViewModel         `json:"unpaid"`
+	Refunds              []PaymentViewModel                `json:"refunds"`
+	TotalSales           float64                           `json:"totalSales"`
+	TotalRefunds         float64                           `json:"totalRefunds"`
+	TotalNet             float64                           `json:"totalNet"`
+	TotalOutstanding     float64                           `json:"totalOutstanding"`
+}
+
+func athleteBalancesToViewModel(balances []businesslogic.AthleteBalance) []AthleteBalanceViewModel {
+	output := make([]AthleteBalanceViewModel, 0)
+	for _, each := range balances {
+		output = append(output, AthleteBalanceViewModel{
+			EntryID:     each.EntryID,
+			AthleteID:   each.AthleteID,
+			AthleteName: each.AthleteName,
+			Total:       each.Total,
+			AmountPaid:  each.AmountPaid,
+			Balance:     each.Balance,
+		})
+	}
+	return output
+}
+
+func FinanceReportDataModelToViewModel(report businesslogic.FinanceReport) FinanceReportViewModel {
+	view := FinanceReportViewModel{
+		CompetitionID:        report.CompetitionID,
+		ProductRevenue:       make([]ProductCategoryRevenueViewModel, 0),
+		RegistrationInvoiced: report.RegistrationInvoiced,
+		RegistrationSales:    report.RegistrationSales,
+		RegistrationRefunds:  report.RegistrationRefunds,
+		OutstandingBalances:  athleteBalancesToViewModel(report.OutstandingBalances),
+		UnpaidEntries:        athleteBalancesToViewModel(report.UnpaidEntries),
+		Refunds:              make([]PaymentViewModel, 0),
+		TotalSales:           report.TotalSales,
+		TotalRefunds:         report.TotalRefunds,
+		TotalNet:             report.TotalNet,
+		TotalOutstanding:     report.TotalOutstanding,
+	}
+	for _, each := range report.ProductRevenue {
+		view.ProductRevenue = append(view.ProductRevenue, ProductCategoryRevenueViewModel{
+			CategoryID:   each.CategoryID,
+			CategoryName: each.CategoryName,
+			Orders:       each.Orders,
+			Quantity:     each.Quantity,
+			Sales:        each.Sales,
+			Refunds:      each.Refunds,
+			Net:          each.Net,
+		})
+	}
+	for _, each := range report.Refunds {
+		view.Refunds = append(view.Refunds, PaymentDataModelToViewModel(each))
+	}
+	return view
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// escapeCSVCell prefixes text that a spreadsheet would read as a formula with an apostrophe, so that names entered by
+// users cannot run formulas when the report is opened. Amounts are numbers and are written as they are.
+func escapeCSVCell(cell string) string {
+	if cell == "" || strings.IndexAny(cell[:1], "=+-@\t\r") < 0 {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+// WriteCSV writes the report as one table, so that it can be reconciled in a spreadsheet. Each row belongs to a
+// section, and columns that do not apply to a section are left empty.
+func (view FinanceReportViewModel) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"Section", "Reference", "Description", "Quantity", "Amount", "Paid", "Refunded", "Balance"},
+	}
+	for _, each := range view.ProductRevenue {
+		rows = append(rows, []string{"product", strconv.Itoa(each.CategoryID), each.CategoryName, strconv.Itoa(each.Quantity),
+			"", formatAmount(each.Sales), formatAmount(each.Refunds), ""})
+	}
+	rows = append(rows, []string{"registration", strconv.Itoa(view.CompetitionID), "Registration fees", "",
+		formatAmount(view.RegistrationInvoiced), formatAmount(view.RegistrationSales), formatAmount(view.RegistrationRefunds), formatAmount(view.TotalOutstanding)})
+	for _, each := range view.OutstandingBalances {
+		rows = append(rows, []string{"outstanding", strconv.Itoa(each.AthleteID), each.AthleteName, "",
+			formatAmount(each.Total), formatAmount(each.AmountPaid), "", formatAmount(each.Balance)})
+	}
+	for _, each := range view.UnpaidEntries {
+		rows = append(rows, []string{"unpaid", strconv.Itoa(each.AthleteID), each.AthleteName, "",
+			formatAmount(each.Total), formatAmount(each.AmountPaid), "", formatAmount(each.Balance)})
+	}
+	for _, each := range view.Refunds {
+		rows = append(rows, []string{"refund", strconv.Itoa(each.ID), fmt.Sprintf("%v %v", each.Purpose, each.Reference), "",
+			formatAmount(each.Amount), formatAmount(each.Amount), formatAmount(each.RefundedAmount), ""})
+	}
+	rows = append(rows, []string{"total", strconv.Itoa(view.CompetitionID), "Total", "",
+		"", formatAmount(view.TotalSales), formatAmount(view.TotalRefunds), formatAmount(view.TotalOutstanding)})
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = escapeCSVCell(cell)
+		}
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,107 @@
+package viewmodel
+
+import "github.com/DancesportSoftware/das/businesslogic"
+
+// CompetitionLeadTagViewModel is the tag of a lead at a competition
+type CompetitionLeadTagViewModel struct {
+	ID            int `json:"id"`
+	CompetitionID int `json:"competition"`
+	LeadID        int `json:"lead"`
+	Tag           int `json:"tag"`
+}
+
+func CompetitionLeadTagDataModelToViewModel(tag businesslogic.CompetitionLeadTag) CompetitionLeadTagViewModel {
+	return CompetitionLeadTagViewModel{
+		ID:            tag.ID,
+		CompetitionID: tag.CompetitionID,
+		LeadID:        tag.LeadID,
+		Tag:           tag.Tag,
+	}
+}
+
+// OverrideLeadTagDTO is the payload that an organizer submits to assign a tag to a lead
+type OverrideLeadTagDTO struct {
+	CompetitionID int `json:"competition" validate:"min=1"`
+	LeadID        int `json:"lead" validate:"min=1"`
+	Tag           int `json:"tag" validate:"min=1"`
+}
+
+// CompetitionLeadTagSettingsViewModel specifies how lead tags of a competition are numbered. It is also the payload
+// that an organizer submits to update the settings.
+type CompetitionLeadTagSettingsViewModel struct {
+	CompetitionID int    `json:"competition" validate:"min=1"`
+	Numbering     string `json:"numbering" validate:"nonzero"`
+	FirstTag      int    `json:"firstTag" validate:"min=1"`
+	BlockSize     int    `json:"blockSize" validate:"min=1"`
+}
+
+func CompetitionLeadTagSettingsDataModelToViewModel(settings businesslogic.CompetitionLeadTagSettings) CompetitionLeadTagSettingsViewModel {
+	return CompetitionLeadTagSettingsViewModel{
+		CompetitionID: settings.CompetitionID,
+		Numbering:     settings.Numbering,
+		FirstTag:      settings.FirstTag,
+		BlockSize:     settings.BlockSize,
+	}
+}
+
+func (dto CompetitionLeadTagSettingsViewModel) ToCompetitionLeadTagSettings() businesslogic.CompetitionLeadTagSettings {
+	return businesslogic.CompetitionLeadTagSettings{
+		CompetitionID: dto.CompetitionID,
+		Numbering:     dto.Numbering,
+		FirstTag:      dto.FirstTag,
+		BlockSize:     dto.BlockSize,
+	}
+}
+
+// CompetitionLeadTagRangeViewModel is a reserved range of lead tags, or the block of tags of a school or studio
+type CompetitionLeadTagRangeViewModel struct {
+	ID            int    `json:"id"`
+	CompetitionID int    `json:"competition"`
+	FirstTag      int    `json:"firstTag"`
+	LastTag       int    `json:"lastTag"`
+	SchoolID      *int   `json:"school"`
+	StudioID      *int   `json:"studio"`
+	Reserved      bool   `json:"reserved"`
+	Description   string `json:"description"`
+}
+
+func CompetitionLeadTagRangeDataModelToViewModel(tagRange businesslogic.CompetitionLeadTagRange) CompetitionLeadTagRangeViewModel {
+	return CompetitionLeadTagRangeViewModel{
+		ID:            tagRange.ID,
+		CompetitionID: tagRange.CompetitionID,
+		FirstTag:      tagRange.FirstTag,
+		LastTag:       tagRange.LastTag,
+		SchoolID:      tagRange.SchoolID,
+		StudioID:      tagRange.StudioID,
+		Reserved:      tagRange.IsReserved(),
+		Description:   tagRange.Description,
+	}
+}
+
+// CreateLeadTagRangeDTO is the payload that an organizer submits to reserve a range of lead tags, or to create the
+// block of a school or studio
+type CreateLeadTagRangeDTO struct {
+	CompetitionID int    `json:"competition" validate:"min=1"`
+	FirstTag      int    `json:"firstTag" validate:"min=1"`
+	LastTag       int    `json:"lastTag" validate:"min=1"`
+	SchoolID      *int   `json:"school"`
+	StudioID      *int   `json:"studio"`
+	Description   string `json:"description"`
+}
+
+func (dto CreateLeadTagRangeDTO) ToCompetitionLeadTagRange() businesslogic.CompetitionLeadTagRange {
+	return businesslogic.CompetitionLeadTagRange{
+		CompetitionID: dto.CompetitionID,
+		FirstTag:      dto.FirstTag,
+		LastTag:       dto.LastTag,
+		SchoolID:      dto.SchoolID,
+		StudioID:      dto.StudioID,
+		Description:   dto.Description,
+	}
+}
+
+// DeleteLeadTagRangeDTO specifies the range of lead tags that an organizer deletes
+type DeleteLeadTagRangeDTO struct {
+	CompetitionID int `json:"competition" validate:"min=1"`
+	RangeID       int `json:"range" validate:"min=1"`
+}
@@ -0,0 +1,93 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"time"
+)
+
+// StartCheckoutDTO is the payload that a user submits to pay for an order or a registration
+type StartCheckoutDTO struct {
+	Purpose    string `json:"purpose" validate:"nonzero"`
+	Reference  int    `json:"reference" validate:"min=1"`
+	SuccessURL string `json:"successUrl" validate:"nonzero"`
+	CancelURL  string `json:"cancelUrl" validate:"nonzero"`
+}
+
+// CheckoutViewModel is a checkout session that a user pays with
+type CheckoutViewModel struct {
+	PaymentID int    `json:"payment"`
+	SessionID string `json:"session"`
+	URL       string `json:"url"`
+}
+
+// PaymentViewModel is a payment that a user makes to the organizer of a competition
+type PaymentViewModel struct {
+	ID             int       `json:"id"`
+	CompetitionID  int       `json:"competition"`
+	AccountID      int       `json:"account"`
+	Purpose        string    `json:"purpose"`
+	Reference      int       `json:"reference"`
+	Amount         float64   `json:"amount"`
+	RefundedAmount float64   `json:"refunded"`
+	Currency       string    `json:"currency"`
+	Status         int       `json:"status"`
+	DateCreated    time.Time `json:"dateCreated"`
+}
+
+func PaymentDataModelToViewModel(payment businesslogic.Payment) PaymentViewModel {
+	return PaymentViewModel{
+		ID:             payment.ID,
+		CompetitionID:  payment.CompetitionID,
+		AccountID:      payment.AccountID,
+		Purpose:        payment.Purpose,
+		Reference:      payment.ReferenceID,
+		Amount:         payment.Amount,
+		RefundedAmount: payment.RefundedAmount,
+		Currency:       payment.Currency,
+		Status:         payment.StatusID,
+		DateCreated:    payment.DateTimeCreated,
+	}
+}
+
+// RefundPaymentDTO is the payload that an organizer submits to refund a payment. An amount of 0 refunds what has not
+// been refunded.
+type RefundPaymentDTO struct {
+	CompetitionID int     `json:"competition" validate:"min=1"`
+	PaymentID     int     `json:"payment" validate:"min=1"`
+	Amount        float64 `json:"amount"`
+}
+
+// StripeSettingsDTO is the payload that an organizer submits to receive payments with a Stripe account
+type StripeSettingsDTO struct {
+	PublicKey     string `json:"publicKey" validate:"nonzero"`
+	RestrictedKey string `json:"restrictedKey" validate:"nonzero"`
+	WebhookSecret string `json:"webhookSecret" validate:"nonzero"`
+	Currency      string `json:"currency"`
+}
+
+func (dto StripeSettingsDTO) ToOrganizerStripeSettings() businesslogic.OrganizerStripeSettings {
+	return businesslogic.OrganizerStripeSettings{
+		StripePublicKey:     dto.PublicKey,
+		StripeRestrictedKey: dto.RestrictedKey,
+		StripeWebhookSecret: dto.WebhookSecret,
+		Currency:            dto.Currency,
+	}
+}
+
+// StripeSettingsViewModel is the Stripe settings of an organizer. Secrets are never returned.
+type StripeSettingsViewModel struct {
+	PublicKey string `json:"publicKey"`
+	Currency  string `json:"currency"`
+}
+
+func StripeSettingsDataModelToViewModel(settings businesslogic.OrganizerStripeSettings) StripeSettingsViewModel {
+	return StripeSettingsViewModel{
+		PublicKey: settings.StripePublicKey,
+		Currency:  settings.Currency,
+	}
+}
+
+// PaymentWebhookDTO specifies the organizer whose payment gateway sends the webhook event
+type PaymentWebhookDTO struct {
+	OrganizerID string `schema:"organizer,required"`
+}
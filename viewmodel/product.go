@@ -0,0 +1,120 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"time"
+)
+
+// ProductCategoryViewModel is a category of competition products
+type ProductCategoryViewModel struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func ProductCategoryDataModelToViewModel(category businesslogic.ProductCategory) ProductCategoryViewModel {
+	return ProductCategoryViewModel{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+	}
+}
+
+// CompetitionProductViewModel is a product that a competition sells. Maximum and available are 0 for products with
+// unlimited inventory.
+type CompetitionProductViewModel struct {
+	ID            int       `json:"id"`
+	CompetitionID int       `json:"competition"`
+	CategoryID    int       `json:"category"`
+	StatusID      int       `json:"status"`
+	Title         string    `json:"title"`
+	Detail        string    `json:"detail"`
+	Cost          float64   `json:"cost"`
+	Unlimited     bool      `json:"unlimited"`
+	Maximum       int       `json:"maximum"`
+	Available     int       `json:"available"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+}
+
+func CompetitionProductDataModelToViewModel(product businesslogic.CompetitionProduct) CompetitionProductViewModel {
+	return CompetitionProductViewModel{
+		ID:            product.ID,
+		CompetitionID: product.CompetitionID,
+		CategoryID:    product.CategoryID,
+		StatusID:      product.StatusID,
+		Title:         product.Title,
+		Detail:        product.Detail,
+		Cost:          product.Cost,
+		Unlimited:     product.IsUnlimited(),
+		Maximum:       product.MaximumAmount,
+		Available:     product.AvailableAmount,
+		Start:         product.EffectiveStart,
+		End:           product.EffectiveEnd,
+	}
+}
+
+// CompetitionProductDTO is the payload that an organizer submits to create or update a product. A maximum of 0 means
+// that the inventory of the product is unlimited.
+type CompetitionProductDTO struct {
+	ID            int       `json:"id"`
+	CompetitionID int       `json:"competition"`
+	CategoryID    int       `json:"category" validate:"min=1"`
+	StatusID      int       `json:"status"`
+	Title         string    `json:"title" validate:"nonzero"`
+	Detail        string    `json:"detail"`
+	Cost          float64   `json:"cost"`
+	Maximum       int       `json:"maximum"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+}
+
+func (dto CompetitionProductDTO) ToCompetitionProduct() businesslogic.CompetitionProduct {
+	return businesslogic.CompetitionProduct{
+		ID:             dto.ID,
+		CompetitionID:  dto.CompetitionID,
+		CategoryID:     dto.CategoryID,
+		StatusID:       dto.StatusID,
+		Title:          dto.Title,
+		Detail:         dto.Detail,
+		Cost:           dto.Cost,
+		MaximumAmount:  dto.Maximum,
+		EffectiveStart: dto.Start,
+		EffectiveEnd:   dto.End,
+	}
+}
+
+// DeleteCompetitionProductDTO specifies the product that an organizer deletes
+type DeleteCompetitionProductDTO struct {
+	CompetitionID int `json:"competition" validate:"min=1"`
+	ProductID     int `json:"product" validate:"min=1"`
+}
+
+// PlaceProductOrderDTO is the payload that a user submits to order a product
+type PlaceProductOrderDTO struct {
+	ProductID int `json:"product" validate:"min=1"`
+	Quantity  int `json:"quantity" validate:"min=1"`
+}
+
+// ProductOrderViewModel is an order of a competition product
+type ProductOrderViewModel struct {
+	ID            int       `json:"id"`
+	CompetitionID int       `json:"competition"`
+	ProductID     int       `json:"product"`
+	UserAccountID int       `json:"user"`
+	Quantity      int       `json:"quantity"`
+	TotalCost     float64   `json:"total"`
+	DateOrdered   time.Time `json:"dateOrdered"`
+}
+
+func ProductOrderDataModelToViewModel(order businesslogic.ProductOrder) ProductOrderViewModel {
+	return ProductOrderViewModel{
+		ID:            order.ID,
+		CompetitionID: order.CompetitionID,
+		ProductID:     order.ProductID,
+		UserAccountID: order.UserAccountID,
+		Quantity:      order.Quantity,
+		TotalCost:     order.TotalCost,
+		DateOrdered:   order.DateTimeCreated,
+	}
+}
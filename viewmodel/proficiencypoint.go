@@ -0,0 +1,122 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"time"
+)
+
+// ProficiencyPointViewModel is the points that an athlete earned from the final of a collegiate event
+type ProficiencyPointViewModel struct {
+	CompetitionID int    `json:"competition"`
+	EventID       int    `json:"event"`
+	PartnershipID int    `json:"partnership"`
+	StyleID       int    `json:"style"`
+	ProficiencyID int    `json:"proficiency"`
+	Level         string `json:"level"`
+	Placement     int    `json:"placement"`
+	Points        int    `json:"points"`
+}
+
+// ProficiencyPointSummaryViewModel is the points that an athlete has at a level of a style
+type ProficiencyPointSummaryViewModel struct {
+	StyleID    int    `json:"style"`
+	Level      string `json:"level"`
+	Earned     int    `json:"earned"`
+	Total      int    `json:"total"`
+	PointedOut bool   `json:"pointedOut"`
+	Overridden bool   `json:"overridden"`
+}
+
+// AthleteProficiencyPointViewModel contains the points that an athlete earned and the summary of each level
+type AthleteProficiencyPointViewModel struct {
+	Points  []ProficiencyPointViewModel        `json:"points"`
+	Summary []ProficiencyPointSummaryViewModel `json:"summary"`
+}
+
+func AthleteProficiencyPointDataModelToViewModel(points []businesslogic.ProficiencyPoint, summaries []businesslogic.ProficiencyPointSummary) AthleteProficiencyPointViewModel {
+	view := AthleteProficiencyPointViewModel{
+		Points:  make([]ProficiencyPointViewModel, 0),
+		Summary: make([]ProficiencyPointSummaryViewModel, 0),
+	}
+	for _, each := range points {
+		view.Points = append(view.Points, ProficiencyPointViewModel{
+			CompetitionID: each.CompetitionID,
+			EventID:       each.EventID,
+			PartnershipID: each.PartnershipID,
+			StyleID:       each.StyleID,
+			ProficiencyID: each.ProficiencyID,
+			Level:         each.Level,
+			Placement:     each.Placement,
+			Points:        each.Points,
+		})
+	}
+	for _, each := range summaries {
+		view.Summary = append(view.Summary, ProficiencyPointSummaryViewModel{
+			StyleID:    each.StyleID,
+			Level:      each.Level,
+			Earned:     each.Earned,
+			Total:      each.Total,
+			PointedOut: each.PointedOut,
+			Overridden: each.Overridden,
+		})
+	}
+	return view
+}
+
+// SearchAthleteProficiencyPointDTO specifies the athlete whose points are searched by an administrator
+type SearchAthleteProficiencyPointDTO struct {
+	AthleteID string `schema:"athlete,required"`
+}
+
+// GrantProficiencyPointOverrideDTO is the payload that an administrator submits to allow an athlete to enter a level
+// that the athlete has pointed out of
+type GrantProficiencyPointOverrideDTO struct {
+	AthleteID     string `json:"athlete" validate:"nonzero"`
+	StyleID       int    `json:"style" validate:"min=1"`
+	ProficiencyID int    `json:"proficiency" validate:"min=1"`
+	Reason        string `json:"reason" validate:"nonzero"`
+}
+
+// RevokeProficiencyPointOverrideDTO is the payload that an administrator submits to revoke an override
+type RevokeProficiencyPointOverrideDTO struct {
+	OverrideID int    `json:"override" validate:"min=1"`
+	Note       string `json:"note" validate:"nonzero"`
+}
+
+// ProficiencyPointOverrideViewModel is an override of proficiency points
+type ProficiencyPointOverrideViewModel struct {
+	ID            int    `json:"id"`
+	StyleID       int    `json:"style"`
+	ProficiencyID int    `json:"proficiency"`
+	Reason        string `json:"reason"`
+	Active        bool   `json:"active"`
+}
+
+func ProficiencyPointOverrideDataModelToViewModel(override businesslogic.ProficiencyPointOverride) ProficiencyPointOverrideViewModel {
+	return ProficiencyPointOverrideViewModel{
+		ID:            override.ID,
+		StyleID:       override.StyleID,
+		ProficiencyID: override.ProficiencyID,
+		Reason:        override.Reason,
+		Active:        override.Active,
+	}
+}
+
+// ProficiencyPointOverrideHistoryViewModel is an entry in the audit trail of overrides
+type ProficiencyPointOverrideHistoryViewModel struct {
+	OverrideID  int       `json:"override"`
+	Action      string    `json:"action"`
+	Note        string    `json:"note"`
+	CreatedBy   int       `json:"createdBy"`
+	DateCreated time.Time `json:"dateCreated"`
+}
+
+func ProficiencyPointOverrideHistoryDataModelToViewModel(entry businesslogic.ProficiencyPointOverrideHistoryEntry) ProficiencyPointOverrideHistoryViewModel {
+	return ProficiencyPointOverrideHistoryViewModel{
+		OverrideID:  entry.OverrideID,
+		Action:      entry.Action,
+		Note:        entry.Note,
+		CreatedBy:   entry.CreateUserID,
+		DateCreated: entry.DateTimeCreated,
+	}
+}
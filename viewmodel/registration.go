@@ -1,5 +1,7 @@
 package viewmodel
 
+import "github.com/DancesportSoftware/das/businesslogic"
+
 // AthleteCompetitionRegistrationForm is the payload that should be submitted by athlete to sign up for a competition
 // This form should only contain events that the couple would compete. If an existing registration
 type AthleteCompetitionRegistrationForm struct {
@@ -14,3 +16,25 @@ type AthleteCompetitionRegistrationForm struct {
 		StudioId  int `json:"studioId,omitempty"`
 	} `json:"representation,omitempty"`
 }
+
+// EligibilityViolationViewModel explains why a registration cannot enter an event. Reason is one of the
+// businesslogic.EligibilityReason codes.
+type EligibilityViolationViewModel struct {
+	Reason    string `json:"reason"`
+	EventID   int    `json:"event,omitempty"`
+	AthleteID int    `json:"athlete,omitempty"`
+	Message   string `json:"message"`
+}
+
+func EligibilityErrorToViewModel(err businesslogic.EventEligibilityError) []EligibilityViolationViewModel {
+	violations := make([]EligibilityViolationViewModel, 0)
+	for _, each := range err.Violations {
+		violations = append(violations, EligibilityViolationViewModel{
+			Reason:    each.Reason,
+			EventID:   each.EventID,
+			AthleteID: each.AthleteID,
+			Message:   each.Message,
+		})
+	}
+	return violations
+}
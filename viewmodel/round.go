@@ -0,0 +1,86 @@
+package viewmodel
+
+import "github.com/DancesportSoftware/das/businesslogic"
+
+// StartEventDTO is the request to start an event. Settings that are not specified use the default settings.
+type StartEventDTO struct {
+	CompetitionID   int `json:"competition"`
+	EventID         int `json:"event"`
+	FloorCapacity   int `json:"floorCapacity"`
+	RecallRate      int `json:"recallRate"`
+	TargetFinalSize int `json:"finalSize"`
+	DanceDuration   int `json:"danceDuration"`
+}
+
+func (dto StartEventDTO) ToRoundGenerationSettings() businesslogic.RoundGenerationSettings {
+	settings := businesslogic.DefaultRoundGenerationSettings
+	if dto.FloorCapacity > 0 {
+		settings.FloorCapacity = dto.FloorCapacity
+	}
+	if dto.RecallRate > 0 {
+		settings.RecallRate = dto.RecallRate
+	}
+	if dto.TargetFinalSize > 0 {
+		settings.TargetFinalSize = dto.TargetFinalSize
+	}
+	if dto.DanceDuration > 0 {
+		settings.DanceDuration = dto.DanceDuration
+	}
+	return settings
+}
+
+// RoundViewModel is the round of an event
+type RoundViewModel struct {
+	ID      int `json:"id"`
+	EventID int `json:"event"`
+	Order   int `json:"order"`
+}
+
+func RoundDataModelToViewModel(round businesslogic.Round) RoundViewModel {
+	return RoundViewModel{
+		ID:      round.ID,
+		EventID: round.EventID,
+		Order:   round.Order.Rank,
+	}
+}
+
+// DrawHeatsDTO is the request to draw the couples of a round into heats. A new seed is generated if seed is not
+// specified, and the seed of a previous draw can be used to reproduce the draw.
+type DrawHeatsDTO struct {
+	CompetitionID int   `json:"competition"`
+	RoundID       int   `json:"round"`
+	FloorCapacity int   `json:"floorCapacity"`
+	Seed          int64 `json:"seed"`
+}
+
+// HeatViewModel is a heat of a dance in a round
+type HeatViewModel struct {
+	EventDanceID int   `json:"dance"`
+	Number       int   `json:"heat"`
+	Couples      []int `json:"couples"`
+}
+
+// HeatDrawViewModel is the heats of a round and the seed that reproduces them
+type HeatDrawViewModel struct {
+	RoundID       int             `json:"round"`
+	Seed          int64           `json:"seed,string"`
+	FloorCapacity int             `json:"floorCapacity"`
+	Heats         []HeatViewModel `json:"heats"`
+}
+
+func HeatDrawDataModelToViewModel(draw businesslogic.RoundHeatDraw, heats []businesslogic.Heat) HeatDrawViewModel {
+	view := HeatDrawViewModel{
+		RoundID:       draw.RoundID,
+		Seed:          draw.Seed,
+		FloorCapacity: draw.FloorCapacity,
+		Heats:         make([]HeatViewModel, 0),
+	}
+	for _, each := range heats {
+		view.Heats = append(view.Heats, HeatViewModel{
+			EventDanceID: each.EventDanceID,
+			Number:       each.Number,
+			Couples:      each.Couples,
+		})
+	}
+	return view
+}
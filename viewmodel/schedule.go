@@ -0,0 +1,166 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/core/scheduler"
+	"time"
+)
+
+// SessionDTO is a period of the competition when the floors are available
+type SessionDTO struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Floors int       `json:"floors"`
+}
+
+// BuildScheduleDTO is the request to build the timetable of a competition. Durations are in minutes, except that
+// DanceDuration is in seconds. Round settings that are not specified use the default settings.
+type BuildScheduleDTO struct {
+	CompetitionID   int          `json:"competition"`
+	Sessions        []SessionDTO `json:"sessions"`
+	FloorCapacity   int          `json:"floorCapacity"`
+	RecallRate      int          `json:"recallRate"`
+	TargetFinalSize int          `json:"finalSize"`
+	DanceDuration   int          `json:"danceDuration"`
+	RoundInterval   int          `json:"roundInterval"`
+	BreakAfter      int          `json:"breakAfter"`
+	BreakDuration   int          `json:"breakDuration"`
+}
+
+func (dto BuildScheduleDTO) ToCompetitionScheduleSettings() businesslogic.CompetitionScheduleSettings {
+	settings := businesslogic.CompetitionScheduleSettings{
+		RoundGenerationSettings: StartEventDTO{
+			FloorCapacity:   dto.FloorCapacity,
+			RecallRate:      dto.RecallRate,
+			TargetFinalSize: dto.TargetFinalSize,
+			DanceDuration:   dto.DanceDuration,
+		}.ToRoundGenerationSettings(),
+		Sessions: make([]scheduler.Session, 0),
+		BreakRule: scheduler.BreakRule{
+			MaxContinuous: time.Duration(dto.BreakAfter) * time.Minute,
+			Duration:      time.Duration(dto.BreakDuration) * time.Minute,
+		},
+		RoundInterval: time.Duration(dto.RoundInterval) * time.Minute,
+	}
+	for _, each := range dto.Sessions {
+		floors := each.Floors
+		if floors == 0 {
+			floors = 1
+		}
+		settings.Sessions = append(settings.Sessions, scheduler.Session{Start: each.Start, End: each.End, Floors: floors})
+	}
+	return settings
+}
+
+// ScheduleSearchDTO is the query to get the timetable of a competition
+type ScheduleSearchDTO struct {
+	CompetitionID int `schema:"competitionId,required"`
+}
+
+// PublishScheduleDTO is the request to publish the timetable of a competition
+type PublishScheduleDTO struct {
+	CompetitionID int `json:"competition"`
+}
+
+// RoundScheduleViewModel is a round in the timetable of a competition
+type RoundScheduleViewModel struct {
+	EventID     int        `json:"event"`
+	Round       int        `json:"round"`
+	Final       bool       `json:"final"`
+	Floor       int        `json:"floor"`
+	Start       time.Time  `json:"start"`
+	End         time.Time  `json:"end"`
+	ActualStart *time.Time `json:"actualStart,omitempty"`
+	Published   bool       `json:"published"`
+}
+
+func RoundScheduleDataModelToViewModel(schedule businesslogic.EventRoundSchedule) RoundScheduleViewModel {
+	return RoundScheduleViewModel{
+		EventID:     schedule.EventID,
+		Round:       schedule.RoundOrder,
+		Final:       !schedule.PreliminaryRoundIndicator,
+		Floor:       schedule.Floor,
+		Start:       schedule.EstimatedStartTime,
+		End:         schedule.EstimatedEndTime,
+		ActualStart: schedule.ActualStartTime,
+		Published:   schedule.Published,
+	}
+}
+
+// ScheduleConflictViewModel is a pair of overlapping rounds that share couples or athletes
+type ScheduleConflictViewModel struct {
+	First    RoundScheduleViewModel `json:"first"`
+	Second   RoundScheduleViewModel `json:"second"`
+	Couples  []int                  `json:"couples"`
+	Athletes []int                  `json:"athletes"`
+}
+
+// CompetitionScheduleViewModel is the timetable of a competition and its conflicts
+type CompetitionScheduleViewModel struct {
+	Rounds    []RoundScheduleViewModel    `json:"rounds"`
+	Conflicts []ScheduleConflictViewModel `json:"conflicts"`
+}
+
+func CompetitionScheduleDataModelToViewModel(schedule businesslogic.CompetitionSchedule) CompetitionScheduleViewModel {
+	view := CompetitionScheduleViewModel{
+		Rounds:    make([]RoundScheduleViewModel, 0),
+		Conflicts: make([]ScheduleConflictViewModel, 0),
+	}
+	for _, each := range schedule.Rounds {
+		view.Rounds = append(view.Rounds, RoundScheduleDataModelToViewModel(each))
+	}
+	for _, each := range schedule.Conflicts {
+		view.Conflicts = append(view.Conflicts, ScheduleConflictViewModel{
+			First:    scheduledRoundToViewModel(each.First),
+			Second:   scheduledRoundToViewModel(each.Second),
+			Couples:  each.Couples,
+			Athletes: each.Athletes,
+		})
+	}
+	return view
+}
+
+func scheduledRoundToViewModel(round scheduler.ScheduledRound) RoundScheduleViewModel {
+	return RoundScheduleViewModel{
+		EventID: round.EventID,
+		Round:   round.Round,
+		Final:   round.Final,
+		Floor:   round.Floor,
+		Start:   round.Start,
+		End:     round.End,
+	}
+}
+
+// AthleteConflictSearchDTO is the query to check the timetable of a competition for athletes who do not have enough
+// rest. MinimumRest is in minutes, and the default minimum rest is used if it is not specified.
+type AthleteConflictSearchDTO struct {
+	CompetitionID int  `schema:"competitionId,required"`
+	MinimumRest   *int `schema:"minimumRest"`
+}
+
+func (dto AthleteConflictSearchDTO) GetMinimumRest() time.Duration {
+	if dto.MinimumRest == nil {
+		return businesslogic.DefaultMinimumRest
+	}
+	return time.Duration(*dto.MinimumRest) * time.Minute
+}
+
+// AthleteConflictViewModel is a pair of rounds of an athlete that overlap or do not leave enough rest. Rest is in
+// minutes, and is negative when the rounds overlap.
+type AthleteConflictViewModel struct {
+	AthleteID int                    `json:"athlete"`
+	First     RoundScheduleViewModel `json:"first"`
+	Second    RoundScheduleViewModel `json:"second"`
+	Clash     bool                   `json:"clash"`
+	Rest      float64                `json:"rest"`
+}
+
+func AthleteConflictDataModelToViewModel(conflict businesslogic.AthleteScheduleConflict) AthleteConflictViewModel {
+	return AthleteConflictViewModel{
+		AthleteID: conflict.AthleteID,
+		First:     RoundScheduleDataModelToViewModel(conflict.First),
+		Second:    RoundScheduleDataModelToViewModel(conflict.Second),
+		Clash:     conflict.Clash,
+		Rest:      conflict.Rest.Minutes(),
+	}
+}
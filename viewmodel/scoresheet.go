@@ -0,0 +1,169 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"github.com/DancesportSoftware/das/core/skating"
+	"time"
+)
+
+// OpenRoundDTO is the request of a scrutineer to open a round for marking
+type OpenRoundDTO struct {
+	CompetitionID int  `json:"competition"`
+	RoundID       int  `json:"round"`
+	Preliminary   bool `json:"preliminary"`
+	RecallSize    int  `json:"recallSize"`
+}
+
+// RoundDTO specifies the round that a request is made for
+type RoundDTO struct {
+	CompetitionID int `json:"competition" schema:"competition"`
+	RoundID       int `json:"round" schema:"round"`
+}
+
+// ComputeRoundResultDTO is the request to calculate the result of a locked round. RecallTied is the decision of the
+// chairman of adjudicators when couples are tied at the cutoff of a preliminary round.
+type ComputeRoundResultDTO struct {
+	CompetitionID int  `json:"competition"`
+	RoundID       int  `json:"round"`
+	RecallTied    bool `json:"recallTied"`
+}
+
+// JudgeMarkDTO is the mark that an adjudicator gives to a couple
+type JudgeMarkDTO struct {
+	CoupleID  int  `json:"couple"`
+	Callback  bool `json:"callback"`
+	Placement int  `json:"placement"`
+}
+
+// JudgeMarksDTO is the marks of an adjudicator in a dance of a round
+type JudgeMarksDTO struct {
+	CompetitionID int            `json:"competition"`
+	RoundID       int            `json:"round"`
+	EventDanceID  int            `json:"dance"`
+	Adjudicator   int            `json:"adjudicator"`
+	Marks         []JudgeMarkDTO `json:"marks"`
+}
+
+// ToJudgeMarksSubmission converts the DTO to the marks that can be saved by businesslogic
+func (dto JudgeMarksDTO) ToJudgeMarksSubmission() businesslogic.JudgeMarksSubmission {
+	submission := businesslogic.JudgeMarksSubmission{
+		RoundID:                 dto.RoundID,
+		EventDanceID:            dto.EventDanceID,
+		AdjudicatorRoundEntryID: dto.Adjudicator,
+		Placements:              make([]businesslogic.Placement, 0),
+	}
+	for _, each := range dto.Marks {
+		submission.Placements = append(submission.Placements, businesslogic.Placement{
+			PartnershipRoundEntryID: each.CoupleID,
+			Callback:                each.Callback,
+			Placement:               each.Placement,
+		})
+	}
+	return submission
+}
+
+// PlacementDTO is a mark that has been entered in a round
+type PlacementDTO struct {
+	EventDanceID int  `json:"dance"`
+	Adjudicator  int  `json:"adjudicator"`
+	CoupleID     int  `json:"couple"`
+	Callback     bool `json:"callback"`
+	Placement    int  `json:"placement"`
+}
+
+// ScoresheetDTO is the marking status of a round and the marks that are entered
+type ScoresheetDTO struct {
+	RoundID        int            `json:"round"`
+	Preliminary    bool           `json:"preliminary"`
+	RecallSize     int            `json:"recallSize"`
+	Locked         bool           `json:"locked"`
+	DateTimeLocked *time.Time     `json:"locked_at,omitempty"`
+	Marks          []PlacementDTO `json:"marks,omitempty"`
+}
+
+func ScoresheetDataModelToViewModel(scoresheet businesslogic.Scoresheet, placements []businesslogic.Placement) ScoresheetDTO {
+	dto := ScoresheetDTO{
+		RoundID:        scoresheet.RoundID,
+		Preliminary:    scoresheet.PreliminaryRoundIndicator,
+		RecallSize:     scoresheet.RecallSize,
+		Locked:         !scoresheet.IsOpen(),
+		DateTimeLocked: scoresheet.DateTimeLocked,
+	}
+	for _, each := range placements {
+		dto.Marks = append(dto.Marks, PlacementDTO{
+			EventDanceID: each.EventDanceID,
+			Adjudicator:  each.AdjudicatorRoundEntryID,
+			CoupleID:     each.PartnershipRoundEntryID,
+			Callback:     each.Callback,
+			Placement:    each.Placement,
+		})
+	}
+	return dto
+}
+
+// CoupleResultDTO is the result of a couple in a round
+type CoupleResultDTO struct {
+	CoupleID  int       `json:"couple"`
+	Marks     int       `json:"marks,omitempty"`
+	Places    []float64 `json:"places,omitempty"`
+	Total     float64   `json:"total,omitempty"`
+	Placement float64   `json:"placement,omitempty"`
+	Rule      int       `json:"rule,omitempty"`
+	Recalled  bool      `json:"recalled"`
+	Tied      bool      `json:"tied"`
+}
+
+// RoundResultDTO is the result of a round. Tabulation is the printable scrutineering sheet of the round.
+type RoundResultDTO struct {
+	RoundID    int               `json:"round"`
+	Couples    []CoupleResultDTO `json:"couples"`
+	Tabulation []string          `json:"tabulation"`
+}
+
+func RoundTabulationToViewModel(roundID int, tabulation skating.RoundTabulation) RoundResultDTO {
+	dto := RoundResultDTO{
+		RoundID:    roundID,
+		Couples:    make([]CoupleResultDTO, 0),
+		Tabulation: make([]string, 0),
+	}
+	for _, each := range tabulation.Couples {
+		dto.Couples = append(dto.Couples, CoupleResultDTO{
+			CoupleID:  each.CoupleID,
+			Places:    each.Places,
+			Total:     each.Total,
+			Placement: each.PlacementValue,
+			Rule:      each.Rule,
+			Tied:      each.Tied,
+		})
+	}
+	for _, each := range tabulation.Dances {
+		dto.Tabulation = append(dto.Tabulation, each.String())
+	}
+	dto.Tabulation = append(dto.Tabulation, tabulation.String())
+	return dto
+}
+
+func RecallTabulationToViewModel(roundID int, tabulation skating.RecallTabulation, recallTied bool) RoundResultDTO {
+	dto := RoundResultDTO{
+		RoundID:    roundID,
+		Couples:    make([]CoupleResultDTO, 0),
+		Tabulation: make([]string, 0),
+	}
+	recalled := make(map[int]bool)
+	for _, each := range tabulation.GetRecalledCouples(recallTied) {
+		recalled[each] = true
+	}
+	tied := make(map[int]bool)
+	for _, each := range tabulation.Tied {
+		tied[each] = true
+	}
+	for _, each := range tabulation.Couples {
+		dto.Couples = append(dto.Couples, CoupleResultDTO{
+			CoupleID: each.CoupleID,
+			Marks:    each.Marks,
+			Recalled: recalled[each.CoupleID],
+			Tied:     tied[each.CoupleID],
+		})
+	}
+	return dto
+}
@@ -0,0 +1,110 @@
+package viewmodel
+
+import (
+	"github.com/DancesportSoftware/das/businesslogic"
+	"time"
+)
+
+// CompetitionTBAEntryViewModel is a "looking for partner" posting of an athlete at a competition
+type CompetitionTBAEntryViewModel struct {
+	ID            int       `json:"id"`
+	CompetitionID int       `json:"competition"`
+	Role          string    `json:"role"`
+	Proficiency   string    `json:"proficiency"`
+	Style         string    `json:"style"`
+	Height        string    `json:"height"`
+	School        string    `json:"school"`
+	ContactEmail  string    `json:"email"`
+	ContactPhone  string    `json:"phone"`
+	MiscInfo      string    `json:"info"`
+	IsOwnEntry    bool      `json:"own"`
+	DatePosted    time.Time `json:"datePosted"`
+}
+
+func CompetitionTBAEntryDataModelToViewModel(currentUser businesslogic.Account, entry businesslogic.CompetitionTBAEntry) CompetitionTBAEntryViewModel {
+	return CompetitionTBAEntryViewModel{
+		ID:            entry.ID,
+		CompetitionID: entry.CompetitionID,
+		Role:          entry.Role,
+		Proficiency:   entry.Proficiency,
+		Style:         entry.Style,
+		Height:        entry.Height,
+		School:        entry.School,
+		ContactEmail:  entry.ContactEmail,
+		ContactPhone:  entry.ContactPhone,
+		MiscInfo:      entry.MiscInfo,
+		IsOwnEntry:    entry.AccountID == currentUser.ID,
+		DatePosted:    entry.DateTimeCreated,
+	}
+}
+
+// CompetitionTBAEntryDTO is the payload that an athlete submits to post or update a TBA entry
+type CompetitionTBAEntryDTO struct {
+	ID            int    `json:"id"`
+	CompetitionID int    `json:"competition"`
+	Role          string `json:"role" validate:"nonzero"`
+	Proficiency   string `json:"proficiency" validate:"nonzero"`
+	Style         string `json:"style" validate:"nonzero"`
+	Height        string `json:"height"`
+	School        string `json:"school"`
+	ContactEmail  string `json:"email"`
+	ContactPhone  string `json:"phone"`
+	MiscInfo      string `json:"info"`
+}
+
+func (dto CompetitionTBAEntryDTO) ToCompetitionTBAEntry() businesslogic.CompetitionTBAEntry {
+	return businesslogic.CompetitionTBAEntry{
+		ID:            dto.ID,
+		CompetitionID: dto.CompetitionID,
+		Role:          dto.Role,
+		Proficiency:   dto.Proficiency,
+		Style:         dto.Style,
+		Height:        dto.Height,
+		School:        dto.School,
+		ContactEmail:  dto.ContactEmail,
+		ContactPhone:  dto.ContactPhone,
+		MiscInfo:      dto.MiscInfo,
+	}
+}
+
+// DeleteCompetitionTBAEntryDTO specifies the TBA entry that an athlete deletes
+type DeleteCompetitionTBAEntryDTO struct {
+	EntryID int `json:"entry" validate:"min=1"`
+}
+
+// TBAPartnershipRequestDTO is the payload that an athlete submits to send a partnership request to the athlete who
+// posted a TBA entry
+type TBAPartnershipRequestDTO struct {
+	EntryID int    `json:"entry" validate:"min=1"`
+	Message string `json:"message"`
+}
+
+// PartnerSuggestionFactorViewModel is a reason why a partner is suggested
+type PartnerSuggestionFactorViewModel struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	Description string `json:"description"`
+}
+
+// PartnerSuggestionViewModel is the TBA entry of a suggested partner, with the factors that it is scored by
+type PartnerSuggestionViewModel struct {
+	Entry   CompetitionTBAEntryViewModel       `json:"entry"`
+	Score   int                                `json:"score"`
+	Factors []PartnerSuggestionFactorViewModel `json:"factors"`
+}
+
+func PartnerSuggestionDataModelToViewModel(currentUser businesslogic.Account, suggestion businesslogic.PartnerSuggestion) PartnerSuggestionViewModel {
+	view := PartnerSuggestionViewModel{
+		Entry:   CompetitionTBAEntryDataModelToViewModel(currentUser, suggestion.Entry),
+		Score:   suggestion.Score,
+		Factors: make([]PartnerSuggestionFactorViewModel, 0),
+	}
+	for _, each := range suggestion.Factors {
+		view.Factors = append(view.Factors, PartnerSuggestionFactorViewModel{
+			Name:        each.Name,
+			Score:       each.Score,
+			Description: each.Description,
+		})
+	}
+	return view
+}